@@ -60,6 +60,8 @@ func (src *AzureClusterIdentity) ConvertTo(dstRaw conversion.Hub) error { // nol
 		dst.Spec.AllowedNamespaces.Selector = restored.Spec.AllowedNamespaces.Selector
 	}
 
+	dst.Spec.ProxyCABundle = restored.Spec.ProxyCABundle
+
 	// removing ownerReference for AzureCluster as ownerReference is not required from v1alpha4/v1beta1 onwards.
 	var restoredOwnerReferences []metav1.OwnerReference
 	for _, ownerRef := range dst.OwnerReferences {
@@ -52,8 +52,11 @@ func (src *AzureMachine) ConvertTo(dstRaw conversion.Hub) error { // nolint
 	}
 
 	dst.Spec.SubnetName = restored.Spec.SubnetName
+	dst.Spec.RoleAssignmentPrincipalIDs = restored.Spec.RoleAssignmentPrincipalIDs
+	dst.Spec.RoleAssignmentScope = restored.Spec.RoleAssignmentScope
 
 	dst.Status.LongRunningOperationStates = restored.Status.LongRunningOperationStates
+	dst.Status.RoleAssignmentCreateAttempts = restored.Status.RoleAssignmentCreateAttempts
 
 	return nil
 }
@@ -53,6 +53,8 @@ func (src *AzureMachineTemplate) ConvertTo(dstRaw conversion.Hub) error { // nol
 	}
 
 	dst.Spec.Template.Spec.SubnetName = restored.Spec.Template.Spec.SubnetName
+	dst.Spec.Template.Spec.RoleAssignmentPrincipalIDs = restored.Spec.Template.Spec.RoleAssignmentPrincipalIDs
+	dst.Spec.Template.Spec.RoleAssignmentScope = restored.Spec.Template.Spec.RoleAssignmentScope
 	dst.Spec.Template.ObjectMeta = restored.Spec.Template.ObjectMeta
 
 	return nil
@@ -578,6 +578,7 @@ func autoConvert_v1beta1_AzureClusterIdentitySpec_To_v1alpha3_AzureClusterIdenti
 	out.ClientSecret = in.ClientSecret
 	out.TenantID = in.TenantID
 	// WARNING: in.AllowedNamespaces requires manual conversion: inconvertible types (*sigs.k8s.io/cluster-api-provider-azure/api/v1beta1.AllowedNamespaces vs []string)
+	// WARNING: in.ProxyCABundle requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -887,6 +888,8 @@ func autoConvert_v1beta1_AzureMachineSpec_To_v1alpha3_AzureMachineSpec(in *v1bet
 	out.Identity = VMIdentity(in.Identity)
 	out.UserAssignedIdentities = *(*[]UserAssignedIdentity)(unsafe.Pointer(&in.UserAssignedIdentities))
 	out.RoleAssignmentName = in.RoleAssignmentName
+	// WARNING: in.RoleAssignmentPrincipalIDs requires manual conversion: does not exist in peer-type
+	// WARNING: in.RoleAssignmentScope requires manual conversion: does not exist in peer-type
 	if err := Convert_v1beta1_OSDisk_To_v1alpha3_OSDisk(&in.OSDisk, &out.OSDisk, s); err != nil {
 		return err
 	}
@@ -950,6 +953,7 @@ func autoConvert_v1beta1_AzureMachineStatus_To_v1alpha3_AzureMachineStatus(in *v
 		out.Conditions = nil
 	}
 	// WARNING: in.LongRunningOperationStates requires manual conversion: does not exist in peer-type
+	// WARNING: in.RoleAssignmentCreateAttempts requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -17,6 +17,8 @@ limitations under the License.
 package v1alpha4
 
 import (
+	apiconversion "k8s.io/apimachinery/pkg/conversion"
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 
 	infrav1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
@@ -25,12 +27,33 @@ import (
 // ConvertTo converts this AzureCluster to the Hub version (v1beta1).
 func (src *AzureClusterIdentity) ConvertTo(dstRaw conversion.Hub) error { // nolint
 	dst := dstRaw.(*infrav1beta1.AzureClusterIdentity)
+	if err := Convert_v1alpha4_AzureClusterIdentity_To_v1beta1_AzureClusterIdentity(src, dst, nil); err != nil {
+		return err
+	}
 
-	return Convert_v1alpha4_AzureClusterIdentity_To_v1beta1_AzureClusterIdentity(src, dst, nil)
+	// Manually restore data.
+	restored := &infrav1beta1.AzureClusterIdentity{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+
+	dst.Spec.ProxyCABundle = restored.Spec.ProxyCABundle
+
+	return nil
 }
 
 // ConvertFrom converts from the Hub version (v1beta1) to this version.
 func (dst *AzureClusterIdentity) ConvertFrom(srcRaw conversion.Hub) error { // nolint
 	src := srcRaw.(*infrav1beta1.AzureClusterIdentity)
-	return Convert_v1beta1_AzureClusterIdentity_To_v1alpha4_AzureClusterIdentity(src, dst, nil)
+	if err := Convert_v1beta1_AzureClusterIdentity_To_v1alpha4_AzureClusterIdentity(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion.
+	return utilconversion.MarshalData(src, dst)
+}
+
+// Convert_v1beta1_AzureClusterIdentitySpec_To_v1alpha4_AzureClusterIdentitySpec is an autogenerated conversion function.
+func Convert_v1beta1_AzureClusterIdentitySpec_To_v1alpha4_AzureClusterIdentitySpec(in *infrav1beta1.AzureClusterIdentitySpec, out *AzureClusterIdentitySpec, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_AzureClusterIdentitySpec_To_v1alpha4_AzureClusterIdentitySpec(in, out, s)
 }
@@ -17,7 +17,9 @@ limitations under the License.
 package v1alpha4
 
 import (
+	apiconversion "k8s.io/apimachinery/pkg/conversion"
 	"sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 )
 
@@ -25,13 +27,36 @@ import (
 func (src *AzureMachine) ConvertTo(dstRaw conversion.Hub) error { // nolint
 	dst := dstRaw.(*v1beta1.AzureMachine)
 
-	return Convert_v1alpha4_AzureMachine_To_v1beta1_AzureMachine(src, dst, nil)
+	if err := Convert_v1alpha4_AzureMachine_To_v1beta1_AzureMachine(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &v1beta1.AzureMachine{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+
+	dst.Spec.RoleAssignmentPrincipalIDs = restored.Spec.RoleAssignmentPrincipalIDs
+	dst.Spec.RoleAssignmentScope = restored.Spec.RoleAssignmentScope
+	dst.Status.RoleAssignmentCreateAttempts = restored.Status.RoleAssignmentCreateAttempts
+
+	return nil
 }
 
 // ConvertFrom converts from the Hub version (v1beta1) to this version.
 func (dst *AzureMachine) ConvertFrom(srcRaw conversion.Hub) error { // nolint
 	src := srcRaw.(*v1beta1.AzureMachine)
-	return Convert_v1beta1_AzureMachine_To_v1alpha4_AzureMachine(src, dst, nil)
+	if err := Convert_v1beta1_AzureMachine_To_v1alpha4_AzureMachine(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion.
+	if err := utilconversion.MarshalData(src, dst); err != nil {
+		return err
+	}
+
+	return nil
 }
 
 // ConvertTo converts this AzureMachineList to the Hub version (v1beta1).
@@ -45,3 +70,13 @@ func (dst *AzureMachineList) ConvertFrom(srcRaw conversion.Hub) error { // nolin
 	src := srcRaw.(*v1beta1.AzureMachineList)
 	return Convert_v1beta1_AzureMachineList_To_v1alpha4_AzureMachineList(src, dst, nil)
 }
+
+// Convert_v1beta1_AzureMachineSpec_To_v1alpha4_AzureMachineSpec is an autogenerated conversion function.
+func Convert_v1beta1_AzureMachineSpec_To_v1alpha4_AzureMachineSpec(in *v1beta1.AzureMachineSpec, out *AzureMachineSpec, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_AzureMachineSpec_To_v1alpha4_AzureMachineSpec(in, out, s)
+}
+
+// Convert_v1beta1_AzureMachineStatus_To_v1alpha4_AzureMachineStatus is an autogenerated conversion function.
+func Convert_v1beta1_AzureMachineStatus_To_v1alpha4_AzureMachineStatus(in *v1beta1.AzureMachineStatus, out *AzureMachineStatus, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_AzureMachineStatus_To_v1alpha4_AzureMachineStatus(in, out, s)
+}
@@ -38,6 +38,8 @@ func (src *AzureMachineTemplate) ConvertTo(dstRaw conversion.Hub) error { // nol
 	}
 
 	dst.Spec.Template.ObjectMeta = restored.Spec.Template.ObjectMeta
+	dst.Spec.Template.Spec.RoleAssignmentPrincipalIDs = restored.Spec.Template.Spec.RoleAssignmentPrincipalIDs
+	dst.Spec.Template.Spec.RoleAssignmentScope = restored.Spec.Template.Spec.RoleAssignmentScope
 
 	return nil
 }
@@ -64,6 +64,11 @@ type AzureClusterIdentitySpec struct {
 	// +optional
 	// +nullable
 	AllowedNamespaces *AllowedNamespaces `json:"allowedNamespaces"`
+	// ProxyCABundle is a secret reference whose ca.crt key holds the PEM encoded certificates of the certificate
+	// authorities the Azure clients trust in addition to the system ones, e.g. the certificate authority of an outbound
+	// proxy intercepting TLS.
+	// +optional
+	ProxyCABundle *corev1.SecretReference `json:"proxyCABundle,omitempty"`
 }
 
 // AzureClusterIdentityStatus defines the observed state of AzureClusterIdentity.
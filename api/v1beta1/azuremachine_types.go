@@ -71,6 +71,19 @@ type AzureMachineSpec struct {
 	// +optional
 	RoleAssignmentName string `json:"roleAssignmentName,omitempty"`
 
+	// RoleAssignmentPrincipalIDs are the IDs of additional principals, e.g. of user assigned identities, to assign the
+	// role of the system assigned identity to, each in a role assignment of its own. They can only be set when using a
+	// system assigned identity.
+	// +optional
+	RoleAssignmentPrincipalIDs []string `json:"roleAssignmentPrincipalIDs,omitempty"`
+
+	// RoleAssignmentScope is the ID of the resource, e.g. a subnet, the role of the system assigned identity is
+	// assigned at. If not specified, the role is assigned at the subscription. When the resource does not exist yet,
+	// e.g. because it is still being created by CAPZ, the role assignment is created once it does. It can only be set
+	// when using a system assigned identity.
+	// +optional
+	RoleAssignmentScope string `json:"roleAssignmentScope,omitempty"`
+
 	// OSDisk specifies the parameters for the operating system disk of the machine
 	OSDisk OSDisk `json:"osDisk"`
 
@@ -183,6 +196,11 @@ type AzureMachineStatus struct {
 	// next reconciliation loop.
 	// +optional
 	LongRunningOperationStates Futures `json:"longRunningOperationStates,omitempty"`
+
+	// RoleAssignmentCreateAttempts is the number of consecutive reconciles in which the role assignment of the
+	// AzureMachine could not be created because of an error that retrying is unlikely to fix.
+	// +optional
+	RoleAssignmentCreateAttempts int32 `json:"roleAssignmentCreateAttempts,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -19,6 +19,7 @@ package v1beta1
 import (
 	"encoding/base64"
 	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 
@@ -47,6 +48,14 @@ func ValidateAzureMachineSpec(spec AzureMachineSpec) field.ErrorList {
 		allErrs = append(allErrs, errs...)
 	}
 
+	if errs := ValidateRoleAssignmentPrincipalIDs(spec.Identity, spec.RoleAssignmentPrincipalIDs, field.NewPath("roleAssignmentPrincipalIDs")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateRoleAssignmentScope(spec.Identity, spec.RoleAssignmentScope, field.NewPath("roleAssignmentScope")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
 	if errs := ValidateUserAssignedIdentity(spec.Identity, spec.UserAssignedIdentities, field.NewPath("userAssignedIdentities")); len(errs) > 0 {
 		allErrs = append(allErrs, errs...)
 	}
@@ -94,6 +103,51 @@ func ValidateSystemAssignedIdentity(identityType VMIdentity, old, new string, fl
 	return allErrs
 }
 
+// ValidateRoleAssignmentPrincipalIDs validates the IDs of the additional principals to assign the role of the
+// system-assigned identity to.
+func ValidateRoleAssignmentPrincipalIDs(identityType VMIdentity, principalIDs []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(principalIDs) == 0 {
+		return allErrs
+	}
+
+	if identityType != VMIdentitySystemAssigned {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "Role assignment principal IDs should only be set when using system assigned identity."))
+	}
+
+	seen := make(map[string]bool, len(principalIDs))
+	for i, principalID := range principalIDs {
+		if _, err := uuid.Parse(principalID); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), principalID, "Principal ID must be a valid GUID."))
+		} else if seen[principalID] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i), principalID))
+		}
+		seen[principalID] = true
+	}
+
+	return allErrs
+}
+
+// ValidateRoleAssignmentScope validates the scope of the role assignment of the system-assigned identity.
+func ValidateRoleAssignmentScope(identityType VMIdentity, scope string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if scope == "" {
+		return allErrs
+	}
+
+	if identityType != VMIdentitySystemAssigned {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "Role assignment scope should only be set when using system assigned identity."))
+	}
+
+	if !strings.HasPrefix(strings.ToLower(scope), "/subscriptions/") {
+		allErrs = append(allErrs, field.Invalid(fldPath, scope, "Role assignment scope must be the ID of an Azure resource, starting with /subscriptions/."))
+	}
+
+	return allErrs
+}
+
 // ValidateUserAssignedIdentity validates the user-assigned identities list.
 func ValidateUserAssignedIdentity(identityType VMIdentity, userAssignedIdenteties []UserAssignedIdentity, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
@@ -456,6 +456,105 @@ func TestAzureMachine_ValidateSystemAssignedIdentity(t *testing.T) {
 	}
 }
 
+func TestAzureMachine_ValidateRoleAssignmentPrincipalIDs(t *testing.T) {
+	g := NewWithT(t)
+
+	principalID := uuid.New().String()
+	tests := []struct {
+		name         string
+		principalIDs []string
+		Identity     VMIdentity
+		wantErr      bool
+	}{
+		{
+			name:     "no principal IDs",
+			Identity: VMIdentityNone,
+			wantErr:  false,
+		},
+		{
+			name:         "valid principal IDs",
+			principalIDs: []string{uuid.New().String(), uuid.New().String()},
+			Identity:     VMIdentitySystemAssigned,
+			wantErr:      false,
+		},
+		{
+			name:         "wrong Identity type",
+			principalIDs: []string{uuid.New().String()},
+			Identity:     VMIdentityUserAssigned,
+			wantErr:      true,
+		},
+		{
+			name:         "not a valid UUID",
+			principalIDs: []string{"notaguid"},
+			Identity:     VMIdentitySystemAssigned,
+			wantErr:      true,
+		},
+		{
+			name:         "duplicate",
+			principalIDs: []string{principalID, principalID},
+			Identity:     VMIdentitySystemAssigned,
+			wantErr:      true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateRoleAssignmentPrincipalIDs(tc.Identity, tc.principalIDs, field.NewPath("roleAssignmentPrincipalIDs"))
+			if tc.wantErr {
+				g.Expect(err).ToNot(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateRoleAssignmentScope(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name     string
+		scope    string
+		Identity VMIdentity
+		wantErr  bool
+	}{
+		{
+			name:     "no scope",
+			Identity: VMIdentityNone,
+			wantErr:  false,
+		},
+		{
+			name:     "subnet scope",
+			scope:    "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet",
+			Identity: VMIdentitySystemAssigned,
+			wantErr:  false,
+		},
+		{
+			name:     "wrong Identity type",
+			scope:    "/subscriptions/123/resourceGroups/my-rg",
+			Identity: VMIdentityUserAssigned,
+			wantErr:  true,
+		},
+		{
+			name:     "not a resource ID",
+			scope:    "my-subnet",
+			Identity: VMIdentitySystemAssigned,
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateRoleAssignmentScope(tc.Identity, tc.scope, field.NewPath("roleAssignmentScope"))
+			if tc.wantErr {
+				g.Expect(err).ToNot(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
 func TestAzureMachine_ValidateDataDisksUpdate(t *testing.T) {
 	g := NewWithT(t)
 
@@ -87,6 +87,13 @@ func (m *AzureMachine) ValidateUpdate(oldRaw runtime.Object) error {
 		)
 	}
 
+	if !reflect.DeepEqual(m.Spec.RoleAssignmentScope, old.Spec.RoleAssignmentScope) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "roleAssignmentScope"),
+				m.Spec.RoleAssignmentScope, "field is immutable"),
+		)
+	}
+
 	if !reflect.DeepEqual(m.Spec.OSDisk, old.Spec.OSDisk) {
 		allErrs = append(allErrs,
 			field.Invalid(field.NewPath("spec", "osDisk"),
@@ -254,6 +254,34 @@ func TestAzureMachine_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "invalidTest: azuremachine.spec.RoleAssignmentScope is immutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					RoleAssignmentScope: "/subscriptions/123/resourceGroups/my-rg",
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					RoleAssignmentScope: "/subscriptions/123/resourceGroups/other-rg",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "validTest: azuremachine.spec.RoleAssignmentScope is immutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					RoleAssignmentScope: "/subscriptions/123/resourceGroups/my-rg",
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					RoleAssignmentScope: "/subscriptions/123/resourceGroups/my-rg",
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "invalidTest: azuremachine.spec.OSDisk is immutable",
 			oldMachine: &AzureMachine{
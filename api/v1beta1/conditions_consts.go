@@ -76,6 +76,42 @@ const (
 	ScaleSetModelOutOfDateReason = "ScaleSetModelOutOfDate"
 )
 
+// AzureManagedControlPlane Conditions and Reasons.
+const (
+	// CreateTimedOutCondition reports the managed cluster was not created within the create timeout of the control plane.
+	CreateTimedOutCondition clusterv1.ConditionType = "CreateTimedOut"
+	// CreateTimeoutExceededReason used when the managed cluster is still not provisioned after the create timeout.
+	CreateTimeoutExceededReason = "CreateTimeoutExceeded"
+	// EgressVerifiedCondition reports whether the egress check Job of a cluster with user-defined routing succeeded.
+	EgressVerifiedCondition clusterv1.ConditionType = "EgressVerified"
+	// EgressCheckRunningReason used while the egress check Job is running.
+	EgressCheckRunningReason = "EgressCheckRunning"
+	// EgressCheckFailedReason used when the egress check Job failed.
+	EgressCheckFailedReason = "EgressCheckFailed"
+	// ManagedClusterRunningCondition reports on the provisioning state of the managed cluster in Azure.
+	ManagedClusterRunningCondition clusterv1.ConditionType = "ManagedClusterRunning"
+	// ManagedClusterProvisioningReason used when the managed cluster is being created, updated or upgraded.
+	ManagedClusterProvisioningReason = "Provisioning"
+	// ManagedClusterFailedReason used when the provisioning of the managed cluster failed or was canceled.
+	ManagedClusterFailedReason = "Failed"
+	// AgentPoolsReadyCondition reports on the Ready conditions of the agent pools of the managed cluster.
+	AgentPoolsReadyCondition clusterv1.ConditionType = "AgentPoolsReady"
+	// VersionUpgradeAllowedCondition reports whether the managed cluster can be upgraded to the Kubernetes version of
+	// the control plane.
+	VersionUpgradeAllowedCondition clusterv1.ConditionType = "VersionUpgradeAllowed"
+	// MinorVersionSkippedReason used when the upgrade to the Kubernetes version of the control plane would skip a minor
+	// version, which AKS does not allow.
+	MinorVersionSkippedReason = "MinorVersionSkipped"
+)
+
+// AzureManagedMachinePool Conditions and Reasons.
+const (
+	// AgentPoolProvisioningReason used when the agent pool is being created, updated, scaled or upgraded.
+	AgentPoolProvisioningReason = "Provisioning"
+	// AgentPoolFailedReason used when the provisioning of the agent pool failed or was canceled.
+	AgentPoolFailedReason = "Failed"
+)
+
 // Azure Services Conditions and Reasons.
 const (
 	// ResourceGroupReadyCondition means the resource group exists and is ready to be used.
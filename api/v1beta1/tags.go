@@ -142,6 +142,17 @@ const (
 	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
 	// for annotation formatting rules.
 	RGTagsLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-tags-rg"
+
+	// NodeRGTagsLastAppliedAnnotation is the key for the Azure Managed Control Plane object annotation
+	// which tracks the AdditionalTags for the node Resource Group of the managed cluster.
+	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
+	NodeRGTagsLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-tags-node-rg"
+
+	// ManagedClusterTagsLastAppliedAnnotation is the key for the Azure Managed Control Plane object annotation
+	// which tracks the AdditionalTags of the managed cluster.
+	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
+	// for annotation formatting rules.
+	ManagedClusterTagsLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-tags-managed-cluster"
 )
 
 // SpecVersionHashTagKey is the key for the spec version hash used to enable quick spec difference comparison.
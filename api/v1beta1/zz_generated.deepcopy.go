@@ -180,6 +180,11 @@ func (in *AzureClusterIdentitySpec) DeepCopyInto(out *AzureClusterIdentitySpec)
 		*out = new(AllowedNamespaces)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ProxyCABundle != nil {
+		in, out := &in.ProxyCABundle, &out.ProxyCABundle
+		*out = new(v1.SecretReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureClusterIdentitySpec.
@@ -397,6 +402,11 @@ func (in *AzureMachineSpec) DeepCopyInto(out *AzureMachineSpec) {
 		*out = make([]UserAssignedIdentity, len(*in))
 		copy(*out, *in)
 	}
+	if in.RoleAssignmentPrincipalIDs != nil {
+		in, out := &in.RoleAssignmentPrincipalIDs, &out.RoleAssignmentPrincipalIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	in.OSDisk.DeepCopyInto(&out.OSDisk)
 	if in.DataDisks != nil {
 		in, out := &in.DataDisks, &out.DataDisks
@@ -58,6 +58,20 @@ func SDKToVMSS(sdkvmss compute.VirtualMachineScaleSet, sdkinstances []compute.Vi
 		vmss.Image = SDKImageToImage(imageRef, sdkvmss.Plan != nil)
 	}
 
+	if sdkvmss.VirtualMachineProfile != nil &&
+		sdkvmss.VirtualMachineProfile.ExtensionProfile != nil &&
+		sdkvmss.VirtualMachineProfile.ExtensionProfile.Extensions != nil {
+		for _, extension := range *sdkvmss.VirtualMachineProfile.ExtensionProfile.Extensions {
+			if extension.VirtualMachineScaleSetExtensionProperties == nil || to.String(extension.ForceUpdateTag) == "" {
+				continue
+			}
+			if vmss.ExtensionForceUpdateTags == nil {
+				vmss.ExtensionForceUpdateTags = map[string]string{}
+			}
+			vmss.ExtensionForceUpdateTags[to.String(extension.Name)] = to.String(extension.ForceUpdateTag)
+		}
+	}
+
 	return vmss
 }
 
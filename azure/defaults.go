@@ -19,6 +19,7 @@ package azure
 import (
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/Azure/go-autorest/autorest/azure"
 
@@ -27,6 +28,7 @@ import (
 	"github.com/pkg/errors"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/util/ratelimit"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	"sigs.k8s.io/cluster-api-provider-azure/version"
 )
@@ -78,6 +80,10 @@ const (
 	// bootstrapSentinelFile is the file written by bootstrap provider on machines to indicate successful bootstrapping,
 	// as defined by the Cluster API Bootstrap Provider contract (https://cluster-api.sigs.k8s.io/developer/providers/bootstrap.html).
 	bootstrapSentinelFile = "/run/cluster-api/bootstrap-success.complete"
+	// bootstrappingExtensionPublisher is the publisher of the CAPZ Bootstrapping extensions.
+	bootstrappingExtensionPublisher = "Microsoft.Azure.ContainerUpstream"
+	// bootstrappingExtensionVersion is the version of the CAPZ Bootstrapping extensions.
+	bootstrappingExtensionVersion = "1.0"
 )
 
 const (
@@ -192,6 +198,11 @@ func ResourceGroupID(subscriptionID, resourceGroup string) string {
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s", subscriptionID, resourceGroup)
 }
 
+// ManagedClusterID returns the azure resource ID for a given managed cluster.
+func ManagedClusterID(subscriptionID, resourceGroup, managedClusterName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ContainerService/managedClusters/%s", subscriptionID, resourceGroup, managedClusterName)
+}
+
 // VMID returns the azure resource ID for a given VM.
 func VMID(subscriptionID, resourceGroup, vmName string) string {
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/virtualMachines/%s", subscriptionID, resourceGroup, vmName)
@@ -343,44 +354,111 @@ func GetBootstrappingVMExtension(osType string, cloud string, vmName string) *Ex
 	// currently, the bootstrap extension is only available in AzurePublicCloud.
 	if osType == LinuxOS && cloud == azure.PublicCloud.Name {
 		// The command checks for the existence of the bootstrapSentinelFile on the machine, with retries and sleep between retries.
-		return &ExtensionSpec{
-			Name:      "CAPZ.Linux.Bootstrapping",
-			VMName:    vmName,
-			Publisher: "Microsoft.Azure.ContainerUpstream",
-			Version:   "1.0",
-			ProtectedSettings: map[string]string{
-				"commandToExecute": LinuxBootstrapExtensionCommand,
-			},
-		}
+		spec := NewCustomScriptExtensionSpec(osType, vmName, LinuxBootstrapExtensionCommand)
+		spec.Name = "CAPZ.Linux.Bootstrapping"
+		spec.Publisher = bootstrappingExtensionPublisher
+		spec.Version = bootstrappingExtensionVersion
+		return &spec
 	} else if osType == WindowsOS && cloud == azure.PublicCloud.Name {
 		// This command for the existence of the bootstrapSentinelFile on the machine, with retries and sleep between reties.
 		// If the file is not present after the retries are exhausted the extension fails with return code '-2' - ERROR_FILE_NOT_FOUND.
-		return &ExtensionSpec{
-			Name:      "CAPZ.Windows.Bootstrapping",
-			VMName:    vmName,
-			Publisher: "Microsoft.Azure.ContainerUpstream",
-			Version:   "1.0",
-			ProtectedSettings: map[string]string{
-				"commandToExecute": WindowsBootstrapExtensionCommand,
-			},
-		}
+		spec := NewCustomScriptExtensionSpec(osType, vmName, WindowsBootstrapExtensionCommand)
+		spec.Name = "CAPZ.Windows.Bootstrapping"
+		spec.Publisher = bootstrappingExtensionPublisher
+		spec.Version = bootstrappingExtensionVersion
+		return &spec
 	}
 
 	return nil
 }
 
+// NewCustomScriptExtensionSpec returns the spec of the Custom Script extension for the OS type, which runs the command
+// to execute on the VM.
+func NewCustomScriptExtensionSpec(osType string, vmName string, commandToExecute string) ExtensionSpec {
+	spec := ExtensionSpec{
+		Name:      "CustomScript",
+		VMName:    vmName,
+		Publisher: "Microsoft.Azure.Extensions",
+		Version:   "2.1",
+		OSType:    osType,
+		ProtectedSettings: map[string]string{
+			"commandToExecute": commandToExecute,
+		},
+	}
+	if osType == WindowsOS {
+		spec.Name = "CustomScriptExtension"
+		spec.Publisher = "Microsoft.Compute"
+		spec.Version = "1.10"
+	}
+	return spec
+}
+
+// NewAADSSHLoginForLinuxExtensionSpec returns the spec of the extension that allows signing in to a Linux VM with
+// Azure Active Directory credentials over SSH.
+func NewAADSSHLoginForLinuxExtensionSpec(vmName string) ExtensionSpec {
+	return ExtensionSpec{
+		Name:      "AADSSHLoginForLinux",
+		VMName:    vmName,
+		Publisher: "Microsoft.Azure.ActiveDirectory",
+		Version:   "1.0",
+		OSType:    LinuxOS,
+	}
+}
+
+// NewApplicationHealthExtensionSpec returns the spec of the Application Health extension for the OS type, which
+// reports the health of the VM by probing the port with the protocol, either tcp, http or https.
+func NewApplicationHealthExtensionSpec(osType string, vmName string, protocol string, port int32) ExtensionSpec {
+	spec := ExtensionSpec{
+		Name:      "ApplicationHealthLinux",
+		VMName:    vmName,
+		Publisher: "Microsoft.ManagedServices",
+		Version:   "1.0",
+		OSType:    osType,
+		Settings: map[string]interface{}{
+			"protocol": protocol,
+			"port":     port,
+		},
+	}
+	if osType == WindowsOS {
+		spec.Name = "ApplicationHealthWindows"
+	}
+	return spec
+}
+
 // UserAgent specifies a string to append to the agent identifier.
 func UserAgent() string {
 	return fmt.Sprintf("cluster-api-provider-azure/%s", version.Get().String())
 }
 
+// senderAuthorizer is an authorizer carrying the sender the autorest clients it authorizes should use.
+type senderAuthorizer struct {
+	autorest.Authorizer
+	sender autorest.Sender
+}
+
+// WithSender returns an authorizer making the autorest clients set up with SetAutoRestClientDefaults send their
+// requests through sender instead of their default sender. The authorizer is returned as is when sender is nil.
+func WithSender(auth autorest.Authorizer, sender autorest.Sender) autorest.Authorizer {
+	if sender == nil {
+		return auth
+	}
+	return senderAuthorizer{Authorizer: auth, sender: sender}
+}
+
 // SetAutoRestClientDefaults set authorizer and user agent for autorest client.
 func SetAutoRestClientDefaults(c *autorest.Client, auth autorest.Authorizer) {
+	if sa, ok := auth.(senderAuthorizer); ok {
+		auth = sa.Authorizer
+		c.Sender = sa.sender
+	}
 	c.Authorizer = auth
 	// Wrap the original Sender on the autorest.Client c.
 	// The wrapped Sender should set the x-ms-correlation-request-id on the given
 	// request, then pass the new request to the underlying Sender.
 	c.Sender = autorest.DecorateSender(c.Sender, msCorrelationIDSendDecorator)
+	// Wrap the Sender once more so that ARM writes wait on the rate limiter shared by all the clients issuing requests
+	// in the same subscription, before ARM throttles them.
+	c.Sender = autorest.DecorateSender(c.Sender, writeRateLimitSendDecorator(ratelimit.Writes()))
 	// The default number of retries is 3. This means the client will attempt to retry operation results like resource
 	// conflicts (HTTP 409). For a reconciling controller, this is undesirable behavior since if the controller runs
 	// into an error reconciling, the controller would be better off to end with an error and try again later.
@@ -396,6 +474,34 @@ func AutoRestClientAppendUserAgent(c *autorest.Client, extension string) {
 	_ = c.AddToUserAgent(extension) // intentionally ignore error as it doesn't matter
 }
 
+// writeRateLimitSendDecorator returns a decorator that waits on the rate limiter of the subscription of the request
+// before sending ARM writes. Reads and requests outside of a subscription are sent right away.
+func writeRateLimitSendDecorator(limiters *ratelimit.Limiters) autorest.SendDecorator {
+	return func(snd autorest.Sender) autorest.Sender {
+		return autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+			switch r.Method {
+			case http.MethodPut, http.MethodPatch, http.MethodPost, http.MethodDelete:
+				if subscriptionID := subscriptionIDFromPath(r.URL.Path); subscriptionID != "" {
+					if err := limiters.ForSubscription(subscriptionID).Wait(r.Context()); err != nil {
+						return nil, errors.Wrapf(err, "failed waiting on the rate limiter of subscription %s", subscriptionID)
+					}
+				}
+			}
+			return snd.Do(r)
+		})
+	}
+}
+
+// subscriptionIDFromPath returns the subscription ID of an ARM request path, or an empty string if the request is
+// not scoped to a subscription.
+func subscriptionIDFromPath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) < 2 || !strings.EqualFold(segments[0], "subscriptions") {
+		return ""
+	}
+	return strings.ToLower(segments[1])
+}
+
 func msCorrelationIDSendDecorator(snd autorest.Sender) autorest.Sender {
 	return autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
 		// if the correlation ID was found in the request context, set
@@ -24,8 +24,12 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/profiles/2019-03-01/authorization/mgmt/authorization"
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
 	. "github.com/onsi/gomega"
+	"sigs.k8s.io/cluster-api-provider-azure/util/ratelimit"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
@@ -241,6 +245,71 @@ func TestGetDefaultUbuntuImage(t *testing.T) {
 	}
 }
 
+func TestExtensionSpecConstructors(t *testing.T) {
+	tests := []struct {
+		name              string
+		spec              ExtensionSpec
+		expectedPublisher string
+		expectedType      string
+		expectedVersion   string
+		expectedOSType    string
+	}{
+		{
+			name:              "Linux custom script",
+			spec:              NewCustomScriptExtensionSpec(LinuxOS, "my-vm", "echo hello"),
+			expectedPublisher: "Microsoft.Azure.Extensions",
+			expectedType:      "CustomScript",
+			expectedVersion:   "2.1",
+			expectedOSType:    LinuxOS,
+		},
+		{
+			name:              "Windows custom script",
+			spec:              NewCustomScriptExtensionSpec(WindowsOS, "my-vm", "echo hello"),
+			expectedPublisher: "Microsoft.Compute",
+			expectedType:      "CustomScriptExtension",
+			expectedVersion:   "1.10",
+			expectedOSType:    WindowsOS,
+		},
+		{
+			name:              "AAD SSH login for Linux",
+			spec:              NewAADSSHLoginForLinuxExtensionSpec("my-vm"),
+			expectedPublisher: "Microsoft.Azure.ActiveDirectory",
+			expectedType:      "AADSSHLoginForLinux",
+			expectedVersion:   "1.0",
+			expectedOSType:    LinuxOS,
+		},
+		{
+			name:              "Linux application health",
+			spec:              NewApplicationHealthExtensionSpec(LinuxOS, "my-vm", "tcp", 10250),
+			expectedPublisher: "Microsoft.ManagedServices",
+			expectedType:      "ApplicationHealthLinux",
+			expectedVersion:   "1.0",
+			expectedOSType:    LinuxOS,
+		},
+		{
+			name:              "Windows application health",
+			spec:              NewApplicationHealthExtensionSpec(WindowsOS, "my-vm", "tcp", 10250),
+			expectedPublisher: "Microsoft.ManagedServices",
+			expectedType:      "ApplicationHealthWindows",
+			expectedVersion:   "1.0",
+			expectedOSType:    WindowsOS,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			g.Expect(tc.spec.VMName).To(Equal("my-vm"))
+			g.Expect(tc.spec.Publisher).To(Equal(tc.expectedPublisher))
+			g.Expect(tc.spec.Name).To(Equal(tc.expectedType))
+			g.Expect(tc.spec.Version).To(Equal(tc.expectedVersion))
+			g.Expect(tc.spec.OSType).To(Equal(tc.expectedOSType))
+		})
+	}
+}
+
 func TestMSCorrelationIDSendDecorator(t *testing.T) {
 	g := NewWithT(t)
 	const corrID tele.CorrID = "TestMSCorrelationIDSendDecoratorCorrID"
@@ -288,3 +357,71 @@ func TestMSCorrelationIDSendDecorator(t *testing.T) {
 		receivedReq.Header.Get(string(tele.CorrIDKeyVal)),
 	).To(Equal(string(corrID)))
 }
+
+func TestWriteRateLimitSharedBySubscription(t *testing.T) {
+	g := NewWithT(t)
+
+	// Allow two writes per subscription, and practically no refill during the test.
+	limiters := ratelimit.New(0.001, 2)
+	previous := ratelimit.Writes()
+	ratelimit.SetWrites(limiters)
+	defer ratelimit.SetWrites(previous)
+
+	testSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer testSrv.Close()
+
+	// The clients of two services, such as role assignments and VM extensions, in the same subscription.
+	roleAssignmentsClient := authorization.NewRoleAssignmentsClientWithBaseURI(testSrv.URL, "123")
+	SetAutoRestClientDefaults(&roleAssignmentsClient.Client, autorest.NullAuthorizer{})
+	vmExtensionsClient := compute.NewVirtualMachineExtensionsClientWithBaseURI(testSrv.URL, "123")
+	SetAutoRestClientDefaults(&vmExtensionsClient.Client, autorest.NullAuthorizer{})
+
+	_, err := roleAssignmentsClient.Get(context.TODO(), "/subscriptions/123/resourceGroups/my-rg", "my-role-assignment")
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = roleAssignmentsClient.Create(context.TODO(), "/subscriptions/123/resourceGroups/my-rg", "my-role-assignment", authorization.RoleAssignmentCreateParameters{
+		Properties: &authorization.RoleAssignmentProperties{
+			RoleDefinitionID: to.StringPtr("/subscriptions/123/providers/Microsoft.Authorization/roleDefinitions/456"),
+			PrincipalID:      to.StringPtr("789"),
+		},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = vmExtensionsClient.CreateOrUpdate(context.TODO(), "my-rg", "my-vm", "my-extension", compute.VirtualMachineExtension{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// Both writes took a token from the limiter of the subscription, while the read did not.
+	g.Expect(limiters.ForSubscription("123").TryAccept()).To(BeFalse())
+	g.Expect(limiters.ForSubscription("456").TryAccept()).To(BeTrue())
+}
+
+func TestSubscriptionIDFromPath(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(subscriptionIDFromPath("/subscriptions/123/resourceGroups/my-rg")).To(Equal("123"))
+	g.Expect(subscriptionIDFromPath("/Subscriptions/ABC/providers/Microsoft.Compute/skus")).To(Equal("abc"))
+	g.Expect(subscriptionIDFromPath("/providers/Microsoft.Authorization/roleDefinitions")).To(BeEmpty())
+}
+
+func TestWithSender(t *testing.T) {
+	g := NewWithT(t)
+
+	auth := autorest.NullAuthorizer{}
+	g.Expect(WithSender(auth, nil)).To(Equal(auth))
+
+	var sent int
+	sender := autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+		sent++
+		return &http.Response{StatusCode: http.StatusOK, Request: r}, nil
+	})
+	client := autorest.NewClientWithUserAgent("")
+	SetAutoRestClientDefaults(&client, WithSender(auth, sender))
+	g.Expect(client.Authorizer).To(Equal(auth))
+
+	req, err := http.NewRequest(http.MethodGet, "https://management.azure.com/subscriptions/123", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = client.Send(req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(sent).To(Equal(1))
+}
@@ -51,6 +51,45 @@ func ResourceConflict(err error) bool {
 	return errors.As(err, &derr) && derr.StatusCode == 409
 }
 
+const (
+	requestIDHeader            = "x-ms-request-id"
+	correlationRequestIDHeader = "x-ms-correlation-request-id"
+)
+
+// RequestIDsError annotates an error returned by an Azure API call with the request and correlation IDs of the
+// response, which Azure support needs to investigate the failure.
+type RequestIDsError struct {
+	error
+	RequestID            string
+	CorrelationRequestID string
+}
+
+// Error returns the error string, followed by the request and correlation IDs.
+func (e RequestIDsError) Error() string {
+	return fmt.Sprintf("%s (request ID: %s, correlation ID: %s)", e.error.Error(), e.RequestID, e.CorrelationRequestID)
+}
+
+// Unwrap returns the underlying error.
+func (e RequestIDsError) Unwrap() error {
+	return e.error
+}
+
+// WithRequestIDs annotates the error with the request and correlation IDs of the Azure response it was built from.
+// The error is returned unchanged if it is nil, already annotated, or carries no response with those IDs.
+func WithRequestIDs(err error) error {
+	derr := autorest.DetailedError{}
+	if !errors.As(err, &derr) || derr.Response == nil || errors.As(err, &RequestIDsError{}) {
+		return err
+	}
+
+	requestID := derr.Response.Header.Get(requestIDHeader)
+	correlationRequestID := derr.Response.Header.Get(correlationRequestIDHeader)
+	if requestID == "" && correlationRequestID == "" {
+		return err
+	}
+	return RequestIDsError{error: err, RequestID: requestID, CorrelationRequestID: correlationRequestID}
+}
+
 // VMDeletedError is returned when a virtual machine is deleted outside of capz.
 type VMDeletedError struct {
 	ProviderID string
@@ -19,14 +19,22 @@ package scope
 import (
 	"context"
 	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/base64"
 	"fmt"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/adal"
 	"github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/go-autorest/autorest/azure/auth"
+	"golang.org/x/net/http/httpproxy"
 )
 
 // AzureClients contains all the Azure clients used by the scopes.
@@ -36,6 +44,10 @@ type AzureClients struct {
 	Authorizer                 autorest.Authorizer
 	ResourceManagerEndpoint    string
 	ResourceManagerVMDNSSuffix string
+
+	// Sender overrides the sender of the Azure clients created from the scope, e.g. to stub Azure API responses in
+	// tests. The default sender is used when nil.
+	Sender autorest.Sender
 }
 
 // CloudEnvironment returns the Azure environment the controller runs in.
@@ -130,7 +142,63 @@ func (c *AzureClients) setCredentialsWithProvider(ctx context.Context, subscript
 	c.Values[auth.ClientSecret] = strings.TrimSuffix(clientSecret, "\n")
 
 	c.Authorizer, err = credentialsProvider.GetAuthorizer(ctx, c.ResourceManagerEndpoint, c.Environment.ActiveDirectoryEndpoint)
-	return err
+	if err != nil {
+		return err
+	}
+
+	caBundle, err := credentialsProvider.GetProxyCABundle(ctx)
+	if err != nil {
+		return err
+	}
+	if caBundle == nil || c.Sender != nil {
+		return nil
+	}
+	c.Sender, err = newProxySender(caBundle)
+	if err != nil {
+		return err
+	}
+	// Token requests go through the sender as well, as they are subject to the same proxy as the Azure API requests.
+	if bearer, ok := c.Authorizer.(*autorest.BearerAuthorizer); ok {
+		if spt, ok := bearer.TokenProvider().(*adal.ServicePrincipalToken); ok {
+			spt.SetSender(c.Sender)
+		}
+	}
+	return nil
+}
+
+// newProxySender returns a sender whose transport sends requests through the proxy configured by the HTTP_PROXY,
+// HTTPS_PROXY and NO_PROXY environment variables, and trusts the certificate authorities of caBundle in addition to
+// the system ones.
+func newProxySender(caBundle []byte) (autorest.Sender, error) {
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil {
+		rootCAs = x509.NewCertPool()
+	}
+	if !rootCAs.AppendCertsFromPEM(caBundle) {
+		return nil, fmt.Errorf("proxy CA bundle contains no PEM encoded certificate")
+	}
+
+	proxy := httpproxy.FromEnvironment().ProxyFunc()
+	// The transport settings are copied from http.DefaultTransport, with the TLS minimum version of the autorest clients.
+	transport := &http.Transport{
+		Proxy: func(req *http.Request) (*url.URL, error) {
+			return proxy(req.URL)
+		},
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig: &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			RootCAs:    rootCAs,
+		},
+	}
+	return &http.Client{Transport: transport}, nil
 }
 
 func (c *AzureClients) getSettingsFromEnvironment(environmentName string) (s auth.EnvironmentSettings, err error) {
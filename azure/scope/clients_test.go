@@ -17,6 +17,11 @@ limitations under the License.
 package scope
 
 import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"testing"
 
 	"github.com/Azure/go-autorest/autorest"
@@ -83,3 +88,96 @@ func TestGettingEnvironment(t *testing.T) {
 		})
 	}
 }
+
+func TestNewProxySender(t *testing.T) {
+	g := NewWithT(t)
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	setEnv(t, "HTTPS_PROXY", "http://proxy.example.com:3128")
+	setEnv(t, "NO_PROXY", "")
+	sender, err := newProxySender(caBundle)
+	g.Expect(err).NotTo(HaveOccurred())
+	transport := sender.(*http.Client).Transport.(*http.Transport)
+
+	// Requests to Azure go through the proxy of the environment.
+	req, err := http.NewRequest(http.MethodGet, "https://management.azure.com/subscriptions", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	proxyURL, err := transport.Proxy(req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(proxyURL).NotTo(BeNil())
+	g.Expect(proxyURL.String()).To(Equal("http://proxy.example.com:3128"))
+
+	// The certificate authorities of the CA bundle are trusted. Requests to localhost are never proxied.
+	req, err = http.NewRequest(http.MethodGet, server.URL, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	resp, err := sender.Do(req)
+	g.Expect(err).NotTo(HaveOccurred())
+	defer resp.Body.Close()
+	g.Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+	_, err = newProxySender([]byte("not a certificate"))
+	g.Expect(err).To(MatchError("proxy CA bundle contains no PEM encoded certificate"))
+}
+
+// setEnv sets an environment variable for the duration of the test.
+func setEnv(t *testing.T, key, value string) {
+	t.Helper()
+	previous, ok := os.LookupEnv(key)
+	if err := os.Setenv(key, value); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if ok {
+			_ = os.Setenv(key, previous)
+		} else {
+			_ = os.Unsetenv(key)
+		}
+	})
+}
+
+// fakeCredentialsProvider is a CredentialsProvider returning fixed credentials and proxy CA bundle.
+type fakeCredentialsProvider struct {
+	caBundle []byte
+}
+
+func (p fakeCredentialsProvider) GetAuthorizer(_ context.Context, _, _ string) (autorest.Authorizer, error) {
+	return autorest.NullAuthorizer{}, nil
+}
+
+func (p fakeCredentialsProvider) GetClientID() string {
+	return "client-id"
+}
+
+func (p fakeCredentialsProvider) GetClientSecret(_ context.Context) (string, error) {
+	return "secret", nil
+}
+
+func (p fakeCredentialsProvider) GetTenantID() string {
+	return "tenant-id"
+}
+
+func (p fakeCredentialsProvider) GetProxyCABundle(_ context.Context) ([]byte, error) {
+	return p.caBundle, nil
+}
+
+func TestSetCredentialsWithProviderProxyCABundle(t *testing.T) {
+	g := NewWithT(t)
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer server.Close()
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	// The default sender is kept when no proxy CA bundle is referenced.
+	c := AzureClients{}
+	g.Expect(c.setCredentialsWithProvider(context.TODO(), "1234", "", fakeCredentialsProvider{})).To(Succeed())
+	g.Expect(c.Sender).To(BeNil())
+
+	c = AzureClients{}
+	g.Expect(c.setCredentialsWithProvider(context.TODO(), "1234", "", fakeCredentialsProvider{caBundle: caBundle})).To(Succeed())
+	g.Expect(c.Sender).NotTo(BeNil())
+}
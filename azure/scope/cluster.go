@@ -68,6 +68,7 @@ func NewClusterScope(ctx context.Context, params ClusterScopeParams) (*ClusterSc
 	if params.Logger == nil {
 		params.Logger = klogr.New()
 	}
+	params.Logger = newRedactingLogger(params.Logger)
 
 	if params.AzureCluster.Spec.IdentityRef == nil {
 		err := params.AzureClients.setCredentials(params.AzureCluster.Spec.SubscriptionID, params.AzureCluster.Spec.AzureEnvironment)
@@ -118,7 +119,7 @@ func (s *ClusterScope) BaseURI() string {
 
 // Authorizer returns the Azure client Authorizer.
 func (s *ClusterScope) Authorizer() autorest.Authorizer {
-	return s.AzureClients.Authorizer
+	return azure.WithSender(s.AzureClients.Authorizer, s.AzureClients.Sender)
 }
 
 // PublicIPSpecs returns the public IP specs.
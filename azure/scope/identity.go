@@ -40,7 +40,12 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-const azureSecretKey = "clientSecret"
+const (
+	azureSecretKey = "clientSecret"
+
+	// proxyCABundleKey is the key of the proxy CA bundle secret holding the PEM encoded certificates.
+	proxyCABundleKey = "ca.crt"
+)
 
 // CredentialsProvider defines the behavior for azure identity based credential providers.
 type CredentialsProvider interface {
@@ -48,6 +53,7 @@ type CredentialsProvider interface {
 	GetClientID() string
 	GetClientSecret(ctx context.Context) (string, error)
 	GetTenantID() string
+	GetProxyCABundle(ctx context.Context) ([]byte, error)
 }
 
 // AzureCredentialsProvider represents a credential provider with azure cluster identity.
@@ -212,6 +218,28 @@ func (p *AzureCredentialsProvider) GetTenantID() string {
 	return p.Identity.Spec.TenantID
 }
 
+// GetProxyCABundle returns the PEM encoded certificates of the proxy CA bundle referenced by the AzureCredentialsProvider's
+// Identity, or nil if the Identity references no proxy CA bundle.
+func (p *AzureCredentialsProvider) GetProxyCABundle(ctx context.Context) ([]byte, error) {
+	secretRef := p.Identity.Spec.ProxyCABundle
+	if secretRef == nil {
+		return nil, nil
+	}
+	key := types.NamespacedName{
+		Namespace: secretRef.Namespace,
+		Name:      secretRef.Name,
+	}
+	secret := &corev1.Secret{}
+	if err := p.Client.Get(ctx, key, secret); err != nil {
+		return nil, errors.Wrap(err, "Unable to fetch ProxyCABundle")
+	}
+	caBundle, ok := secret.Data[proxyCABundleKey]
+	if !ok {
+		return nil, errors.Errorf("ProxyCABundle secret %s/%s has no %s key", key.Namespace, key.Name, proxyCABundleKey)
+	}
+	return caBundle, nil
+}
+
 func createAzureIdentityWithBindings(ctx context.Context, azureIdentity *infrav1.AzureClusterIdentity, clusterMeta metav1.ObjectMeta,
 	kubeClient client.Client) error {
 	azureIdentityType, err := getAzureIdentityType(azureIdentity)
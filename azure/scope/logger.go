@@ -0,0 +1,93 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"strings"
+
+	"github.com/go-logr/logr"
+)
+
+// redactedValue replaces the value of sensitive keys in log output.
+const redactedValue = "<redacted>"
+
+// sensitiveLogKeys are the lowercased log keys whose values are never written to the logs.
+var sensitiveLogKeys = map[string]struct{}{
+	"clientsecret": {},
+	"password":     {},
+	"token":        {},
+}
+
+// redactingLogger is a logr.Logger that masks the values of sensitive keys before
+// passing them to the underlying logger.
+type redactingLogger struct {
+	logger logr.Logger
+}
+
+// newRedactingLogger wraps the logger so that the values of sensitive keys are masked.
+func newRedactingLogger(logger logr.Logger) logr.Logger {
+	if _, ok := logger.(redactingLogger); ok {
+		return logger
+	}
+	return redactingLogger{logger: logger}
+}
+
+// Enabled implements logr.Logger.
+func (l redactingLogger) Enabled() bool {
+	return l.logger.Enabled()
+}
+
+// Info implements logr.Logger.
+func (l redactingLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.logger.Info(msg, redactKeysAndValues(keysAndValues)...)
+}
+
+// Error implements logr.Logger.
+func (l redactingLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	l.logger.Error(err, msg, redactKeysAndValues(keysAndValues)...)
+}
+
+// V implements logr.Logger.
+func (l redactingLogger) V(level int) logr.Logger {
+	return redactingLogger{logger: l.logger.V(level)}
+}
+
+// WithValues implements logr.Logger.
+func (l redactingLogger) WithValues(keysAndValues ...interface{}) logr.Logger {
+	return redactingLogger{logger: l.logger.WithValues(redactKeysAndValues(keysAndValues)...)}
+}
+
+// WithName implements logr.Logger.
+func (l redactingLogger) WithName(name string) logr.Logger {
+	return redactingLogger{logger: l.logger.WithName(name)}
+}
+
+// redactKeysAndValues returns a copy of keysAndValues with the values of sensitive keys masked.
+func redactKeysAndValues(keysAndValues []interface{}) []interface{} {
+	redacted := make([]interface{}, len(keysAndValues))
+	copy(redacted, keysAndValues)
+	for i := 0; i+1 < len(redacted); i += 2 {
+		key, ok := redacted[i].(string)
+		if !ok {
+			continue
+		}
+		if _, sensitive := sensitiveLogKeys[strings.ToLower(key)]; sensitive {
+			redacted[i+1] = redactedValue
+		}
+	}
+	return redacted
+}
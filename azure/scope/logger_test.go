@@ -0,0 +1,102 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+)
+
+// recordingLogger is a logr.Logger that records the keys and values it is given.
+type recordingLogger struct {
+	values *[]interface{}
+}
+
+func (l recordingLogger) Enabled() bool { return true }
+
+func (l recordingLogger) Info(_ string, keysAndValues ...interface{}) {
+	*l.values = append(*l.values, keysAndValues...)
+}
+
+func (l recordingLogger) Error(_ error, _ string, keysAndValues ...interface{}) {
+	*l.values = append(*l.values, keysAndValues...)
+}
+
+func (l recordingLogger) V(_ int) logr.Logger { return l }
+
+func (l recordingLogger) WithValues(keysAndValues ...interface{}) logr.Logger {
+	*l.values = append(*l.values, keysAndValues...)
+	return l
+}
+
+func (l recordingLogger) WithName(_ string) logr.Logger { return l }
+
+func TestRedactingLogger(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(logger logr.Logger)
+		want []interface{}
+	}{
+		{
+			name: "Info masks sensitive keys",
+			log: func(logger logr.Logger) {
+				logger.Info("creating role assignment", "clientSecret", "hunter2", "name", "role")
+			},
+			want: []interface{}{"clientSecret", redactedValue, "name", "role"},
+		},
+		{
+			name: "Error masks sensitive keys regardless of case",
+			log: func(logger logr.Logger) {
+				logger.Error(errors.New("boom"), "failed to create vmss extension", "Password", "hunter2", "TOKEN", "abc")
+			},
+			want: []interface{}{"Password", redactedValue, "TOKEN", redactedValue},
+		},
+		{
+			name: "WithValues and V mask sensitive keys",
+			log: func(logger logr.Logger) {
+				logger.WithValues("token", "abc").V(2).Info("getting vmss extension", "extension", "ext")
+			},
+			want: []interface{}{"token", redactedValue, "extension", "ext"},
+		},
+		{
+			name: "values without a key are left untouched",
+			log: func(logger logr.Logger) {
+				logger.Info("odd number of values", "name", "role", "password")
+			},
+			want: []interface{}{"name", "role", "password"},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			var values []interface{}
+			tc.log(newRedactingLogger(recordingLogger{values: &values}))
+			g.Expect(values).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestNewRedactingLoggerDoesNotWrapTwice(t *testing.T) {
+	g := NewWithT(t)
+	var values []interface{}
+	logger := newRedactingLogger(newRedactingLogger(recordingLogger{values: &values}))
+	g.Expect(logger.(redactingLogger).logger).To(BeAssignableToTypeOf(recordingLogger{}))
+}
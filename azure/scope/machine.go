@@ -45,6 +45,10 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
+// maxBootstrapStatusMessageLength is the maximum length of the extension status message kept in the BootstrapSucceeded
+// condition, so that the unbounded output of a failed extension cannot grow the object beyond what the API server accepts.
+const maxBootstrapStatusMessageLength = 1024
+
 // MachineScopeParams defines the input parameters used to create a new MachineScope.
 type MachineScopeParams struct {
 	Client       client.Client
@@ -70,6 +74,7 @@ func NewMachineScope(params MachineScopeParams) (*MachineScope, error) {
 	if params.Logger == nil {
 		params.Logger = klogr.New()
 	}
+	params.Logger = newRedactingLogger(params.Logger)
 
 	helper, err := patch.NewHelper(params.AzureMachine, params.Client)
 	if err != nil {
@@ -266,16 +271,43 @@ func (m *MachineScope) DiskSpecs() []azure.DiskSpec {
 
 // RoleAssignmentSpecs returns the role assignment specs.
 func (m *MachineScope) RoleAssignmentSpecs() []azure.RoleAssignmentSpec {
-	if m.AzureMachine.Spec.Identity == infrav1.VMIdentitySystemAssigned {
-		return []azure.RoleAssignmentSpec{
-			{
-				MachineName:  m.Name(),
-				Name:         m.AzureMachine.Spec.RoleAssignmentName,
-				ResourceType: azure.VirtualMachine,
-			},
-		}
+	if m.AzureMachine.Spec.Identity != infrav1.VMIdentitySystemAssigned {
+		return []azure.RoleAssignmentSpec{}
+	}
+
+	roles := []azure.RoleAssignmentSpec{
+		{
+			MachineName:  m.Name(),
+			Name:         m.AzureMachine.Spec.RoleAssignmentName,
+			ResourceType: azure.VirtualMachine,
+			Scope:        m.AzureMachine.Spec.RoleAssignmentScope,
+		},
+	}
+	if len(m.AzureMachine.Spec.RoleAssignmentPrincipalIDs) > 0 {
+		roles = append(roles, azure.RoleAssignmentSpec{
+			Name:         m.AzureMachine.Spec.RoleAssignmentName,
+			PrincipalIDs: m.AzureMachine.Spec.RoleAssignmentPrincipalIDs,
+			Scope:        m.AzureMachine.Spec.RoleAssignmentScope,
+		})
 	}
-	return []azure.RoleAssignmentSpec{}
+	return roles
+}
+
+// IsRoleAssignmentReady returns true if the role assignments of the AzureMachine have propagated.
+func (m *MachineScope) IsRoleAssignmentReady() bool {
+	return conditions.IsTrue(m.AzureMachine, infrav1.RoleAssignmentReadyCondition)
+}
+
+// RoleAssignmentCreateAttempts returns the number of consecutive reconciles in which the role assignment of the AzureMachine
+// could not be created.
+func (m *MachineScope) RoleAssignmentCreateAttempts() int32 {
+	return m.AzureMachine.Status.RoleAssignmentCreateAttempts
+}
+
+// SetRoleAssignmentCreateAttempts sets the number of consecutive reconciles in which the role assignment of the
+// AzureMachine could not be created.
+func (m *MachineScope) SetRoleAssignmentCreateAttempts(attempts int32) {
+	m.AzureMachine.Status.RoleAssignmentCreateAttempts = attempts
 }
 
 // VMExtensionSpecs returns the vm extension specs.
@@ -437,7 +469,8 @@ func (m *MachineScope) SetFailureReason(v capierrors.MachineStatusError) {
 }
 
 // SetBootstrapConditions sets the AzureMachine BootstrapSucceeded condition based on the extension provisioning states.
-func (m *MachineScope) SetBootstrapConditions(provisioningState string, extensionName string) error {
+// The status message describes why a failed extension failed and is used as the condition message.
+func (m *MachineScope) SetBootstrapConditions(provisioningState string, extensionName string, statusMessage string) error {
 	switch infrav1.ProvisioningState(provisioningState) {
 	case infrav1.Succeeded:
 		m.V(4).Info("extension provisioning state is succeeded", "vm extension", extensionName, "virtual machine", m.Name())
@@ -449,13 +482,24 @@ func (m *MachineScope) SetBootstrapConditions(provisioningState string, extensio
 		return azure.WithTransientError(errors.New("extension is still in provisioning state. This likely means that bootstrapping has not yet completed on the VM"), 30*time.Second)
 	case infrav1.Failed:
 		m.V(4).Info("extension provisioning state is failed", "vm extension", extensionName, "virtual machine", m.Name())
-		conditions.MarkFalse(m.AzureMachine, infrav1.BootstrapSucceededCondition, infrav1.BootstrapFailedReason, clusterv1.ConditionSeverityError, "")
+		conditions.MarkFalse(m.AzureMachine, infrav1.BootstrapSucceededCondition, infrav1.BootstrapFailedReason, clusterv1.ConditionSeverityError, "%s", truncateBootstrapStatusMessage(statusMessage))
 		return azure.WithTerminalError(errors.New("extension state failed. This likely means the Kubernetes node bootstrapping process failed or timed out. Check VM boot diagnostics logs to learn more"))
 	default:
 		return nil
 	}
 }
 
+// truncateBootstrapStatusMessage keeps the end of an extension status message that is longer than
+// maxBootstrapStatusMessageLength, as the end of the output usually holds the error that failed the extension.
+func truncateBootstrapStatusMessage(message string) string {
+	const ellipsis = "..."
+	runes := []rune(message)
+	if len(runes) <= maxBootstrapStatusMessageLength {
+		return message
+	}
+	return ellipsis + string(runes[len(runes)-maxBootstrapStatusMessageLength+len(ellipsis):])
+}
+
 // SetAnnotation sets a key value annotation on the AzureMachine.
 func (m *MachineScope) SetAnnotation(key, value string) {
 	if m.AzureMachine.Annotations == nil {
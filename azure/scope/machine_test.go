@@ -380,6 +380,63 @@ func TestMachineScope_RoleAssignmentSpecs(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "returns a RoleAssignmentSpec for the additional principals",
+			machineScope: MachineScope{
+				Machine: &clusterv1.Machine{},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						Identity:                   infrav1.VMIdentitySystemAssigned,
+						RoleAssignmentName:         "azure-role-assignment-name",
+						RoleAssignmentPrincipalIDs: []string{"principal-1", "principal-2"},
+					},
+				},
+			},
+			want: []azure.RoleAssignmentSpec{
+				{
+					MachineName:  "machine-name",
+					Name:         "azure-role-assignment-name",
+					ResourceType: azure.VirtualMachine,
+				},
+				{
+					Name:         "azure-role-assignment-name",
+					PrincipalIDs: []string{"principal-1", "principal-2"},
+				},
+			},
+		},
+		{
+			name: "returns RoleAssignmentSpecs at the role assignment scope",
+			machineScope: MachineScope{
+				Machine: &clusterv1.Machine{},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						Identity:                   infrav1.VMIdentitySystemAssigned,
+						RoleAssignmentName:         "azure-role-assignment-name",
+						RoleAssignmentPrincipalIDs: []string{"principal-1"},
+						RoleAssignmentScope:        "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet",
+					},
+				},
+			},
+			want: []azure.RoleAssignmentSpec{
+				{
+					MachineName:  "machine-name",
+					Name:         "azure-role-assignment-name",
+					ResourceType: azure.VirtualMachine,
+					Scope:        "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet",
+				},
+				{
+					Name:         "azure-role-assignment-name",
+					PrincipalIDs: []string{"principal-1"},
+					Scope:        "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet",
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -429,6 +486,7 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 					ProtectedSettings: map[string]string{
 						"commandToExecute": azure.LinuxBootstrapExtensionCommand,
 					},
+					OSType: azure.LinuxOS,
 				},
 			},
 		},
@@ -491,6 +549,7 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 					ProtectedSettings: map[string]string{
 						"commandToExecute": azure.WindowsBootstrapExtensionCommand,
 					},
+					OSType: azure.WindowsOS,
 				},
 			},
 		},
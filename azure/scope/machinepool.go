@@ -19,6 +19,7 @@ package scope
 import (
 	"context"
 	"encoding/base64"
+	"sort"
 	"strings"
 	"time"
 
@@ -29,6 +30,7 @@ import (
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2/klogr"
 	"k8s.io/utils/pointer"
@@ -98,6 +100,7 @@ func NewMachinePoolScope(params MachinePoolScopeParams) (*MachinePoolScope, erro
 	if params.Logger == nil {
 		params.Logger = klogr.New()
 	}
+	params.Logger = newRedactingLogger(params.Logger)
 
 	helper, err := patch.NewHelper(params.AzureMachinePool, params.Client)
 	if err != nil {
@@ -456,7 +459,8 @@ func (m *MachinePoolScope) SetFailureReason(v capierrors.MachineStatusError) {
 }
 
 // SetBootstrapConditions sets the AzureMachinePool BootstrapSucceeded condition based on the extension provisioning states.
-func (m *MachinePoolScope) SetBootstrapConditions(provisioningState string, extensionName string) error {
+// The status message describes why a failed extension failed and is used as the condition message.
+func (m *MachinePoolScope) SetBootstrapConditions(provisioningState string, extensionName string, statusMessage string) error {
 	switch infrav1.ProvisioningState(provisioningState) {
 	case infrav1.Succeeded:
 		m.V(4).Info("extension provisioning state is succeeded", "vm extension", extensionName, "scale set", m.Name())
@@ -468,13 +472,23 @@ func (m *MachinePoolScope) SetBootstrapConditions(provisioningState string, exte
 		return azure.WithTransientError(errors.New("extension is still in provisioning state. This likely means that bootstrapping has not yet completed on the VM"), 30*time.Second)
 	case infrav1.Failed:
 		m.V(4).Info("extension provisioning state is failed", "vm extension", extensionName, "scale set", m.Name())
-		conditions.MarkFalse(m.AzureMachinePool, infrav1.BootstrapSucceededCondition, infrav1.BootstrapFailedReason, clusterv1.ConditionSeverityError, "")
+		conditions.MarkFalse(m.AzureMachinePool, infrav1.BootstrapSucceededCondition, infrav1.BootstrapFailedReason, clusterv1.ConditionSeverityError, "%s", truncateBootstrapStatusMessage(statusMessage))
 		return azure.WithTerminalError(errors.New("extension state failed. This likely means the Kubernetes node bootstrapping process failed or timed out. Check VM boot diagnostics logs to learn more"))
 	default:
 		return nil
 	}
 }
 
+// BootstrapSucceededCondition returns the BootstrapSucceeded condition of the AzureMachinePool, or nil if it is not set.
+func (m *MachinePoolScope) BootstrapSucceededCondition() *clusterv1.Condition {
+	return conditions.Get(m.AzureMachinePool, infrav1.BootstrapSucceededCondition)
+}
+
+// AzureMachinePoolObject returns the AzureMachinePool as the object events about its scale set are recorded on.
+func (m *MachinePoolScope) AzureMachinePoolObject() runtime.Object {
+	return m.AzureMachinePool
+}
+
 // AdditionalTags merges AdditionalTags from the scope's AzureCluster and AzureMachinePool. If the same key is present in both,
 // the value from AzureMachinePool takes precedence.
 func (m *MachinePoolScope) AdditionalTags() infrav1.Tags {
@@ -586,19 +600,46 @@ func (m *MachinePoolScope) SaveVMImageToStatus(image *infrav1.Image) {
 
 // RoleAssignmentSpecs returns the role assignment specs.
 func (m *MachinePoolScope) RoleAssignmentSpecs() []azure.RoleAssignmentSpec {
-	if m.AzureMachinePool.Spec.Identity == infrav1.VMIdentitySystemAssigned {
-		return []azure.RoleAssignmentSpec{
-			{
-				MachineName:  m.Name(),
-				Name:         m.AzureMachinePool.Spec.RoleAssignmentName,
-				ResourceType: azure.VirtualMachineScaleSet,
-			},
-		}
+	if m.AzureMachinePool.Spec.Identity != infrav1.VMIdentitySystemAssigned {
+		return []azure.RoleAssignmentSpec{}
+	}
+
+	roles := []azure.RoleAssignmentSpec{
+		{
+			MachineName:  m.Name(),
+			Name:         m.AzureMachinePool.Spec.RoleAssignmentName,
+			ResourceType: azure.VirtualMachineScaleSet,
+			Scope:        m.AzureMachinePool.Spec.RoleAssignmentScope,
+		},
 	}
-	return []azure.RoleAssignmentSpec{}
+	if len(m.AzureMachinePool.Spec.RoleAssignmentPrincipalIDs) > 0 {
+		roles = append(roles, azure.RoleAssignmentSpec{
+			Name:         m.AzureMachinePool.Spec.RoleAssignmentName,
+			PrincipalIDs: m.AzureMachinePool.Spec.RoleAssignmentPrincipalIDs,
+			Scope:        m.AzureMachinePool.Spec.RoleAssignmentScope,
+		})
+	}
+	return roles
+}
+
+// IsRoleAssignmentReady returns true if the role assignments of the AzureMachinePool have propagated.
+func (m *MachinePoolScope) IsRoleAssignmentReady() bool {
+	return conditions.IsTrue(m.AzureMachinePool, infrav1.RoleAssignmentReadyCondition)
+}
+
+// RoleAssignmentCreateAttempts returns the number of consecutive reconciles in which the role assignment of the AzureMachinePool
+// could not be created.
+func (m *MachinePoolScope) RoleAssignmentCreateAttempts() int32 {
+	return m.AzureMachinePool.Status.RoleAssignmentCreateAttempts
+}
+
+// SetRoleAssignmentCreateAttempts sets the number of consecutive reconciles in which the role assignment of the
+// AzureMachinePool could not be created.
+func (m *MachinePoolScope) SetRoleAssignmentCreateAttempts(attempts int32) {
+	m.AzureMachinePool.Status.RoleAssignmentCreateAttempts = attempts
 }
 
-// VMSSExtensionSpecs returns the vmss extension specs.
+// VMSSExtensionSpecs returns the vmss extension specs, sorted by name.
 func (m *MachinePoolScope) VMSSExtensionSpecs() []azure.ExtensionSpec {
 	var extensionSpecs = []azure.ExtensionSpec{}
 	extensionSpec := azure.GetBootstrappingVMExtension(m.AzureMachinePool.Spec.Template.OSDisk.OSType, m.CloudEnvironment(), m.Name())
@@ -607,6 +648,11 @@ func (m *MachinePoolScope) VMSSExtensionSpecs() []azure.ExtensionSpec {
 		extensionSpecs = append(extensionSpecs, *extensionSpec)
 	}
 
+	// Sort the extensions by name so that the VMSS model does not change between reconciles.
+	sort.SliceStable(extensionSpecs, func(i, j int) bool {
+		return extensionSpecs[i].Name < extensionSpecs[j].Name
+	})
+
 	return extensionSpecs
 }
 
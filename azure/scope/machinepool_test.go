@@ -20,6 +20,8 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 	"testing"
 
 	autorestazure "github.com/Azure/go-autorest/autorest/azure"
@@ -100,13 +102,13 @@ func TestMachinePoolScope_Name(t *testing.T) {
 func TestMachinePoolScope_SetBootstrapConditions(t *testing.T) {
 	cases := []struct {
 		Name   string
-		Setup  func() (provisioningState string, extensionName string)
+		Setup  func() (provisioningState string, extensionName string, statusMessage string)
 		Verify func(g *WithT, amp *infrav1exp.AzureMachinePool, err error)
 	}{
 		{
 			Name: "should set bootstrap succeeded condition if provisioning state succeeded",
-			Setup: func() (provisioningState string, extensionName string) {
-				return string(infrav1.Succeeded), "foo"
+			Setup: func() (provisioningState string, extensionName string, statusMessage string) {
+				return string(infrav1.Succeeded), "foo", ""
 			},
 			Verify: func(g *WithT, amp *infrav1exp.AzureMachinePool, err error) {
 				g.Expect(err).ToNot(HaveOccurred())
@@ -115,8 +117,8 @@ func TestMachinePoolScope_SetBootstrapConditions(t *testing.T) {
 		},
 		{
 			Name: "should set bootstrap succeeded false condition with reason if provisioning state creating",
-			Setup: func() (provisioningState string, extensionName string) {
-				return string(infrav1.Creating), "bazz"
+			Setup: func() (provisioningState string, extensionName string, statusMessage string) {
+				return string(infrav1.Creating), "bazz", ""
 			},
 			Verify: func(g *WithT, amp *infrav1exp.AzureMachinePool, err error) {
 				g.Expect(err).To(MatchError("extension is still in provisioning state. This likely means that bootstrapping has not yet completed on the VM. Object will be requeued after 30s"))
@@ -129,8 +131,8 @@ func TestMachinePoolScope_SetBootstrapConditions(t *testing.T) {
 		},
 		{
 			Name: "should set bootstrap succeeded false condition with reason if provisioning state failed",
-			Setup: func() (provisioningState string, extensionName string) {
-				return string(infrav1.Failed), "buzz"
+			Setup: func() (provisioningState string, extensionName string, statusMessage string) {
+				return string(infrav1.Failed), "buzz", "script exited with code 1"
 			},
 			Verify: func(g *WithT, amp *infrav1exp.AzureMachinePool, err error) {
 				g.Expect(err).To(MatchError("reconcile error that cannot be recovered occurred: extension state failed. This likely means the Kubernetes node bootstrapping process failed or timed out. Check VM boot diagnostics logs to learn more. Object will not be requeued"))
@@ -139,6 +141,20 @@ func TestMachinePoolScope_SetBootstrapConditions(t *testing.T) {
 				severity := conditions.GetSeverity(amp, infrav1.BootstrapSucceededCondition)
 				g.Expect(severity).ToNot(BeNil())
 				g.Expect(*severity).To(Equal(clusterv1.ConditionSeverityError))
+				g.Expect(conditions.GetMessage(amp, infrav1.BootstrapSucceededCondition)).To(Equal("script exited with code 1"))
+			},
+		},
+		{
+			Name: "should keep the end of a long status message if provisioning state failed",
+			Setup: func() (provisioningState string, extensionName string, statusMessage string) {
+				return string(infrav1.Failed), "buzz", strings.Repeat("ü", 2*maxBootstrapStatusMessageLength) + "script exited with code 1"
+			},
+			Verify: func(g *WithT, amp *infrav1exp.AzureMachinePool, err error) {
+				g.Expect(err).To(HaveOccurred())
+				message := conditions.GetMessage(amp, infrav1.BootstrapSucceededCondition)
+				g.Expect([]rune(message)).To(HaveLen(maxBootstrapStatusMessageLength))
+				g.Expect(message).To(HavePrefix("...ü"))
+				g.Expect(message).To(HaveSuffix("script exited with code 1"))
 			},
 		},
 	}
@@ -151,12 +167,12 @@ func TestMachinePoolScope_SetBootstrapConditions(t *testing.T) {
 			)
 			defer mockCtrl.Finish()
 
-			state, name := c.Setup()
+			state, name, message := c.Setup()
 			s := &MachinePoolScope{
 				AzureMachinePool: &infrav1exp.AzureMachinePool{},
 				Logger:           klogr.New(),
 			}
-			err := s.SetBootstrapConditions(state, name)
+			err := s.SetBootstrapConditions(state, name, message)
 			c.Verify(g, s.AzureMachinePool, err)
 		})
 	}
@@ -668,6 +684,7 @@ func TestMachinePoolScope_VMSSExtensionSpecs(t *testing.T) {
 					ProtectedSettings: map[string]string{
 						"commandToExecute": azure.LinuxBootstrapExtensionCommand,
 					},
+					OSType: azure.LinuxOS,
 				},
 			},
 		},
@@ -736,6 +753,7 @@ func TestMachinePoolScope_VMSSExtensionSpecs(t *testing.T) {
 					ProtectedSettings: map[string]string{
 						"commandToExecute": azure.WindowsBootstrapExtensionCommand,
 					},
+					OSType: azure.WindowsOS,
 				},
 			},
 		},
@@ -833,6 +851,43 @@ func TestMachinePoolScope_VMSSExtensionSpecs(t *testing.T) {
 	}
 }
 
+func TestMachinePoolScope_VMSSExtensionSpecsOrdering(t *testing.T) {
+	g := NewWithT(t)
+	machinePoolScope := MachinePoolScope{
+		MachinePool: &clusterv1exp.MachinePool{},
+		AzureMachinePool: &infrav1exp.AzureMachinePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "machinepool-name",
+			},
+			Spec: infrav1exp.AzureMachinePoolSpec{
+				Template: infrav1exp.AzureMachinePoolMachineTemplate{
+					OSDisk: infrav1.OSDisk{
+						OSType: "Linux",
+					},
+				},
+			},
+		},
+		ClusterScoper: &ClusterScope{
+			AzureClients: AzureClients{
+				EnvironmentSettings: auth.EnvironmentSettings{
+					Environment: autorestazure.Environment{
+						Name: autorestazure.PublicCloud.Name,
+					},
+				},
+			},
+		},
+	}
+
+	first := machinePoolScope.VMSSExtensionSpecs()
+	g.Expect(first).NotTo(BeEmpty())
+	for i := 0; i < 10; i++ {
+		g.Expect(machinePoolScope.VMSSExtensionSpecs()).To(Equal(first))
+	}
+	g.Expect(sort.SliceIsSorted(first, func(i, j int) bool {
+		return first[i].Name < first[j].Name
+	})).To(BeTrue())
+}
+
 func getReadyAzureMachinePoolMachines(count int32) []infrav1exp.AzureMachinePoolMachine {
 	machines := make([]infrav1exp.AzureMachinePoolMachine, count)
 	for i := 0; i < int(count); i++ {
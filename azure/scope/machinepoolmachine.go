@@ -129,6 +129,7 @@ func NewMachinePoolMachineScope(params MachinePoolMachineScopeParams) (*MachineP
 	if params.Logger == nil {
 		params.Logger = klogr.New()
 	}
+	params.Logger = newRedactingLogger(params.Logger)
 
 	mpScope, err := NewMachinePoolScope(MachinePoolScopeParams{
 		Client:           params.Client,
@@ -22,17 +22,32 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/blang/semver"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
 	"k8s.io/klog/v2/klogr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/remote"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	capiexputil "sigs.k8s.io/cluster-api/exp/util"
+	drain "sigs.k8s.io/cluster-api/third_party/kubernetes-drain"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/secret"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -40,8 +55,30 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/groups"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/util/futures"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	// ManagedControlPlaneScopeName is the sourceName, or more specifically the UserAgent, of client used in cordon and drain.
+	ManagedControlPlaneScopeName = "azuremanagedcontrolplane-scope"
+
+	// defaultMaintenanceConfigurationName is the name of the maintenance configuration AKS applies to planned maintenance.
+	defaultMaintenanceConfigurationName = "default"
+	// nodeOSUpgradeMaintenanceConfigurationName is the name of the maintenance configuration AKS applies to node OS
+	// upgrades.
+	nodeOSUpgradeMaintenanceConfigurationName = "aksManagedNodeOSUpgradeSchedule"
+
+	// agentPoolNodeLabel is the label AKS sets on every node with the name of the agent pool the node belongs to.
+	agentPoolNodeLabel = "agentpool"
+
+	// egressCheckJobName is the name of the Job that verifies the egress of a cluster with user-defined routing.
+	egressCheckJobName = "capz-egress-check"
+
+	// defaultEgressCheckImage is the container image of the egress check Job when the egress check doesn't set one.
+	defaultEgressCheckImage = "mcr.microsoft.com/azure-cli"
 )
 
 // ManagedControlPlaneScopeParams defines the input parameters used to create a new managed
@@ -71,6 +108,7 @@ func NewManagedControlPlaneScope(ctx context.Context, params ManagedControlPlane
 	if params.Logger == nil {
 		params.Logger = klogr.New()
 	}
+	params.Logger = newRedactingLogger(params.Logger)
 
 	if params.ControlPlane.Spec.IdentityRef == nil {
 		if err := params.AzureClients.setCredentials(params.ControlPlane.Spec.SubscriptionID, ""); err != nil {
@@ -108,9 +146,8 @@ func NewManagedControlPlaneScope(ctx context.Context, params ManagedControlPlane
 // ManagedControlPlaneScope defines the basic context for an actuator to operate upon.
 type ManagedControlPlaneScope struct {
 	logr.Logger
-	Client         client.Client
-	patchHelper    *patch.Helper
-	kubeConfigData []byte
+	Client      client.Client
+	patchHelper *patch.Helper
 
 	AzureClients
 	Cluster          *clusterv1.Cluster
@@ -120,6 +157,22 @@ type ManagedControlPlaneScope struct {
 	PatchTarget      client.Object
 
 	AllNodePools []infrav1exp.AzureManagedMachinePool
+
+	// ManagedClustersClient fetches the kubeconfigs AKS issues for the managed cluster. It is set to the client of the
+	// managed clusters service, which the scope cannot depend on.
+	ManagedClustersClient ManagedClusterCredentialsClient
+
+	// workloadKubeClient is only used for testing purposes and provides a way for mocking requests to the workload cluster
+	workloadKubeClient kubernetes.Interface
+
+	// skuCache is only used for testing purposes and provides a way for mocking the resource SKUs of the location
+	skuCache *resourceskus.Cache
+}
+
+// ManagedClusterCredentialsClient fetches the kubeconfigs AKS issues for a managed cluster.
+type ManagedClusterCredentialsClient interface {
+	GetCredentials(context.Context, string, string) ([]byte, error)
+	GetUserCredentials(context.Context, string, string) ([]byte, error)
 }
 
 // ResourceGroup returns the managed control plane's resource group.
@@ -130,6 +183,14 @@ func (s *ManagedControlPlaneScope) ResourceGroup() string {
 	return s.ControlPlane.Spec.ResourceGroupName
 }
 
+// DNSPrefix returns the DNS prefix of the managed cluster, which defaults to the name of the managed control plane.
+func (s *ManagedControlPlaneScope) DNSPrefix() string {
+	if s.ControlPlane.Spec.DNSPrefix != nil {
+		return *s.ControlPlane.Spec.DNSPrefix
+	}
+	return s.ControlPlane.Name
+}
+
 // NodeResourceGroup returns the managed control plane's node resource group.
 func (s *ManagedControlPlaneScope) NodeResourceGroup() string {
 	if s.ControlPlane == nil {
@@ -138,6 +199,34 @@ func (s *ManagedControlPlaneScope) NodeResourceGroup() string {
 	return s.ControlPlane.Spec.NodeResourceGroupName
 }
 
+// AADProfile returns the AKS managed Azure Active Directory configuration of the managed cluster, or nil if it is not
+// configured.
+func (s *ManagedControlPlaneScope) AADProfile() *azure.AADProfile {
+	aadProfile := s.ControlPlane.Spec.AADProfile
+	if aadProfile == nil {
+		return nil
+	}
+
+	// Azure RBAC used to always be enabled together with managed AAD, so keep doing so for control planes that
+	// don't set it.
+	enableAzureRBAC := aadProfile.Managed
+	if aadProfile.EnableAzureRBAC != nil {
+		enableAzureRBAC = *aadProfile.EnableAzureRBAC
+	}
+
+	return &azure.AADProfile{
+		Managed:             aadProfile.Managed,
+		EnableAzureRBAC:     enableAzureRBAC,
+		AdminGroupObjectIDs: aadProfile.AdminGroupObjectIDs,
+		TenantID:            aadProfile.TenantID,
+	}
+}
+
+// AreLocalAccountsDisabled returns true if getting static credentials for the managed cluster is disabled.
+func (s *ManagedControlPlaneScope) AreLocalAccountsDisabled() bool {
+	return to.Bool(s.ControlPlane.Spec.DisableLocalAccounts)
+}
+
 // ClusterName returns the managed control plane's name.
 func (s *ManagedControlPlaneScope) ClusterName() string {
 	return s.Cluster.Name
@@ -177,12 +266,38 @@ func (s *ManagedControlPlaneScope) BaseURI() string {
 
 // Authorizer returns the Azure client Authorizer.
 func (s *ManagedControlPlaneScope) Authorizer() autorest.Authorizer {
-	return s.AzureClients.Authorizer
+	return azure.WithSender(s.AzureClients.Authorizer, s.AzureClients.Sender)
 }
 
 // PatchObject persists the cluster configuration and status.
 func (s *ManagedControlPlaneScope) PatchObject(ctx context.Context) error {
-	return s.patchHelper.Patch(ctx, s.PatchTarget)
+	if s.PatchTarget != client.Object(s.ControlPlane) {
+		return s.patchHelper.Patch(ctx, s.PatchTarget)
+	}
+
+	conditions.SetSummary(s.ControlPlane,
+		conditions.WithConditions(
+			infrav1.ResourceGroupReadyCondition,
+			infrav1.ManagedClusterRunningCondition,
+			infrav1.AgentPoolsReadyCondition,
+			infrav1.EgressVerifiedCondition,
+			infrav1.VersionUpgradeAllowedCondition,
+			infrav1.RoleAssignmentReadyCondition,
+		),
+	)
+
+	return s.patchHelper.Patch(
+		ctx,
+		s.ControlPlane,
+		patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
+			clusterv1.ReadyCondition,
+			infrav1.ResourceGroupReadyCondition,
+			infrav1.ManagedClusterRunningCondition,
+			infrav1.AgentPoolsReadyCondition,
+			infrav1.EgressVerifiedCondition,
+			infrav1.VersionUpgradeAllowedCondition,
+			infrav1.RoleAssignmentReadyCondition,
+		}})
 }
 
 // Close closes the current scope persisting the cluster configuration and status.
@@ -233,15 +348,23 @@ func (s *ManagedControlPlaneScope) NodeNatGateway() infrav1.NatGateway {
 	return infrav1.NatGateway{}
 }
 
-// SubnetSpecs returns the subnets specs.
+// SubnetSpecs returns the subnets specs, which are the node subnet and the pod subnets.
 func (s *ManagedControlPlaneScope) SubnetSpecs() []azure.SubnetSpec {
-	return []azure.SubnetSpec{
+	subnetSpecs := []azure.SubnetSpec{
 		{
 			Name:     s.NodeSubnet().Name,
 			CIDRs:    s.NodeSubnet().CIDRBlocks,
 			VNetName: s.Vnet().Name,
 		},
 	}
+	for _, subnet := range s.ControlPlane.Spec.VirtualNetwork.PodSubnets {
+		subnetSpecs = append(subnetSpecs, azure.SubnetSpec{
+			Name:     subnet.Name,
+			CIDRs:    []string{subnet.CIDRBlock},
+			VNetName: s.Vnet().Name,
+		})
+	}
+	return subnetSpecs
 }
 
 // Subnets returns the subnets specs.
@@ -343,7 +466,8 @@ func (s *ManagedControlPlaneScope) FailureDomains() []string {
 	return []string{}
 }
 
-// ManagedClusterSpec returns the managed cluster spec.
+// ManagedClusterSpec returns the managed cluster spec. It does not include the agent pools of the cluster, which are
+// only added with AllAgentPoolSpecs when the managed cluster is created.
 func (s *ManagedControlPlaneScope) ManagedClusterSpec() (azure.ManagedClusterSpec, error) {
 	decodedSSHPublicKey, err := base64.StdEncoding.DecodeString(s.ControlPlane.Spec.SSHPublicKey)
 	if err != nil {
@@ -354,8 +478,11 @@ func (s *ManagedControlPlaneScope) ManagedClusterSpec() (azure.ManagedClusterSpe
 		Name:                  s.ControlPlane.Name,
 		ResourceGroupName:     s.ControlPlane.Spec.ResourceGroupName,
 		NodeResourceGroupName: s.ControlPlane.Spec.NodeResourceGroupName,
+		DiskEncryptionSetID:   s.ControlPlane.Spec.DiskEncryptionSetID,
+		DNSPrefix:             s.DNSPrefix(),
+		IdentityType:          string(containerservice.ResourceIdentityTypeSystemAssigned),
 		Location:              s.ControlPlane.Spec.Location,
-		Tags:                  s.ControlPlane.Spec.AdditionalTags,
+		Tags:                  s.ownedTags(s.ControlPlane.Name),
 		Version:               strings.TrimPrefix(s.ControlPlane.Spec.Version, "v"),
 		SSHPublicKey:          string(decodedSSHPublicKey),
 		DNSServiceIP:          s.ControlPlane.Spec.DNSServiceIP,
@@ -376,6 +503,12 @@ func (s *ManagedControlPlaneScope) ManagedClusterSpec() (azure.ManagedClusterSpe
 	if s.ControlPlane.Spec.LoadBalancerSKU != nil {
 		managedClusterSpec.LoadBalancerSKU = *s.ControlPlane.Spec.LoadBalancerSKU
 	}
+	if s.ControlPlane.Spec.OutboundType != nil {
+		managedClusterSpec.OutboundType = *s.ControlPlane.Spec.OutboundType
+	}
+	if s.ControlPlane.Spec.DisableLocalAccounts != nil {
+		managedClusterSpec.DisableLocalAccounts = to.BoolPtr(s.AreLocalAccountsDisabled())
+	}
 
 	if net := s.Cluster.Spec.ClusterNetwork; net != nil {
 		if net.Services != nil {
@@ -414,17 +547,34 @@ func (s *ManagedControlPlaneScope) ManagedClusterSpec() (azure.ManagedClusterSpe
 		}
 	}
 
-	if s.ControlPlane.Spec.AADProfile != nil {
-		managedClusterSpec.AADProfile = &azure.AADProfile{
-			Managed:             s.ControlPlane.Spec.AADProfile.Managed,
-			EnableAzureRBAC:     s.ControlPlane.Spec.AADProfile.Managed,
-			AdminGroupObjectIDs: s.ControlPlane.Spec.AADProfile.AdminGroupObjectIDs,
-		}
-	}
+	managedClusterSpec.AADProfile = s.AADProfile()
 
+	managedClusterSpec.SKU = &azure.SKU{
+		Tier: infrav1exp.SKUTierFree,
+	}
 	if s.ControlPlane.Spec.SKU != nil {
-		managedClusterSpec.SKU = &azure.SKU{
-			Tier: s.ControlPlane.Spec.SKU.Tier,
+		managedClusterSpec.SKU.Tier = s.ControlPlane.Spec.SKU.Tier
+	}
+
+	if s.ControlPlane.Spec.AutoScalerProfile != nil {
+		managedClusterSpec.AutoScalerProfile = &azure.AutoScalerProfile{
+			BalanceSimilarNodeGroups:      s.ControlPlane.Spec.AutoScalerProfile.BalanceSimilarNodeGroups,
+			Expander:                      s.ControlPlane.Spec.AutoScalerProfile.Expander,
+			MaxEmptyBulkDelete:            s.ControlPlane.Spec.AutoScalerProfile.MaxEmptyBulkDelete,
+			MaxGracefulTerminationSec:     s.ControlPlane.Spec.AutoScalerProfile.MaxGracefulTerminationSec,
+			MaxNodeProvisionTime:          s.ControlPlane.Spec.AutoScalerProfile.MaxNodeProvisionTime,
+			MaxTotalUnreadyPercentage:     s.ControlPlane.Spec.AutoScalerProfile.MaxTotalUnreadyPercentage,
+			NewPodScaleUpDelay:            s.ControlPlane.Spec.AutoScalerProfile.NewPodScaleUpDelay,
+			OkTotalUnreadyCount:           s.ControlPlane.Spec.AutoScalerProfile.OkTotalUnreadyCount,
+			ScanInterval:                  s.ControlPlane.Spec.AutoScalerProfile.ScanInterval,
+			ScaleDownDelayAfterAdd:        s.ControlPlane.Spec.AutoScalerProfile.ScaleDownDelayAfterAdd,
+			ScaleDownDelayAfterDelete:     s.ControlPlane.Spec.AutoScalerProfile.ScaleDownDelayAfterDelete,
+			ScaleDownDelayAfterFailure:    s.ControlPlane.Spec.AutoScalerProfile.ScaleDownDelayAfterFailure,
+			ScaleDownUnneededTime:         s.ControlPlane.Spec.AutoScalerProfile.ScaleDownUnneededTime,
+			ScaleDownUnreadyTime:          s.ControlPlane.Spec.AutoScalerProfile.ScaleDownUnreadyTime,
+			ScaleDownUtilizationThreshold: s.ControlPlane.Spec.AutoScalerProfile.ScaleDownUtilizationThreshold,
+			SkipNodesWithLocalStorage:     s.ControlPlane.Spec.AutoScalerProfile.SkipNodesWithLocalStorage,
+			SkipNodesWithSystemPods:       s.ControlPlane.Spec.AutoScalerProfile.SkipNodesWithSystemPods,
 		}
 	}
 
@@ -438,41 +588,104 @@ func (s *ManagedControlPlaneScope) ManagedClusterSpec() (azure.ManagedClusterSpe
 		}
 	}
 
-	if s.ControlPlane.Spec.APIServerAccessProfile != nil {
-		managedClusterSpec.APIServerAccessProfile = &azure.APIServerAccessProfile{
-			AuthorizedIPRanges:             s.ControlPlane.Spec.APIServerAccessProfile.AuthorizedIPRanges,
-			EnablePrivateCluster:           s.ControlPlane.Spec.APIServerAccessProfile.EnablePrivateCluster,
-			PrivateDNSZone:                 s.ControlPlane.Spec.APIServerAccessProfile.PrivateDNSZone,
-			EnablePrivateClusterPublicFQDN: s.ControlPlane.Spec.APIServerAccessProfile.EnablePrivateClusterPublicFQDN,
-		}
+	managedClusterSpec.APIServerAccessProfile = s.apiServerAccessProfile()
+
+	for _, addon := range s.ControlPlane.Spec.AddonProfiles {
+		managedClusterSpec.AddonProfiles = append(managedClusterSpec.AddonProfiles, azure.AddonProfile{
+			Name:    addon.Name,
+			Config:  addon.Config,
+			Enabled: addon.Enabled,
+		})
 	}
 
 	return managedClusterSpec, nil
 }
 
-// GetAgentPoolSpecs gets a slice of azure.AgentPoolSpec for the list of agent pools.
-func (s *ManagedControlPlaneScope) GetAgentPoolSpecs(ctx context.Context) ([]azure.AgentPoolSpec, error) {
-	if len(s.AllNodePools) == 0 {
-		opt1 := client.InNamespace(s.ControlPlane.Namespace)
-		opt2 := client.MatchingLabels(map[string]string{
-			clusterv1.ClusterLabelName: s.Cluster.Name,
-		})
+// apiServerAccessProfile returns the API server access profile for the managed cluster.
+// Authorized IP ranges are only kept for public clusters, and the private DNS zone and public FQDN
+// settings are only kept for private clusters, matching what the webhook allows.
+func (s *ManagedControlPlaneScope) apiServerAccessProfile() *azure.APIServerAccessProfile {
+	profile := s.ControlPlane.Spec.APIServerAccessProfile
+	if profile == nil {
+		return nil
+	}
 
-		ammpList := &infrav1exp.AzureManagedMachinePoolList{}
+	if profile.EnablePrivateCluster != nil && *profile.EnablePrivateCluster {
+		return &azure.APIServerAccessProfile{
+			EnablePrivateCluster:           profile.EnablePrivateCluster,
+			PrivateDNSZone:                 profile.PrivateDNSZone,
+			EnablePrivateClusterPublicFQDN: profile.EnablePrivateClusterPublicFQDN,
+		}
+	}
 
-		if err := s.Client.List(ctx, ammpList, opt1, opt2); err != nil {
-			return nil, err
+	return &azure.APIServerAccessProfile{
+		AuthorizedIPRanges:   profile.AuthorizedIPRanges,
+		EnablePrivateCluster: profile.EnablePrivateCluster,
+	}
+}
+
+// listNodePools lists the AzureManagedMachinePools of the cluster into AllNodePools, unless they were listed before.
+func (s *ManagedControlPlaneScope) listNodePools(ctx context.Context) error {
+	if len(s.AllNodePools) > 0 {
+		return nil
+	}
+
+	opt1 := client.InNamespace(s.ControlPlane.Namespace)
+	opt2 := client.MatchingLabels(map[string]string{
+		clusterv1.ClusterLabelName: s.Cluster.Name,
+	})
+
+	ammpList := &infrav1exp.AzureManagedMachinePoolList{}
+
+	if err := s.Client.List(ctx, ammpList, opt1, opt2); err != nil {
+		return err
+	}
+
+	s.AllNodePools = ammpList.Items
+	return nil
+}
+
+// SetAgentPoolsReadyCondition sets the AgentPoolsReady condition of the AzureManagedControlPlane to the worst Ready
+// condition of its AzureManagedMachinePools, so that the Ready condition summarizing the control plane reflects the
+// state of the agent pools too.
+func (s *ManagedControlPlaneScope) SetAgentPoolsReadyCondition(ctx context.Context) error {
+	if err := s.listNodePools(ctx); err != nil {
+		return err
+	}
+
+	var pools []conditions.Getter
+	for i := range s.AllNodePools {
+		pool := &s.AllNodePools[i]
+		if conditions.Has(pool, clusterv1.ReadyCondition) {
+			// The kind of the pool is part of the reason of the aggregated condition, but isn't set on listed objects.
+			pool.SetGroupVersionKind(infrav1exp.GroupVersion.WithKind("AzureManagedMachinePool"))
+			pools = append(pools, pool)
 		}
+	}
+	if len(pools) == 0 {
+		conditions.Delete(s.ControlPlane, infrav1.AgentPoolsReadyCondition)
+		return nil
+	}
+
+	conditions.SetAggregate(s.ControlPlane, infrav1.AgentPoolsReadyCondition, pools, conditions.AddSourceRef(), conditions.WithStepCounterIf(false))
+	return nil
+}
 
-		s.AllNodePools = ammpList.Items
+// AllAgentPoolSpecs returns the specs of all the AzureManagedMachinePools of the cluster, as used to create the
+// managed cluster with its agent pools. AzureManagedMachinePools without an owner MachinePool are skipped, and an error
+// is returned if none of the agent pools is a system agent pool or if an agent pool does not meet its constraints.
+func (s *ManagedControlPlaneScope) AllAgentPoolSpecs(ctx context.Context) ([]azure.AgentPoolSpec, error) {
+	if err := s.listNodePools(ctx); err != nil {
+		return nil, err
 	}
 
 	ammps := []azure.AgentPoolSpec{}
 
 	foundSystemPool := false
-	for _, pool := range s.AllNodePools {
-		// Fetch the owning MachinePool.
+	for i := range s.AllNodePools {
+		pool := &s.AllNodePools[i]
 
+		// Fetch the owning MachinePool.
 		ownerPool, err := capiexputil.GetOwnerMachinePool(ctx, s.Client, pool.ObjectMeta)
 		if err != nil {
 			s.Logger.Error(err, "failed to fetch owner ref for system pool: %s", pool.Name)
@@ -487,21 +700,17 @@ func (s *ManagedControlPlaneScope) GetAgentPoolSpecs(ctx context.Context) ([]azu
 			foundSystemPool = true
 		}
 
-		ammp := azure.AgentPoolSpec{
-			Name:         *pool.Spec.Name,
-			SKU:          pool.Spec.SKU,
-			Replicas:     1,
-			OSDiskSizeGB: 0,
-			Mode:         pool.Spec.Mode,
-		}
+		ammp := s.agentPoolSpec(pool, ownerPool)
 
-		// Set optional values
-		if pool.Spec.OSDiskSizeGB != nil {
-			ammp.OSDiskSizeGB = *pool.Spec.OSDiskSizeGB
+		if ammp.SKU == "" && pool.Spec.SKUSelector != nil {
+			ammp.SKU, err = s.resolveSKU(ctx, pool.Spec.SKUSelector)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to resolve the SKU of agent pool %s", ammp.Name)
+			}
 		}
 
-		if ownerPool.Spec.Replicas != nil {
-			ammp.Replicas = *ownerPool.Spec.Replicas
+		if err := s.validateAgentPoolSpec(ctx, ammp, pool); err != nil {
+			return nil, err
 		}
 
 		ammps = append(ammps, ammp)
@@ -514,24 +723,67 @@ func (s *ManagedControlPlaneScope) GetAgentPoolSpecs(ctx context.Context) ([]azu
 	return ammps, nil
 }
 
+// agentPoolCreateOnlyFields are the agent pool fields AKS rejects changes to once the agent pool exists.
+var agentPoolCreateOnlyFields = []azure.AgentPoolField{
+	azure.AgentPoolSKU,
+	azure.AgentPoolOSDiskSizeGB,
+	azure.AgentPoolVnetSubnetID,
+	azure.AgentPoolPodSubnetID,
+	azure.AgentPoolOSType,
+	azure.AgentPoolOSDiskType,
+	azure.AgentPoolEnableEncryptionAtHost,
+	azure.AgentPoolProximityPlacementGroupID,
+	azure.AgentPoolEnableNodePublicIP,
+	azure.AgentPoolNodePublicIPPrefixID,
+	azure.AgentPoolScaleSetPriority,
+}
+
 // AgentPoolSpec returns an azure.AgentPoolSpec for currently reconciled AzureManagedMachinePool.
-func (s *ManagedControlPlaneScope) AgentPoolSpec() azure.AgentPoolSpec {
+// An error is returned if the AzureManagedMachinePool does not meet the constraints of its OS type, or if no other
+// system agent pool remains in the cluster while it is a user agent pool or a system agent pool being deleted.
+func (s *ManagedControlPlaneScope) AgentPoolSpec(ctx context.Context) (azure.AgentPoolSpec, error) {
+	agentPoolSpec := s.agentPoolSpec(s.InfraMachinePool, s.MachinePool)
+
+	// Keep the current version of the agent pool until the agent pools of the earlier upgrade groups are upgraded.
+	blockers, err := s.agentPoolUpgradeBlockers(ctx)
+	if err != nil {
+		return azure.AgentPoolSpec{}, err
+	}
+	if len(blockers) > 0 {
+		currentVersion := s.InfraMachinePool.Status.Version
+		agentPoolSpec.Version = &currentVersion
+	}
+
+	agentPoolSpec.RemovedNodeLabels = removedAgentPoolNodeLabels(s.InfraMachinePool)
+
+	if err := s.validateAgentPoolSpec(ctx, agentPoolSpec, s.InfraMachinePool); err != nil {
+		return azure.AgentPoolSpec{}, err
+	}
+
+	if err := s.validateSystemAgentPoolRemains(ctx); err != nil {
+		return azure.AgentPoolSpec{}, err
+	}
+
+	return agentPoolSpec, nil
+}
+
+// agentPoolSpec returns the azure.AgentPoolSpec of an AzureManagedMachinePool owned by a MachinePool.
+func (s *ManagedControlPlaneScope) agentPoolSpec(pool *infrav1exp.AzureManagedMachinePool, machinePool *expv1.MachinePool) azure.AgentPoolSpec {
 	var normalizedVersion *string
-	if s.MachinePool.Spec.Template.Spec.Version != nil {
-		v := strings.TrimPrefix(*s.MachinePool.Spec.Template.Spec.Version, "v")
+	if v := s.agentPoolVersion(pool, machinePool); v != "" {
 		normalizedVersion = &v
 	}
 
 	replicas := int32(1)
-	if s.MachinePool.Spec.Replicas != nil {
-		replicas = *s.MachinePool.Spec.Replicas
+	if machinePool.Spec.Replicas != nil {
+		replicas = *machinePool.Spec.Replicas
 	}
 
 	agentPoolSpec := azure.AgentPoolSpec{
-		Name:          *s.InfraMachinePool.Spec.Name,
+		Name:          *pool.Spec.Name,
 		ResourceGroup: s.ControlPlane.Spec.ResourceGroupName,
 		Cluster:       s.ControlPlane.Name,
-		SKU:           s.InfraMachinePool.Spec.SKU,
+		SKU:           pool.Spec.SKU,
 		Replicas:      replicas,
 		Version:       normalizedVersion,
 		VnetSubnetID: azure.SubnetID(
@@ -540,16 +792,463 @@ func (s *ManagedControlPlaneScope) AgentPoolSpec() azure.AgentPoolSpec {
 			s.ControlPlane.Spec.VirtualNetwork.Name,
 			s.ControlPlane.Spec.VirtualNetwork.Subnet.Name,
 		),
-		Mode: s.InfraMachinePool.Spec.Mode,
+		Mode:             pool.Spec.Mode,
+		OSType:           azure.LinuxOS,
+		CreateOnlyFields: agentPoolCreateOnlyFields,
+	}
+
+	if pool.Spec.OSDiskSizeGB != nil {
+		agentPoolSpec.OSDiskSizeGB = *pool.Spec.OSDiskSizeGB
+	}
+
+	if pool.Spec.OSType != nil {
+		agentPoolSpec.OSType = *pool.Spec.OSType
+	}
+
+	if pool.Spec.OSDiskType != nil {
+		agentPoolSpec.OSDiskType = *pool.Spec.OSDiskType
 	}
 
-	if s.InfraMachinePool.Spec.OSDiskSizeGB != nil {
-		agentPoolSpec.OSDiskSizeGB = *s.InfraMachinePool.Spec.OSDiskSizeGB
+	agentPoolSpec.EnableEncryptionAtHost = pool.Spec.EnableEncryptionAtHost
+
+	agentPoolSpec.ProximityPlacementGroupID = pool.Spec.ProximityPlacementGroupID
+
+	agentPoolSpec.EnableNodePublicIP = pool.Spec.EnableNodePublicIP
+	agentPoolSpec.NodePublicIPPrefixID = pool.Spec.NodePublicIPPrefixID
+
+	if pool.Spec.PodSubnetName != nil {
+		agentPoolSpec.PodSubnetID = azure.SubnetID(
+			s.ControlPlane.Spec.SubscriptionID,
+			s.ControlPlane.Spec.ResourceGroupName,
+			s.ControlPlane.Spec.VirtualNetwork.Name,
+			*pool.Spec.PodSubnetName,
+		)
+	}
+
+	if pool.Spec.ScaleSetPriority != nil {
+		agentPoolSpec.ScaleSetPriority = *pool.Spec.ScaleSetPriority
+	}
+
+	agentPoolSpec.NodeTaints = agentPoolNodeTaints(pool)
+
+	agentPoolSpec.NodeLabels = agentPoolNodeLabels(pool)
+
+	if pool.Spec.Scaling != nil {
+		setAgentPoolScaling(&agentPoolSpec, pool.Spec.Scaling)
 	}
 
 	return agentPoolSpec
 }
 
+// validateAgentPoolSpec validates that the azure.AgentPoolSpec of an AzureManagedMachinePool meets the constraints of
+// its VM size, scaling and OS type. The VM size and the replicas don't matter for an agent pool that is being deleted.
+func (s *ManagedControlPlaneScope) validateAgentPoolSpec(ctx context.Context, agentPoolSpec azure.AgentPoolSpec, pool *infrav1exp.AzureManagedMachinePool) error {
+	if pool.DeletionTimestamp.IsZero() {
+		if agentPoolSpec.EnableEncryptionAtHost != nil {
+			if err := s.validateEncryptionAtHost(ctx, agentPoolSpec); err != nil {
+				return err
+			}
+		}
+		if pool.Spec.Scaling != nil {
+			if err := validateAgentPoolReplicas(agentPoolSpec); err != nil {
+				return err
+			}
+		}
+	}
+
+	return validateWindowsAgentPoolSpec(agentPoolSpec)
+}
+
+// WaitForAgentPoolUpgradeGroup returns a transient error while the upgrade of the agent pool to its desired
+// Kubernetes version is held back by agent pools of earlier upgrade groups that have not been upgraded yet.
+func (s *ManagedControlPlaneScope) WaitForAgentPoolUpgradeGroup(ctx context.Context) error {
+	blockers, err := s.agentPoolUpgradeBlockers(ctx)
+	if err != nil {
+		return err
+	}
+	if len(blockers) > 0 {
+		return azure.WithTransientError(errors.Errorf("waiting for agent pools %s of earlier upgrade groups to be upgraded", strings.Join(blockers, ", ")), 30*time.Second)
+	}
+	return nil
+}
+
+// agentPoolUpgradeBlockers returns the names of the agent pools of the cluster in a lower upgrade group than the
+// currently reconciled AzureManagedMachinePool that do not run their desired Kubernetes version yet, when the
+// currently reconciled agent pool has an upgrade group and is to be upgraded.
+func (s *ManagedControlPlaneScope) agentPoolUpgradeBlockers(ctx context.Context) ([]string, error) {
+	pool := s.InfraMachinePool
+	group, ok := agentPoolUpgradeGroup(pool)
+	if !ok || pool.Status.Version == "" {
+		return nil, nil
+	}
+	if s.agentPoolVersion(pool, s.MachinePool) == pool.Status.Version {
+		return nil, nil
+	}
+
+	clusterName, ok := pool.Labels[clusterv1.ClusterLabelName]
+	if !ok {
+		return nil, nil
+	}
+
+	ammpList := &infrav1exp.AzureManagedMachinePoolList{}
+	if err := s.Client.List(ctx, ammpList, client.InNamespace(pool.Namespace), client.MatchingLabels{
+		clusterv1.ClusterLabelName: clusterName,
+	}); err != nil {
+		return nil, errors.Wrap(err, "failed to list the agent pools of the cluster")
+	}
+
+	var blockers []string
+	for i := range ammpList.Items {
+		other := &ammpList.Items[i]
+		otherGroup, ok := agentPoolUpgradeGroup(other)
+		if !ok || otherGroup >= group || !other.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		ownerPool, err := capiexputil.GetOwnerMachinePool(ctx, s.Client, other.ObjectMeta)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the owner MachinePool of agent pool %s", other.Name)
+		}
+		if ownerPool == nil {
+			continue
+		}
+
+		if s.agentPoolVersion(other, ownerPool) != other.Status.Version {
+			blockers = append(blockers, other.Name)
+		}
+	}
+
+	return blockers, nil
+}
+
+// agentPoolVersion returns the desired Kubernetes version of the agent pool, without the "v" prefix: its
+// OrchestratorVersion when set, otherwise the version of its MachinePool, otherwise the version of the control plane.
+func (s *ManagedControlPlaneScope) agentPoolVersion(pool *infrav1exp.AzureManagedMachinePool, machinePool *expv1.MachinePool) string {
+	version := s.ControlPlane.Spec.Version
+	switch {
+	case pool.Spec.OrchestratorVersion != nil:
+		version = *pool.Spec.OrchestratorVersion
+	case machinePool != nil && machinePool.Spec.Template.Spec.Version != nil:
+		version = *machinePool.Spec.Template.Spec.Version
+	}
+	return strings.TrimPrefix(version, "v")
+}
+
+// agentPoolUpgradeGroup returns the upgrade group of the agent pool, and false if it has none.
+func agentPoolUpgradeGroup(pool *infrav1exp.AzureManagedMachinePool) (int, bool) {
+	group, ok := pool.Labels[infrav1exp.AgentPoolUpgradeGroupLabel]
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.Atoi(group)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// validateSystemAgentPoolRemains checks that another system agent pool remains in the cluster when the currently
+// reconciled AzureManagedMachinePool is a user agent pool, or a system agent pool being deleted, as AKS requires at
+// least one system agent pool at all times. This prevents the last system agent pool from being demoted to a user
+// agent pool or deleted, e.g. when its deletion bypassed the webhook. The deletion of the last system agent pool is
+// retried, as it can proceed once another system agent pool is added to the cluster.
+func (s *ManagedControlPlaneScope) validateSystemAgentPoolRemains(ctx context.Context) error {
+	pool := s.InfraMachinePool
+	deleting := !pool.DeletionTimestamp.IsZero()
+	if deleting {
+		// Deleting a user agent pool never removes the last system agent pool.
+		if pool.Spec.Mode != string(infrav1exp.NodePoolModeSystem) {
+			return nil
+		}
+	} else if pool.Spec.Mode != string(infrav1exp.NodePoolModeUser) {
+		// A system agent pool that is not being deleted is a system agent pool that remains.
+		return nil
+	}
+
+	clusterName, ok := pool.Labels[clusterv1.ClusterLabelName]
+	if !ok {
+		return nil
+	}
+
+	ammpList := &infrav1exp.AzureManagedMachinePoolList{}
+	if err := s.Client.List(ctx, ammpList, client.InNamespace(pool.Namespace), client.MatchingLabels{
+		clusterv1.ClusterLabelName: clusterName,
+	}); err != nil {
+		return errors.Wrap(err, "failed to list the agent pools of the cluster")
+	}
+
+	for _, other := range ammpList.Items {
+		if other.Name != pool.Name && other.Spec.Mode == string(infrav1exp.NodePoolModeSystem) && other.DeletionTimestamp.IsZero() {
+			return nil
+		}
+	}
+
+	if deleting {
+		return azure.WithTransientError(errors.Errorf("agent pool %s cannot be deleted, the cluster must have at least one agent pool in mode %s", *pool.Spec.Name, infrav1exp.NodePoolModeSystem), 30*time.Second)
+	}
+	return errors.Errorf("agent pool %s cannot be in mode %s, the cluster must have at least one agent pool in mode %s", *pool.Spec.Name, pool.Spec.Mode, infrav1exp.NodePoolModeSystem)
+}
+
+// setAgentPoolScaling enables the cluster autoscaler on the agent pool within the given bounds. The replicas of the
+// agent pool spec are left untouched, as they are only used as the initial node count of the agent pool.
+func setAgentPoolScaling(agentPoolSpec *azure.AgentPoolSpec, scaling *infrav1exp.ManagedMachinePoolScaling) {
+	agentPoolSpec.EnableAutoScaling = to.BoolPtr(true)
+	agentPoolSpec.MinCount = to.Int32Ptr(scaling.MinSize)
+	agentPoolSpec.MaxCount = to.Int32Ptr(scaling.MaxSize)
+}
+
+// validateAgentPoolReplicas validates that the initial node count of an agent pool with autoscaling enabled is within
+// its autoscaling bounds, as AKS rejects agent pools whose node count is outside of them.
+func validateAgentPoolReplicas(agentPoolSpec azure.AgentPoolSpec) error {
+	if agentPoolSpec.MinCount == nil || agentPoolSpec.MaxCount == nil {
+		return nil
+	}
+
+	if agentPoolSpec.Replicas < *agentPoolSpec.MinCount || agentPoolSpec.Replicas > *agentPoolSpec.MaxCount {
+		return errors.Errorf("the replicas of agent pool %s must be between its scaling minSize %d and maxSize %d, not %d",
+			agentPoolSpec.Name, *agentPoolSpec.MinCount, *agentPoolSpec.MaxCount, agentPoolSpec.Replicas)
+	}
+	return nil
+}
+
+// MaintenanceConfigurationSpecs returns the specs of the planned maintenance configurations of the managed cluster:
+// the default configuration for the maintenance window and the node OS upgrade configuration for the node OS upgrade
+// maintenance window. No spec is returned for a window that is not configured.
+func (s *ManagedControlPlaneScope) MaintenanceConfigurationSpecs() []azure.MaintenanceConfigurationSpec {
+	var specs []azure.MaintenanceConfigurationSpec
+	if window := s.ControlPlane.Spec.MaintenanceWindow; window != nil {
+		specs = append(specs, s.maintenanceConfigurationSpec(defaultMaintenanceConfigurationName, window))
+	}
+	if window := s.ControlPlane.Spec.NodeOSUpgradeMaintenanceWindow; window != nil {
+		specs = append(specs, s.maintenanceConfigurationSpec(nodeOSUpgradeMaintenanceConfigurationName, window))
+	}
+	return specs
+}
+
+// maintenanceConfigurationSpec returns the spec of the maintenance configuration with the given name for a
+// maintenance window.
+func (s *ManagedControlPlaneScope) maintenanceConfigurationSpec(name string, window *infrav1exp.MaintenanceWindow) azure.MaintenanceConfigurationSpec {
+	spec := azure.MaintenanceConfigurationSpec{
+		Name:          name,
+		ResourceGroup: s.ControlPlane.Spec.ResourceGroupName,
+		Cluster:       s.ControlPlane.Name,
+	}
+	for _, timeInWeek := range window.TimeInWeek {
+		spec.TimeInWeek = append(spec.TimeInWeek, azure.MaintenanceTimeInWeek{
+			Day:       timeInWeek.Day,
+			HourSlots: timeInWeek.HourSlots,
+		})
+	}
+	for _, timeSpan := range window.NotAllowedTime {
+		spec.NotAllowedTime = append(spec.NotAllowedTime, azure.MaintenanceTimeSpan{
+			Start: timeSpan.Start.UTC(),
+			End:   timeSpan.End.UTC(),
+		})
+	}
+	return spec
+}
+
+// RoleAssignmentSpecs returns the spec of the role assignments of the principals to assign a role at the node resource
+// group to. The role assignments are named in the namespace of the UID of the AzureManagedControlPlane.
+func (s *ManagedControlPlaneScope) RoleAssignmentSpecs() []azure.RoleAssignmentSpec {
+	if len(s.ControlPlane.Spec.NodeResourceGroupRoleAssignmentPrincipalIDs) == 0 {
+		return []azure.RoleAssignmentSpec{}
+	}
+
+	return []azure.RoleAssignmentSpec{
+		{
+			Name:                string(s.ControlPlane.UID),
+			PrincipalIDs:        s.ControlPlane.Spec.NodeResourceGroupRoleAssignmentPrincipalIDs,
+			AtNodeResourceGroup: true,
+		},
+	}
+}
+
+// IsRoleAssignmentReady returns true if the role assignments at the node resource group have propagated.
+func (s *ManagedControlPlaneScope) IsRoleAssignmentReady() bool {
+	return conditions.IsTrue(s.ControlPlane, infrav1.RoleAssignmentReadyCondition)
+}
+
+// RoleAssignmentCreateAttempts returns the number of consecutive reconciles in which the role assignments at the node
+// resource group could not be created.
+func (s *ManagedControlPlaneScope) RoleAssignmentCreateAttempts() int32 {
+	return s.ControlPlane.Status.RoleAssignmentCreateAttempts
+}
+
+// SetRoleAssignmentCreateAttempts sets the number of consecutive reconciles in which the role assignments at the node
+// resource group could not be created.
+func (s *ManagedControlPlaneScope) SetRoleAssignmentCreateAttempts(attempts int32) {
+	s.ControlPlane.Status.RoleAssignmentCreateAttempts = attempts
+}
+
+// SetSKUFromSelector sets the SKU of the AzureManagedMachinePool to the VM size resolved from its SKU selector when
+// no SKU is set. The resolved SKU is persisted with the AzureManagedMachinePool so the agent pool keeps its VM size.
+func (s *ManagedControlPlaneScope) SetSKUFromSelector(ctx context.Context) error {
+	if s.InfraMachinePool.Spec.SKU != "" || s.InfraMachinePool.Spec.SKUSelector == nil {
+		return nil
+	}
+
+	sku, err := s.resolveSKU(ctx, s.InfraMachinePool.Spec.SKUSelector)
+	if err != nil {
+		return errors.Wrapf(err, "failed to resolve the SKU of agent pool %s", s.InfraMachinePool.Name)
+	}
+	s.V(2).Info("resolved agent pool SKU from the SKU selector", "sku", sku)
+	s.InfraMachinePool.Spec.SKU = sku
+	return nil
+}
+
+// resolveSKU returns the smallest VM size available in the location that satisfies the SKU selector, ordered by
+// vCPUs, then memory, then name.
+func (s *ManagedControlPlaneScope) resolveSKU(ctx context.Context, selector *infrav1exp.SKUSelector) (string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.ManagedControlPlaneScope.resolveSKU")
+	defer done()
+
+	skuCache, err := s.getSKUCache()
+	if err != nil {
+		return "", err
+	}
+
+	type candidate struct {
+		name     string
+		vCPUs    float64
+		memoryGB float64
+	}
+	var candidates []candidate
+	mapFn := func(sku resourceskus.SKU) {
+		if sku.Name == nil || sku.ResourceType == nil || !strings.EqualFold(*sku.ResourceType, string(resourceskus.VirtualMachines)) {
+			return
+		}
+		if selector.Family != nil && !strings.EqualFold(to.String(sku.Family), *selector.Family) {
+			return
+		}
+		if sku.Restrictions != nil {
+			for _, restriction := range *sku.Restrictions {
+				if restriction.Type == compute.ResourceSkuRestrictionsTypeLocation {
+					return
+				}
+			}
+		}
+		vCPUs, ok := skuCapabilityValue(sku, resourceskus.VCPUs)
+		if !ok || (selector.MinVCPUs != nil && vCPUs < float64(*selector.MinVCPUs)) {
+			return
+		}
+		memoryGB, ok := skuCapabilityValue(sku, resourceskus.MemoryGB)
+		if !ok || (selector.MinMemoryGB != nil && memoryGB < float64(*selector.MinMemoryGB)) {
+			return
+		}
+		candidates = append(candidates, candidate{name: *sku.Name, vCPUs: vCPUs, memoryGB: memoryGB})
+	}
+	if err := skuCache.Map(ctx, mapFn); err != nil {
+		return "", err
+	}
+
+	if len(candidates) == 0 {
+		return "", errors.Errorf("no VM size available in location %s matches the SKU selector (family: %s, minimum vCPUs: %d, minimum memory: %dGB)",
+			s.Location(), to.String(selector.Family), to.Int32(selector.MinVCPUs), to.Int32(selector.MinMemoryGB))
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].vCPUs != candidates[j].vCPUs {
+			return candidates[i].vCPUs < candidates[j].vCPUs
+		}
+		if candidates[i].memoryGB != candidates[j].memoryGB {
+			return candidates[i].memoryGB < candidates[j].memoryGB
+		}
+		return candidates[i].name < candidates[j].name
+	})
+	return candidates[0].name, nil
+}
+
+func (s *ManagedControlPlaneScope) getSKUCache() (*resourceskus.Cache, error) {
+	if s.skuCache != nil {
+		return s.skuCache, nil
+	}
+	return resourceskus.GetCache(s, s.Location())
+}
+
+// skuCapabilityValue returns the numeric value of the capability of the SKU. Capabilities such as MemoryGB can be fractional.
+func skuCapabilityValue(sku resourceskus.SKU, name string) (float64, bool) {
+	value, ok := sku.GetCapability(name)
+	if !ok {
+		return 0, false
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, false
+	}
+	return number, true
+}
+
+// RequiredResourceProviders returns the namespaces of the resource providers the subscription must be registered with
+// to create an AKS cluster.
+func (s *ManagedControlPlaneScope) RequiredResourceProviders() []string {
+	return []string{
+		"Microsoft.ContainerService",
+		"Microsoft.Compute",
+		"Microsoft.Network",
+		"Microsoft.Storage",
+	}
+}
+
+// ResourceProviderRegistrationAllowed returns true if the subscription may be registered with the resource providers
+// an AKS cluster requires when it is not registered with them yet.
+func (s *ManagedControlPlaneScope) ResourceProviderRegistrationAllowed() bool {
+	_, ok := s.ControlPlane.Annotations[infrav1exp.RegisterResourceProvidersAnnotation]
+	return ok
+}
+
+// ResourceProvidersRegistered returns true once the control plane is initialized, as the managed cluster could only be
+// created if the subscription was registered with the resource providers it requires.
+func (s *ManagedControlPlaneScope) ResourceProvidersRegistered() bool {
+	return s.ControlPlane.Status.Initialized
+}
+
+// AgentPoolRecreateAllowed returns true if the agent pool may be deleted and recreated to apply changes to fields
+// that AKS does not allow to be updated.
+func (s *ManagedControlPlaneScope) AgentPoolRecreateAllowed() bool {
+	_, ok := s.InfraMachinePool.Annotations[infrav1exp.AgentPoolRecreateAnnotation]
+	return ok
+}
+
+// validateEncryptionAtHost returns a terminal error if encryption at host is enabled on an agent pool whose VM size
+// does not support it.
+func (s *ManagedControlPlaneScope) validateEncryptionAtHost(ctx context.Context, agentPoolSpec azure.AgentPoolSpec) error {
+	if !to.Bool(agentPoolSpec.EnableEncryptionAtHost) {
+		return nil
+	}
+
+	skuCache, err := s.getSKUCache()
+	if err != nil {
+		return err
+	}
+	sku, err := skuCache.Get(ctx, agentPoolSpec.SKU, resourceskus.VirtualMachines)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the SKU of agent pool %s", agentPoolSpec.Name)
+	}
+	if !sku.HasCapability(resourceskus.EncryptionAtHost) {
+		return azure.WithTerminalError(errors.Errorf("encryption at host is not supported for VM size %s of agent pool %s", agentPoolSpec.SKU, agentPoolSpec.Name))
+	}
+	return nil
+}
+
+// validateWindowsAgentPoolSpec returns an error if a Windows agent pool does not meet the constraints AKS places on
+// Windows agent pools, so that it is not left to AKS to reject the agent pool.
+func validateWindowsAgentPoolSpec(agentPoolSpec azure.AgentPoolSpec) error {
+	if agentPoolSpec.OSType != azure.WindowsOS {
+		return nil
+	}
+
+	if len(agentPoolSpec.Name) > infrav1exp.WindowsAgentPoolNameMaxLength {
+		return errors.Errorf("Windows agent pool name %q must be at most %d characters long", agentPoolSpec.Name, infrav1exp.WindowsAgentPoolNameMaxLength)
+	}
+
+	if agentPoolSpec.Mode != string(infrav1exp.NodePoolModeUser) {
+		return errors.Errorf("Windows agent pool %s must be in mode %s, not %s", agentPoolSpec.Name, infrav1exp.NodePoolModeUser, agentPoolSpec.Mode)
+	}
+
+	return nil
+}
+
 // SetAgentPoolProviderIDList sets a list of agent pool's Azure VM IDs.
 func (s *ManagedControlPlaneScope) SetAgentPoolProviderIDList(providerIDs []string) {
 	s.InfraMachinePool.Spec.ProviderIDList = providerIDs
@@ -560,37 +1259,640 @@ func (s *ManagedControlPlaneScope) SetAgentPoolReplicas(replicas int32) {
 	s.InfraMachinePool.Status.Replicas = replicas
 }
 
+// SetAgentPoolVersion sets the observed Kubernetes version of the agent pool.
+func (s *ManagedControlPlaneScope) SetAgentPoolVersion(version string) {
+	s.InfraMachinePool.Status.Version = version
+}
+
 // SetAgentPoolReady sets the flag that indicates if the agent pool is ready or not.
 func (s *ManagedControlPlaneScope) SetAgentPoolReady(ready bool) {
 	s.InfraMachinePool.Status.Ready = ready
 }
 
+// SetAgentPoolProvisioningState sets the Ready condition of the agent pool from its provisioning state in Azure. The
+// condition is true once the agent pool provisioning succeeded, and false while it is in progress or after it failed.
+func (s *ManagedControlPlaneScope) SetAgentPoolProvisioningState(state string) {
+	switch infrav1.ProvisioningState(state) {
+	case infrav1.Succeeded:
+		conditions.MarkTrue(s.InfraMachinePool, clusterv1.ReadyCondition)
+	case infrav1.Failed, infrav1.Canceled:
+		conditions.MarkFalse(s.InfraMachinePool, clusterv1.ReadyCondition, infrav1.AgentPoolFailedReason, clusterv1.ConditionSeverityError, "agent pool provisioning state is %s", state)
+	default:
+		conditions.MarkFalse(s.InfraMachinePool, clusterv1.ReadyCondition, infrav1.AgentPoolProvisioningReason, clusterv1.ConditionSeverityInfo, "agent pool provisioning state is %s", state)
+	}
+}
+
+// ManagedClusterCreateTimedOut checks whether the CreateTimeout of the AzureManagedControlPlane has elapsed since it
+// was created without the control plane becoming ready.
+func (s *ManagedControlPlaneScope) ManagedClusterCreateTimedOut() bool {
+	timeout := s.ControlPlane.Spec.CreateTimeout
+	if timeout == nil || timeout.Seconds() <= 0 || s.ControlPlane.Status.Ready {
+		return false
+	}
+
+	diff := time.Since(s.ControlPlane.CreationTimestamp.Time)
+	return diff.Seconds() >= timeout.Seconds()
+}
+
+// SetManagedClusterProvisioningState sets the ManagedClusterRunning condition of the AzureManagedControlPlane from the
+// provisioning state of the managed cluster in Azure.
+func (s *ManagedControlPlaneScope) SetManagedClusterProvisioningState(state string) {
+	switch infrav1.ProvisioningState(state) {
+	case infrav1.Succeeded:
+		conditions.MarkTrue(s.ControlPlane, infrav1.ManagedClusterRunningCondition)
+	case infrav1.Failed, infrav1.Canceled:
+		conditions.MarkFalse(s.ControlPlane, infrav1.ManagedClusterRunningCondition, infrav1.ManagedClusterFailedReason, clusterv1.ConditionSeverityError, "managed cluster provisioning state is %s", state)
+	default:
+		conditions.MarkFalse(s.ControlPlane, infrav1.ManagedClusterRunningCondition, infrav1.ManagedClusterProvisioningReason, clusterv1.ConditionSeverityInfo, "managed cluster provisioning state is %s", state)
+	}
+}
+
+// SetManagedClusterCreateTimedOut sets the CreateTimedOut condition on the AzureManagedControlPlane with the last
+// known provisioning state of the managed cluster.
+func (s *ManagedControlPlaneScope) SetManagedClusterCreateTimedOut(state string) {
+	conditions.Set(s.ControlPlane, &clusterv1.Condition{
+		Type:    infrav1.CreateTimedOutCondition,
+		Status:  corev1.ConditionTrue,
+		Reason:  infrav1.CreateTimeoutExceededReason,
+		Message: fmt.Sprintf("managed cluster was not created within %s, last known provisioning state is %s", s.ControlPlane.Spec.CreateTimeout.Duration, state),
+	})
+}
+
+// ValidateVersionUpgrade returns a terminal error if upgrading the managed cluster from its current Kubernetes version,
+// as reported by AKS, to the version of the AzureManagedControlPlane would skip a minor version, which AKS does not
+// allow. The outcome is reported in the VersionUpgradeAllowed condition of the AzureManagedControlPlane.
+func (s *ManagedControlPlaneScope) ValidateVersionUpgrade(currentVersion string) error {
+	if currentVersion == "" {
+		return nil
+	}
+
+	delta, err := minorVersionDelta(currentVersion, s.ControlPlane.Spec.Version)
+	if err != nil {
+		return errors.Wrap(err, "failed to compare the current and desired Kubernetes versions of the managed cluster")
+	}
+
+	if delta > 1 {
+		conditions.MarkFalse(s.ControlPlane, infrav1.VersionUpgradeAllowedCondition, infrav1.MinorVersionSkippedReason, clusterv1.ConditionSeverityError,
+			"upgrading from Kubernetes version %s to %s skips minor versions, upgrade one minor version at a time", currentVersion, s.ControlPlane.Spec.Version)
+		return azure.WithTerminalError(errors.Errorf("cannot upgrade managed cluster %s from Kubernetes version %s to %s: AKS does not allow skipping minor versions",
+			s.ControlPlane.Name, currentVersion, s.ControlPlane.Spec.Version))
+	}
+
+	conditions.MarkTrue(s.ControlPlane, infrav1.VersionUpgradeAllowedCondition)
+	return nil
+}
+
+// minorVersionDelta returns the number of minor versions between the current and desired Kubernetes versions, which is
+// negative for downgrades. An error is returned if the versions differ in major version.
+func minorVersionDelta(currentVersion, desiredVersion string) (int, error) {
+	current, err := semver.ParseTolerant(currentVersion)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to parse Kubernetes version %s", currentVersion)
+	}
+	desired, err := semver.ParseTolerant(desiredVersion)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to parse Kubernetes version %s", desiredVersion)
+	}
+
+	if desired.Major != current.Major {
+		return 0, errors.Errorf("cannot compare Kubernetes versions %s and %s of different major versions", currentVersion, desiredVersion)
+	}
+
+	return int(desired.Minor) - int(current.Minor), nil
+}
+
+// DrainAgentPoolNodes cordons and drains the nodes of the agent pool when a NodeDrainTimeout is set, so that their
+// workloads are rescheduled before the agent pool is deleted. All nodes of the agent pool are cordoned before any of
+// them is drained to avoid evicted pods from being scheduled onto nodes that are about to be removed. A transient
+// error is returned while the nodes cannot be drained, until the NodeDrainTimeout has elapsed.
+func (s *ManagedControlPlaneScope) DrainAgentPoolNodes(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(
+		ctx,
+		"scope.ManagedControlPlaneScope.DrainAgentPoolNodes",
+	)
+	defer done()
+
+	pool := s.InfraMachinePool
+	if pool == nil || pool.Spec.NodeDrainTimeout == nil || pool.Spec.NodeDrainTimeout.Seconds() <= 0 {
+		return nil
+	}
+
+	if s.agentPoolNodeDrainTimeoutExceeded() {
+		s.V(2).Info("NodeDrainTimeout exceeded, deleting agent pool without waiting for its nodes to be drained")
+		return nil
+	}
+
+	kubeClient, nodes, err := s.listAgentPoolNodes(ctx)
+	if err != nil {
+		return err
+	}
+
+	drainer := &drain.Helper{
+		Client:              kubeClient,
+		Force:               true,
+		IgnoreAllDaemonSets: true,
+		DeleteLocalData:     true,
+		GracePeriodSeconds:  -1,
+		// If a pod is not evicted in 20 seconds, retry the eviction next time the
+		// machine pool gets reconciled again.
+		Timeout: 20 * time.Second,
+		OnPodDeletedOrEvicted: func(pod *corev1.Pod, usingEviction bool) {
+			verbStr := "Deleted"
+			if usingEviction {
+				verbStr = "Evicted"
+			}
+			s.V(4).Info(fmt.Sprintf("%s pod from Node", verbStr),
+				"pod", fmt.Sprintf("%s/%s", pod.Name, pod.Namespace))
+		},
+		Out:    writer{klog.Info},
+		ErrOut: writer{klog.Error},
+	}
+
+	for i := range nodes {
+		node := &nodes[i]
+		if err := drain.RunCordonOrUncordon(ctx, drainer, node, true); err != nil {
+			return azure.WithTransientError(errors.Errorf("unable to cordon node %s: %v", node.Name, err), 20*time.Second)
+		}
+	}
+
+	for _, node := range nodes {
+		if err := drain.RunNodeDrain(ctx, drainer, node.Name); err != nil {
+			return azure.WithTransientError(errors.Wrapf(err, "failed to drain node %s, retry in 20s", node.Name), 20*time.Second)
+		}
+	}
+
+	s.V(4).Info("Drain of agent pool nodes successful")
+	return nil
+}
+
+// listAgentPoolNodes returns a client of the workload cluster together with the nodes of the agent pool. The errors
+// returned are transient, as the workload cluster may not be reachable yet.
+func (s *ManagedControlPlaneScope) listAgentPoolNodes(ctx context.Context) (kubernetes.Interface, []corev1.Node, error) {
+	kubeClient, err := s.getWorkloadKubeClient(ctx)
+	if err != nil {
+		return nil, nil, azure.WithTransientError(errors.Wrap(err, "failed to create the workload cluster client"), 20*time.Second)
+	}
+
+	nodes, err := kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{agentPoolNodeLabel: *s.InfraMachinePool.Spec.Name}).String(),
+	})
+	if err != nil {
+		return nil, nil, azure.WithTransientError(errors.Wrap(err, "failed to list the nodes of the agent pool"), 20*time.Second)
+	}
+	return kubeClient, nodes.Items, nil
+}
+
+// agentPoolNodeDrainTimeoutExceeded checks whether the NodeDrainTimeout of the AzureManagedMachinePool has elapsed
+// since its deletion was requested.
+func (s *ManagedControlPlaneScope) agentPoolNodeDrainTimeoutExceeded() bool {
+	pool := s.InfraMachinePool
+	if pool.DeletionTimestamp.IsZero() {
+		return false
+	}
+
+	diff := time.Since(pool.DeletionTimestamp.Time)
+	return diff.Seconds() >= pool.Spec.NodeDrainTimeout.Seconds()
+}
+
+// RemoveAgentPoolStartupTaints removes the startup taint of the agent pool from the nodes that pass its readiness
+// check. A transient error is returned while nodes of the agent pool are still waiting for the taint to be removed.
+func (s *ManagedControlPlaneScope) RemoveAgentPoolStartupTaints(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(
+		ctx,
+		"scope.ManagedControlPlaneScope.RemoveAgentPoolStartupTaints",
+	)
+	defer done()
+
+	pool := s.InfraMachinePool
+	if pool == nil || pool.Spec.StartupTaint == nil {
+		return nil
+	}
+
+	kubeClient, nodes, err := s.listAgentPoolNodes(ctx)
+	if err != nil {
+		return err
+	}
+
+	readinessConditionType := corev1.NodeReady
+	if pool.Spec.StartupTaint.ReadinessConditionType != nil {
+		readinessConditionType = corev1.NodeConditionType(*pool.Spec.StartupTaint.ReadinessConditionType)
+	}
+
+	var pending []string
+	for i := range nodes {
+		node := &nodes[i]
+		taints, found := withoutStartupTaint(node.Spec.Taints, pool.Spec.StartupTaint.Key)
+		if !found {
+			continue
+		}
+
+		if !nodeConditionTrue(node, readinessConditionType) {
+			pending = append(pending, node.Name)
+			continue
+		}
+
+		node.Spec.Taints = taints
+		if _, err := kubeClient.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{}); err != nil {
+			return azure.WithTransientError(errors.Wrapf(err, "failed to remove startup taint from node %s", node.Name), 20*time.Second)
+		}
+		s.V(2).Info("Removed startup taint from node", "node", node.Name)
+	}
+
+	if len(pending) > 0 {
+		return azure.WithTransientError(errors.Errorf("waiting for nodes %s to have condition %s before removing their startup taint",
+			strings.Join(pending, ", "), readinessConditionType), 15*time.Second)
+	}
+
+	return nil
+}
+
+// RemoveAgentPoolNodeLabels removes the node labels that were removed from the spec of the agent pool from its existing
+// nodes, and records the keys of the node labels CAPZ currently applies in the node labels annotation of the agent pool.
+func (s *ManagedControlPlaneScope) RemoveAgentPoolNodeLabels(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(
+		ctx,
+		"scope.ManagedControlPlaneScope.RemoveAgentPoolNodeLabels",
+	)
+	defer done()
+
+	pool := s.InfraMachinePool
+	if pool == nil {
+		return nil
+	}
+
+	if removed := removedAgentPoolNodeLabels(pool); len(removed) > 0 {
+		kubeClient, nodes, err := s.listAgentPoolNodes(ctx)
+		if err != nil {
+			return err
+		}
+
+		for i := range nodes {
+			node := &nodes[i]
+			found := false
+			for _, key := range removed {
+				if _, ok := node.Labels[key]; ok {
+					delete(node.Labels, key)
+					found = true
+				}
+			}
+			if !found {
+				continue
+			}
+
+			if _, err := kubeClient.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{}); err != nil {
+				return azure.WithTransientError(errors.Wrapf(err, "failed to remove labels from node %s", node.Name), 20*time.Second)
+			}
+			s.V(2).Info("Removed labels from node", "node", node.Name, "labels", removed)
+		}
+	}
+
+	nodeLabels := agentPoolNodeLabels(pool)
+	if len(nodeLabels) == 0 {
+		delete(pool.Annotations, infrav1exp.AgentPoolNodeLabelsAnnotation)
+		return nil
+	}
+
+	keys := make([]string, 0, len(nodeLabels))
+	for key := range nodeLabels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if pool.Annotations == nil {
+		pool.Annotations = map[string]string{}
+	}
+	pool.Annotations[infrav1exp.AgentPoolNodeLabelsAnnotation] = strings.Join(keys, ",")
+
+	return nil
+}
+
+// AnnotateAgentPoolNodes applies the autoscaler node annotations of the agent pool to its nodes, so that the cluster
+// autoscaler honors them when scaling the agent pool down.
+func (s *ManagedControlPlaneScope) AnnotateAgentPoolNodes(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(
+		ctx,
+		"scope.ManagedControlPlaneScope.AnnotateAgentPoolNodes",
+	)
+	defer done()
+
+	pool := s.InfraMachinePool
+	if pool == nil || len(pool.Spec.AutoscalerNodeAnnotations) == 0 {
+		return nil
+	}
+
+	kubeClient, nodes, err := s.listAgentPoolNodes(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range nodes {
+		node := &nodes[i]
+		changed := false
+		for key, value := range pool.Spec.AutoscalerNodeAnnotations {
+			if current, ok := node.Annotations[key]; ok && current == value {
+				continue
+			}
+			if node.Annotations == nil {
+				node.Annotations = map[string]string{}
+			}
+			node.Annotations[key] = value
+			changed = true
+		}
+		if !changed {
+			continue
+		}
+
+		if _, err := kubeClient.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{}); err != nil {
+			return azure.WithTransientError(errors.Wrapf(err, "failed to annotate node %s", node.Name), 20*time.Second)
+		}
+		s.V(2).Info("Applied autoscaler annotations to node", "node", node.Name)
+	}
+
+	return nil
+}
+
+// VerifyEgress runs the egress check Job of a cluster with user-defined routing in the workload cluster and reports
+// its result in the EgressVerified condition. A transient error is returned until the Job succeeds, so that the
+// control plane is only marked ready once egress works. A failed Job is deleted to be run again.
+func (s *ManagedControlPlaneScope) VerifyEgress(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(
+		ctx,
+		"scope.ManagedControlPlaneScope.VerifyEgress",
+	)
+	defer done()
+
+	check := s.ControlPlane.Spec.EgressCheck
+	if check == nil || to.String(s.ControlPlane.Spec.OutboundType) != infrav1exp.OutboundTypeUserDefinedRouting {
+		return nil
+	}
+	if conditions.IsTrue(s.ControlPlane, infrav1.EgressVerifiedCondition) {
+		return nil
+	}
+
+	kubeClient, err := s.getWorkloadKubeClient(ctx)
+	if err != nil {
+		return azure.WithTransientError(errors.Wrap(err, "failed to create the workload cluster client"), 20*time.Second)
+	}
+
+	jobs := kubeClient.BatchV1().Jobs(metav1.NamespaceSystem)
+	job, err := jobs.Get(ctx, egressCheckJobName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		if _, err := jobs.Create(ctx, egressCheckJob(check), metav1.CreateOptions{}); err != nil {
+			return azure.WithTransientError(errors.Wrap(err, "failed to create the egress check job"), 20*time.Second)
+		}
+		conditions.MarkFalse(s.ControlPlane, infrav1.EgressVerifiedCondition, infrav1.EgressCheckRunningReason, clusterv1.ConditionSeverityInfo, "verifying egress to %s", check.URL)
+		return azure.WithTransientError(errors.Errorf("waiting for the egress check job to verify egress to %s", check.URL), 15*time.Second)
+	}
+	if err != nil {
+		return azure.WithTransientError(errors.Wrap(err, "failed to get the egress check job"), 20*time.Second)
+	}
+
+	// A finished Job is deleted, so that it is neither left behind in the workload cluster nor reused by a later check.
+	propagationPolicy := metav1.DeletePropagationBackground
+	deleteOptions := metav1.DeleteOptions{PropagationPolicy: &propagationPolicy}
+	switch {
+	case job.Status.Succeeded > 0:
+		if err := jobs.Delete(ctx, egressCheckJobName, deleteOptions); err != nil && !apierrors.IsNotFound(err) {
+			return azure.WithTransientError(errors.Wrap(err, "failed to delete the succeeded egress check job"), 20*time.Second)
+		}
+		conditions.MarkTrue(s.ControlPlane, infrav1.EgressVerifiedCondition)
+		return nil
+	case jobConditionTrue(job, batchv1.JobFailed):
+		conditions.MarkFalse(s.ControlPlane, infrav1.EgressVerifiedCondition, infrav1.EgressCheckFailedReason, clusterv1.ConditionSeverityError, "egress to %s failed, check the routes and firewall rules of the node subnet", check.URL)
+		if err := jobs.Delete(ctx, egressCheckJobName, deleteOptions); err != nil && !apierrors.IsNotFound(err) {
+			return azure.WithTransientError(errors.Wrap(err, "failed to delete the failed egress check job"), 20*time.Second)
+		}
+		return azure.WithTransientError(errors.Errorf("egress check job failed to verify egress to %s", check.URL), time.Minute)
+	default:
+		conditions.MarkFalse(s.ControlPlane, infrav1.EgressVerifiedCondition, infrav1.EgressCheckRunningReason, clusterv1.ConditionSeverityInfo, "verifying egress to %s", check.URL)
+		return azure.WithTransientError(errors.Errorf("waiting for the egress check job to verify egress to %s", check.URL), 15*time.Second)
+	}
+}
+
+// egressCheckJob returns the Job that fetches the URL of the egress check.
+func egressCheckJob(check *infrav1exp.EgressCheck) *batchv1.Job {
+	image := defaultEgressCheckImage
+	if check.Image != nil {
+		image = *check.Image
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      egressCheckJobName,
+			Namespace: metav1.NamespaceSystem,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: to.Int32Ptr(2),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					NodeSelector: map[string]string{
+						corev1.LabelOSStable: "linux",
+					},
+					Containers: []corev1.Container{
+						{
+							Name:    "egress-check",
+							Image:   image,
+							Command: []string{"curl", "--fail", "--silent", "--show-error", "--max-time", "30", "--output", "/dev/null", check.URL},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// jobConditionTrue returns true if the job has a condition of the given type with status True.
+func jobConditionTrue(job *batchv1.Job, conditionType batchv1.JobConditionType) bool {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == conditionType {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// startupTaintString returns the startup taint in the form AKS expects agent pool node taints in.
+func startupTaintString(taint *infrav1exp.StartupTaint) string {
+	if taint.Value == "" {
+		return fmt.Sprintf("%s:%s", taint.Key, corev1.TaintEffectNoSchedule)
+	}
+	return fmt.Sprintf("%s=%s:%s", taint.Key, taint.Value, corev1.TaintEffectNoSchedule)
+}
+
+// agentPoolNodeTaints returns the taints CAPZ applies to new nodes of the agent pool, or nil if there are none.
+func agentPoolNodeTaints(pool *infrav1exp.AzureManagedMachinePool) []string {
+	var nodeTaints []string
+	if pool.Spec.StartupTaint != nil {
+		nodeTaints = append(nodeTaints, startupTaintString(pool.Spec.StartupTaint))
+	}
+	for _, taint := range pool.Spec.Taints {
+		nodeTaints = append(nodeTaints, taintString(taint))
+	}
+	return nodeTaints
+}
+
+// taintString returns the taint in the form AKS expects agent pool node taints in.
+func taintString(taint infrav1exp.Taint) string {
+	if taint.Value == "" {
+		return fmt.Sprintf("%s:%s", taint.Key, taint.Effect)
+	}
+	return fmt.Sprintf("%s=%s:%s", taint.Key, taint.Value, taint.Effect)
+}
+
+// agentPoolNodeLabels returns the node labels CAPZ applies to the agent pool, or nil if there are none.
+func agentPoolNodeLabels(pool *infrav1exp.AzureManagedMachinePool) map[string]string {
+	var nodeLabels map[string]string
+	if len(pool.Spec.NodeLabels) > 0 || pool.Spec.GPUSharing != nil {
+		nodeLabels = make(map[string]string, len(pool.Spec.NodeLabels)+1)
+	}
+	for k, v := range pool.Spec.NodeLabels {
+		nodeLabels[k] = v
+	}
+	if pool.Spec.GPUSharing != nil {
+		for k, v := range gpuSharingNodeLabels(pool.Spec.GPUSharing) {
+			nodeLabels[k] = v
+		}
+	}
+	return nodeLabels
+}
+
+// removedAgentPoolNodeLabels returns the sorted keys of the node labels CAPZ applied to the agent pool, as recorded in
+// its node labels annotation, that CAPZ no longer applies.
+func removedAgentPoolNodeLabels(pool *infrav1exp.AzureManagedMachinePool) []string {
+	applied, ok := pool.Annotations[infrav1exp.AgentPoolNodeLabelsAnnotation]
+	if !ok || applied == "" {
+		return nil
+	}
+
+	nodeLabels := agentPoolNodeLabels(pool)
+	var removed []string
+	for _, key := range strings.Split(applied, ",") {
+		if _, ok := nodeLabels[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	sort.Strings(removed)
+	return removed
+}
+
+// gpuSharingNodeLabels returns the node labels that select the GPU operator device plugin configuration for the GPU sharing.
+func gpuSharingNodeLabels(sharing *infrav1exp.GPUSharing) map[string]string {
+	return map[string]string{
+		infrav1exp.GPUDevicePluginConfigLabel: fmt.Sprintf("time-slicing-%d", sharing.TimeSlicingReplicas),
+	}
+}
+
+// withoutStartupTaint returns the taints without the NoSchedule taint with the given key, and whether it was found.
+func withoutStartupTaint(taints []corev1.Taint, key string) ([]corev1.Taint, bool) {
+	found := false
+	result := []corev1.Taint{}
+	for _, taint := range taints {
+		if taint.Key == key && taint.Effect == corev1.TaintEffectNoSchedule {
+			found = true
+			continue
+		}
+		result = append(result, taint)
+	}
+	return result, found
+}
+
+// nodeConditionTrue returns true if the node has a condition of the given type with status True.
+func nodeConditionTrue(node *corev1.Node, conditionType corev1.NodeConditionType) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == conditionType {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func (s *ManagedControlPlaneScope) getWorkloadKubeClient(ctx context.Context) (kubernetes.Interface, error) {
+	if s.workloadKubeClient != nil {
+		return s.workloadKubeClient, nil
+	}
+
+	restConfig, err := remote.RESTConfig(ctx, ManagedControlPlaneScopeName, s.Client, client.ObjectKey{
+		Name:      s.ClusterName(),
+		Namespace: s.Cluster.Namespace,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return kubernetes.NewForConfig(restConfig)
+}
+
 // SetControlPlaneEndpoint sets a control plane endpoint.
 func (s *ManagedControlPlaneScope) SetControlPlaneEndpoint(endpoint clusterv1.APIEndpoint) {
 	s.ControlPlane.Spec.ControlPlaneEndpoint = endpoint
 }
 
-// MakeEmptyKubeConfigSecret creates an empty secret object that is used for storing kubeconfig secret data.
-func (s *ManagedControlPlaneScope) MakeEmptyKubeConfigSecret() corev1.Secret {
-	return corev1.Secret{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      secret.Name(s.Cluster.Name, secret.Kubeconfig),
-			Namespace: s.Cluster.Namespace,
-			OwnerReferences: []metav1.OwnerReference{
-				*metav1.NewControllerRef(s.ControlPlane, infrav1exp.GroupVersion.WithKind("AzureManagedControlPlane")),
+// MakeEmptyKubeConfigSecrets creates the empty secret objects that are used for storing kubeconfig secret data, with
+// the labels and annotations configured for them. The first secret is the one Cluster API relies on.
+func (s *ManagedControlPlaneScope) MakeEmptyKubeConfigSecrets() []corev1.Secret {
+	names := []string{secret.Name(s.Cluster.Name, secret.Kubeconfig)}
+	var labels, annotations map[string]string
+	if kubeconfigSecret := s.ControlPlane.Spec.KubeconfigSecret; kubeconfigSecret != nil {
+		if kubeconfigSecret.Name != nil && *kubeconfigSecret.Name != names[0] {
+			names = append(names, *kubeconfigSecret.Name)
+		}
+		labels = kubeconfigSecret.Labels
+		annotations = kubeconfigSecret.Annotations
+	}
+
+	secrets := make([]corev1.Secret, 0, len(names))
+	for _, name := range names {
+		secrets = append(secrets, corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   s.Cluster.Namespace,
+				Labels:      labels,
+				Annotations: annotations,
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(s.ControlPlane, infrav1exp.GroupVersion.WithKind("AzureManagedControlPlane")),
+				},
 			},
-		},
+		})
 	}
+	return secrets
 }
 
-// GetKubeConfigData returns a []byte that contains kubeconfig.
-func (s *ManagedControlPlaneScope) GetKubeConfigData() []byte {
-	return s.kubeConfigData
+// GetAdminKubeconfig fetches the admin kubeconfig of the managed cluster. The admin kubeconfig holds static
+// credentials, which AKS doesn't issue when local accounts are disabled.
+func (s *ManagedControlPlaneScope) GetAdminKubeconfig(ctx context.Context) ([]byte, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.ManagedControlPlaneScope.GetAdminKubeconfig")
+	defer done()
+
+	if s.AreLocalAccountsDisabled() {
+		return nil, errors.Errorf("the admin kubeconfig of managed cluster %s is not available as its local accounts are disabled", s.ControlPlane.Name)
+	}
+
+	if s.ManagedClustersClient == nil {
+		return nil, errors.New("no managed clusters client to get the admin kubeconfig with")
+	}
+
+	kubeconfig, err := s.ManagedClustersClient.GetCredentials(ctx, s.ResourceGroup(), s.ControlPlane.Name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the admin kubeconfig of managed cluster %s", s.ControlPlane.Name)
+	}
+	return kubeconfig, nil
 }
 
-// SetKubeConfigData sets kubeconfig data.
-func (s *ManagedControlPlaneScope) SetKubeConfigData(kubeConfigData []byte) {
-	s.kubeConfigData = kubeConfigData
+// GetUserKubeconfig fetches the user kubeconfig of the managed cluster, which is available whether or not local accounts
+// are disabled.
+func (s *ManagedControlPlaneScope) GetUserKubeconfig(ctx context.Context) ([]byte, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.ManagedControlPlaneScope.GetUserKubeconfig")
+	defer done()
+
+	if s.ManagedClustersClient == nil {
+		return nil, errors.New("no managed clusters client to get the user kubeconfig with")
+	}
+
+	kubeconfig, err := s.ManagedClustersClient.GetUserCredentials(ctx, s.ResourceGroup(), s.ControlPlane.Name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the user kubeconfig of managed cluster %s", s.ControlPlane.Name)
+	}
+	return kubeconfig, nil
 }
 
 // SetLongRunningOperationState will set the future on the AzureManagedControlPlane status to allow the resource to continue
@@ -611,17 +1913,44 @@ func (s *ManagedControlPlaneScope) DeleteLongRunningOperationState(name, service
 
 // UpdateDeleteStatus updates a condition on the AzureManagedControlPlane status after a DELETE operation.
 func (s *ManagedControlPlaneScope) UpdateDeleteStatus(condition clusterv1.ConditionType, service string, err error) {
-	// TODO: add condition to AzureManagedControlPlane status
+	switch {
+	case err == nil:
+		conditions.MarkFalse(s.ControlPlane, condition, infrav1.DeletedReason, clusterv1.ConditionSeverityInfo, "%s successfully deleted", service)
+	case errors.Is(err, azure.ErrNotOwned):
+		// do nothing
+	case azure.IsOperationNotDoneError(err):
+		conditions.MarkFalse(s.ControlPlane, condition, infrav1.DeletingReason, clusterv1.ConditionSeverityInfo, "%s deleting", service)
+	default:
+		conditions.MarkFalse(s.ControlPlane, condition, infrav1.DeletionFailedReason, clusterv1.ConditionSeverityError, "%s failed to delete. err: %s", service, err.Error())
+	}
 }
 
 // UpdatePutStatus updates a condition on the AzureManagedControlPlane status after a PUT operation.
 func (s *ManagedControlPlaneScope) UpdatePutStatus(condition clusterv1.ConditionType, service string, err error) {
-	// TODO: add condition to AzureManagedControlPlane status
+	switch {
+	case err == nil:
+		conditions.MarkTrue(s.ControlPlane, condition)
+	case errors.Is(err, azure.ErrNotOwned):
+		// do nothing
+	case azure.IsOperationNotDoneError(err):
+		conditions.MarkFalse(s.ControlPlane, condition, infrav1.CreatingReason, clusterv1.ConditionSeverityInfo, "%s creating or updating", service)
+	default:
+		conditions.MarkFalse(s.ControlPlane, condition, infrav1.FailedReason, clusterv1.ConditionSeverityError, "%s failed to create or update. err: %s", service, err.Error())
+	}
 }
 
 // UpdatePatchStatus updates a condition on the AzureManagedControlPlane status after a PATCH operation.
 func (s *ManagedControlPlaneScope) UpdatePatchStatus(condition clusterv1.ConditionType, service string, err error) {
-	// TODO: add condition to AzureManagedControlPlane status
+	switch {
+	case err == nil:
+		conditions.MarkTrue(s.ControlPlane, condition)
+	case errors.Is(err, azure.ErrNotOwned):
+		// do nothing
+	case azure.IsOperationNotDoneError(err):
+		conditions.MarkFalse(s.ControlPlane, condition, infrav1.UpdatingReason, clusterv1.ConditionSeverityInfo, "%s updating", service)
+	default:
+		conditions.MarkFalse(s.ControlPlane, condition, infrav1.FailedReason, clusterv1.ConditionSeverityError, "%s failed to update. err: %s", service, err.Error())
+	}
 }
 
 // AnnotationJSON returns a map[string]interface from a JSON annotation.
@@ -667,5 +1996,25 @@ func (s *ManagedControlPlaneScope) TagsSpecs() []azure.TagsSpec {
 			Tags:       s.AdditionalTags(),
 			Annotation: infrav1.RGTagsLastAppliedAnnotation,
 		},
+		{
+			Scope:      azure.ResourceGroupID(s.SubscriptionID(), s.NodeResourceGroup()),
+			Tags:       s.ownedTags(s.NodeResourceGroup()),
+			Annotation: infrav1.NodeRGTagsLastAppliedAnnotation,
+		},
+		{
+			Scope:      azure.ManagedClusterID(s.SubscriptionID(), s.ResourceGroup(), s.ControlPlane.Name),
+			Tags:       s.ownedTags(s.ControlPlane.Name),
+			Annotation: infrav1.ManagedClusterTagsLastAppliedAnnotation,
+		},
 	}
 }
+
+// ownedTags returns the additional tags together with the tags that identify the named resource as owned by the cluster.
+func (s *ManagedControlPlaneScope) ownedTags(name string) infrav1.Tags {
+	return infrav1.Build(infrav1.BuildParams{
+		ClusterName: s.ClusterName(),
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        to.StringPtr(name),
+		Additional:  s.AdditionalTags(),
+	})
+}
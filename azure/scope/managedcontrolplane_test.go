@@ -0,0 +1,2132 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/klog/v2/klogr"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/managedclusters/mock_managedclusters"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+// newTestManagedControlPlaneScope returns a scope of the AzureManagedControlPlane my-cluster-control-plane of the
+// cluster my-cluster, after applying the overrides to it.
+func newTestManagedControlPlaneScope(overrides ...func(*ManagedControlPlaneScope)) *ManagedControlPlaneScope {
+	s := &ManagedControlPlaneScope{
+		Logger: klogr.New(),
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-cluster",
+				Namespace: "default",
+			},
+		},
+		ControlPlane: &infrav1exp.AzureManagedControlPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-cluster-control-plane",
+				Namespace: "default",
+			},
+			Spec: infrav1exp.AzureManagedControlPlaneSpec{
+				SubscriptionID:    "00000000-0000-0000-0000-000000000000",
+				ResourceGroupName: "my-rg",
+				Location:          "westus2",
+				Version:           "v1.21.2",
+				VirtualNetwork: infrav1exp.ManagedControlPlaneVirtualNetwork{
+					Name: "my-vnet",
+					Subnet: infrav1exp.ManagedControlPlaneSubnet{
+						Name: "my-subnet",
+					},
+				},
+			},
+		},
+	}
+	for _, override := range overrides {
+		override(s)
+	}
+	return s
+}
+
+func TestManagedControlPlaneScope_ManagedClusterSpec(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+		s.ControlPlane.Spec.NodeResourceGroupName = "my-node-rg"
+		s.ControlPlane.Spec.NetworkPlugin = pointer.String("azure")
+	})
+
+	got, err := s.ManagedClusterSpec()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got).To(Equal(azure.ManagedClusterSpec{
+		Name:                  "my-cluster-control-plane",
+		ResourceGroupName:     "my-rg",
+		NodeResourceGroupName: "my-node-rg",
+		DNSPrefix:             "my-cluster-control-plane",
+		IdentityType:          "SystemAssigned",
+		VnetSubnetID:          "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet",
+		Location:              "westus2",
+		Tags: map[string]string{
+			"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
+			"Name": "my-cluster-control-plane",
+		},
+		Version:       "1.21.2",
+		NetworkPlugin: "azure",
+		SKU:           &azure.SKU{Tier: infrav1exp.SKUTierFree},
+	}))
+}
+
+func TestManagedControlPlaneScope_NodeResourceGroup(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+		s.ControlPlane.Spec.NodeResourceGroupName = "my-pinned-node-rg"
+	})
+
+	g.Expect(s.NodeResourceGroup()).To(Equal("my-pinned-node-rg"))
+	got, err := s.ManagedClusterSpec()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got.NodeResourceGroupName).To(Equal("my-pinned-node-rg"))
+}
+
+func TestManagedControlPlaneScope_RoleAssignmentSpecs(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestManagedControlPlaneScope()
+	g.Expect(s.RoleAssignmentSpecs()).To(BeEmpty())
+
+	s = newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+		s.ControlPlane.UID = "2d9a7b2a-3f3e-4b5e-9c8e-1f1b7c9f0a11"
+		s.ControlPlane.Spec.NodeResourceGroupRoleAssignmentPrincipalIDs = []string{"00000000-0000-0000-0000-000000000001"}
+	})
+	g.Expect(s.RoleAssignmentSpecs()).To(Equal([]azure.RoleAssignmentSpec{
+		{
+			Name:                "2d9a7b2a-3f3e-4b5e-9c8e-1f1b7c9f0a11",
+			PrincipalIDs:        []string{"00000000-0000-0000-0000-000000000001"},
+			AtNodeResourceGroup: true,
+		},
+	}))
+}
+
+func TestManagedControlPlaneScope_DiskEncryptionSetID(t *testing.T) {
+	g := NewWithT(t)
+	desID := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/my-rg/providers/Microsoft.Compute/diskEncryptionSets/my-des"
+	s := newTestManagedControlPlaneScope()
+
+	got, err := s.ManagedClusterSpec()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got.DiskEncryptionSetID).To(BeNil())
+
+	s.ControlPlane.Spec.DiskEncryptionSetID = pointer.String(desID)
+	got, err = s.ManagedClusterSpec()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got.DiskEncryptionSetID).To(Equal(pointer.String(desID)))
+}
+
+// TestManagedControlPlaneScope_DNSPrefix only covers valid DNS prefixes: the scope passes the DNS prefix through as is,
+// and invalid DNS prefixes are rejected by the AzureManagedControlPlane webhook, whose tests cover them.
+func TestManagedControlPlaneScope_DNSPrefix(t *testing.T) {
+	tests := []struct {
+		name      string
+		dnsPrefix *string
+		expect    string
+	}{
+		{
+			name:   "DNS prefix defaults to the name of the control plane",
+			expect: "my-cluster-control-plane",
+		},
+		{
+			name:      "explicit DNS prefix",
+			dnsPrefix: pointer.String("my-cluster-westus2"),
+			expect:    "my-cluster-westus2",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			s := newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+				s.ControlPlane.Spec.DNSPrefix = tt.dnsPrefix
+			})
+
+			g.Expect(s.DNSPrefix()).To(Equal(tt.expect))
+			got, err := s.ManagedClusterSpec()
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got.DNSPrefix).To(Equal(tt.expect))
+		})
+	}
+}
+
+func TestManagedControlPlaneScope_ManagedClusterSpecSKU(t *testing.T) {
+	tests := []struct {
+		name string
+		sku  *infrav1exp.SKU
+		want *azure.SKU
+	}{
+		{
+			name: "defaults to the Free tier when unset",
+			sku:  nil,
+			want: &azure.SKU{Tier: infrav1exp.SKUTierFree},
+		},
+		{
+			name: "uses the Paid tier when set",
+			sku:  &infrav1exp.SKU{Tier: infrav1exp.SKUTierPaid},
+			want: &azure.SKU{Tier: infrav1exp.SKUTierPaid},
+		},
+		{
+			name: "uses the Standard tier when set",
+			sku:  &infrav1exp.SKU{Tier: infrav1exp.SKUTierStandard},
+			want: &azure.SKU{Tier: infrav1exp.SKUTierStandard},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+				s.ControlPlane.Spec.SKU = tt.sku
+			})
+			got, err := s.ManagedClusterSpec()
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got.SKU).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestManagedControlPlaneScope_ManagedClusterSpecOutbound(t *testing.T) {
+	tests := []struct {
+		name                    string
+		outboundType            *string
+		loadBalancerProfile     *infrav1exp.LoadBalancerProfile
+		wantOutboundType        string
+		wantLoadBalancerProfile *azure.LoadBalancerProfile
+	}{
+		{
+			name: "managed outbound IPs",
+			loadBalancerProfile: &infrav1exp.LoadBalancerProfile{
+				ManagedOutboundIPs:     pointer.Int32(3),
+				AllocatedOutboundPorts: pointer.Int32(1000),
+			},
+			wantLoadBalancerProfile: &azure.LoadBalancerProfile{
+				ManagedOutboundIPs:     pointer.Int32(3),
+				AllocatedOutboundPorts: pointer.Int32(1000),
+			},
+		},
+		{
+			name:             "user-defined routing",
+			outboundType:     pointer.String(infrav1exp.OutboundTypeUserDefinedRouting),
+			wantOutboundType: infrav1exp.OutboundTypeUserDefinedRouting,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+				s.ControlPlane.Spec.OutboundType = tt.outboundType
+				s.ControlPlane.Spec.LoadBalancerProfile = tt.loadBalancerProfile
+			})
+			got, err := s.ManagedClusterSpec()
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got.OutboundType).To(Equal(tt.wantOutboundType))
+			g.Expect(got.LoadBalancerProfile).To(Equal(tt.wantLoadBalancerProfile))
+		})
+	}
+}
+
+func TestManagedControlPlaneScope_OwnershipTags(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+		s.ControlPlane.Spec.NodeResourceGroupName = "my-node-rg"
+		s.ControlPlane.Spec.AdditionalTags = infrav1.Tags{
+			"environment": "test",
+		}
+	})
+
+	got, err := s.ManagedClusterSpec()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got.Tags).To(BeEquivalentTo(infrav1.Tags{
+		"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
+		"Name":        "my-cluster-control-plane",
+		"environment": "test",
+	}))
+
+	tagsSpecs := s.TagsSpecs()
+	g.Expect(tagsSpecs).To(HaveLen(3))
+	g.Expect(tagsSpecs[1].Scope).To(Equal("/subscriptions//resourceGroups/my-node-rg"))
+	g.Expect(tagsSpecs[1].Annotation).To(Equal(infrav1.NodeRGTagsLastAppliedAnnotation))
+	g.Expect(tagsSpecs[1].Tags).To(Equal(infrav1.Tags{
+		"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
+		"Name":        "my-node-rg",
+		"environment": "test",
+	}))
+	g.Expect(tagsSpecs[2].Scope).To(Equal("/subscriptions//resourceGroups/my-rg/providers/Microsoft.ContainerService/managedClusters/my-cluster-control-plane"))
+	g.Expect(tagsSpecs[2].Annotation).To(Equal(infrav1.ManagedClusterTagsLastAppliedAnnotation))
+	g.Expect(tagsSpecs[2].Tags).To(BeEquivalentTo(got.Tags))
+}
+
+func TestManagedControlPlaneScope_AADProfile(t *testing.T) {
+	tests := []struct {
+		name       string
+		aadProfile *infrav1exp.AADProfile
+		expected   *azure.AADProfile
+	}{
+		{
+			name:       "without AAD",
+			aadProfile: nil,
+			expected:   nil,
+		},
+		{
+			name: "managed AAD with admin groups",
+			aadProfile: &infrav1exp.AADProfile{
+				Managed:             true,
+				EnableAzureRBAC:     pointer.Bool(false),
+				AdminGroupObjectIDs: []string{"917056a9-8eb5-439c-g679-b34901ade75h"},
+			},
+			expected: &azure.AADProfile{
+				Managed:             true,
+				EnableAzureRBAC:     false,
+				AdminGroupObjectIDs: []string{"917056a9-8eb5-439c-g679-b34901ade75h"},
+			},
+		},
+		{
+			name: "managed AAD with Azure RBAC",
+			aadProfile: &infrav1exp.AADProfile{
+				Managed:         true,
+				EnableAzureRBAC: pointer.Bool(true),
+				TenantID:        "72f988bf-86f1-41af-91ab-2d7cd011db47",
+			},
+			expected: &azure.AADProfile{
+				Managed:         true,
+				EnableAzureRBAC: true,
+				TenantID:        "72f988bf-86f1-41af-91ab-2d7cd011db47",
+			},
+		},
+		{
+			name: "managed AAD enables Azure RBAC by default",
+			aadProfile: &infrav1exp.AADProfile{
+				Managed:             true,
+				AdminGroupObjectIDs: []string{"917056a9-8eb5-439c-g679-b34901ade75h"},
+			},
+			expected: &azure.AADProfile{
+				Managed:             true,
+				EnableAzureRBAC:     true,
+				AdminGroupObjectIDs: []string{"917056a9-8eb5-439c-g679-b34901ade75h"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			s := newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+				s.ControlPlane.Spec.AADProfile = tt.aadProfile
+			})
+
+			g.Expect(s.AADProfile()).To(Equal(tt.expected))
+			got, err := s.ManagedClusterSpec()
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got.AADProfile).To(Equal(tt.expected))
+		})
+	}
+}
+
+func TestManagedControlPlaneScope_DisableLocalAccounts(t *testing.T) {
+	tests := []struct {
+		name                 string
+		disableLocalAccounts *bool
+		expectDisabled       bool
+		expectSpec           *bool
+	}{
+		{
+			name:           "local accounts are enabled by default",
+			expectDisabled: false,
+			expectSpec:     nil,
+		},
+		{
+			name:                 "local accounts are disabled",
+			disableLocalAccounts: pointer.Bool(true),
+			expectDisabled:       true,
+			expectSpec:           pointer.Bool(true),
+		},
+		{
+			name:                 "local accounts are explicitly enabled",
+			disableLocalAccounts: pointer.Bool(false),
+			expectDisabled:       false,
+			expectSpec:           pointer.Bool(false),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			s := newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+				s.ControlPlane.Spec.DisableLocalAccounts = tt.disableLocalAccounts
+			})
+
+			g.Expect(s.AreLocalAccountsDisabled()).To(Equal(tt.expectDisabled))
+			got, err := s.ManagedClusterSpec()
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got.DisableLocalAccounts).To(Equal(tt.expectSpec))
+		})
+	}
+}
+
+func TestManagedControlPlaneScope_Kubeconfigs(t *testing.T) {
+	tests := []struct {
+		name                 string
+		disableLocalAccounts *bool
+		expect               func(m *mock_managedclusters.MockClientMockRecorder)
+		expectAdmin          string
+		expectAdminErr       string
+	}{
+		{
+			name: "admin and user kubeconfigs are fetched from the managed cluster",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder) {
+				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-cluster-control-plane").Return([]byte("admin-kubeconfig"), nil)
+				m.GetUserCredentials(gomockinternal.AContext(), "my-rg", "my-cluster-control-plane").Return([]byte("user-kubeconfig"), nil)
+			},
+			expectAdmin: "admin-kubeconfig",
+		},
+		{
+			name:                 "only the user kubeconfig is available when local accounts are disabled",
+			disableLocalAccounts: pointer.Bool(true),
+			expect: func(m *mock_managedclusters.MockClientMockRecorder) {
+				m.GetUserCredentials(gomockinternal.AContext(), "my-rg", "my-cluster-control-plane").Return([]byte("user-kubeconfig"), nil)
+			},
+			expectAdminErr: "the admin kubeconfig of managed cluster my-cluster-control-plane is not available as its local accounts are disabled",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			clientMock := mock_managedclusters.NewMockClient(mockCtrl)
+			tt.expect(clientMock.EXPECT())
+			s := newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+				s.ControlPlane.Spec.DisableLocalAccounts = tt.disableLocalAccounts
+				s.ManagedClustersClient = clientMock
+			})
+
+			admin, err := s.GetAdminKubeconfig(context.TODO())
+			if tt.expectAdminErr != "" {
+				g.Expect(err).To(MatchError(tt.expectAdminErr))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(string(admin)).To(Equal(tt.expectAdmin))
+			}
+			user, err := s.GetUserKubeconfig(context.TODO())
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(string(user)).To(Equal("user-kubeconfig"))
+		})
+	}
+}
+
+func TestManagedControlPlaneScope_AutoScalerProfile(t *testing.T) {
+	tests := []struct {
+		name       string
+		profile    *infrav1exp.AutoScalerProfile
+		expectSpec *azure.AutoScalerProfile
+	}{
+		{
+			name:       "AKS defaults are kept by default",
+			expectSpec: nil,
+		},
+		{
+			name: "autoscaler profile is set",
+			profile: &infrav1exp.AutoScalerProfile{
+				Expander:                      pointer.String("least-waste"),
+				ScaleDownDelayAfterAdd:        pointer.String("5m"),
+				ScaleDownUtilizationThreshold: pointer.String("0.6"),
+			},
+			expectSpec: &azure.AutoScalerProfile{
+				Expander:                      pointer.String("least-waste"),
+				ScaleDownDelayAfterAdd:        pointer.String("5m"),
+				ScaleDownUtilizationThreshold: pointer.String("0.6"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			s := newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+				s.ControlPlane.Spec.AutoScalerProfile = tt.profile
+			})
+
+			got, err := s.ManagedClusterSpec()
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got.AutoScalerProfile).To(Equal(tt.expectSpec))
+		})
+	}
+}
+
+func TestManagedControlPlaneScope_AddonProfiles(t *testing.T) {
+	tests := []struct {
+		name       string
+		addons     []infrav1exp.AddonProfile
+		expectSpec []azure.AddonProfile
+	}{
+		{
+			name:       "add-ons are left to AKS by default",
+			expectSpec: nil,
+		},
+		{
+			name: "HTTP application routing is enabled",
+			addons: []infrav1exp.AddonProfile{
+				{Name: "httpApplicationRouting", Enabled: true},
+			},
+			expectSpec: []azure.AddonProfile{
+				{Name: "httpApplicationRouting", Enabled: true},
+			},
+		},
+		{
+			name: "add-on with config",
+			addons: []infrav1exp.AddonProfile{
+				{Name: "azureKeyvaultSecretsProvider", Enabled: true, Config: map[string]string{"enableSecretRotation": "true"}},
+				{Name: "httpApplicationRouting", Enabled: false},
+			},
+			expectSpec: []azure.AddonProfile{
+				{Name: "azureKeyvaultSecretsProvider", Enabled: true, Config: map[string]string{"enableSecretRotation": "true"}},
+				{Name: "httpApplicationRouting", Enabled: false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			s := newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+				s.ControlPlane.Spec.AddonProfiles = tt.addons
+			})
+
+			got, err := s.ManagedClusterSpec()
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got.AddonProfiles).To(Equal(tt.expectSpec))
+		})
+	}
+}
+
+func TestManagedControlPlaneScope_VerifyEgress(t *testing.T) {
+	g := NewWithT(t)
+	kubeClient := fake.NewSimpleClientset()
+	s := newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+		s.ControlPlane.Spec.OutboundType = pointer.String(infrav1exp.OutboundTypeUserDefinedRouting)
+		s.ControlPlane.Spec.EgressCheck = &infrav1exp.EgressCheck{
+			URL: "https://mcr.microsoft.com",
+		}
+		s.workloadKubeClient = kubeClient
+	})
+
+	var reconcileError azure.ReconcileError
+
+	// The control plane isn't ready while the egress check Job runs.
+	err := s.VerifyEgress(context.TODO())
+	g.Expect(errors.As(err, &reconcileError)).To(BeTrue())
+	g.Expect(reconcileError.IsTransient()).To(BeTrue())
+	g.Expect(conditions.GetReason(s.ControlPlane, infrav1.EgressVerifiedCondition)).To(Equal(infrav1.EgressCheckRunningReason))
+	job, err := kubeClient.BatchV1().Jobs(metav1.NamespaceSystem).Get(context.TODO(), egressCheckJobName, metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(job.Spec.Template.Spec.Containers[0].Image).To(Equal(defaultEgressCheckImage))
+	g.Expect(job.Spec.Template.Spec.Containers[0].Command).To(ContainElement("https://mcr.microsoft.com"))
+
+	// A failed Job is reported and deleted to be run again.
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}
+	_, err = kubeClient.BatchV1().Jobs(metav1.NamespaceSystem).UpdateStatus(context.TODO(), job, metav1.UpdateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	err = s.VerifyEgress(context.TODO())
+	g.Expect(errors.As(err, &reconcileError)).To(BeTrue())
+	g.Expect(reconcileError.IsTransient()).To(BeTrue())
+	g.Expect(conditions.GetReason(s.ControlPlane, infrav1.EgressVerifiedCondition)).To(Equal(infrav1.EgressCheckFailedReason))
+	_, err = kubeClient.BatchV1().Jobs(metav1.NamespaceSystem).Get(context.TODO(), egressCheckJobName, metav1.GetOptions{})
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+	// The control plane is ready once the egress check Job succeeds, and the Job is deleted.
+	g.Expect(s.VerifyEgress(context.TODO())).NotTo(Succeed())
+	job, err = kubeClient.BatchV1().Jobs(metav1.NamespaceSystem).Get(context.TODO(), egressCheckJobName, metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	job.Status.Succeeded = 1
+	_, err = kubeClient.BatchV1().Jobs(metav1.NamespaceSystem).UpdateStatus(context.TODO(), job, metav1.UpdateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(s.VerifyEgress(context.TODO())).To(Succeed())
+	g.Expect(conditions.IsTrue(s.ControlPlane, infrav1.EgressVerifiedCondition)).To(BeTrue())
+	_, err = kubeClient.BatchV1().Jobs(metav1.NamespaceSystem).Get(context.TODO(), egressCheckJobName, metav1.GetOptions{})
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+}
+
+func TestManagedControlPlaneScope_DrainAgentPoolNodes(t *testing.T) {
+	tests := []struct {
+		name              string
+		nodeDrainTimeout  *metav1.Duration
+		deletionTimestamp *metav1.Time
+		blockPodDeletion  bool
+		expectCordoned    bool
+		expectErr         bool
+	}{
+		{
+			name:           "does not cordon nodes when NodeDrainTimeout is unset",
+			expectCordoned: false,
+		},
+		{
+			name:              "cordons and drains nodes when NodeDrainTimeout is set",
+			nodeDrainTimeout:  &metav1.Duration{Duration: 10 * time.Minute},
+			deletionTimestamp: &metav1.Time{Time: time.Now()},
+			expectCordoned:    true,
+		},
+		{
+			name:              "waits for the nodes to be drained until NodeDrainTimeout has elapsed",
+			nodeDrainTimeout:  &metav1.Duration{Duration: 10 * time.Minute},
+			deletionTimestamp: &metav1.Time{Time: time.Now()},
+			blockPodDeletion:  true,
+			expectCordoned:    true,
+			expectErr:         true,
+		},
+		{
+			name:              "stops waiting for the nodes to be drained once NodeDrainTimeout has elapsed",
+			nodeDrainTimeout:  &metav1.Duration{Duration: 10 * time.Minute},
+			deletionTimestamp: &metav1.Time{Time: time.Now().Add(-11 * time.Minute)},
+			blockPodDeletion:  true,
+			expectCordoned:    false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			kubeClient := fake.NewSimpleClientset(
+				&corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "aks-pool1-12345678-vmss000000",
+						Labels: map[string]string{"agentpool": "pool1"},
+					},
+				},
+				&corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "aks-pool0-12345678-vmss000000",
+						Labels: map[string]string{"agentpool": "pool0"},
+					},
+				},
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "critical-workload",
+						Namespace: "default",
+					},
+					Spec: corev1.PodSpec{
+						NodeName: "aks-pool1-12345678-vmss000000",
+					},
+				},
+			)
+			if tt.blockPodDeletion {
+				kubeClient.PrependReactor("delete", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+					return true, nil, errors.New("cannot delete pod")
+				})
+			}
+
+			s := newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+				s.MachinePool = &expv1.MachinePool{}
+				s.InfraMachinePool = &infrav1exp.AzureManagedMachinePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              "pool1",
+						DeletionTimestamp: tt.deletionTimestamp,
+					},
+					Spec: infrav1exp.AzureManagedMachinePoolSpec{
+						Name:             pointer.StringPtr("pool1"),
+						Mode:             string(infrav1exp.NodePoolModeUser),
+						NodeDrainTimeout: tt.nodeDrainTimeout,
+					},
+				}
+				s.workloadKubeClient = kubeClient
+			})
+
+			err := s.DrainAgentPoolNodes(context.TODO())
+			if tt.expectErr {
+				var reconcileError azure.ReconcileError
+				g.Expect(errors.As(err, &reconcileError)).To(BeTrue())
+				g.Expect(reconcileError.IsTransient()).To(BeTrue())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+
+			node, err := kubeClient.CoreV1().Nodes().Get(context.TODO(), "aks-pool1-12345678-vmss000000", metav1.GetOptions{})
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(node.Spec.Unschedulable).To(Equal(tt.expectCordoned))
+
+			otherNode, err := kubeClient.CoreV1().Nodes().Get(context.TODO(), "aks-pool0-12345678-vmss000000", metav1.GetOptions{})
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(otherNode.Spec.Unschedulable).To(BeFalse())
+		})
+	}
+}
+
+func TestManagedControlPlaneScope_RemoveAgentPoolStartupTaints(t *testing.T) {
+	startupTaint := corev1.Taint{Key: "example.com/setup", Value: "pending", Effect: corev1.TaintEffectNoSchedule}
+	otherTaint := corev1.Taint{Key: "example.com/dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}
+
+	tests := []struct {
+		name                   string
+		readinessConditionType *string
+		conditions             []corev1.NodeCondition
+		expectTaints           []corev1.Taint
+		expectErr              bool
+	}{
+		{
+			name:         "keeps the startup taint while the node is not ready",
+			conditions:   []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionFalse}},
+			expectTaints: []corev1.Taint{startupTaint, otherTaint},
+			expectErr:    true,
+		},
+		{
+			name:         "removes the startup taint once the node is ready",
+			conditions:   []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+			expectTaints: []corev1.Taint{otherTaint},
+		},
+		{
+			name:                   "keeps the startup taint until the custom readiness condition is true",
+			readinessConditionType: pointer.StringPtr("SetupComplete"),
+			conditions:             []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+			expectTaints:           []corev1.Taint{startupTaint, otherTaint},
+			expectErr:              true,
+		},
+		{
+			name:                   "removes the startup taint once the custom readiness condition is true",
+			readinessConditionType: pointer.StringPtr("SetupComplete"),
+			conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+				{Type: "SetupComplete", Status: corev1.ConditionTrue},
+			},
+			expectTaints: []corev1.Taint{otherTaint},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			kubeClient := fake.NewSimpleClientset(
+				&corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "aks-pool1-12345678-vmss000000",
+						Labels: map[string]string{"agentpool": "pool1"},
+					},
+					Spec: corev1.NodeSpec{
+						Taints: []corev1.Taint{startupTaint, otherTaint},
+					},
+					Status: corev1.NodeStatus{
+						Conditions: tt.conditions,
+					},
+				},
+				&corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "aks-pool0-12345678-vmss000000",
+						Labels: map[string]string{"agentpool": "pool0"},
+					},
+					Spec: corev1.NodeSpec{
+						Taints: []corev1.Taint{startupTaint},
+					},
+					Status: corev1.NodeStatus{
+						Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+					},
+				},
+			)
+
+			s := newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+				s.MachinePool = &expv1.MachinePool{}
+				s.InfraMachinePool = &infrav1exp.AzureManagedMachinePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "pool1",
+					},
+					Spec: infrav1exp.AzureManagedMachinePoolSpec{
+						Name: pointer.StringPtr("pool1"),
+						Mode: string(infrav1exp.NodePoolModeUser),
+						StartupTaint: &infrav1exp.StartupTaint{
+							Key:                    startupTaint.Key,
+							Value:                  startupTaint.Value,
+							ReadinessConditionType: tt.readinessConditionType,
+						},
+					},
+				}
+				s.workloadKubeClient = kubeClient
+			})
+
+			agentPoolSpec, err := s.AgentPoolSpec(context.TODO())
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(agentPoolSpec.NodeTaints).To(Equal([]string{"example.com/setup=pending:NoSchedule"}))
+
+			err = s.RemoveAgentPoolStartupTaints(context.TODO())
+			if tt.expectErr {
+				var reconcileError azure.ReconcileError
+				g.Expect(errors.As(err, &reconcileError)).To(BeTrue())
+				g.Expect(reconcileError.IsTransient()).To(BeTrue())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+
+			node, err := kubeClient.CoreV1().Nodes().Get(context.TODO(), "aks-pool1-12345678-vmss000000", metav1.GetOptions{})
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(node.Spec.Taints).To(Equal(tt.expectTaints))
+
+			otherNode, err := kubeClient.CoreV1().Nodes().Get(context.TODO(), "aks-pool0-12345678-vmss000000", metav1.GetOptions{})
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(otherNode.Spec.Taints).To(Equal([]corev1.Taint{startupTaint}))
+		})
+	}
+}
+
+func TestManagedControlPlaneScope_RemoveAgentPoolNodeLabels(t *testing.T) {
+	g := NewWithT(t)
+	kubeClient := fake.NewSimpleClientset(
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "aks-pool1-12345678-vmss000000",
+				Labels: map[string]string{"agentpool": "pool1", "team": "a", "env": "prod"},
+			},
+		},
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "aks-pool0-12345678-vmss000000",
+				Labels: map[string]string{"agentpool": "pool0", "env": "prod"},
+			},
+		},
+	)
+
+	s := newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+		s.MachinePool = &expv1.MachinePool{}
+		s.InfraMachinePool = &infrav1exp.AzureManagedMachinePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "pool1",
+				Annotations: map[string]string{
+					infrav1exp.AgentPoolNodeLabelsAnnotation: "env,team",
+				},
+			},
+			Spec: infrav1exp.AzureManagedMachinePoolSpec{
+				Name: pointer.StringPtr("pool1"),
+				Mode: string(infrav1exp.NodePoolModeUser),
+				NodeLabels: map[string]string{
+					"team": "b",
+				},
+			},
+		}
+		s.workloadKubeClient = kubeClient
+	})
+
+	agentPoolSpec, err := s.AgentPoolSpec(context.TODO())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(agentPoolSpec.NodeLabels).To(Equal(map[string]string{"team": "b"}))
+	g.Expect(agentPoolSpec.RemovedNodeLabels).To(Equal([]string{"env"}))
+
+	g.Expect(s.RemoveAgentPoolNodeLabels(context.TODO())).To(Succeed())
+	g.Expect(s.InfraMachinePool.Annotations).To(HaveKeyWithValue(infrav1exp.AgentPoolNodeLabelsAnnotation, "team"))
+
+	node, err := kubeClient.CoreV1().Nodes().Get(context.TODO(), "aks-pool1-12345678-vmss000000", metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(node.Labels).To(Equal(map[string]string{"agentpool": "pool1", "team": "a"}))
+
+	otherNode, err := kubeClient.CoreV1().Nodes().Get(context.TODO(), "aks-pool0-12345678-vmss000000", metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(otherNode.Labels).To(Equal(map[string]string{"agentpool": "pool0", "env": "prod"}))
+}
+
+func TestManagedControlPlaneScope_AnnotateAgentPoolNodes(t *testing.T) {
+	g := NewWithT(t)
+	kubeClient := fake.NewSimpleClientset(
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "aks-pool1-12345678-vmss000000",
+				Labels:      map[string]string{"agentpool": "pool1"},
+				Annotations: map[string]string{"team": "a"},
+			},
+		},
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "aks-pool0-12345678-vmss000000",
+				Labels: map[string]string{"agentpool": "pool0"},
+			},
+		},
+	)
+
+	s := newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+		s.MachinePool = &expv1.MachinePool{}
+		s.InfraMachinePool = &infrav1exp.AzureManagedMachinePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "pool1",
+			},
+			Spec: infrav1exp.AzureManagedMachinePoolSpec{
+				Name: pointer.StringPtr("pool1"),
+				Mode: string(infrav1exp.NodePoolModeUser),
+				AutoscalerNodeAnnotations: map[string]string{
+					"cluster-autoscaler.kubernetes.io/scale-down-disabled": "true",
+				},
+			},
+		}
+		s.workloadKubeClient = kubeClient
+	})
+
+	g.Expect(s.AnnotateAgentPoolNodes(context.TODO())).To(Succeed())
+
+	node, err := kubeClient.CoreV1().Nodes().Get(context.TODO(), "aks-pool1-12345678-vmss000000", metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(node.Annotations).To(Equal(map[string]string{
+		"team": "a",
+		"cluster-autoscaler.kubernetes.io/scale-down-disabled": "true",
+	}))
+
+	otherNode, err := kubeClient.CoreV1().Nodes().Get(context.TODO(), "aks-pool0-12345678-vmss000000", metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(otherNode.Annotations).To(BeEmpty())
+}
+
+func TestManagedControlPlaneScope_AgentPoolSpecWindows(t *testing.T) {
+	tests := []struct {
+		name    string
+		pool    infrav1exp.AzureManagedMachinePoolSpec
+		want    azure.AgentPoolSpec
+		wantErr string
+	}{
+		{
+			name: "valid Windows agent pool",
+			pool: infrav1exp.AzureManagedMachinePoolSpec{
+				Name:   pointer.StringPtr("win1"),
+				Mode:   string(infrav1exp.NodePoolModeUser),
+				SKU:    "Standard_D2s_v3",
+				OSType: pointer.StringPtr(azure.WindowsOS),
+			},
+			want: azure.AgentPoolSpec{
+				Name:          "win1",
+				ResourceGroup: "my-rg",
+				Cluster:       "my-cluster-control-plane",
+				Version:       pointer.StringPtr("1.21.2"),
+				SKU:           "Standard_D2s_v3",
+				Replicas:      1,
+				VnetSubnetID:  "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet",
+				Mode:          string(infrav1exp.NodePoolModeUser),
+				OSType:        azure.WindowsOS,
+				CreateOnlyFields: []azure.AgentPoolField{
+					azure.AgentPoolSKU,
+					azure.AgentPoolOSDiskSizeGB,
+					azure.AgentPoolVnetSubnetID,
+					azure.AgentPoolPodSubnetID,
+					azure.AgentPoolOSType,
+					azure.AgentPoolOSDiskType,
+					azure.AgentPoolEnableEncryptionAtHost,
+					azure.AgentPoolProximityPlacementGroupID,
+					azure.AgentPoolEnableNodePublicIP,
+					azure.AgentPoolNodePublicIPPrefixID,
+					azure.AgentPoolScaleSetPriority,
+				},
+			},
+		},
+		{
+			name: "Windows agent pool name longer than 6 characters",
+			pool: infrav1exp.AzureManagedMachinePoolSpec{
+				Name:   pointer.StringPtr("windows"),
+				Mode:   string(infrav1exp.NodePoolModeUser),
+				SKU:    "Standard_D2s_v3",
+				OSType: pointer.StringPtr(azure.WindowsOS),
+			},
+			wantErr: `Windows agent pool name "windows" must be at most 6 characters long`,
+		},
+		{
+			name: "Windows system agent pool",
+			pool: infrav1exp.AzureManagedMachinePoolSpec{
+				Name:   pointer.StringPtr("win1"),
+				Mode:   string(infrav1exp.NodePoolModeSystem),
+				SKU:    "Standard_D2s_v3",
+				OSType: pointer.StringPtr(azure.WindowsOS),
+			},
+			wantErr: "Windows agent pool win1 must be in mode User, not System",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+				s.MachinePool = &expv1.MachinePool{}
+				s.InfraMachinePool = &infrav1exp.AzureManagedMachinePool{
+					Spec: tt.pool,
+				}
+			})
+			got, err := s.AgentPoolSpec(context.TODO())
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(tt.wantErr))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(got).To(Equal(tt.want))
+			}
+		})
+	}
+}
+
+func TestManagedControlPlaneScope_AgentPoolSpecOSDiskType(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+		s.MachinePool = &expv1.MachinePool{}
+		s.InfraMachinePool = &infrav1exp.AzureManagedMachinePool{
+			Spec: infrav1exp.AzureManagedMachinePoolSpec{
+				Name:         pointer.StringPtr("pool1"),
+				Mode:         string(infrav1exp.NodePoolModeSystem),
+				SKU:          "Standard_D4s_v3",
+				OSDiskSizeGB: pointer.Int32Ptr(64),
+				OSDiskType:   pointer.StringPtr("Ephemeral"),
+			},
+		}
+	})
+	got, err := s.AgentPoolSpec(context.TODO())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got.OSDiskType).To(Equal("Ephemeral"))
+	g.Expect(got.OSDiskSizeGB).To(Equal(int32(64)))
+	g.Expect(got.IsCreateOnly(azure.AgentPoolOSDiskType)).To(BeTrue())
+}
+
+func TestManagedControlPlaneScope_AgentPoolSpecEncryptionAtHost(t *testing.T) {
+	skus := []compute.ResourceSku{
+		{
+			Name:         pointer.StringPtr("Standard_D4s_v3"),
+			ResourceType: pointer.StringPtr(string(resourceskus.VirtualMachines)),
+			Capabilities: &[]compute.ResourceSkuCapabilities{
+				{Name: pointer.StringPtr(resourceskus.EncryptionAtHost), Value: pointer.StringPtr("True")},
+			},
+		},
+		{
+			Name:         pointer.StringPtr("Standard_A1_v2"),
+			ResourceType: pointer.StringPtr(string(resourceskus.VirtualMachines)),
+			Capabilities: &[]compute.ResourceSkuCapabilities{
+				{Name: pointer.StringPtr(resourceskus.EncryptionAtHost), Value: pointer.StringPtr("False")},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		sku     string
+		enabled *bool
+		wantErr string
+	}{
+		{
+			name: "encryption at host is left to AKS by default",
+			sku:  "Standard_A1_v2",
+		},
+		{
+			name:    "encryption at host is enabled",
+			sku:     "Standard_D4s_v3",
+			enabled: pointer.Bool(true),
+		},
+		{
+			name:    "encryption at host is not supported by the VM size",
+			sku:     "Standard_A1_v2",
+			enabled: pointer.Bool(true),
+			wantErr: "encryption at host is not supported for VM size Standard_A1_v2 of agent pool pool1",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			s := newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+				s.MachinePool = &expv1.MachinePool{}
+				s.InfraMachinePool = &infrav1exp.AzureManagedMachinePool{
+					Spec: infrav1exp.AzureManagedMachinePoolSpec{
+						Name:                   pointer.StringPtr("pool1"),
+						Mode:                   string(infrav1exp.NodePoolModeSystem),
+						SKU:                    tt.sku,
+						EnableEncryptionAtHost: tt.enabled,
+					},
+				}
+				s.skuCache = resourceskus.NewStaticCache(skus, "westus2")
+			})
+			got, err := s.AgentPoolSpec(context.TODO())
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				var reconcileError azure.ReconcileError
+				g.Expect(errors.As(err, &reconcileError)).To(BeTrue())
+				g.Expect(reconcileError.IsTerminal()).To(BeTrue())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got.EnableEncryptionAtHost).To(Equal(tt.enabled))
+			g.Expect(got.IsCreateOnly(azure.AgentPoolEnableEncryptionAtHost)).To(BeTrue())
+		})
+	}
+}
+
+func TestManagedControlPlaneScope_AgentPoolSpecPodSubnet(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+		s.ControlPlane.Spec.NetworkPlugin = pointer.StringPtr("azure")
+		s.MachinePool = &expv1.MachinePool{}
+		s.InfraMachinePool = &infrav1exp.AzureManagedMachinePool{
+			Spec: infrav1exp.AzureManagedMachinePoolSpec{
+				Name:          pointer.StringPtr("pool1"),
+				Mode:          string(infrav1exp.NodePoolModeSystem),
+				SKU:           "Standard_D2s_v3",
+				PodSubnetName: pointer.StringPtr("my-pod-subnet"),
+			},
+		}
+	})
+	got, err := s.AgentPoolSpec(context.TODO())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got.VnetSubnetID).To(Equal("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet"))
+	g.Expect(got.PodSubnetID).To(Equal("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-pod-subnet"))
+	g.Expect(got.IsCreateOnly(azure.AgentPoolPodSubnetID)).To(BeTrue())
+}
+
+func TestManagedControlPlaneScope_SubnetSpecs(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+		s.ControlPlane.Spec.VirtualNetwork.Subnet.CIDRBlock = "10.240.0.0/16"
+		s.ControlPlane.Spec.VirtualNetwork.PodSubnets = []infrav1exp.ManagedControlPlaneSubnet{
+			{Name: "my-pod-subnet", CIDRBlock: "10.241.0.0/16"},
+		}
+	})
+	g.Expect(s.SubnetSpecs()).To(Equal([]azure.SubnetSpec{
+		{Name: "my-subnet", CIDRs: []string{"10.240.0.0/16"}, VNetName: "my-vnet"},
+		{Name: "my-pod-subnet", CIDRs: []string{"10.241.0.0/16"}, VNetName: "my-vnet"},
+	}))
+}
+
+func TestManagedControlPlaneScope_AgentPoolSpecProximityPlacementGroup(t *testing.T) {
+	g := NewWithT(t)
+	ppgID := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/my-rg/providers/Microsoft.Compute/proximityPlacementGroups/my-ppg"
+	s := newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+		s.MachinePool = &expv1.MachinePool{}
+		s.InfraMachinePool = &infrav1exp.AzureManagedMachinePool{
+			Spec: infrav1exp.AzureManagedMachinePoolSpec{
+				Name:                      pointer.StringPtr("pool1"),
+				Mode:                      string(infrav1exp.NodePoolModeSystem),
+				SKU:                       "Standard_D2s_v3",
+				ProximityPlacementGroupID: pointer.StringPtr(ppgID),
+			},
+		}
+	})
+	got, err := s.AgentPoolSpec(context.TODO())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got.ProximityPlacementGroupID).To(Equal(pointer.StringPtr(ppgID)))
+	g.Expect(got.IsCreateOnly(azure.AgentPoolProximityPlacementGroupID)).To(BeTrue())
+}
+
+func TestManagedControlPlaneScope_AgentPoolSpecNodePublicIP(t *testing.T) {
+	g := NewWithT(t)
+	prefixID := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/my-rg/providers/Microsoft.Network/publicIPPrefixes/my-prefix"
+	s := newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+		s.MachinePool = &expv1.MachinePool{}
+		s.InfraMachinePool = &infrav1exp.AzureManagedMachinePool{
+			Spec: infrav1exp.AzureManagedMachinePoolSpec{
+				Name:                 pointer.StringPtr("pool1"),
+				Mode:                 string(infrav1exp.NodePoolModeSystem),
+				SKU:                  "Standard_D2s_v3",
+				EnableNodePublicIP:   pointer.BoolPtr(true),
+				NodePublicIPPrefixID: pointer.StringPtr(prefixID),
+			},
+		}
+	})
+	got, err := s.AgentPoolSpec(context.TODO())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got.EnableNodePublicIP).To(Equal(pointer.BoolPtr(true)))
+	g.Expect(got.NodePublicIPPrefixID).To(Equal(pointer.StringPtr(prefixID)))
+	g.Expect(got.IsCreateOnly(azure.AgentPoolEnableNodePublicIP)).To(BeTrue())
+	g.Expect(got.IsCreateOnly(azure.AgentPoolNodePublicIPPrefixID)).To(BeTrue())
+}
+
+func TestManagedControlPlaneScope_AgentPoolSpecVersion(t *testing.T) {
+	tests := []struct {
+		name                string
+		controlPlaneVersion string
+		machinePoolVersion  *string
+		orchestratorVersion *string
+		want                string
+	}{
+		{
+			name:                "agent pool version defaults to the control plane version",
+			controlPlaneVersion: "v1.22.4",
+			want:                "1.22.4",
+		},
+		{
+			name:                "agent pool version defaults to the MachinePool version",
+			controlPlaneVersion: "v1.22.4",
+			machinePoolVersion:  pointer.StringPtr("v1.22.4"),
+			want:                "1.22.4",
+		},
+		{
+			name:                "agent pool version matching the control plane version",
+			controlPlaneVersion: "v1.22.4",
+			machinePoolVersion:  pointer.StringPtr("v1.22.4"),
+			orchestratorVersion: pointer.StringPtr("v1.22.4"),
+			want:                "1.22.4",
+		},
+		{
+			name:                "agent pool version lagging behind the control plane version",
+			controlPlaneVersion: "v1.22.4",
+			machinePoolVersion:  pointer.StringPtr("v1.22.4"),
+			orchestratorVersion: pointer.StringPtr("v1.21.7"),
+			want:                "1.21.7",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+				s.ControlPlane.Spec.Version = tt.controlPlaneVersion
+				s.MachinePool = &expv1.MachinePool{
+					Spec: expv1.MachinePoolSpec{
+						Template: clusterv1.MachineTemplateSpec{
+							Spec: clusterv1.MachineSpec{
+								Version: tt.machinePoolVersion,
+							},
+						},
+					},
+				}
+				s.InfraMachinePool = &infrav1exp.AzureManagedMachinePool{
+					Spec: infrav1exp.AzureManagedMachinePoolSpec{
+						Name:                pointer.StringPtr("pool1"),
+						Mode:                string(infrav1exp.NodePoolModeSystem),
+						SKU:                 "Standard_D2s_v3",
+						OrchestratorVersion: tt.orchestratorVersion,
+					},
+				}
+			})
+			got, err := s.AgentPoolSpec(context.TODO())
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got.Version).To(Equal(pointer.StringPtr(tt.want)))
+		})
+	}
+}
+
+func TestManagedControlPlaneScope_AgentPoolSpecTaints(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+		s.MachinePool = &expv1.MachinePool{}
+		s.InfraMachinePool = &infrav1exp.AzureManagedMachinePool{
+			Spec: infrav1exp.AzureManagedMachinePoolSpec{
+				Name: pointer.StringPtr("pool1"),
+				Mode: string(infrav1exp.NodePoolModeSystem),
+				SKU:  "Standard_D2s_v3",
+				StartupTaint: &infrav1exp.StartupTaint{
+					Key: "node.example.com/setup",
+				},
+				Taints: []infrav1exp.Taint{
+					{Key: "dedicated", Value: "gpu", Effect: infrav1exp.TaintEffectNoExecute},
+					{Key: "spare", Effect: infrav1exp.TaintEffectPreferNoSchedule},
+				},
+			},
+		}
+	})
+	got, err := s.AgentPoolSpec(context.TODO())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got.NodeTaints).To(Equal([]string{
+		"node.example.com/setup:NoSchedule",
+		"dedicated=gpu:NoExecute",
+		"spare:PreferNoSchedule",
+	}))
+}
+
+func TestManagedControlPlaneScope_AgentPoolSpecScaling(t *testing.T) {
+	tests := []struct {
+		name     string
+		replicas *int32
+		scaling  *infrav1exp.ManagedMachinePoolScaling
+		want     azure.AgentPoolSpec
+		wantErr  string
+	}{
+		{
+			name:     "autoscaling disabled",
+			replicas: pointer.Int32Ptr(3),
+			want: azure.AgentPoolSpec{
+				Replicas: 3,
+			},
+		},
+		{
+			name:     "autoscaling bounds are set and the MachinePool replicas are kept as the initial node count",
+			replicas: pointer.Int32Ptr(3),
+			scaling: &infrav1exp.ManagedMachinePoolScaling{
+				MinSize: 2,
+				MaxSize: 10,
+			},
+			want: azure.AgentPoolSpec{
+				Replicas:          3,
+				EnableAutoScaling: pointer.Bool(true),
+				MinCount:          pointer.Int32Ptr(2),
+				MaxCount:          pointer.Int32Ptr(10),
+			},
+		},
+		{
+			name:     "replicas below the autoscaling minimum",
+			replicas: pointer.Int32Ptr(1),
+			scaling: &infrav1exp.ManagedMachinePoolScaling{
+				MinSize: 2,
+				MaxSize: 10,
+			},
+			wantErr: "the replicas of agent pool pool1 must be between its scaling minSize 2 and maxSize 10, not 1",
+		},
+		{
+			name:     "replicas above the autoscaling maximum",
+			replicas: pointer.Int32Ptr(11),
+			scaling: &infrav1exp.ManagedMachinePoolScaling{
+				MinSize: 2,
+				MaxSize: 10,
+			},
+			wantErr: "the replicas of agent pool pool1 must be between its scaling minSize 2 and maxSize 10, not 11",
+		},
+		{
+			name:     "replicas at the autoscaling bounds",
+			replicas: pointer.Int32Ptr(10),
+			scaling: &infrav1exp.ManagedMachinePoolScaling{
+				MinSize: 10,
+				MaxSize: 10,
+			},
+			want: azure.AgentPoolSpec{
+				Replicas:          10,
+				EnableAutoScaling: pointer.Bool(true),
+				MinCount:          pointer.Int32Ptr(10),
+				MaxCount:          pointer.Int32Ptr(10),
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+				s.MachinePool = &expv1.MachinePool{
+					Spec: expv1.MachinePoolSpec{
+						Replicas: tt.replicas,
+					},
+				}
+				s.InfraMachinePool = &infrav1exp.AzureManagedMachinePool{
+					Spec: infrav1exp.AzureManagedMachinePoolSpec{
+						Name:    pointer.StringPtr("pool1"),
+						Mode:    string(infrav1exp.NodePoolModeUser),
+						SKU:     "Standard_D2s_v3",
+						Scaling: tt.scaling,
+					},
+				}
+			})
+			got, err := s.AgentPoolSpec(context.TODO())
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(tt.wantErr))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got.Replicas).To(Equal(tt.want.Replicas))
+			g.Expect(got.EnableAutoScaling).To(Equal(tt.want.EnableAutoScaling))
+			g.Expect(got.MinCount).To(Equal(tt.want.MinCount))
+			g.Expect(got.MaxCount).To(Equal(tt.want.MaxCount))
+		})
+	}
+}
+
+func TestManagedControlPlaneScope_AgentPoolSpecMode(t *testing.T) {
+	agentPool := func(name, mode string) *infrav1exp.AzureManagedMachinePool {
+		return &infrav1exp.AzureManagedMachinePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					clusterv1.ClusterLabelName: "my-cluster",
+				},
+			},
+			Spec: infrav1exp.AzureManagedMachinePoolSpec{
+				Name: pointer.StringPtr(name),
+				Mode: mode,
+				SKU:  "Standard_D2s_v3",
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		otherPools []client.Object
+		wantErr    string
+	}{
+		{
+			name:    "demoting the only system agent pool is rejected",
+			wantErr: "agent pool pool0 cannot be in mode User, the cluster must have at least one agent pool in mode System",
+		},
+		{
+			name:       "demoting a system agent pool is rejected when only user agent pools remain",
+			otherPools: []client.Object{agentPool("pool1", string(infrav1exp.NodePoolModeUser))},
+			wantErr:    "agent pool pool0 cannot be in mode User, the cluster must have at least one agent pool in mode System",
+		},
+		{
+			name:       "demoting a system agent pool is allowed when another system agent pool remains",
+			otherPools: []client.Object{agentPool("pool1", string(infrav1exp.NodePoolModeSystem))},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			g.Expect(infrav1exp.AddToScheme(scheme)).To(Succeed())
+
+			pool := agentPool("pool0", string(infrav1exp.NodePoolModeUser))
+			s := newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+				s.Client = fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(append(tt.otherPools, pool)...).Build()
+				s.MachinePool = &expv1.MachinePool{}
+				s.InfraMachinePool = pool
+			})
+			got, err := s.AgentPoolSpec(context.TODO())
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(tt.wantErr))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(got.Mode).To(Equal(string(infrav1exp.NodePoolModeUser)))
+			}
+		})
+	}
+}
+
+func TestManagedControlPlaneScope_AgentPoolSpecUpgradeGroup(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	g.Expect(infrav1exp.AddToScheme(scheme)).To(Succeed())
+	g.Expect(expv1.AddToScheme(scheme)).To(Succeed())
+
+	machinePool := func(name string) *expv1.MachinePool {
+		return &expv1.MachinePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: expv1.MachinePoolSpec{
+				Template: clusterv1.MachineTemplateSpec{
+					Spec: clusterv1.MachineSpec{
+						Version: pointer.StringPtr("v1.22.4"),
+					},
+				},
+			},
+		}
+	}
+	agentPool := func(name, group string) *infrav1exp.AzureManagedMachinePool {
+		return &infrav1exp.AzureManagedMachinePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					clusterv1.ClusterLabelName:            "my-cluster",
+					infrav1exp.AgentPoolUpgradeGroupLabel: group,
+				},
+				OwnerReferences: []metav1.OwnerReference{
+					{
+						APIVersion: expv1.GroupVersion.String(),
+						Kind:       "MachinePool",
+						Name:       name,
+					},
+				},
+			},
+			Spec: infrav1exp.AzureManagedMachinePoolSpec{
+				Name: pointer.StringPtr(name),
+				Mode: string(infrav1exp.NodePoolModeSystem),
+				SKU:  "Standard_D2s_v3",
+			},
+			Status: infrav1exp.AzureManagedMachinePoolStatus{
+				Version: "1.21.2",
+			},
+		}
+	}
+
+	pool0, pool1, pool2 := agentPool("pool0", "0"), agentPool("pool1", "1"), agentPool("pool2", "2")
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(
+		pool0, pool1, pool2, machinePool("pool0"), machinePool("pool1"), machinePool("pool2"),
+	).Build()
+	scopeFor := func(pool *infrav1exp.AzureManagedMachinePool) *ManagedControlPlaneScope {
+		return newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+			s.Client = c
+			s.MachinePool = machinePool(pool.Name)
+			s.InfraMachinePool = pool
+		})
+	}
+
+	// Only the agent pool of the first upgrade group is upgraded.
+	for _, tt := range []struct {
+		pool    *infrav1exp.AzureManagedMachinePool
+		version string
+		waiting bool
+	}{
+		{pool: pool0, version: "1.22.4"},
+		{pool: pool1, version: "1.21.2", waiting: true},
+		{pool: pool2, version: "1.21.2", waiting: true},
+	} {
+		s := scopeFor(tt.pool)
+		got, err := s.AgentPoolSpec(context.TODO())
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(*got.Version).To(Equal(tt.version), tt.pool.Name)
+		if tt.waiting {
+			g.Expect(s.WaitForAgentPoolUpgradeGroup(context.TODO())).To(HaveOccurred(), tt.pool.Name)
+		} else {
+			g.Expect(s.WaitForAgentPoolUpgradeGroup(context.TODO())).To(Succeed(), tt.pool.Name)
+		}
+	}
+
+	// Once the first upgrade group is upgraded, the second one is upgraded while the third one still waits.
+	pool0.Status.Version = "1.22.4"
+	g.Expect(c.Status().Update(context.TODO(), pool0)).To(Succeed())
+
+	got, err := scopeFor(pool1).AgentPoolSpec(context.TODO())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(*got.Version).To(Equal("1.22.4"))
+
+	got, err = scopeFor(pool2).AgentPoolSpec(context.TODO())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(*got.Version).To(Equal("1.21.2"))
+}
+
+func TestManagedControlPlaneScope_AllAgentPoolSpecs(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	g.Expect(infrav1exp.AddToScheme(scheme)).To(Succeed())
+	g.Expect(expv1.AddToScheme(scheme)).To(Succeed())
+
+	machinePool := func(name string, replicas int32) *expv1.MachinePool {
+		return &expv1.MachinePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: expv1.MachinePoolSpec{
+				Replicas: pointer.Int32(replicas),
+			},
+		}
+	}
+	agentPool := func(name, mode string) *infrav1exp.AzureManagedMachinePool {
+		return &infrav1exp.AzureManagedMachinePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					clusterv1.ClusterLabelName: "my-cluster",
+				},
+				OwnerReferences: []metav1.OwnerReference{
+					{
+						APIVersion: expv1.GroupVersion.String(),
+						Kind:       "MachinePool",
+						Name:       name,
+					},
+				},
+			},
+			Spec: infrav1exp.AzureManagedMachinePoolSpec{
+				Name: pointer.String(name),
+				Mode: mode,
+				SKU:  "Standard_D2s_v3",
+			},
+		}
+	}
+
+	systemPool := agentPool("pool0", string(infrav1exp.NodePoolModeSystem))
+	userPool := agentPool("pool1", string(infrav1exp.NodePoolModeUser))
+	userPool.Spec.OSDiskSizeGB = pointer.Int32(64)
+	userPool.Spec.Taints = []infrav1exp.Taint{
+		{Key: "dedicated", Value: "gpu", Effect: infrav1exp.TaintEffectNoSchedule},
+	}
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(
+		systemPool, userPool, machinePool("pool0", 1), machinePool("pool1", 3),
+	).Build()
+	newScope := func() *ManagedControlPlaneScope {
+		return newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+			s.Client = c
+			s.ControlPlane.Spec.Version = "v1.22.4"
+		})
+	}
+
+	got, err := newScope().AllAgentPoolSpecs(context.TODO())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got).To(HaveLen(2))
+
+	// The spec of each agent pool is the one the agent pool is reconciled with on its own.
+	for i, tt := range []struct {
+		pool     *infrav1exp.AzureManagedMachinePool
+		replicas int32
+	}{
+		{pool: systemPool, replicas: 1},
+		{pool: userPool, replicas: 3},
+	} {
+		s := newScope()
+		s.MachinePool = machinePool(tt.pool.Name, tt.replicas)
+		s.InfraMachinePool = tt.pool
+		expected, err := s.AgentPoolSpec(context.TODO())
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(got[i]).To(Equal(expected))
+	}
+	g.Expect(got[0].Name).To(Equal("pool0"))
+	g.Expect(got[0].Mode).To(Equal("System"))
+	g.Expect(got[0].Replicas).To(Equal(int32(1)))
+	g.Expect(*got[0].Version).To(Equal("1.22.4"))
+	g.Expect(got[1].Name).To(Equal("pool1"))
+	g.Expect(got[1].Mode).To(Equal("User"))
+	g.Expect(got[1].Replicas).To(Equal(int32(3)))
+	g.Expect(got[1].OSDiskSizeGB).To(Equal(int32(64)))
+	g.Expect(got[1].NodeTaints).To(Equal([]string{"dedicated=gpu:NoSchedule"}))
+
+	// Creating the managed cluster requires a system agent pool.
+	systemPool.Spec.Mode = string(infrav1exp.NodePoolModeUser)
+	g.Expect(c.Update(context.TODO(), systemPool)).To(Succeed())
+	_, err = newScope().AllAgentPoolSpecs(context.TODO())
+	g.Expect(err).To(MatchError("failed to fetch azuremanagedMachine pool with mode:System, require at least 1 system node pool"))
+}
+
+func TestManagedControlPlaneScope_SetAgentPoolProvisioningState(t *testing.T) {
+	tests := []struct {
+		state    string
+		status   corev1.ConditionStatus
+		reason   string
+		severity clusterv1.ConditionSeverity
+	}{
+		{
+			state:  "Succeeded",
+			status: corev1.ConditionTrue,
+		},
+		{
+			state:    "Creating",
+			status:   corev1.ConditionFalse,
+			reason:   infrav1.AgentPoolProvisioningReason,
+			severity: clusterv1.ConditionSeverityInfo,
+		},
+		{
+			state:    "Updating",
+			status:   corev1.ConditionFalse,
+			reason:   infrav1.AgentPoolProvisioningReason,
+			severity: clusterv1.ConditionSeverityInfo,
+		},
+		{
+			state:    "Upgrading",
+			status:   corev1.ConditionFalse,
+			reason:   infrav1.AgentPoolProvisioningReason,
+			severity: clusterv1.ConditionSeverityInfo,
+		},
+		{
+			state:    "Scaling",
+			status:   corev1.ConditionFalse,
+			reason:   infrav1.AgentPoolProvisioningReason,
+			severity: clusterv1.ConditionSeverityInfo,
+		},
+		{
+			state:    "Failed",
+			status:   corev1.ConditionFalse,
+			reason:   infrav1.AgentPoolFailedReason,
+			severity: clusterv1.ConditionSeverityError,
+		},
+		{
+			state:    "Canceled",
+			status:   corev1.ConditionFalse,
+			reason:   infrav1.AgentPoolFailedReason,
+			severity: clusterv1.ConditionSeverityError,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.state, func(t *testing.T) {
+			g := NewWithT(t)
+			s := newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+				s.InfraMachinePool = &infrav1exp.AzureManagedMachinePool{}
+			})
+			s.SetAgentPoolProvisioningState(tt.state)
+			cond := conditions.Get(s.InfraMachinePool, clusterv1.ReadyCondition)
+			g.Expect(cond).NotTo(BeNil())
+			g.Expect(cond.Status).To(Equal(tt.status))
+			g.Expect(cond.Reason).To(Equal(tt.reason))
+			g.Expect(cond.Severity).To(Equal(tt.severity))
+		})
+	}
+}
+
+func TestManagedControlPlaneScope_SetManagedClusterProvisioningState(t *testing.T) {
+	tests := []struct {
+		state    string
+		status   corev1.ConditionStatus
+		reason   string
+		severity clusterv1.ConditionSeverity
+	}{
+		{
+			state:  "Succeeded",
+			status: corev1.ConditionTrue,
+		},
+		{
+			state:    "Updating",
+			status:   corev1.ConditionFalse,
+			reason:   infrav1.ManagedClusterProvisioningReason,
+			severity: clusterv1.ConditionSeverityInfo,
+		},
+		{
+			state:    "Failed",
+			status:   corev1.ConditionFalse,
+			reason:   infrav1.ManagedClusterFailedReason,
+			severity: clusterv1.ConditionSeverityError,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.state, func(t *testing.T) {
+			g := NewWithT(t)
+			s := newTestManagedControlPlaneScope()
+			s.SetManagedClusterProvisioningState(tt.state)
+			cond := conditions.Get(s.ControlPlane, infrav1.ManagedClusterRunningCondition)
+			g.Expect(cond).NotTo(BeNil())
+			g.Expect(cond.Status).To(Equal(tt.status))
+			g.Expect(cond.Reason).To(Equal(tt.reason))
+			g.Expect(cond.Severity).To(Equal(tt.severity))
+		})
+	}
+}
+
+func TestManagedControlPlaneScope_ConditionsSummary(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	g.Expect(infrav1exp.AddToScheme(scheme)).To(Succeed())
+
+	pool := func(name string, ready *clusterv1.Condition) *infrav1exp.AzureManagedMachinePool {
+		return &infrav1exp.AzureManagedMachinePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					clusterv1.ClusterLabelName: "my-cluster",
+				},
+			},
+			Status: infrav1exp.AzureManagedMachinePoolStatus{
+				Conditions: clusterv1.Conditions{*ready},
+			},
+		}
+	}
+	s := newTestManagedControlPlaneScope()
+	controlPlane := s.ControlPlane
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(
+		controlPlane,
+		pool("pool0", conditions.TrueCondition(clusterv1.ReadyCondition)),
+		pool("pool1", conditions.FalseCondition(clusterv1.ReadyCondition, infrav1.AgentPoolFailedReason, clusterv1.ConditionSeverityError, "agent pool provisioning state is Failed")),
+	).Build()
+	helper, err := patch.NewHelper(controlPlane, c)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	s.Client = c
+	s.PatchTarget = controlPlane
+	s.patchHelper = helper
+	s.UpdatePutStatus(infrav1.ResourceGroupReadyCondition, "group", nil)
+	s.SetManagedClusterProvisioningState("Succeeded")
+	g.Expect(s.SetAgentPoolsReadyCondition(context.TODO())).To(Succeed())
+	g.Expect(s.PatchObject(context.TODO())).To(Succeed())
+
+	agentPools := conditions.Get(s.ControlPlane, infrav1.AgentPoolsReadyCondition)
+	g.Expect(agentPools).NotTo(BeNil())
+	g.Expect(agentPools.Status).To(Equal(corev1.ConditionFalse))
+	g.Expect(agentPools.Reason).To(Equal("Failed @ AzureManagedMachinePool/pool1"))
+	g.Expect(agentPools.Message).To(Equal("agent pool provisioning state is Failed"))
+
+	summary := conditions.Get(s.ControlPlane, clusterv1.ReadyCondition)
+	g.Expect(summary).NotTo(BeNil())
+	g.Expect(summary.Status).To(Equal(corev1.ConditionFalse))
+	g.Expect(summary.Reason).To(Equal("Failed @ AzureManagedMachinePool/pool1"))
+	g.Expect(summary.Severity).To(Equal(clusterv1.ConditionSeverityError))
+	g.Expect(summary.Message).To(Equal("agent pool provisioning state is Failed"))
+
+	persisted := &infrav1exp.AzureManagedControlPlane{}
+	g.Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(controlPlane), persisted)).To(Succeed())
+	g.Expect(conditions.IsFalse(persisted, clusterv1.ReadyCondition)).To(BeTrue())
+	g.Expect(conditions.IsTrue(persisted, infrav1.ManagedClusterRunningCondition)).To(BeTrue())
+}
+
+func TestManagedControlPlaneScope_ManagedClusterCreateTimedOut(t *testing.T) {
+	tests := []struct {
+		name          string
+		createTimeout *metav1.Duration
+		age           time.Duration
+		ready         bool
+		want          bool
+	}{
+		{
+			name: "no create timeout",
+			age:  time.Hour,
+			want: false,
+		},
+		{
+			name:          "create timeout not elapsed",
+			createTimeout: &metav1.Duration{Duration: time.Hour},
+			age:           time.Minute,
+			want:          false,
+		},
+		{
+			name:          "create timeout elapsed",
+			createTimeout: &metav1.Duration{Duration: time.Hour},
+			age:           2 * time.Hour,
+			want:          true,
+		},
+		{
+			name:          "create timeout elapsed after the control plane became ready",
+			createTimeout: &metav1.Duration{Duration: time.Hour},
+			age:           2 * time.Hour,
+			ready:         true,
+			want:          false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+				s.ControlPlane.CreationTimestamp = metav1.NewTime(time.Now().Add(-tt.age))
+				s.ControlPlane.Spec.CreateTimeout = tt.createTimeout
+				s.ControlPlane.Status.Ready = tt.ready
+			})
+			g.Expect(s.ManagedClusterCreateTimedOut()).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestManagedControlPlaneScope_SetManagedClusterCreateTimedOut(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+		s.ControlPlane.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+		s.ControlPlane.Spec.CreateTimeout = &metav1.Duration{Duration: time.Hour}
+	})
+	g.Expect(s.ManagedClusterCreateTimedOut()).To(BeTrue())
+	s.SetManagedClusterCreateTimedOut("Creating")
+	cond := conditions.Get(s.ControlPlane, infrav1.CreateTimedOutCondition)
+	g.Expect(cond).NotTo(BeNil())
+	g.Expect(cond.Status).To(Equal(corev1.ConditionTrue))
+	g.Expect(cond.Reason).To(Equal(infrav1.CreateTimeoutExceededReason))
+	g.Expect(cond.Message).To(ContainSubstring("Creating"))
+}
+
+func TestManagedControlPlaneScope_ValidateVersionUpgrade(t *testing.T) {
+	tests := []struct {
+		name           string
+		currentVersion string
+		desiredVersion string
+		wantErr        bool
+		status         corev1.ConditionStatus
+	}{
+		{
+			name:           "same version",
+			currentVersion: "1.21.7",
+			desiredVersion: "v1.21.7",
+			status:         corev1.ConditionTrue,
+		},
+		{
+			name:           "patch upgrade",
+			currentVersion: "1.21.7",
+			desiredVersion: "v1.21.9",
+			status:         corev1.ConditionTrue,
+		},
+		{
+			name:           "minor upgrade",
+			currentVersion: "1.21.7",
+			desiredVersion: "v1.22.4",
+			status:         corev1.ConditionTrue,
+		},
+		{
+			name:           "upgrade skipping a minor version",
+			currentVersion: "1.21.7",
+			desiredVersion: "v1.23.3",
+			wantErr:        true,
+			status:         corev1.ConditionFalse,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+				s.ControlPlane.Spec.Version = tt.desiredVersion
+			})
+			err := s.ValidateVersionUpgrade(tt.currentVersion)
+			cond := conditions.Get(s.ControlPlane, infrav1.VersionUpgradeAllowedCondition)
+			g.Expect(cond).NotTo(BeNil())
+			g.Expect(cond.Status).To(Equal(tt.status))
+			if tt.wantErr {
+				var reconcileError azure.ReconcileError
+				g.Expect(errors.As(err, &reconcileError)).To(BeTrue())
+				g.Expect(reconcileError.IsTerminal()).To(BeTrue())
+				g.Expect(cond.Reason).To(Equal(infrav1.MinorVersionSkippedReason))
+				g.Expect(cond.Severity).To(Equal(clusterv1.ConditionSeverityError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+func TestManagedControlPlaneScope_SetSKUFromSelector(t *testing.T) {
+	vmSKU := func(name, family, vCPUs, memoryGB string, restricted bool) compute.ResourceSku {
+		sku := compute.ResourceSku{
+			Name:         pointer.StringPtr(name),
+			Family:       pointer.StringPtr(family),
+			ResourceType: pointer.StringPtr(string(resourceskus.VirtualMachines)),
+			Capabilities: &[]compute.ResourceSkuCapabilities{
+				{Name: pointer.StringPtr(resourceskus.VCPUs), Value: pointer.StringPtr(vCPUs)},
+				{Name: pointer.StringPtr(resourceskus.MemoryGB), Value: pointer.StringPtr(memoryGB)},
+			},
+		}
+		if restricted {
+			sku.Restrictions = &[]compute.ResourceSkuRestrictions{
+				{Type: compute.ResourceSkuRestrictionsTypeLocation},
+			}
+		}
+		return sku
+	}
+	skus := []compute.ResourceSku{
+		vmSKU("Standard_D8s_v3", "standardDSv3Family", "8", "32", false),
+		vmSKU("Standard_D4s_v3", "standardDSv3Family", "4", "16", false),
+		vmSKU("Standard_D2s_v3", "standardDSv3Family", "2", "8", true),
+		vmSKU("Standard_E4s_v3", "standardESv3Family", "4", "32", false),
+		vmSKU("Standard_B2s", "standardBSFamily", "2", "4", false),
+		vmSKU("Standard_A1_v2", "standardAv2Family", "1", "2", false),
+	}
+
+	tests := []struct {
+		name    string
+		spec    infrav1exp.AzureManagedMachinePoolSpec
+		want    string
+		wantErr string
+	}{
+		{
+			name: "explicit SKU takes precedence",
+			spec: infrav1exp.AzureManagedMachinePoolSpec{
+				SKU:         "Standard_D8s_v3",
+				SKUSelector: &infrav1exp.SKUSelector{MinVCPUs: pointer.Int32Ptr(2)},
+			},
+			want: "Standard_D8s_v3",
+		},
+		{
+			name: "smallest SKU of the family that is not restricted in the location",
+			spec: infrav1exp.AzureManagedMachinePoolSpec{
+				SKUSelector: &infrav1exp.SKUSelector{
+					Family:   pointer.StringPtr("standardDSv3Family"),
+					MinVCPUs: pointer.Int32Ptr(2),
+				},
+			},
+			want: "Standard_D4s_v3",
+		},
+		{
+			name: "smallest SKU with enough vCPUs and memory",
+			spec: infrav1exp.AzureManagedMachinePoolSpec{
+				SKUSelector: &infrav1exp.SKUSelector{
+					MinVCPUs:    pointer.Int32Ptr(4),
+					MinMemoryGB: pointer.Int32Ptr(20),
+				},
+			},
+			want: "Standard_E4s_v3",
+		},
+		{
+			name: "no SKU matches the selector",
+			spec: infrav1exp.AzureManagedMachinePoolSpec{
+				SKUSelector: &infrav1exp.SKUSelector{
+					Family:   pointer.StringPtr("standardBSFamily"),
+					MinVCPUs: pointer.Int32Ptr(4),
+				},
+			},
+			wantErr: "failed to resolve the SKU of agent pool my-pool: no VM size available in location westus2 matches the SKU selector (family: standardBSFamily, minimum vCPUs: 4, minimum memory: 0GB)",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+				s.InfraMachinePool = &infrav1exp.AzureManagedMachinePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "my-pool",
+					},
+					Spec: tt.spec,
+				}
+				s.skuCache = resourceskus.NewStaticCache(skus, "westus2")
+			})
+			err := s.SetSKUFromSelector(context.Background())
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(tt.wantErr))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(s.InfraMachinePool.Spec.SKU).To(Equal(tt.want))
+			}
+		})
+	}
+}
+
+func TestManagedControlPlaneScope_MaintenanceConfigurationSpecs(t *testing.T) {
+	start := time.Date(2021, time.December, 24, 1, 0, 0, 0, time.FixedZone("UTC+1", 3600))
+	end := time.Date(2021, time.December, 27, 0, 0, 0, 0, time.UTC)
+	maintenanceWindow := &infrav1exp.MaintenanceWindow{
+		TimeInWeek: []infrav1exp.TimeInWeek{
+			{Day: "Saturday", HourSlots: []int32{1, 2}},
+			{Day: "Sunday", HourSlots: []int32{3}},
+		},
+		NotAllowedTime: []infrav1exp.TimeSpan{
+			{Start: metav1.NewTime(start), End: metav1.NewTime(end)},
+		},
+	}
+	nodeOSUpgradeMaintenanceWindow := &infrav1exp.MaintenanceWindow{
+		TimeInWeek: []infrav1exp.TimeInWeek{
+			{Day: "Wednesday", HourSlots: []int32{22, 23}},
+		},
+	}
+	defaultSpec := azure.MaintenanceConfigurationSpec{
+		Name:          "default",
+		ResourceGroup: "my-rg",
+		Cluster:       "my-cluster-control-plane",
+		TimeInWeek: []azure.MaintenanceTimeInWeek{
+			{Day: "Saturday", HourSlots: []int32{1, 2}},
+			{Day: "Sunday", HourSlots: []int32{3}},
+		},
+		NotAllowedTime: []azure.MaintenanceTimeSpan{
+			{Start: time.Date(2021, time.December, 24, 0, 0, 0, 0, time.UTC), End: end},
+		},
+	}
+	nodeOSUpgradeSpec := azure.MaintenanceConfigurationSpec{
+		Name:          "aksManagedNodeOSUpgradeSchedule",
+		ResourceGroup: "my-rg",
+		Cluster:       "my-cluster-control-plane",
+		TimeInWeek: []azure.MaintenanceTimeInWeek{
+			{Day: "Wednesday", HourSlots: []int32{22, 23}},
+		},
+	}
+
+	cases := []struct {
+		name                           string
+		maintenanceWindow              *infrav1exp.MaintenanceWindow
+		nodeOSUpgradeMaintenanceWindow *infrav1exp.MaintenanceWindow
+		expected                       []azure.MaintenanceConfigurationSpec
+	}{
+		{
+			name:     "no maintenance windows",
+			expected: nil,
+		},
+		{
+			name:              "maintenance window",
+			maintenanceWindow: maintenanceWindow,
+			expected:          []azure.MaintenanceConfigurationSpec{defaultSpec},
+		},
+		{
+			name:                           "node OS upgrade maintenance window",
+			nodeOSUpgradeMaintenanceWindow: nodeOSUpgradeMaintenanceWindow,
+			expected:                       []azure.MaintenanceConfigurationSpec{nodeOSUpgradeSpec},
+		},
+		{
+			name:                           "both maintenance windows",
+			maintenanceWindow:              maintenanceWindow,
+			nodeOSUpgradeMaintenanceWindow: nodeOSUpgradeMaintenanceWindow,
+			expected:                       []azure.MaintenanceConfigurationSpec{defaultSpec, nodeOSUpgradeSpec},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := newTestManagedControlPlaneScope(func(s *ManagedControlPlaneScope) {
+				s.ControlPlane.Spec.MaintenanceWindow = c.maintenanceWindow
+				s.ControlPlane.Spec.NodeOSUpgradeMaintenanceWindow = c.nodeOSUpgradeMaintenanceWindow
+			})
+			g.Expect(s.MaintenanceConfigurationSpecs()).To(Equal(c.expected))
+		})
+	}
+}
+
+func TestManagedControlPlaneScope_Sender(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(infrav1exp.AddToScheme(scheme)).To(Succeed())
+	controlPlane := &infrav1exp.AzureManagedControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-cluster-control-plane",
+			Namespace: "default",
+		},
+		Spec: infrav1exp.AzureManagedControlPlaneSpec{
+			SubscriptionID: "00000000-0000-0000-0000-000000000000",
+			Location:       "westus2",
+		},
+	}
+
+	var requests []*http.Request
+	sender := autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+		requests = append(requests, r)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body: ioutil.NopCloser(strings.NewReader(`{"value": [
+				{"resourceType": "virtualMachines", "name": "Standard_D2s_v3", "locations": ["westus2"]},
+				{"resourceType": "disks", "name": "Premium_LRS", "locations": ["westus2"]}
+			]}`)),
+			Request: r,
+		}, nil
+	})
+
+	s, err := NewManagedControlPlaneScope(context.TODO(), ManagedControlPlaneScopeParams{
+		AzureClients: AzureClients{
+			Authorizer: autorest.NullAuthorizer{},
+			Sender:     sender,
+		},
+		Client: fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(controlPlane).Build(),
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-cluster",
+				Namespace: "default",
+			},
+		},
+		ControlPlane: controlPlane,
+		PatchTarget:  controlPlane,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	skus, err := resourceskus.NewClient(s).List(context.TODO(), "location eq 'westus2'")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(skus).To(HaveLen(2))
+	g.Expect(skus[0].Name).To(Equal(pointer.String("Standard_D2s_v3")))
+	g.Expect(skus[0].ResourceType).To(Equal(pointer.String("virtualMachines")))
+	g.Expect(skus[1].Name).To(Equal(pointer.String("Premium_LRS")))
+
+	g.Expect(requests).To(HaveLen(1))
+	g.Expect(requests[0].Method).To(Equal(http.MethodGet))
+	g.Expect(requests[0].URL.Path).To(Equal("/subscriptions/00000000-0000-0000-0000-000000000000/providers/Microsoft.Compute/skus"))
+}
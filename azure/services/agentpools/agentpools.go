@@ -19,8 +19,10 @@ package agentpools
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
+	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
@@ -31,16 +33,22 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
+// scaleSetPriorityNodeLabel is the node label and taint key AKS manages on spot agent pools.
+const scaleSetPriorityNodeLabel = "kubernetes.azure.com/scalesetpriority"
+
 // ManagedMachinePoolScope defines the scope interface for a managed machine pool.
 type ManagedMachinePoolScope interface {
 	logr.Logger
 	azure.ClusterDescriber
 
 	NodeResourceGroup() string
-	AgentPoolSpec() azure.AgentPoolSpec
+	AgentPoolSpec(ctx context.Context) (azure.AgentPoolSpec, error)
+	AgentPoolRecreateAllowed() bool
 	SetAgentPoolProviderIDList([]string)
 	SetAgentPoolReplicas(int32)
+	SetAgentPoolVersion(string)
 	SetAgentPoolReady(bool)
+	SetAgentPoolProvisioningState(string)
 }
 
 // Service provides operations on Azure resources.
@@ -65,21 +73,45 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	)
 	defer done()
 
-	agentPoolSpec := s.scope.AgentPoolSpec()
+	agentPoolSpec, err := s.scope.AgentPoolSpec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get agent pool spec")
+	}
 
 	profile := containerservice.AgentPool{
 		ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
-			VMSize:              &agentPoolSpec.SKU,
-			OsType:              containerservice.OSTypeLinux,
-			OsDiskSizeGB:        &agentPoolSpec.OSDiskSizeGB,
-			Count:               &agentPoolSpec.Replicas,
-			Type:                containerservice.AgentPoolTypeVirtualMachineScaleSets,
-			OrchestratorVersion: agentPoolSpec.Version,
-			VnetSubnetID:        &agentPoolSpec.VnetSubnetID,
-			Mode:                containerservice.AgentPoolMode(agentPoolSpec.Mode),
+			VMSize:                    &agentPoolSpec.SKU,
+			OsType:                    containerservice.OSType(agentPoolSpec.OSType),
+			OsDiskSizeGB:              &agentPoolSpec.OSDiskSizeGB,
+			OsDiskType:                containerservice.OSDiskType(agentPoolSpec.OSDiskType),
+			EnableEncryptionAtHost:    agentPoolSpec.EnableEncryptionAtHost,
+			ProximityPlacementGroupID: agentPoolSpec.ProximityPlacementGroupID,
+			EnableNodePublicIP:        agentPoolSpec.EnableNodePublicIP,
+			NodePublicIPPrefixID:      agentPoolSpec.NodePublicIPPrefixID,
+			Count:                     &agentPoolSpec.Replicas,
+			Type:                      containerservice.AgentPoolTypeVirtualMachineScaleSets,
+			OrchestratorVersion:       agentPoolSpec.Version,
+			VnetSubnetID:              &agentPoolSpec.VnetSubnetID,
+			Mode:                      containerservice.AgentPoolMode(agentPoolSpec.Mode),
+			ScaleSetPriority:          containerservice.ScaleSetPriority(agentPoolSpec.ScaleSetPriority),
+			EnableAutoScaling:         agentPoolSpec.EnableAutoScaling,
+			MinCount:                  agentPoolSpec.MinCount,
+			MaxCount:                  agentPoolSpec.MaxCount,
 		},
 	}
 
+	if agentPoolSpec.PodSubnetID != "" {
+		profile.PodSubnetID = &agentPoolSpec.PodSubnetID
+	}
+
+	if len(agentPoolSpec.NodeTaints) > 0 {
+		profile.NodeTaints = &agentPoolSpec.NodeTaints
+	}
+
+	if len(agentPoolSpec.NodeLabels) > 0 {
+		profile.NodeLabels = *to.StringMapPtr(agentPoolSpec.NodeLabels)
+	}
+
 	existingPool, err := s.Client.Get(ctx, agentPoolSpec.ResourceGroup, agentPoolSpec.Cluster, agentPoolSpec.Name)
 	if err != nil && !azure.ResourceNotFound(err) {
 		return errors.Wrap(err, "failed to get existing agent pool")
@@ -91,24 +123,57 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	// to strip/clean to match what we expect.
 	isCreate := azure.ResourceNotFound(err)
 	if isCreate {
+		s.scope.SetAgentPoolProvisioningState(string(infrav1alpha4.Creating))
 		err = s.Client.CreateOrUpdate(ctx, agentPoolSpec.ResourceGroup, agentPoolSpec.Cluster, agentPoolSpec.Name, profile)
 		if err != nil {
 			return errors.Wrap(err, "failed to create or update agent pool")
 		}
+		s.scope.SetAgentPoolProvisioningState(string(infrav1alpha4.Succeeded))
+		if agentPoolSpec.Version != nil {
+			s.scope.SetAgentPoolVersion(*agentPoolSpec.Version)
+		}
 	} else {
 		ps := *existingPool.ManagedClusterAgentPoolProfileProperties.ProvisioningState
+		s.scope.SetAgentPoolProvisioningState(ps)
+		if existingPool.OrchestratorVersion != nil {
+			s.scope.SetAgentPoolVersion(*existingPool.OrchestratorVersion)
+		}
 		if ps != string(infrav1alpha4.Canceled) && ps != string(infrav1alpha4.Failed) && ps != string(infrav1alpha4.Succeeded) {
 			msg := fmt.Sprintf("Unable to update existing agent pool in non terminal state. Agent pool must be in one of the following provisioning states: canceled, failed, or succeeded. Actual state: %s", ps)
 			klog.V(2).Infof(msg)
 			return errors.New(msg)
 		}
 
+		existingPriority := normalizeScaleSetPriority(existingPool.ScaleSetPriority)
+		desiredPriority := normalizeScaleSetPriority(profile.ScaleSetPriority)
+		if existingPriority != desiredPriority {
+			if s.scope.AgentPoolRecreateAllowed() {
+				klog.V(2).Infof("Recreating agent pool %s to change its scale set priority from %s to %s", agentPoolSpec.Name, existingPriority, desiredPriority)
+				return s.recreate(ctx, agentPoolSpec, existingPool, profile)
+			}
+			klog.V(2).Infof("Scale set priority of agent pool %s cannot be changed from %s to %s without recreating it, skipping", agentPoolSpec.Name, existingPriority, desiredPriority)
+			profile.ScaleSetPriority = existingPool.ScaleSetPriority
+		}
+
+		// When the cluster autoscaler governs the node count of the agent pool, keep the node count it set rather
+		// than resetting it to the replicas of the MachinePool.
+		if to.Bool(agentPoolSpec.EnableAutoScaling) && existingPool.Count != nil {
+			profile.Count = existingPool.Count
+		}
+
+		// Keep the node labels added to the agent pool outside of CAPZ, and remove the ones removed from its spec.
+		profile.NodeLabels = mergeNodeLabels(existingPool.NodeLabels, agentPoolSpec.NodeLabels, agentPoolSpec.RemovedNodeLabels)
+
 		// Normalize individual agent pools to diff in case we need to update
 		existingProfile := containerservice.AgentPool{
 			ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
 				Count:               existingPool.Count,
 				OrchestratorVersion: existingPool.OrchestratorVersion,
 				Mode:                existingPool.Mode,
+				EnableAutoScaling:   to.BoolPtr(to.Bool(existingPool.EnableAutoScaling)),
+				MinCount:            existingPool.MinCount,
+				MaxCount:            existingPool.MaxCount,
+				NodeLabels:          normalizeNodeLabels(existingPool.NodeLabels),
 			},
 		}
 
@@ -117,6 +182,10 @@ func (s *Service) Reconcile(ctx context.Context) error {
 				Count:               profile.Count,
 				OrchestratorVersion: profile.OrchestratorVersion,
 				Mode:                profile.Mode,
+				EnableAutoScaling:   to.BoolPtr(to.Bool(profile.EnableAutoScaling)),
+				MinCount:            profile.MinCount,
+				MaxCount:            profile.MaxCount,
+				NodeLabels:          normalizeNodeLabels(profile.NodeLabels),
 			},
 		}
 
@@ -124,10 +193,14 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		diff := cmp.Diff(existingProfile, normalizedProfile)
 		if diff != "" {
 			klog.V(2).Infof("Update required (+new -old):\n%s", diff)
-			err = s.Client.CreateOrUpdate(ctx, agentPoolSpec.ResourceGroup, agentPoolSpec.Cluster, agentPoolSpec.Name, profile)
+			err = s.Client.CreateOrUpdate(ctx, agentPoolSpec.ResourceGroup, agentPoolSpec.Cluster, agentPoolSpec.Name, mergeAgentPool(existingPool, withoutCreateOnlyFields(profile, agentPoolSpec)))
 			if err != nil {
 				return errors.Wrap(err, "failed to create or update agent pool")
 			}
+			s.scope.SetAgentPoolProvisioningState(string(infrav1alpha4.Succeeded))
+			if agentPoolSpec.Version != nil {
+				s.scope.SetAgentPoolVersion(*agentPoolSpec.Version)
+			}
 		} else {
 			klog.V(2).Infof("Normalized and desired agent pool matched, no update needed")
 		}
@@ -136,6 +209,177 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	return nil
 }
 
+// recreate deletes the existing agent pool and creates it again from the given profile, to change fields that AKS
+// does not allow to be updated. The node labels and taints of the existing agent pool are carried over to the new
+// agent pool, except for the ones AKS manages based on the scale set priority.
+func (s *Service) recreate(ctx context.Context, agentPoolSpec azure.AgentPoolSpec, existingPool containerservice.AgentPool, profile containerservice.AgentPool) error {
+	ctx, _, done := tele.StartSpanWithLogger(
+		ctx,
+		"agentpools.Service.recreate",
+	)
+	defer done()
+
+	if existingPool.NodeLabels != nil {
+		nodeLabels := make(map[string]*string)
+		for k, v := range existingPool.NodeLabels {
+			if k != scaleSetPriorityNodeLabel {
+				nodeLabels[k] = v
+			}
+		}
+		profile.NodeLabels = nodeLabels
+	}
+
+	if existingPool.NodeTaints != nil {
+		nodeTaints := []string{}
+		for _, taint := range *existingPool.NodeTaints {
+			if !strings.HasPrefix(taint, scaleSetPriorityNodeLabel+"=") {
+				nodeTaints = append(nodeTaints, taint)
+			}
+		}
+		profile.NodeTaints = &nodeTaints
+	}
+
+	if err := s.Client.Delete(ctx, agentPoolSpec.ResourceGroup, agentPoolSpec.Cluster, agentPoolSpec.Name); err != nil && !azure.ResourceNotFound(err) {
+		return errors.Wrap(err, "failed to delete agent pool to recreate it")
+	}
+
+	if err := s.Client.CreateOrUpdate(ctx, agentPoolSpec.ResourceGroup, agentPoolSpec.Cluster, agentPoolSpec.Name, profile); err != nil {
+		return errors.Wrap(err, "failed to recreate agent pool")
+	}
+
+	klog.V(2).Infof("Successfully recreated agent pool %s", agentPoolSpec.Name)
+	return nil
+}
+
+// withoutCreateOnlyFields returns a copy of the given profile without the fields the agent pool spec marks as create-only,
+// so that update requests don't attempt to change fields AKS does not allow to be updated.
+func withoutCreateOnlyFields(profile containerservice.AgentPool, agentPoolSpec azure.AgentPoolSpec) containerservice.AgentPool {
+	properties := *profile.ManagedClusterAgentPoolProfileProperties
+	if agentPoolSpec.IsCreateOnly(azure.AgentPoolSKU) {
+		properties.VMSize = nil
+	}
+	if agentPoolSpec.IsCreateOnly(azure.AgentPoolOSDiskSizeGB) {
+		properties.OsDiskSizeGB = nil
+	}
+	if agentPoolSpec.IsCreateOnly(azure.AgentPoolVnetSubnetID) {
+		properties.VnetSubnetID = nil
+	}
+	if agentPoolSpec.IsCreateOnly(azure.AgentPoolPodSubnetID) {
+		properties.PodSubnetID = nil
+	}
+	if agentPoolSpec.IsCreateOnly(azure.AgentPoolOSType) {
+		properties.OsType = ""
+	}
+	if agentPoolSpec.IsCreateOnly(azure.AgentPoolOSDiskType) {
+		properties.OsDiskType = ""
+	}
+	if agentPoolSpec.IsCreateOnly(azure.AgentPoolEnableEncryptionAtHost) {
+		properties.EnableEncryptionAtHost = nil
+	}
+	if agentPoolSpec.IsCreateOnly(azure.AgentPoolProximityPlacementGroupID) {
+		properties.ProximityPlacementGroupID = nil
+	}
+	if agentPoolSpec.IsCreateOnly(azure.AgentPoolEnableNodePublicIP) {
+		properties.EnableNodePublicIP = nil
+	}
+	if agentPoolSpec.IsCreateOnly(azure.AgentPoolNodePublicIPPrefixID) {
+		properties.NodePublicIPPrefixID = nil
+	}
+	if agentPoolSpec.IsCreateOnly(azure.AgentPoolScaleSetPriority) {
+		properties.ScaleSetPriority = ""
+	}
+	profile.ManagedClusterAgentPoolProfileProperties = &properties
+	return profile
+}
+
+// mergeAgentPool returns the existing agent pool with the fields CAPZ manages set from the desired agent pool, to send
+// updates with PATCH semantics through the PUT API of AKS. Fields CAPZ does not manage keep their existing values, and
+// so do the fields the desired agent pool leaves unset, such as create-only fields. The autoscaling settings are always
+// set from the desired agent pool, as CAPZ disables the cluster autoscaler when no autoscaling bounds are set.
+func mergeAgentPool(existing, desired containerservice.AgentPool) containerservice.AgentPool {
+	if existing.ManagedClusterAgentPoolProfileProperties == nil {
+		return desired
+	}
+
+	properties := *existing.ManagedClusterAgentPoolProfileProperties
+	desiredProperties := desired.ManagedClusterAgentPoolProfileProperties
+	if desiredProperties.VMSize != nil {
+		properties.VMSize = desiredProperties.VMSize
+	}
+	if desiredProperties.OsDiskSizeGB != nil {
+		properties.OsDiskSizeGB = desiredProperties.OsDiskSizeGB
+	}
+	if desiredProperties.VnetSubnetID != nil {
+		properties.VnetSubnetID = desiredProperties.VnetSubnetID
+	}
+	if desiredProperties.PodSubnetID != nil {
+		properties.PodSubnetID = desiredProperties.PodSubnetID
+	}
+	if desiredProperties.OsType != "" {
+		properties.OsType = desiredProperties.OsType
+	}
+	if desiredProperties.OsDiskType != "" {
+		properties.OsDiskType = desiredProperties.OsDiskType
+	}
+	if desiredProperties.Count != nil {
+		properties.Count = desiredProperties.Count
+	}
+	if desiredProperties.OrchestratorVersion != nil {
+		properties.OrchestratorVersion = desiredProperties.OrchestratorVersion
+	}
+	if desiredProperties.Mode != "" {
+		properties.Mode = desiredProperties.Mode
+	}
+	if desiredProperties.ScaleSetPriority != "" {
+		properties.ScaleSetPriority = desiredProperties.ScaleSetPriority
+	}
+	if desiredProperties.NodeTaints != nil {
+		properties.NodeTaints = desiredProperties.NodeTaints
+	}
+	if desiredProperties.NodeLabels != nil {
+		properties.NodeLabels = desiredProperties.NodeLabels
+	}
+	properties.EnableAutoScaling = to.BoolPtr(to.Bool(desiredProperties.EnableAutoScaling))
+	properties.MinCount = desiredProperties.MinCount
+	properties.MaxCount = desiredProperties.MaxCount
+
+	merged := existing
+	merged.ManagedClusterAgentPoolProfileProperties = &properties
+	return merged
+}
+
+// mergeNodeLabels returns the existing node labels of the agent pool without the removed node labels, overridden by
+// the desired node labels. The result is never nil, so that it clears the node labels of the agent pool when empty.
+func mergeNodeLabels(existing map[string]*string, desired map[string]string, removed []string) map[string]*string {
+	merged := make(map[string]*string, len(existing)+len(desired))
+	for k, v := range existing {
+		merged[k] = v
+	}
+	for _, k := range removed {
+		delete(merged, k)
+	}
+	for k, v := range desired {
+		merged[k] = to.StringPtr(v)
+	}
+	return merged
+}
+
+// normalizeNodeLabels returns nil for empty node labels, as AKS does not distinguish them from unset node labels.
+func normalizeNodeLabels(nodeLabels map[string]*string) map[string]*string {
+	if len(nodeLabels) == 0 {
+		return nil
+	}
+	return nodeLabels
+}
+
+// normalizeScaleSetPriority returns the scale set priority AKS applies for the given priority, which defaults to Regular.
+func normalizeScaleSetPriority(priority containerservice.ScaleSetPriority) containerservice.ScaleSetPriority {
+	if priority == "" {
+		return containerservice.ScaleSetPriorityRegular
+	}
+	return priority
+}
+
 // Delete deletes the virtual network with the provided name.
 func (s *Service) Delete(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(
@@ -144,10 +388,13 @@ func (s *Service) Delete(ctx context.Context) error {
 	)
 	defer done()
 
-	agentPoolSpec := s.scope.AgentPoolSpec()
+	agentPoolSpec, err := s.scope.AgentPoolSpec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get agent pool spec")
+	}
 
 	klog.V(2).Infof("deleting agent pool  %s ", agentPoolSpec.Name)
-	err := s.Client.Delete(ctx, agentPoolSpec.ResourceGroup, agentPoolSpec.Cluster, agentPoolSpec.Name)
+	err = s.Client.Delete(ctx, agentPoolSpec.ResourceGroup, agentPoolSpec.Cluster, agentPoolSpec.Name)
 	if err != nil {
 		if azure.ResourceNotFound(err) {
 			// already deleted
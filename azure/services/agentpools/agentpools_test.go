@@ -20,6 +20,7 @@ import (
 	"context"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
 	"github.com/Azure/go-autorest/autorest"
@@ -27,8 +28,10 @@ import (
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	capi "sigs.k8s.io/cluster-api/api/v1beta1"
 	capiexp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
@@ -69,7 +72,7 @@ func TestReconcile(t *testing.T) {
 				Name:          "my-agentpool",
 			},
 			provisioningStatesToTest: []string{"Deleting", "InProgress", "randomStringHere"},
-			expectedError:            "Unable to update existing agent pool in non terminal state. Agent pool must be in one of the following provisioning states: canceled, failed, or succeeded. Actual state: randomStringHere",
+			expectedError:            "Unable to update existing agent pool in non terminal state. Agent pool must be in one of the following provisioning states: canceled, failed, or succeeded. Actual state: ",
 			expect: func(m *mock_agentpools.MockClientMockRecorder, provisioningstate string) {
 				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agentpool").Return(containerservice.AgentPool{ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
 					ProvisioningState: &provisioningstate,
@@ -82,6 +85,7 @@ func TestReconcile(t *testing.T) {
 		for _, provisioningstate := range tc.provisioningStatesToTest {
 			t.Logf("Testing agentpool provision state: " + provisioningstate)
 			tc := tc
+			provisioningstate := provisioningstate
 			t.Run(tc.name, func(t *testing.T) {
 				g := NewWithT(t)
 				t.Parallel()
@@ -316,6 +320,7 @@ func TestDeleteAgentPools(t *testing.T) {
 	testcases := []struct {
 		name           string
 		agentPoolsSpec azure.AgentPoolSpec
+		mode           infraexpv1.NodePoolMode
 		expectedError  string
 		expect         func(m *mock_agentpools.MockClientMockRecorder)
 	}{
@@ -357,6 +362,30 @@ func TestDeleteAgentPools(t *testing.T) {
 					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
 			},
 		},
+		{
+			name: "successfully delete a user agent pool",
+			agentPoolsSpec: azure.AgentPoolSpec{
+				Name:          "my-agent-pool",
+				ResourceGroup: "my-rg",
+				Cluster:       "my-cluster",
+			},
+			mode:          infraexpv1.NodePoolModeUser,
+			expectedError: "",
+			expect: func(m *mock_agentpools.MockClientMockRecorder) {
+				m.Delete(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool")
+			},
+		},
+		{
+			name: "refuse to delete the last system agent pool",
+			agentPoolsSpec: azure.AgentPoolSpec{
+				Name:          "my-agent-pool",
+				ResourceGroup: "my-rg",
+				Cluster:       "my-cluster",
+			},
+			mode:          infraexpv1.NodePoolModeSystem,
+			expectedError: "failed to get agent pool spec: agent pool my-agent-pool cannot be deleted, the cluster must have at least one agent pool in mode System. Object will be requeued after 30s",
+			expect:        func(m *mock_agentpools.MockClientMockRecorder) {},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -367,8 +396,12 @@ func TestDeleteAgentPools(t *testing.T) {
 			mockCtrl := gomock.NewController(t)
 			defer mockCtrl.Finish()
 
+			scheme := runtime.NewScheme()
+			g.Expect(infraexpv1.AddToScheme(scheme)).To(Succeed())
+
 			agentPoolsMock := mock_agentpools.NewMockClient(mockCtrl)
 			machinePoolScope := &scope.ManagedControlPlaneScope{
+				Client: fakeclient.NewClientBuilder().WithScheme(scheme).Build(),
 				ControlPlane: &infraexpv1.AzureManagedControlPlane{
 					ObjectMeta: metav1.ObjectMeta{
 						Name: tc.agentPoolsSpec.Cluster,
@@ -380,10 +413,15 @@ func TestDeleteAgentPools(t *testing.T) {
 				MachinePool: &capiexp.MachinePool{},
 				InfraMachinePool: &infraexpv1.AzureManagedMachinePool{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: tc.agentPoolsSpec.Name,
+						Name:              tc.agentPoolsSpec.Name,
+						DeletionTimestamp: &metav1.Time{Time: time.Now()},
+						Labels: map[string]string{
+							capi.ClusterLabelName: tc.agentPoolsSpec.Cluster,
+						},
 					},
 					Spec: infraexpv1.AzureManagedMachinePoolSpec{
 						Name: &tc.agentPoolsSpec.Name,
+						Mode: string(tc.mode),
 					},
 				},
 			}
@@ -405,3 +443,519 @@ func TestDeleteAgentPools(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileScaleSetPriority(t *testing.T) {
+	existingPool := containerservice.AgentPool{
+		ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
+			Count:               to.Int32Ptr(2),
+			OrchestratorVersion: to.StringPtr("9.99.9999"),
+			Mode:                containerservice.AgentPoolModeUser,
+			ProvisioningState:   to.StringPtr("Succeeded"),
+			ScaleSetPriority:    containerservice.ScaleSetPrioritySpot,
+			NodeLabels: map[string]*string{
+				"workload":                              to.StringPtr("batch"),
+				"kubernetes.azure.com/scalesetpriority": to.StringPtr("spot"),
+			},
+			NodeTaints: &[]string{
+				"dedicated=batch:NoSchedule",
+				"kubernetes.azure.com/scalesetpriority=spot:NoSchedule",
+			},
+		},
+	}
+
+	testcases := []struct {
+		name          string
+		annotations   map[string]string
+		replicas      int32
+		expectedError string
+		expect        func(g *WithT, m *mock_agentpools.MockClientMockRecorder)
+	}{
+		{
+			name: "priority is not changed without the recreate annotation",
+			expect: func(g *WithT, m *mock_agentpools.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(existingPool, nil)
+			},
+		},
+		{
+			name:     "priority is kept when the node count changes without the recreate annotation",
+			replicas: 3,
+			expect: func(g *WithT, m *mock_agentpools.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(existingPool, nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool", gomock.AssignableToTypeOf(containerservice.AgentPool{})).
+					DoAndReturn(func(_ context.Context, _, _, _ string, profile containerservice.AgentPool) error {
+						g.Expect(profile.ScaleSetPriority).To(Equal(containerservice.ScaleSetPrioritySpot))
+						g.Expect(profile.Count).To(Equal(to.Int32Ptr(3)))
+						return nil
+					})
+			},
+		},
+		{
+			name: "agent pool is recreated with the new priority and its labels and taints when the recreate annotation is set",
+			annotations: map[string]string{
+				infraexpv1.AgentPoolRecreateAnnotation: "",
+			},
+			expect: func(g *WithT, m *mock_agentpools.MockClientMockRecorder) {
+				gomock.InOrder(
+					m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(existingPool, nil),
+					m.Delete(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(nil),
+					m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool", gomock.AssignableToTypeOf(containerservice.AgentPool{})).
+						DoAndReturn(func(_ context.Context, _, _, _ string, profile containerservice.AgentPool) error {
+							g.Expect(profile.ScaleSetPriority).To(Equal(containerservice.ScaleSetPriorityRegular))
+							g.Expect(profile.NodeLabels).To(Equal(map[string]*string{"workload": to.StringPtr("batch")}))
+							g.Expect(profile.NodeTaints).To(Equal(&[]string{"dedicated=batch:NoSchedule"}))
+							return nil
+						}),
+				)
+			},
+		},
+		{
+			name: "agent pool recreation fails to delete the existing agent pool",
+			annotations: map[string]string{
+				infraexpv1.AgentPoolRecreateAnnotation: "",
+			},
+			expectedError: "failed to delete agent pool to recreate it: #: Internal Server Error: StatusCode=500",
+			expect: func(g *WithT, m *mock_agentpools.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(existingPool, nil)
+				m.Delete(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").
+					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			replicas := int32(2)
+			if tc.replicas != 0 {
+				replicas = tc.replicas
+			}
+			agentPoolsMock := mock_agentpools.NewMockClient(mockCtrl)
+			machinePoolScope := &scope.ManagedControlPlaneScope{
+				ControlPlane: &infraexpv1.AzureManagedControlPlane{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "my-cluster",
+					},
+					Spec: infraexpv1.AzureManagedControlPlaneSpec{
+						ResourceGroupName: "my-rg",
+					},
+				},
+				MachinePool: &capiexp.MachinePool{
+					Spec: capiexp.MachinePoolSpec{
+						Replicas: &replicas,
+						Template: capi.MachineTemplateSpec{
+							Spec: capi.MachineSpec{
+								Version: to.StringPtr("9.99.9999"),
+							},
+						},
+					},
+				},
+				InfraMachinePool: &infraexpv1.AzureManagedMachinePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "my-agent-pool",
+						Annotations: tc.annotations,
+					},
+					Spec: infraexpv1.AzureManagedMachinePoolSpec{
+						Name:             to.StringPtr("my-agent-pool"),
+						Mode:             "User",
+						ScaleSetPriority: to.StringPtr("Regular"),
+					},
+				},
+			}
+
+			tc.expect(g, agentPoolsMock.EXPECT())
+
+			s := &Service{
+				Client: agentPoolsMock,
+				scope:  machinePoolScope,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestReconcileGPUSharingNodeLabels(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	agentPoolsMock := mock_agentpools.NewMockClient(mockCtrl)
+	machinePoolScope := &scope.ManagedControlPlaneScope{
+		ControlPlane: &infraexpv1.AzureManagedControlPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "my-cluster",
+			},
+			Spec: infraexpv1.AzureManagedControlPlaneSpec{
+				ResourceGroupName: "my-rg",
+			},
+		},
+		MachinePool: &capiexp.MachinePool{},
+		InfraMachinePool: &infraexpv1.AzureManagedMachinePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "my-agent-pool",
+			},
+			Spec: infraexpv1.AzureManagedMachinePoolSpec{
+				Name: to.StringPtr("my-agent-pool"),
+				Mode: "User",
+				SKU:  "Standard_NC6s_v3",
+				GPUSharing: &infraexpv1.GPUSharing{
+					TimeSlicingReplicas: 4,
+				},
+			},
+		},
+	}
+
+	agentPoolsMock.EXPECT().Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").
+		Return(containerservice.AgentPool{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+	agentPoolsMock.EXPECT().CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool", gomock.AssignableToTypeOf(containerservice.AgentPool{})).
+		DoAndReturn(func(_ context.Context, _, _, _ string, profile containerservice.AgentPool) error {
+			g.Expect(profile.NodeLabels).To(Equal(map[string]*string{"nvidia.com/device-plugin.config": to.StringPtr("time-slicing-4")}))
+			return nil
+		})
+
+	s := &Service{
+		Client: agentPoolsMock,
+		scope:  machinePoolScope,
+	}
+
+	g.Expect(s.Reconcile(context.TODO())).To(Succeed())
+}
+
+func TestReconcileCreateOnlyFields(t *testing.T) {
+	testcases := []struct {
+		name   string
+		expect func(g *WithT, m *mock_agentpools.MockClientMockRecorder)
+	}{
+		{
+			name: "create-only fields are sent when the agent pool is created",
+			expect: func(g *WithT, m *mock_agentpools.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").
+					Return(containerservice.AgentPool{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool", gomock.AssignableToTypeOf(containerservice.AgentPool{})).
+					DoAndReturn(func(_ context.Context, _, _, _ string, profile containerservice.AgentPool) error {
+						g.Expect(profile.VMSize).To(Equal(to.StringPtr("Standard_D2s_v3")))
+						g.Expect(profile.OsDiskSizeGB).To(Equal(to.Int32Ptr(128)))
+						g.Expect(profile.VnetSubnetID).To(Equal(to.StringPtr("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet")))
+						g.Expect(profile.OsType).To(Equal(containerservice.OSTypeLinux))
+						return nil
+					})
+			},
+		},
+		{
+			name: "create-only fields are omitted when the agent pool is updated",
+			expect: func(g *WithT, m *mock_agentpools.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(containerservice.AgentPool{
+					ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
+						Count:             to.Int32Ptr(1),
+						Mode:              containerservice.AgentPoolModeUser,
+						ProvisioningState: to.StringPtr("Succeeded"),
+					},
+				}, nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool", gomock.AssignableToTypeOf(containerservice.AgentPool{})).
+					DoAndReturn(func(_ context.Context, _, _, _ string, profile containerservice.AgentPool) error {
+						g.Expect(profile.Count).To(Equal(to.Int32Ptr(3)))
+						g.Expect(profile.VMSize).To(BeNil())
+						g.Expect(profile.OsDiskSizeGB).To(BeNil())
+						g.Expect(profile.VnetSubnetID).To(BeNil())
+						g.Expect(profile.OsType).To(BeEmpty())
+						return nil
+					})
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			agentPoolsMock := mock_agentpools.NewMockClient(mockCtrl)
+			machinePoolScope := &scope.ManagedControlPlaneScope{
+				ControlPlane: &infraexpv1.AzureManagedControlPlane{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "my-cluster",
+					},
+					Spec: infraexpv1.AzureManagedControlPlaneSpec{
+						ResourceGroupName: "my-rg",
+						SubscriptionID:    "00000000-0000-0000-0000-000000000000",
+						VirtualNetwork: infraexpv1.ManagedControlPlaneVirtualNetwork{
+							Name: "my-vnet",
+							Subnet: infraexpv1.ManagedControlPlaneSubnet{
+								Name: "my-subnet",
+							},
+						},
+					},
+				},
+				MachinePool: &capiexp.MachinePool{
+					Spec: capiexp.MachinePoolSpec{
+						Replicas: to.Int32Ptr(3),
+					},
+				},
+				InfraMachinePool: &infraexpv1.AzureManagedMachinePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "my-agent-pool",
+					},
+					Spec: infraexpv1.AzureManagedMachinePoolSpec{
+						Name:         to.StringPtr("my-agent-pool"),
+						Mode:         "User",
+						SKU:          "Standard_D2s_v3",
+						OSDiskSizeGB: to.Int32Ptr(128),
+					},
+				},
+			}
+
+			tc.expect(g, agentPoolsMock.EXPECT())
+
+			s := &Service{
+				Client: agentPoolsMock,
+				scope:  machinePoolScope,
+			}
+
+			g.Expect(s.Reconcile(context.TODO())).To(Succeed())
+		})
+	}
+}
+
+func TestReconcileAutoScaling(t *testing.T) {
+	existingPool := func(maxCount int32) containerservice.AgentPool {
+		return containerservice.AgentPool{
+			ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
+				Count:             to.Int32Ptr(5),
+				Mode:              containerservice.AgentPoolModeUser,
+				ProvisioningState: to.StringPtr("Succeeded"),
+				EnableAutoScaling: to.BoolPtr(true),
+				MinCount:          to.Int32Ptr(2),
+				MaxCount:          to.Int32Ptr(maxCount),
+			},
+		}
+	}
+
+	testcases := []struct {
+		name   string
+		expect func(g *WithT, m *mock_agentpools.MockClientMockRecorder)
+	}{
+		{
+			name: "agent pool is created with the MachinePool replicas as its initial node count",
+			expect: func(g *WithT, m *mock_agentpools.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").
+					Return(containerservice.AgentPool{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool", gomock.AssignableToTypeOf(containerservice.AgentPool{})).
+					DoAndReturn(func(_ context.Context, _, _, _ string, profile containerservice.AgentPool) error {
+						g.Expect(profile.Count).To(Equal(to.Int32Ptr(3)))
+						g.Expect(profile.EnableAutoScaling).To(Equal(to.BoolPtr(true)))
+						g.Expect(profile.MinCount).To(Equal(to.Int32Ptr(2)))
+						g.Expect(profile.MaxCount).To(Equal(to.Int32Ptr(10)))
+						return nil
+					})
+			},
+		},
+		{
+			name: "node count set by the cluster autoscaler is not reset to the MachinePool replicas",
+			expect: func(g *WithT, m *mock_agentpools.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(existingPool(10), nil)
+			},
+		},
+		{
+			name: "autoscaling bounds are updated without resetting the node count",
+			expect: func(g *WithT, m *mock_agentpools.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(existingPool(20), nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool", gomock.AssignableToTypeOf(containerservice.AgentPool{})).
+					DoAndReturn(func(_ context.Context, _, _, _ string, profile containerservice.AgentPool) error {
+						g.Expect(profile.Count).To(Equal(to.Int32Ptr(5)))
+						g.Expect(profile.MaxCount).To(Equal(to.Int32Ptr(10)))
+						return nil
+					})
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			agentPoolsMock := mock_agentpools.NewMockClient(mockCtrl)
+			machinePoolScope := &scope.ManagedControlPlaneScope{
+				ControlPlane: &infraexpv1.AzureManagedControlPlane{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "my-cluster",
+					},
+					Spec: infraexpv1.AzureManagedControlPlaneSpec{
+						ResourceGroupName: "my-rg",
+					},
+				},
+				MachinePool: &capiexp.MachinePool{
+					Spec: capiexp.MachinePoolSpec{
+						Replicas: to.Int32Ptr(3),
+					},
+				},
+				InfraMachinePool: &infraexpv1.AzureManagedMachinePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "my-agent-pool",
+					},
+					Spec: infraexpv1.AzureManagedMachinePoolSpec{
+						Name: to.StringPtr("my-agent-pool"),
+						Mode: "User",
+						SKU:  "Standard_D2s_v3",
+						Scaling: &infraexpv1.ManagedMachinePoolScaling{
+							MinSize: 2,
+							MaxSize: 10,
+						},
+					},
+				},
+			}
+
+			tc.expect(g, agentPoolsMock.EXPECT())
+
+			s := &Service{
+				Client: agentPoolsMock,
+				scope:  machinePoolScope,
+			}
+
+			g.Expect(s.Reconcile(context.TODO())).To(Succeed())
+		})
+	}
+}
+
+func TestReconcileKeepsOutOfBandChanges(t *testing.T) {
+	g := NewWithT(t)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	agentPoolsMock := mock_agentpools.NewMockClient(mockCtrl)
+	machinePoolScope := &scope.ManagedControlPlaneScope{
+		ControlPlane: &infraexpv1.AzureManagedControlPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "my-cluster",
+			},
+			Spec: infraexpv1.AzureManagedControlPlaneSpec{
+				ResourceGroupName: "my-rg",
+			},
+		},
+		MachinePool: &capiexp.MachinePool{
+			Spec: capiexp.MachinePoolSpec{
+				Replicas: to.Int32Ptr(3),
+			},
+		},
+		InfraMachinePool: &infraexpv1.AzureManagedMachinePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "my-agent-pool",
+			},
+			Spec: infraexpv1.AzureManagedMachinePoolSpec{
+				Name: to.StringPtr("my-agent-pool"),
+				Mode: "User",
+				SKU:  "Standard_D2s_v3",
+			},
+		},
+	}
+
+	agentPoolsMock.EXPECT().Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(containerservice.AgentPool{
+		ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
+			Count:             to.Int32Ptr(1),
+			Mode:              containerservice.AgentPoolModeUser,
+			ProvisioningState: to.StringPtr("Succeeded"),
+			UpgradeSettings: &containerservice.AgentPoolUpgradeSettings{
+				MaxSurge: to.StringPtr("33%"),
+			},
+		},
+	}, nil)
+	agentPoolsMock.EXPECT().CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool", gomock.AssignableToTypeOf(containerservice.AgentPool{})).
+		DoAndReturn(func(_ context.Context, _, _, _ string, profile containerservice.AgentPool) error {
+			g.Expect(profile.Count).To(Equal(to.Int32Ptr(3)))
+			g.Expect(profile.UpgradeSettings).To(Equal(&containerservice.AgentPoolUpgradeSettings{MaxSurge: to.StringPtr("33%")}))
+			return nil
+		})
+
+	s := &Service{
+		Client: agentPoolsMock,
+		scope:  machinePoolScope,
+	}
+
+	g.Expect(s.Reconcile(context.TODO())).To(Succeed())
+}
+
+func TestReconcileRemovedNodeLabels(t *testing.T) {
+	g := NewWithT(t)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	agentPoolsMock := mock_agentpools.NewMockClient(mockCtrl)
+	machinePoolScope := &scope.ManagedControlPlaneScope{
+		ControlPlane: &infraexpv1.AzureManagedControlPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "my-cluster",
+			},
+			Spec: infraexpv1.AzureManagedControlPlaneSpec{
+				ResourceGroupName: "my-rg",
+			},
+		},
+		MachinePool: &capiexp.MachinePool{
+			Spec: capiexp.MachinePoolSpec{
+				Replicas: to.Int32Ptr(1),
+			},
+		},
+		InfraMachinePool: &infraexpv1.AzureManagedMachinePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "my-agent-pool",
+				Annotations: map[string]string{
+					infraexpv1.AgentPoolNodeLabelsAnnotation: "env,team",
+				},
+			},
+			Spec: infraexpv1.AzureManagedMachinePoolSpec{
+				Name: to.StringPtr("my-agent-pool"),
+				Mode: "User",
+				SKU:  "Standard_D2s_v3",
+				NodeLabels: map[string]string{
+					"team": "b",
+				},
+			},
+		},
+	}
+
+	agentPoolsMock.EXPECT().Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(containerservice.AgentPool{
+		ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
+			Count:             to.Int32Ptr(1),
+			Mode:              containerservice.AgentPoolModeUser,
+			ProvisioningState: to.StringPtr("Succeeded"),
+			NodeLabels: map[string]*string{
+				"team":     to.StringPtr("a"),
+				"env":      to.StringPtr("prod"),
+				"external": to.StringPtr("true"),
+			},
+		},
+	}, nil)
+	agentPoolsMock.EXPECT().CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool", gomock.AssignableToTypeOf(containerservice.AgentPool{})).
+		DoAndReturn(func(_ context.Context, _, _, _ string, profile containerservice.AgentPool) error {
+			g.Expect(profile.NodeLabels).To(Equal(map[string]*string{
+				"team":     to.StringPtr("b"),
+				"external": to.StringPtr("true"),
+			}))
+			return nil
+		})
+
+	s := &Service{
+		Client: agentPoolsMock,
+		scope:  machinePoolScope,
+	}
+
+	g.Expect(s.Reconcile(context.TODO())).To(Succeed())
+}
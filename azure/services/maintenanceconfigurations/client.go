@@ -0,0 +1,69 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenanceconfigurations
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
+	"github.com/Azure/go-autorest/autorest"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// client wraps go-sdk.
+type client interface {
+	Get(ctx context.Context, resourceGroupName, clusterName, name string) (containerservice.MaintenanceConfiguration, error)
+	CreateOrUpdate(ctx context.Context, resourceGroupName, clusterName, name string, parameters containerservice.MaintenanceConfiguration) (containerservice.MaintenanceConfiguration, error)
+}
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	maintenanceconfigurations containerservice.MaintenanceConfigurationsClient
+}
+
+var _ client = (*azureClient)(nil)
+
+// newClient creates a new maintenance configurations client from subscription ID.
+func newClient(auth azure.Authorizer) *azureClient {
+	c := newMaintenanceConfigurationsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
+	return &azureClient{c}
+}
+
+// newMaintenanceConfigurationsClient creates a new maintenance configurations client from subscription ID.
+func newMaintenanceConfigurationsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) containerservice.MaintenanceConfigurationsClient {
+	maintenanceConfigurationsClient := containerservice.NewMaintenanceConfigurationsClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&maintenanceConfigurationsClient.Client, authorizer)
+	return maintenanceConfigurationsClient
+}
+
+// Get gets the maintenance configuration of a managed cluster.
+func (ac *azureClient) Get(ctx context.Context, resourceGroupName, clusterName, name string) (containerservice.MaintenanceConfiguration, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "maintenanceconfigurations.AzureClient.Get")
+	defer done()
+
+	return ac.maintenanceconfigurations.Get(ctx, resourceGroupName, clusterName, name)
+}
+
+// CreateOrUpdate creates or updates the maintenance configuration of a managed cluster.
+func (ac *azureClient) CreateOrUpdate(ctx context.Context, resourceGroupName, clusterName, name string, parameters containerservice.MaintenanceConfiguration) (containerservice.MaintenanceConfiguration, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "maintenanceconfigurations.AzureClient.CreateOrUpdate")
+	defer done()
+
+	return ac.maintenanceconfigurations.CreateOrUpdate(ctx, resourceGroupName, clusterName, name, parameters)
+}
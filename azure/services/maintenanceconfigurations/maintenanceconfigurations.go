@@ -0,0 +1,143 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenanceconfigurations
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
+	"github.com/Azure/go-autorest/autorest/date"
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// MaintenanceConfigurationScope defines the scope interface for a maintenance configurations service.
+type MaintenanceConfigurationScope interface {
+	logr.Logger
+	azure.Authorizer
+	MaintenanceConfigurationSpecs() []azure.MaintenanceConfigurationSpec
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope MaintenanceConfigurationScope
+	client
+}
+
+// New creates a new service.
+func New(scope MaintenanceConfigurationScope) *Service {
+	return &Service{
+		Scope:  scope,
+		client: newClient(scope),
+	}
+}
+
+// Reconcile creates or updates the planned maintenance configurations of the managed cluster for the configured
+// maintenance windows. Each maintenance configuration is reconciled independently, so that failing to reconcile one
+// does not hold back the others. The maintenance configuration of a window that is not configured is left untouched.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "maintenanceconfigurations.Service.Reconcile")
+	defer done()
+
+	var errs []error
+	for _, spec := range s.Scope.MaintenanceConfigurationSpecs() {
+		if err := s.reconcileMaintenanceConfiguration(ctx, spec); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// reconcileMaintenanceConfiguration creates or updates a maintenance configuration unless it is up to date.
+func (s *Service) reconcileMaintenanceConfiguration(ctx context.Context, spec azure.MaintenanceConfigurationSpec) error {
+	properties := maintenanceConfigurationProperties(spec)
+
+	existing, err := s.client.Get(ctx, spec.ResourceGroup, spec.Cluster, spec.Name)
+	if err != nil && !azure.ResourceNotFound(err) {
+		return errors.Wrapf(err, "failed to get maintenance configuration %s of managed cluster %s", spec.Name, spec.Cluster)
+	}
+	if err == nil && existing.MaintenanceConfigurationProperties != nil && maintenanceConfigurationPropertiesEqual(properties, *existing.MaintenanceConfigurationProperties) {
+		s.Scope.V(4).Info("maintenance configuration is up to date", "maintenance configuration", spec.Name)
+		return nil
+	}
+
+	s.Scope.V(2).Info("creating or updating maintenance configuration", "maintenance configuration", spec.Name)
+	if _, err := s.client.CreateOrUpdate(ctx, spec.ResourceGroup, spec.Cluster, spec.Name, containerservice.MaintenanceConfiguration{
+		MaintenanceConfigurationProperties: &properties,
+	}); err != nil {
+		return errors.Wrapf(err, "failed to create or update maintenance configuration %s of managed cluster %s", spec.Name, spec.Cluster)
+	}
+	s.Scope.V(2).Info("successfully created or updated maintenance configuration", "maintenance configuration", spec.Name)
+	return nil
+}
+
+// Delete is a no-op as the maintenance configuration is deleted with the managed cluster.
+func (s *Service) Delete(ctx context.Context) error {
+	return nil
+}
+
+// maintenanceConfigurationProperties returns the maintenance configuration properties for the spec.
+func maintenanceConfigurationProperties(spec azure.MaintenanceConfigurationSpec) containerservice.MaintenanceConfigurationProperties {
+	timeInWeek := make([]containerservice.TimeInWeek, 0, len(spec.TimeInWeek))
+	for _, t := range spec.TimeInWeek {
+		hourSlots := t.HourSlots
+		timeInWeek = append(timeInWeek, containerservice.TimeInWeek{
+			Day:       containerservice.WeekDay(t.Day),
+			HourSlots: &hourSlots,
+		})
+	}
+	notAllowedTime := make([]containerservice.TimeSpan, 0, len(spec.NotAllowedTime))
+	for _, t := range spec.NotAllowedTime {
+		notAllowedTime = append(notAllowedTime, containerservice.TimeSpan{
+			Start: &date.Time{Time: t.Start},
+			End:   &date.Time{Time: t.End},
+		})
+	}
+	return containerservice.MaintenanceConfigurationProperties{
+		TimeInWeek:     &timeInWeek,
+		NotAllowedTime: &notAllowedTime,
+	}
+}
+
+// maintenanceConfigurationPropertiesEqual reports whether the maintenance configuration properties are equal, treating
+// unset and empty lists as equal and comparing times regardless of their location.
+func maintenanceConfigurationPropertiesEqual(a, b containerservice.MaintenanceConfigurationProperties) bool {
+	return cmp.Equal(a, b,
+		cmp.Transformer("timeInWeek", func(t *[]containerservice.TimeInWeek) []containerservice.TimeInWeek {
+			if t == nil {
+				return nil
+			}
+			return *t
+		}),
+		cmp.Transformer("timeSpans", func(t *[]containerservice.TimeSpan) []containerservice.TimeSpan {
+			if t == nil {
+				return nil
+			}
+			return *t
+		}),
+		cmp.Comparer(func(x, y date.Time) bool {
+			return x.Equal(y.Time)
+		}),
+		cmpopts.EquateEmpty(),
+	)
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenanceconfigurations
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/date"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/v2/klogr"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/maintenanceconfigurations/mock_maintenanceconfigurations"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+func TestReconcileMaintenanceConfigurations(t *testing.T) {
+	start := time.Date(2021, time.December, 24, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2021, time.December, 27, 0, 0, 0, 0, time.UTC)
+	spec := azure.MaintenanceConfigurationSpec{
+		Name:          "default",
+		ResourceGroup: "my-rg",
+		Cluster:       "my-cluster",
+		TimeInWeek: []azure.MaintenanceTimeInWeek{
+			{Day: "Saturday", HourSlots: []int32{1, 2}},
+		},
+		NotAllowedTime: []azure.MaintenanceTimeSpan{
+			{Start: start, End: end},
+		},
+	}
+	nodeOSUpgradeSpec := spec
+	nodeOSUpgradeSpec.Name = "aksManagedNodeOSUpgradeSchedule"
+	properties := &containerservice.MaintenanceConfigurationProperties{
+		TimeInWeek: &[]containerservice.TimeInWeek{
+			{Day: containerservice.WeekDaySaturday, HourSlots: &[]int32{1, 2}},
+		},
+		NotAllowedTime: &[]containerservice.TimeSpan{
+			{Start: &date.Time{Time: start}, End: &date.Time{Time: end}},
+		},
+	}
+
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_maintenanceconfigurations.MockMaintenanceConfigurationScopeMockRecorder, m *mock_maintenanceconfigurations.MockclientMockRecorder)
+	}{
+		{
+			name:          "no maintenance windows",
+			expectedError: "",
+			expect: func(s *mock_maintenanceconfigurations.MockMaintenanceConfigurationScopeMockRecorder, m *mock_maintenanceconfigurations.MockclientMockRecorder) {
+				s.MaintenanceConfigurationSpecs().Return(nil)
+			},
+		},
+		{
+			name:          "maintenance configuration does not exist",
+			expectedError: "",
+			expect: func(s *mock_maintenanceconfigurations.MockMaintenanceConfigurationScopeMockRecorder, m *mock_maintenanceconfigurations.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.MaintenanceConfigurationSpecs().Return([]azure.MaintenanceConfigurationSpec{spec})
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "default").
+					Return(containerservice.MaintenanceConfiguration{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster", "default", containerservice.MaintenanceConfiguration{
+					MaintenanceConfigurationProperties: properties,
+				})
+			},
+		},
+		{
+			name:          "maintenance configuration is up to date",
+			expectedError: "",
+			expect: func(s *mock_maintenanceconfigurations.MockMaintenanceConfigurationScopeMockRecorder, m *mock_maintenanceconfigurations.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.MaintenanceConfigurationSpecs().Return([]azure.MaintenanceConfigurationSpec{spec})
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "default").Return(containerservice.MaintenanceConfiguration{
+					MaintenanceConfigurationProperties: &containerservice.MaintenanceConfigurationProperties{
+						TimeInWeek: properties.TimeInWeek,
+						NotAllowedTime: &[]containerservice.TimeSpan{
+							{Start: &date.Time{Time: start.In(time.FixedZone("UTC+1", 3600))}, End: &date.Time{Time: end}},
+						},
+					},
+				}, nil)
+			},
+		},
+		{
+			name:          "maintenance configuration is updated",
+			expectedError: "",
+			expect: func(s *mock_maintenanceconfigurations.MockMaintenanceConfigurationScopeMockRecorder, m *mock_maintenanceconfigurations.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.MaintenanceConfigurationSpecs().Return([]azure.MaintenanceConfigurationSpec{spec})
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "default").Return(containerservice.MaintenanceConfiguration{
+					MaintenanceConfigurationProperties: &containerservice.MaintenanceConfigurationProperties{
+						TimeInWeek: &[]containerservice.TimeInWeek{
+							{Day: containerservice.WeekDaySunday, HourSlots: &[]int32{1, 2}},
+						},
+					},
+				}, nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster", "default", containerservice.MaintenanceConfiguration{
+					MaintenanceConfigurationProperties: properties,
+				})
+			},
+		},
+		{
+			name:          "fail to get maintenance configuration",
+			expectedError: "failed to get maintenance configuration default of managed cluster my-cluster: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_maintenanceconfigurations.MockMaintenanceConfigurationScopeMockRecorder, m *mock_maintenanceconfigurations.MockclientMockRecorder) {
+				s.MaintenanceConfigurationSpecs().Return([]azure.MaintenanceConfigurationSpec{spec})
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "default").
+					Return(containerservice.MaintenanceConfiguration{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+		{
+			name:          "node OS upgrade maintenance configuration is reconciled when the default one fails",
+			expectedError: "failed to get maintenance configuration default of managed cluster my-cluster: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_maintenanceconfigurations.MockMaintenanceConfigurationScopeMockRecorder, m *mock_maintenanceconfigurations.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.MaintenanceConfigurationSpecs().Return([]azure.MaintenanceConfigurationSpec{spec, nodeOSUpgradeSpec})
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "default").
+					Return(containerservice.MaintenanceConfiguration{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "aksManagedNodeOSUpgradeSchedule").
+					Return(containerservice.MaintenanceConfiguration{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster", "aksManagedNodeOSUpgradeSchedule", containerservice.MaintenanceConfiguration{
+					MaintenanceConfigurationProperties: properties,
+				})
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_maintenanceconfigurations.NewMockMaintenanceConfigurationScope(mockCtrl)
+			clientMock := mock_maintenanceconfigurations.NewMockclient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
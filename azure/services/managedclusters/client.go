@@ -20,6 +20,7 @@ import (
 	"context"
 
 	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-05-01/resources"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/pkg/errors"
 
@@ -31,6 +32,7 @@ import (
 type Client interface {
 	Get(context.Context, string, string) (containerservice.ManagedCluster, error)
 	GetCredentials(context.Context, string, string) ([]byte, error)
+	GetUserCredentials(context.Context, string, string) ([]byte, error)
 	CreateOrUpdate(context.Context, string, string, containerservice.ManagedCluster) (containerservice.ManagedCluster, error)
 	Delete(context.Context, string, string) error
 }
@@ -49,6 +51,53 @@ func NewClient(auth azure.Authorizer) *AzureClient {
 	}
 }
 
+// nodeResourceGroupClient wraps the go-sdk clients used to inspect an existing node resource group.
+type nodeResourceGroupClient interface {
+	Get(context.Context, string) (resources.Group, error)
+	HasResources(context.Context, string) (bool, error)
+}
+
+// azureNodeResourceGroupClient contains the Azure go-sdk clients for resource groups and their resources.
+type azureNodeResourceGroupClient struct {
+	groups    resources.GroupsClient
+	resources resources.Client
+}
+
+var _ nodeResourceGroupClient = (*azureNodeResourceGroupClient)(nil)
+
+// newNodeResourceGroupClient creates a new node resource group client from subscription ID.
+func newNodeResourceGroupClient(auth azure.Authorizer) *azureNodeResourceGroupClient {
+	groupsClient := resources.NewGroupsClientWithBaseURI(auth.BaseURI(), auth.SubscriptionID())
+	azure.SetAutoRestClientDefaults(&groupsClient.Client, auth.Authorizer())
+	resourcesClient := resources.NewClientWithBaseURI(auth.BaseURI(), auth.SubscriptionID())
+	azure.SetAutoRestClientDefaults(&resourcesClient.Client, auth.Authorizer())
+	return &azureNodeResourceGroupClient{
+		groups:    groupsClient,
+		resources: resourcesClient,
+	}
+}
+
+// Get gets a resource group.
+func (ac *azureNodeResourceGroupClient) Get(ctx context.Context, name string) (resources.Group, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.azureNodeResourceGroupClient.Get")
+	defer done()
+
+	return ac.groups.Get(ctx, name)
+}
+
+// HasResources returns true if the resource group contains any resources.
+func (ac *azureNodeResourceGroupClient) HasResources(ctx context.Context, name string) (bool, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.azureNodeResourceGroupClient.HasResources")
+	defer done()
+
+	var top int32 = 1
+	page, err := ac.resources.ListByResourceGroup(ctx, name, "", "", &top)
+	if err != nil {
+		return false, err
+	}
+	return len(page.Values()) > 0, nil
+}
+
 // newManagedClustersClient creates a new managed clusters client from subscription ID.
 func newManagedClustersClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) containerservice.ManagedClustersClient {
 	managedClustersClient := containerservice.NewManagedClustersClientWithBaseURI(baseURI, subscriptionID)
@@ -78,6 +127,24 @@ func (ac *AzureClient) GetCredentials(ctx context.Context, resourceGroupName, na
 	return *(*credentialList.Kubeconfigs)[0].Value, nil
 }
 
+// GetUserCredentials fetches the user kubeconfig for a managed cluster, which is the only kubeconfig available when
+// local accounts are disabled.
+func (ac *AzureClient) GetUserCredentials(ctx context.Context, resourceGroupName, name string) ([]byte, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.AzureClient.GetUserCredentials")
+	defer done()
+
+	credentialList, err := ac.managedclusters.ListClusterUserCredentials(ctx, resourceGroupName, name, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if credentialList.Kubeconfigs == nil || len(*credentialList.Kubeconfigs) < 1 {
+		return nil, errors.New("no user kubeconfigs available for the managed cluster")
+	}
+
+	return *(*credentialList.Kubeconfigs)[0].Value, nil
+}
+
 // CreateOrUpdate creates or updates a managed cluster.
 func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName, name string, cluster containerservice.ManagedCluster) (containerservice.ManagedCluster, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.AzureClient.CreateOrUpdate")
@@ -20,8 +20,10 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"path"
 
 	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
+	autorest "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
@@ -32,6 +34,10 @@ import (
 
 	infrav1alpha4 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/routetables"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
@@ -40,22 +46,30 @@ var (
 	managedIdentity string = "msi"
 )
 
+// defaultRouteAddressPrefix is the address prefix of a default route.
+const defaultRouteAddressPrefix = "0.0.0.0/0"
+
 // ManagedClusterScope defines the scope interface for a managed cluster.
 type ManagedClusterScope interface {
 	logr.Logger
 	azure.ClusterDescriber
 	ManagedClusterSpec() (azure.ManagedClusterSpec, error)
-	GetAgentPoolSpecs(ctx context.Context) ([]azure.AgentPoolSpec, error)
+	AllAgentPoolSpecs(ctx context.Context) ([]azure.AgentPoolSpec, error)
 	SetControlPlaneEndpoint(clusterv1.APIEndpoint)
-	MakeEmptyKubeConfigSecret() corev1.Secret
-	GetKubeConfigData() []byte
-	SetKubeConfigData([]byte)
+	MakeEmptyKubeConfigSecrets() []corev1.Secret
+	ManagedClusterCreateTimedOut() bool
+	SetManagedClusterCreateTimedOut(state string)
+	SetManagedClusterProvisioningState(state string)
+	ValidateVersionUpgrade(currentVersion string) error
 }
 
 // Service provides operations on azure resources.
 type Service struct {
 	Scope ManagedClusterScope
 	Client
+	subnetsClient           subnets.Client
+	routeTablesClient       routetables.Client
+	nodeResourceGroupClient nodeResourceGroupClient
 }
 
 func convertToResourceReferences(resources []string) *[]containerservice.ResourceReference {
@@ -88,6 +102,7 @@ func computeDiffOfNormalizedClusters(managedCluster containerservice.ManagedClus
 			Managed:             managedCluster.AadProfile.Managed,
 			EnableAzureRBAC:     managedCluster.AadProfile.EnableAzureRBAC,
 			AdminGroupObjectIDs: managedCluster.AadProfile.AdminGroupObjectIDs,
+			TenantID:            managedCluster.AadProfile.TenantID,
 		}
 	}
 
@@ -97,6 +112,14 @@ func computeDiffOfNormalizedClusters(managedCluster containerservice.ManagedClus
 			EnableAzureRBAC:     existingMC.AadProfile.EnableAzureRBAC,
 			AdminGroupObjectIDs: existingMC.AadProfile.AdminGroupObjectIDs,
 		}
+		// AKS may report an empty list of admin groups, which is the same as not setting any.
+		if existingMC.AadProfile.AdminGroupObjectIDs != nil && len(*existingMC.AadProfile.AdminGroupObjectIDs) == 0 {
+			existingMCPropertiesNormalized.AadProfile.AdminGroupObjectIDs = nil
+		}
+		// The tenant ID is only compared when set, as AKS reports the tenant of the subscription otherwise.
+		if managedCluster.AadProfile != nil && managedCluster.AadProfile.TenantID != nil {
+			existingMCPropertiesNormalized.AadProfile.TenantID = existingMC.AadProfile.TenantID
+		}
 	}
 
 	if managedCluster.NetworkProfile != nil {
@@ -133,15 +156,62 @@ func computeDiffOfNormalizedClusters(managedCluster containerservice.ManagedClus
 		existingMCClusterNormalized.Sku = existingMC.Sku
 	}
 
+	// The autoscaler profile is only compared when set, as AKS reports its defaults for clusters that never set it.
+	if managedCluster.AutoScalerProfile != nil {
+		propertiesNormalized.AutoScalerProfile = managedCluster.AutoScalerProfile
+		existingMCPropertiesNormalized.AutoScalerProfile = existingMC.AutoScalerProfile
+	}
+
+	// DisableLocalAccounts is only compared when set, as AKS may not report it for clusters that never set it.
+	if managedCluster.DisableLocalAccounts != nil {
+		propertiesNormalized.DisableLocalAccounts = managedCluster.DisableLocalAccounts
+		existingMCPropertiesNormalized.DisableLocalAccounts = to.BoolPtr(to.Bool(existingMC.DisableLocalAccounts))
+	}
+
+	// Add-ons are only compared when set, and only the add-ons and config keys set in the spec, as AKS reports the
+	// add-ons enabled outside of CAPZ and the config it adds to them.
+	if managedCluster.AddonProfiles != nil {
+		propertiesNormalized.AddonProfiles = managedCluster.AddonProfiles
+		existingMCPropertiesNormalized.AddonProfiles = normalizeAddonProfiles(managedCluster.AddonProfiles, existingMC.AddonProfiles)
+	}
+
 	diff := cmp.Diff(clusterNormalized, existingMCClusterNormalized)
 	return diff
 }
 
+// normalizeAddonProfiles returns the existing add-on profiles restricted to the add-ons and config keys of the
+// desired add-on profiles.
+func normalizeAddonProfiles(desired, existing map[string]*containerservice.ManagedClusterAddonProfile) map[string]*containerservice.ManagedClusterAddonProfile {
+	normalized := map[string]*containerservice.ManagedClusterAddonProfile{}
+	for name, desiredAddon := range desired {
+		existingAddon, ok := existing[name]
+		if !ok || existingAddon == nil {
+			continue
+		}
+		normalizedAddon := &containerservice.ManagedClusterAddonProfile{
+			Enabled: to.BoolPtr(to.Bool(existingAddon.Enabled)),
+		}
+		if desiredAddon.Config != nil {
+			normalizedAddon.Config = map[string]*string{}
+			for key := range desiredAddon.Config {
+				if value, ok := existingAddon.Config[key]; ok {
+					normalizedAddon.Config[key] = value
+				}
+			}
+		}
+		normalized[name] = normalizedAddon
+	}
+	return normalized
+}
+
 // New creates a new service.
 func New(scope ManagedClusterScope) *Service {
 	return &Service{
-		Scope:  scope,
-		Client: NewClient(scope),
+		Scope:                   scope,
+		Client:                  NewClient(scope),
+		subnetsClient:           subnets.NewClient(scope),
+		routeTablesClient:       routetables.NewClient(scope),
+		nodeResourceGroupClient: newNodeResourceGroupClient(scope),
 	}
 }
 
@@ -169,7 +239,7 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	if azure.ResourceNotFound(err) {
 		isCreate = true
 		// Add system agent pool to cluster spec that will be submitted to the API
-		managedClusterSpec.AgentPools, err = s.Scope.GetAgentPoolSpecs(ctx)
+		managedClusterSpec.AgentPools, err = s.Scope.AllAgentPoolSpecs(ctx)
 		if err != nil {
 			return errors.Wrapf(err, "failed to get system agent pool specs for managed cluster %s", s.Scope.ClusterName())
 		}
@@ -177,15 +247,17 @@ func (s *Service) Reconcile(ctx context.Context) error {
 
 	managedCluster := containerservice.ManagedCluster{
 		Identity: &containerservice.ManagedClusterIdentity{
-			Type: containerservice.ResourceIdentityTypeSystemAssigned,
+			Type: containerservice.ResourceIdentityType(managedClusterSpec.IdentityType),
 		},
 		Location: &managedClusterSpec.Location,
 		Tags:     *to.StringMapPtr(managedClusterSpec.Tags),
 		ManagedClusterProperties: &containerservice.ManagedClusterProperties{
-			NodeResourceGroup: &managedClusterSpec.NodeResourceGroupName,
-			EnableRBAC:        to.BoolPtr(true),
-			DNSPrefix:         &managedClusterSpec.Name,
-			KubernetesVersion: &managedClusterSpec.Version,
+			NodeResourceGroup:    &managedClusterSpec.NodeResourceGroupName,
+			DiskEncryptionSetID:  managedClusterSpec.DiskEncryptionSetID,
+			EnableRBAC:           to.BoolPtr(true),
+			DisableLocalAccounts: managedClusterSpec.DisableLocalAccounts,
+			DNSPrefix:            &managedClusterSpec.DNSPrefix,
+			KubernetesVersion:    &managedClusterSpec.Version,
 			LinuxProfile: &containerservice.LinuxProfile{
 				AdminUsername: &defaultUser,
 				SSH: &containerservice.SSHConfiguration{
@@ -208,6 +280,10 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		},
 	}
 
+	if managedClusterSpec.OutboundType != "" {
+		managedCluster.NetworkProfile.OutboundType = containerservice.OutboundType(managedClusterSpec.OutboundType)
+	}
+
 	if managedClusterSpec.PodCIDR != "" {
 		managedCluster.NetworkProfile.PodCidr = &managedClusterSpec.PodCIDR
 	}
@@ -234,27 +310,55 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	for i := range managedClusterSpec.AgentPools {
 		pool := managedClusterSpec.AgentPools[i]
 		profile := containerservice.ManagedClusterAgentPoolProfile{
-			Name:         &pool.Name,
-			VMSize:       &pool.SKU,
-			OsDiskSizeGB: &pool.OSDiskSizeGB,
-			Count:        &pool.Replicas,
-			Type:         containerservice.AgentPoolTypeVirtualMachineScaleSets,
-			VnetSubnetID: &managedClusterSpec.VnetSubnetID,
-			Mode:         containerservice.AgentPoolMode(pool.Mode),
+			Name:                      &pool.Name,
+			VMSize:                    &pool.SKU,
+			OsDiskSizeGB:              &pool.OSDiskSizeGB,
+			OsDiskType:                containerservice.OSDiskType(pool.OSDiskType),
+			EnableEncryptionAtHost:    pool.EnableEncryptionAtHost,
+			ProximityPlacementGroupID: pool.ProximityPlacementGroupID,
+			EnableNodePublicIP:        pool.EnableNodePublicIP,
+			NodePublicIPPrefixID:      pool.NodePublicIPPrefixID,
+			OrchestratorVersion:       pool.Version,
+			Count:                     &pool.Replicas,
+			Type:                      containerservice.AgentPoolTypeVirtualMachineScaleSets,
+			VnetSubnetID:              &managedClusterSpec.VnetSubnetID,
+			Mode:                      containerservice.AgentPoolMode(pool.Mode),
+			OsType:                    containerservice.OSType(pool.OSType),
+			EnableAutoScaling:         pool.EnableAutoScaling,
+			MinCount:                  pool.MinCount,
+			MaxCount:                  pool.MaxCount,
+		}
+		if pool.PodSubnetID != "" {
+			profile.PodSubnetID = to.StringPtr(pool.PodSubnetID)
+		}
+		if len(pool.NodeTaints) > 0 {
+			profile.NodeTaints = &pool.NodeTaints
+		}
+		if len(pool.NodeLabels) > 0 {
+			profile.NodeLabels = *to.StringMapPtr(pool.NodeLabels)
 		}
 		*managedCluster.AgentPoolProfiles = append(*managedCluster.AgentPoolProfiles, profile)
 	}
 
 	if managedClusterSpec.AADProfile != nil {
 		managedCluster.AadProfile = &containerservice.ManagedClusterAADProfile{
-			Managed:             &managedClusterSpec.AADProfile.Managed,
-			EnableAzureRBAC:     &managedClusterSpec.AADProfile.EnableAzureRBAC,
-			AdminGroupObjectIDs: &managedClusterSpec.AADProfile.AdminGroupObjectIDs,
+			Managed:         &managedClusterSpec.AADProfile.Managed,
+			EnableAzureRBAC: &managedClusterSpec.AADProfile.EnableAzureRBAC,
+		}
+		if len(managedClusterSpec.AADProfile.AdminGroupObjectIDs) > 0 {
+			managedCluster.AadProfile.AdminGroupObjectIDs = &managedClusterSpec.AADProfile.AdminGroupObjectIDs
+		}
+		if managedClusterSpec.AADProfile.TenantID != "" {
+			managedCluster.AadProfile.TenantID = &managedClusterSpec.AADProfile.TenantID
 		}
 	}
 
 	if managedClusterSpec.SKU != nil {
 		tierName := containerservice.ManagedClusterSKUTier(managedClusterSpec.SKU.Tier)
+		// Standard is the current name of the Paid tier, which this API version still refers to as Paid.
+		if managedClusterSpec.SKU.Tier == infrav1exp.SKUTierStandard {
+			tierName = containerservice.ManagedClusterSKUTierPaid
+		}
 		managedCluster.Sku = &containerservice.ManagedClusterSKU{
 			Name: containerservice.ManagedClusterSKUNameBasic,
 			Tier: tierName,
@@ -281,6 +385,41 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		}
 	}
 
+	if managedClusterSpec.AutoScalerProfile != nil {
+		managedCluster.AutoScalerProfile = &containerservice.ManagedClusterPropertiesAutoScalerProfile{
+			BalanceSimilarNodeGroups:      managedClusterSpec.AutoScalerProfile.BalanceSimilarNodeGroups,
+			Expander:                      containerservice.Expander(to.String(managedClusterSpec.AutoScalerProfile.Expander)),
+			MaxEmptyBulkDelete:            managedClusterSpec.AutoScalerProfile.MaxEmptyBulkDelete,
+			MaxGracefulTerminationSec:     managedClusterSpec.AutoScalerProfile.MaxGracefulTerminationSec,
+			MaxNodeProvisionTime:          managedClusterSpec.AutoScalerProfile.MaxNodeProvisionTime,
+			MaxTotalUnreadyPercentage:     managedClusterSpec.AutoScalerProfile.MaxTotalUnreadyPercentage,
+			NewPodScaleUpDelay:            managedClusterSpec.AutoScalerProfile.NewPodScaleUpDelay,
+			OkTotalUnreadyCount:           managedClusterSpec.AutoScalerProfile.OkTotalUnreadyCount,
+			ScanInterval:                  managedClusterSpec.AutoScalerProfile.ScanInterval,
+			ScaleDownDelayAfterAdd:        managedClusterSpec.AutoScalerProfile.ScaleDownDelayAfterAdd,
+			ScaleDownDelayAfterDelete:     managedClusterSpec.AutoScalerProfile.ScaleDownDelayAfterDelete,
+			ScaleDownDelayAfterFailure:    managedClusterSpec.AutoScalerProfile.ScaleDownDelayAfterFailure,
+			ScaleDownUnneededTime:         managedClusterSpec.AutoScalerProfile.ScaleDownUnneededTime,
+			ScaleDownUnreadyTime:          managedClusterSpec.AutoScalerProfile.ScaleDownUnreadyTime,
+			ScaleDownUtilizationThreshold: managedClusterSpec.AutoScalerProfile.ScaleDownUtilizationThreshold,
+			SkipNodesWithLocalStorage:     managedClusterSpec.AutoScalerProfile.SkipNodesWithLocalStorage,
+			SkipNodesWithSystemPods:       managedClusterSpec.AutoScalerProfile.SkipNodesWithSystemPods,
+		}
+	}
+
+	if len(managedClusterSpec.AddonProfiles) > 0 {
+		managedCluster.AddonProfiles = map[string]*containerservice.ManagedClusterAddonProfile{}
+		for _, addon := range managedClusterSpec.AddonProfiles {
+			addonProfile := &containerservice.ManagedClusterAddonProfile{
+				Enabled: to.BoolPtr(addon.Enabled),
+			}
+			if len(addon.Config) > 0 {
+				addonProfile.Config = *to.StringMapPtr(addon.Config)
+			}
+			managedCluster.AddonProfiles[addon.Name] = addonProfile
+		}
+	}
+
 	if managedClusterSpec.APIServerAccessProfile != nil {
 		managedCluster.APIServerAccessProfile = &containerservice.ManagedClusterAPIServerAccessProfile{
 			AuthorizedIPRanges:             &managedClusterSpec.APIServerAccessProfile.AuthorizedIPRanges,
@@ -291,18 +430,43 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	}
 
 	if isCreate {
+		if managedClusterSpec.NodeResourceGroupName != "" {
+			if err := s.validateNodeResourceGroup(ctx, managedClusterSpec.NodeResourceGroupName); err != nil {
+				return errors.Wrapf(err, "failed to validate node resource group for managed cluster %s", managedClusterSpec.Name)
+			}
+		}
+		if managedClusterSpec.OutboundType == string(containerservice.OutboundTypeUserDefinedRouting) {
+			if err := s.validateUserDefinedRouting(ctx, managedClusterSpec.VnetSubnetID); err != nil {
+				return errors.Wrapf(err, "failed to validate user defined routing for managed cluster %s", managedClusterSpec.Name)
+			}
+		}
+		s.Scope.SetManagedClusterProvisioningState(string(infrav1alpha4.Creating))
 		managedCluster, err = s.Client.CreateOrUpdate(ctx, managedClusterSpec.ResourceGroupName, managedClusterSpec.Name, managedCluster)
 		if err != nil {
 			return fmt.Errorf("failed to create managed cluster, %w", err)
 		}
+		s.Scope.SetManagedClusterProvisioningState(string(infrav1alpha4.Succeeded))
 	} else {
 		ps := *existingMC.ManagedClusterProperties.ProvisioningState
+		s.Scope.SetManagedClusterProvisioningState(ps)
 		if ps != string(infrav1alpha4.Canceled) && ps != string(infrav1alpha4.Failed) && ps != string(infrav1alpha4.Succeeded) {
+			// Stop waiting for a cluster whose creation hangs once the create timeout has elapsed.
+			if s.Scope.ManagedClusterCreateTimedOut() {
+				s.Scope.SetManagedClusterCreateTimedOut(ps)
+				return azure.WithTerminalError(errors.Errorf("managed cluster %s was not created within the create timeout, last known provisioning state: %s", managedClusterSpec.Name, ps))
+			}
 			msg := fmt.Sprintf("Unable to update existing managed cluster in non terminal state. Managed cluster must be in one of the following provisioning states: canceled, failed, or succeeded. Actual state: %s", ps)
 			klog.V(2).Infof(msg)
 			return errors.New(msg)
 		}
 
+		if err := s.Scope.ValidateVersionUpgrade(to.String(existingMC.KubernetesVersion)); err != nil {
+			return err
+		}
+
+		// Only update the fields CAPZ manages, so that the update doesn't revert changes made outside of CAPZ.
+		managedCluster = mergeManagedCluster(existingMC, managedCluster)
+
 		diff := computeDiffOfNormalizedClusters(managedCluster, existingMC)
 		if diff != "" {
 			klog.V(2).Infof("Update required (+new -old):\n%s", diff)
@@ -310,6 +474,7 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			if err != nil {
 				return fmt.Errorf("failed to update managed cluster, %w", err)
 			}
+			s.Scope.SetManagedClusterProvisioningState(string(infrav1alpha4.Succeeded))
 		}
 	}
 
@@ -322,17 +487,240 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		s.Scope.SetControlPlaneEndpoint(endpoint)
 	}
 
-	// Update kubeconfig data
-	// Always fetch credentials in case of rotation
-	kubeConfigData, err := s.Client.GetCredentials(ctx, s.Scope.ResourceGroup(), s.Scope.ClusterName())
+	return nil
+}
+
+// mergeManagedCluster returns the existing managed cluster with the fields CAPZ manages set from the desired managed
+// cluster, to send updates with PATCH semantics through the PUT API of AKS. Fields CAPZ does not manage, such as addon
+// profiles enabled outside of CAPZ, keep their existing values, and so do the optional fields the desired managed
+// cluster leaves unset. Agent pools and tags are left out of updates, as they are managed by the agent pools and tags
+// services.
+func mergeManagedCluster(existing, desired containerservice.ManagedCluster) containerservice.ManagedCluster {
+	if existing.ManagedClusterProperties == nil {
+		return desired
+	}
+
+	merged := existing
+	if desired.Sku != nil {
+		merged.Sku = desired.Sku
+	}
+
+	properties := *existing.ManagedClusterProperties
+	desiredProperties := desired.ManagedClusterProperties
+	properties.KubernetesVersion = desiredProperties.KubernetesVersion
+	properties.EnableRBAC = desiredProperties.EnableRBAC
+	properties.LinuxProfile = desiredProperties.LinuxProfile
+	properties.ServicePrincipalProfile = desiredProperties.ServicePrincipalProfile
+	properties.AgentPoolProfiles = desiredProperties.AgentPoolProfiles
+	if desiredProperties.DisableLocalAccounts != nil {
+		properties.DisableLocalAccounts = desiredProperties.DisableLocalAccounts
+	}
+	if desiredProperties.AadProfile != nil {
+		properties.AadProfile = desiredProperties.AadProfile
+	}
+	if desiredProperties.APIServerAccessProfile != nil {
+		properties.APIServerAccessProfile = desiredProperties.APIServerAccessProfile
+	}
+	properties.AutoScalerProfile = mergeAutoScalerProfile(existing.AutoScalerProfile, desiredProperties.AutoScalerProfile)
+	properties.AddonProfiles = mergeAddonProfiles(existing.AddonProfiles, desiredProperties.AddonProfiles)
+	properties.NetworkProfile = mergeNetworkProfile(existing.NetworkProfile, desiredProperties.NetworkProfile)
+	merged.ManagedClusterProperties = &properties
+
+	return merged
+}
+
+// mergeNetworkProfile returns the existing network profile with the fields set in the desired network profile.
+func mergeNetworkProfile(existing, desired *containerservice.NetworkProfile) *containerservice.NetworkProfile {
+	if existing == nil || desired == nil {
+		return desired
+	}
+
+	merged := *existing
+	if desired.NetworkPlugin != "" {
+		merged.NetworkPlugin = desired.NetworkPlugin
+	}
+	if desired.NetworkPolicy != "" {
+		merged.NetworkPolicy = desired.NetworkPolicy
+	}
+	if desired.LoadBalancerSku != "" {
+		merged.LoadBalancerSku = desired.LoadBalancerSku
+	}
+	if desired.OutboundType != "" {
+		merged.OutboundType = desired.OutboundType
+	}
+	if desired.PodCidr != nil {
+		merged.PodCidr = desired.PodCidr
+	}
+	if desired.ServiceCidr != nil {
+		merged.ServiceCidr = desired.ServiceCidr
+	}
+	if desired.DNSServiceIP != nil {
+		merged.DNSServiceIP = desired.DNSServiceIP
+	}
+	if desired.LoadBalancerProfile != nil {
+		merged.LoadBalancerProfile = desired.LoadBalancerProfile
+	}
+
+	return &merged
+}
+
+// mergeAddonProfiles returns the existing add-on profiles with the desired add-on profiles set over them, so that the
+// add-ons enabled outside of CAPZ are kept.
+func mergeAddonProfiles(existing, desired map[string]*containerservice.ManagedClusterAddonProfile) map[string]*containerservice.ManagedClusterAddonProfile {
+	if len(desired) == 0 {
+		return existing
+	}
+
+	merged := map[string]*containerservice.ManagedClusterAddonProfile{}
+	for name, addon := range existing {
+		merged[name] = addon
+	}
+	for name, addon := range desired {
+		merged[name] = addon
+	}
+	return merged
+}
+
+// mergeAutoScalerProfile returns the existing autoscaler profile with the fields set in the desired autoscaler profile,
+// so that the fields left unset keep the values AKS reports for them.
+func mergeAutoScalerProfile(existing, desired *containerservice.ManagedClusterPropertiesAutoScalerProfile) *containerservice.ManagedClusterPropertiesAutoScalerProfile {
+	if desired == nil {
+		return existing
+	}
+	if existing == nil {
+		return desired
+	}
+
+	merged := *existing
+	if desired.BalanceSimilarNodeGroups != nil {
+		merged.BalanceSimilarNodeGroups = desired.BalanceSimilarNodeGroups
+	}
+	if desired.Expander != "" {
+		merged.Expander = desired.Expander
+	}
+	if desired.MaxEmptyBulkDelete != nil {
+		merged.MaxEmptyBulkDelete = desired.MaxEmptyBulkDelete
+	}
+	if desired.MaxGracefulTerminationSec != nil {
+		merged.MaxGracefulTerminationSec = desired.MaxGracefulTerminationSec
+	}
+	if desired.MaxNodeProvisionTime != nil {
+		merged.MaxNodeProvisionTime = desired.MaxNodeProvisionTime
+	}
+	if desired.MaxTotalUnreadyPercentage != nil {
+		merged.MaxTotalUnreadyPercentage = desired.MaxTotalUnreadyPercentage
+	}
+	if desired.NewPodScaleUpDelay != nil {
+		merged.NewPodScaleUpDelay = desired.NewPodScaleUpDelay
+	}
+	if desired.OkTotalUnreadyCount != nil {
+		merged.OkTotalUnreadyCount = desired.OkTotalUnreadyCount
+	}
+	if desired.ScanInterval != nil {
+		merged.ScanInterval = desired.ScanInterval
+	}
+	if desired.ScaleDownDelayAfterAdd != nil {
+		merged.ScaleDownDelayAfterAdd = desired.ScaleDownDelayAfterAdd
+	}
+	if desired.ScaleDownDelayAfterDelete != nil {
+		merged.ScaleDownDelayAfterDelete = desired.ScaleDownDelayAfterDelete
+	}
+	if desired.ScaleDownDelayAfterFailure != nil {
+		merged.ScaleDownDelayAfterFailure = desired.ScaleDownDelayAfterFailure
+	}
+	if desired.ScaleDownUnneededTime != nil {
+		merged.ScaleDownUnneededTime = desired.ScaleDownUnneededTime
+	}
+	if desired.ScaleDownUnreadyTime != nil {
+		merged.ScaleDownUnreadyTime = desired.ScaleDownUnreadyTime
+	}
+	if desired.ScaleDownUtilizationThreshold != nil {
+		merged.ScaleDownUtilizationThreshold = desired.ScaleDownUtilizationThreshold
+	}
+	if desired.SkipNodesWithLocalStorage != nil {
+		merged.SkipNodesWithLocalStorage = desired.SkipNodesWithLocalStorage
+	}
+	if desired.SkipNodesWithSystemPods != nil {
+		merged.SkipNodesWithSystemPods = desired.SkipNodesWithSystemPods
+	}
+
+	return &merged
+}
+
+// validateNodeResourceGroup checks that the node resource group either doesn't exist yet, is owned by the cluster or is
+// empty, so that creating the managed cluster doesn't clobber resources in a resource group CAPZ doesn't manage.
+func (s *Service) validateNodeResourceGroup(ctx context.Context, name string) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.Service.validateNodeResourceGroup")
+	defer done()
+
+	group, err := s.nodeResourceGroupClient.Get(ctx, name)
+	if azure.ResourceNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Wrapf(err, "failed to get resource group %s", name)
+	}
+
+	if converters.MapToTags(group.Tags).HasOwned(s.Scope.ClusterName()) {
+		return nil
+	}
+
+	hasResources, err := s.nodeResourceGroupClient.HasResources(ctx, name)
 	if err != nil {
-		return errors.Wrap(err, "failed to get credentials for managed cluster")
+		return errors.Wrapf(err, "failed to list resources in resource group %s", name)
+	}
+	if hasResources {
+		return errors.Errorf("resource group %s already exists, is not owned by cluster %s and is not empty. Use an empty resource group or a different nodeResourceGroupName", name, s.Scope.ClusterName())
 	}
-	s.Scope.SetKubeConfigData(kubeConfigData)
 
 	return nil
 }
 
+// validateUserDefinedRouting checks that the node subnet is associated with a route table containing a default route,
+// which AKS requires to provision a cluster with the userDefinedRouting outbound type.
+func (s *Service) validateUserDefinedRouting(ctx context.Context, subnetID string) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.Service.validateUserDefinedRouting")
+	defer done()
+
+	subnetResource, err := autorest.ParseResourceID(subnetID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse subnet ID %s", subnetID)
+	}
+	// A subnet is nested below its virtual network, so the ID of the virtual network is the subnet ID without its
+	// trailing subnets/<subnet> segments.
+	vnetID := path.Dir(path.Dir(subnetID))
+	vnetResource, err := autorest.ParseResourceID(vnetID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse virtual network ID %s", vnetID)
+	}
+	subnet, err := s.subnetsClient.Get(ctx, subnetResource.ResourceGroup, vnetResource.ResourceName, subnetResource.ResourceName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get subnet %s", subnetID)
+	}
+
+	if subnet.SubnetPropertiesFormat == nil || subnet.RouteTable == nil || subnet.RouteTable.ID == nil {
+		return errors.Errorf("subnet %s must be associated with a route table containing a default route (%s) when outbound type is %s", subnetID, defaultRouteAddressPrefix, containerservice.OutboundTypeUserDefinedRouting)
+	}
+
+	routeTableResource, err := autorest.ParseResourceID(*subnet.RouteTable.ID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse route table ID %s", *subnet.RouteTable.ID)
+	}
+	routeTable, err := s.routeTablesClient.Get(ctx, routeTableResource.ResourceGroup, routeTableResource.ResourceName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get route table %s", *subnet.RouteTable.ID)
+	}
+
+	if routeTable.RouteTablePropertiesFormat != nil && routeTable.Routes != nil {
+		for _, route := range *routeTable.Routes {
+			if route.RoutePropertiesFormat != nil && to.String(route.AddressPrefix) == defaultRouteAddressPrefix {
+				return nil
+			}
+		}
+	}
+
+	return errors.Errorf("route table %s associated with subnet %s must contain a default route (%s) when outbound type is %s", *subnet.RouteTable.ID, subnetID, defaultRouteAddressPrefix, containerservice.OutboundTypeUserDefinedRouting)
+}
+
 // Delete deletes the virtual network with the provided name.
 func (s *Service) Delete(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.Service.Delete")
@@ -22,13 +22,19 @@ import (
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-05-01/resources"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	"k8s.io/utils/pointer"
 
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/managedclusters/mock_managedclusters"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/routetables/mock_routetables"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets/mock_subnets"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
 )
 
@@ -52,7 +58,6 @@ func TestReconcile(t *testing.T) {
 					Fqdn:              pointer.String("my-managedcluster-fqdn"),
 					ProvisioningState: &provisioningstate,
 				}}, nil)
-				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Times(1)
 				s.ClusterName().AnyTimes().Return("my-managedcluster")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				s.ManagedClusterSpec().AnyTimes().Return(azure.ManagedClusterSpec{
@@ -60,7 +65,9 @@ func TestReconcile(t *testing.T) {
 					ResourceGroupName: "my-rg",
 				}, nil)
 				s.SetControlPlaneEndpoint(gomock.Any()).Times(1)
-				s.SetKubeConfigData(gomock.Any()).Times(1)
+				s.SetManagedClusterProvisioningState(provisioningstate)
+				s.SetManagedClusterProvisioningState("Succeeded")
+				s.ValidateVersionUpgrade("").Return(nil)
 			},
 		},
 		{
@@ -77,6 +84,27 @@ func TestReconcile(t *testing.T) {
 					Name:              "my-managedcluster",
 					ResourceGroupName: "my-rg",
 				}, nil)
+				s.ManagedClusterCreateTimedOut().Return(false)
+				s.SetManagedClusterProvisioningState(provisioningstate)
+			},
+		},
+		{
+			name:                     "managedcluster not created within the create timeout",
+			provisioningStatesToTest: []string{"Creating"},
+			expectedError:            "reconcile error that cannot be recovered occurred: managed cluster my-managedcluster was not created within the create timeout, last known provisioning state",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, provisioningstate string, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+					ProvisioningState: &provisioningstate,
+				}}, nil)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec().AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:              "my-managedcluster",
+					ResourceGroupName: "my-rg",
+				}, nil)
+				s.ManagedClusterCreateTimedOut().Return(true)
+				s.SetManagedClusterCreateTimedOut(provisioningstate)
+				s.SetManagedClusterProvisioningState(provisioningstate)
 			},
 		},
 	}
@@ -122,14 +150,13 @@ func TestReconcile(t *testing.T) {
 			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
 				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-managedcluster", gomock.Any()).Return(containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{}}, nil)
 				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found"))
-				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Times(1)
 				s.ClusterName().AnyTimes().Return("my-managedcluster")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				s.ManagedClusterSpec().AnyTimes().Return(azure.ManagedClusterSpec{
 					Name:              "my-managedcluster",
 					ResourceGroupName: "my-rg",
 				}, nil)
-				s.GetAgentPoolSpecs(gomockinternal.AContext()).AnyTimes().Return([]azure.AgentPoolSpec{
+				s.AllAgentPoolSpecs(gomockinternal.AContext()).AnyTimes().Return([]azure.AgentPoolSpec{
 					{
 						Name:         "my-agentpool",
 						SKU:          "Standard_D4s_v3",
@@ -137,7 +164,8 @@ func TestReconcile(t *testing.T) {
 						OSDiskSizeGB: 0,
 					},
 				}, nil)
-				s.SetKubeConfigData(gomock.Any()).Times(1)
+				s.SetManagedClusterProvisioningState("Creating")
+				s.SetManagedClusterProvisioningState("Succeeded")
 			},
 		},
 	}
@@ -169,3 +197,333 @@ func TestReconcile(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileVersionUpgradeSkippingMinorVersion(t *testing.T) {
+	g := NewWithT(t)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	scopeMock := mock_managedclusters.NewMockManagedClusterScope(mockCtrl)
+	clientMock := mock_managedclusters.NewMockClient(mockCtrl)
+
+	scopeMock.EXPECT().ManagedClusterSpec().Return(azure.ManagedClusterSpec{
+		Name:              "my-managedcluster",
+		ResourceGroupName: "my-rg",
+		Version:           "1.23.3",
+	}, nil)
+	clientMock.EXPECT().Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{
+		ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+			KubernetesVersion: pointer.String("1.21.7"),
+			ProvisioningState: pointer.String("Succeeded"),
+		},
+	}, nil)
+	scopeMock.EXPECT().SetManagedClusterProvisioningState("Succeeded")
+	scopeMock.EXPECT().ValidateVersionUpgrade("1.21.7").Return(azure.WithTerminalError(errors.New("AKS does not allow skipping minor versions")))
+
+	s := &Service{
+		Scope:  scopeMock,
+		Client: clientMock,
+	}
+
+	// The managed cluster is not updated.
+	err := s.Reconcile(context.TODO())
+	g.Expect(err).To(MatchError(ContainSubstring("AKS does not allow skipping minor versions")))
+}
+
+func TestReconcileLeavesTagsToTagsService(t *testing.T) {
+	g := NewWithT(t)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	scopeMock := mock_managedclusters.NewMockManagedClusterScope(mockCtrl)
+	clientMock := mock_managedclusters.NewMockClient(mockCtrl)
+
+	scopeMock.EXPECT().ClusterName().AnyTimes().Return("my-cluster")
+	scopeMock.EXPECT().ResourceGroup().AnyTimes().Return("my-rg")
+	scopeMock.EXPECT().ManagedClusterSpec().Return(azure.ManagedClusterSpec{
+		Name:              "my-managedcluster",
+		ResourceGroupName: "my-rg",
+		Tags: infrav1.Build(infrav1.BuildParams{
+			ClusterName: "my-cluster",
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        pointer.String("my-managedcluster"),
+		}),
+	}, nil)
+	clientMock.EXPECT().Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{
+		Tags: map[string]*string{
+			"created-by-policy": pointer.String("true"),
+		},
+		ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+			ProvisioningState: pointer.String("Succeeded"),
+		},
+	}, nil)
+	var updated containerservice.ManagedCluster
+	clientMock.EXPECT().CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-managedcluster", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _ string, managedCluster containerservice.ManagedCluster) (containerservice.ManagedCluster, error) {
+			updated = managedCluster
+			return managedCluster, nil
+		})
+	scopeMock.EXPECT().SetManagedClusterProvisioningState("Succeeded").AnyTimes()
+	scopeMock.EXPECT().ValidateVersionUpgrade(gomock.Any()).AnyTimes()
+
+	s := &Service{
+		Scope:  scopeMock,
+		Client: clientMock,
+	}
+
+	// Updates keep the existing tags, as the tags of the managed cluster are reconciled by the tags service.
+	g.Expect(s.Reconcile(context.TODO())).To(Succeed())
+	g.Expect(updated.Tags).To(Equal(map[string]*string{
+		"created-by-policy": pointer.String("true"),
+	}))
+}
+
+func TestReconcileKeepsOutOfBandChanges(t *testing.T) {
+	g := NewWithT(t)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	scopeMock := mock_managedclusters.NewMockManagedClusterScope(mockCtrl)
+	clientMock := mock_managedclusters.NewMockClient(mockCtrl)
+
+	scopeMock.EXPECT().ClusterName().AnyTimes().Return("my-cluster")
+	scopeMock.EXPECT().ResourceGroup().AnyTimes().Return("my-rg")
+	scopeMock.EXPECT().ManagedClusterSpec().Return(azure.ManagedClusterSpec{
+		Name:              "my-managedcluster",
+		ResourceGroupName: "my-rg",
+		Version:           "v1.22.4",
+		NetworkPlugin:     "azure",
+		AddonProfiles: []azure.AddonProfile{
+			{Name: "httpApplicationRouting", Enabled: true},
+		},
+	}, nil)
+	clientMock.EXPECT().Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{
+		ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+			ProvisioningState: pointer.String("Succeeded"),
+			KubernetesVersion: pointer.String("v1.21.7"),
+			AddonProfiles: map[string]*containerservice.ManagedClusterAddonProfile{
+				"azurepolicy": {
+					Enabled: pointer.Bool(true),
+				},
+			},
+			NetworkProfile: &containerservice.NetworkProfile{
+				NetworkPlugin:    containerservice.NetworkPluginAzure,
+				DockerBridgeCidr: pointer.String("172.17.0.1/16"),
+			},
+		},
+	}, nil)
+	var updated containerservice.ManagedCluster
+	clientMock.EXPECT().CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-managedcluster", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _ string, managedCluster containerservice.ManagedCluster) (containerservice.ManagedCluster, error) {
+			updated = managedCluster
+			return managedCluster, nil
+		})
+	scopeMock.EXPECT().SetManagedClusterProvisioningState("Succeeded").AnyTimes()
+	scopeMock.EXPECT().ValidateVersionUpgrade(gomock.Any()).AnyTimes()
+
+	s := &Service{
+		Scope:  scopeMock,
+		Client: clientMock,
+	}
+
+	g.Expect(s.Reconcile(context.TODO())).To(Succeed())
+	g.Expect(updated.KubernetesVersion).To(Equal(pointer.String("v1.22.4")))
+	g.Expect(updated.AddonProfiles).To(HaveKeyWithValue("azurepolicy", &containerservice.ManagedClusterAddonProfile{Enabled: pointer.Bool(true)}))
+	g.Expect(updated.AddonProfiles).To(HaveKeyWithValue("httpApplicationRouting", &containerservice.ManagedClusterAddonProfile{Enabled: pointer.Bool(true)}))
+	g.Expect(updated.NetworkProfile.NetworkPlugin).To(Equal(containerservice.NetworkPluginAzure))
+	g.Expect(updated.NetworkProfile.DockerBridgeCidr).To(Equal(pointer.String("172.17.0.1/16")))
+}
+
+func TestReconcileUserDefinedRouting(t *testing.T) {
+	const (
+		subnetID     = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet"
+		routeTableID = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/routeTables/my-routetable"
+	)
+
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(m *mock_managedclusters.MockClientMockRecorder, sn *mock_subnets.MockClientMockRecorder, rt *mock_routetables.MockClientMockRecorder)
+	}{
+		{
+			name:          "subnet without a route table blocks creation",
+			expectedError: "failed to validate user defined routing for managed cluster my-managedcluster: subnet " + subnetID + " must be associated with a route table containing a default route (0.0.0.0/0) when outbound type is userDefinedRouting",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, sn *mock_subnets.MockClientMockRecorder, rt *mock_routetables.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found"))
+				sn.Get(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{SubnetPropertiesFormat: &network.SubnetPropertiesFormat{}}, nil)
+			},
+		},
+		{
+			name:          "route table without a default route blocks creation",
+			expectedError: "failed to validate user defined routing for managed cluster my-managedcluster: route table " + routeTableID + " associated with subnet " + subnetID + " must contain a default route (0.0.0.0/0) when outbound type is userDefinedRouting",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, sn *mock_subnets.MockClientMockRecorder, rt *mock_routetables.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found"))
+				sn.Get(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+					RouteTable: &network.RouteTable{ID: pointer.String(routeTableID)},
+				}}, nil)
+				rt.Get(gomockinternal.AContext(), "my-rg", "my-routetable").Return(network.RouteTable{RouteTablePropertiesFormat: &network.RouteTablePropertiesFormat{
+					Routes: &[]network.Route{
+						{RoutePropertiesFormat: &network.RoutePropertiesFormat{AddressPrefix: pointer.String("10.0.0.0/8")}},
+					},
+				}}, nil)
+			},
+		},
+		{
+			name:          "route table with a default route allows creation",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, sn *mock_subnets.MockClientMockRecorder, rt *mock_routetables.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found"))
+				sn.Get(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+					RouteTable: &network.RouteTable{ID: pointer.String(routeTableID)},
+				}}, nil)
+				rt.Get(gomockinternal.AContext(), "my-rg", "my-routetable").Return(network.RouteTable{RouteTablePropertiesFormat: &network.RouteTablePropertiesFormat{
+					Routes: &[]network.Route{
+						{RoutePropertiesFormat: &network.RoutePropertiesFormat{AddressPrefix: pointer.String("0.0.0.0/0")}},
+					},
+				}}, nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-managedcluster", gomock.Any()).Return(containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{}}, nil)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_managedclusters.NewMockManagedClusterScope(mockCtrl)
+			clientMock := mock_managedclusters.NewMockClient(mockCtrl)
+			subnetsMock := mock_subnets.NewMockClient(mockCtrl)
+			routeTablesMock := mock_routetables.NewMockClient(mockCtrl)
+
+			scopeMock.EXPECT().ClusterName().AnyTimes().Return("my-managedcluster")
+			scopeMock.EXPECT().ResourceGroup().AnyTimes().Return("my-rg")
+			scopeMock.EXPECT().ManagedClusterSpec().AnyTimes().Return(azure.ManagedClusterSpec{
+				Name:              "my-managedcluster",
+				ResourceGroupName: "my-rg",
+				VnetSubnetID:      subnetID,
+				OutboundType:      "userDefinedRouting",
+			}, nil)
+			scopeMock.EXPECT().AllAgentPoolSpecs(gomockinternal.AContext()).AnyTimes().Return([]azure.AgentPoolSpec{}, nil)
+			scopeMock.EXPECT().SetManagedClusterProvisioningState(gomock.Any()).AnyTimes()
+			scopeMock.EXPECT().ValidateVersionUpgrade(gomock.Any()).AnyTimes()
+			tc.expect(clientMock.EXPECT(), subnetsMock.EXPECT(), routeTablesMock.EXPECT())
+
+			s := &Service{
+				Scope:             scopeMock,
+				Client:            clientMock,
+				subnetsClient:     subnetsMock,
+				routeTablesClient: routeTablesMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestReconcileNodeResourceGroup(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(m *mock_managedclusters.MockClientMockRecorder, rg *mock_managedclusters.MocknodeResourceGroupClientMockRecorder)
+	}{
+		{
+			name:          "node resource group does not exist",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, rg *mock_managedclusters.MocknodeResourceGroupClientMockRecorder) {
+				rg.Get(gomockinternal.AContext(), "my-node-rg").Return(resources.Group{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found"))
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-managedcluster", gomock.Any()).Return(containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{}}, nil)
+			},
+		},
+		{
+			name:          "node resource group is owned by the cluster",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, rg *mock_managedclusters.MocknodeResourceGroupClientMockRecorder) {
+				rg.Get(gomockinternal.AContext(), "my-node-rg").Return(resources.Group{
+					Tags: map[string]*string{
+						infrav1.ClusterTagKey("my-cluster"): pointer.String(string(infrav1.ResourceLifecycleOwned)),
+					},
+				}, nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-managedcluster", gomock.Any()).Return(containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{}}, nil)
+			},
+		},
+		{
+			name:          "empty node resource group not owned by the cluster",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, rg *mock_managedclusters.MocknodeResourceGroupClientMockRecorder) {
+				rg.Get(gomockinternal.AContext(), "my-node-rg").Return(resources.Group{}, nil)
+				rg.HasResources(gomockinternal.AContext(), "my-node-rg").Return(false, nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-managedcluster", gomock.Any()).Return(containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{}}, nil)
+			},
+		},
+		{
+			name:          "non-empty node resource group not owned by the cluster blocks creation",
+			expectedError: "failed to validate node resource group for managed cluster my-managedcluster: resource group my-node-rg already exists, is not owned by cluster my-cluster and is not empty. Use an empty resource group or a different nodeResourceGroupName",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, rg *mock_managedclusters.MocknodeResourceGroupClientMockRecorder) {
+				rg.Get(gomockinternal.AContext(), "my-node-rg").Return(resources.Group{
+					Tags: map[string]*string{
+						infrav1.ClusterTagKey("other-cluster"): pointer.String(string(infrav1.ResourceLifecycleOwned)),
+					},
+				}, nil)
+				rg.HasResources(gomockinternal.AContext(), "my-node-rg").Return(true, nil)
+			},
+		},
+		{
+			name:          "error getting node resource group",
+			expectedError: "failed to validate node resource group for managed cluster my-managedcluster: failed to get resource group my-node-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, rg *mock_managedclusters.MocknodeResourceGroupClientMockRecorder) {
+				rg.Get(gomockinternal.AContext(), "my-node-rg").Return(resources.Group{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_managedclusters.NewMockManagedClusterScope(mockCtrl)
+			clientMock := mock_managedclusters.NewMockClient(mockCtrl)
+			nodeResourceGroupMock := mock_managedclusters.NewMocknodeResourceGroupClient(mockCtrl)
+
+			scopeMock.EXPECT().ClusterName().AnyTimes().Return("my-cluster")
+			scopeMock.EXPECT().ResourceGroup().AnyTimes().Return("my-rg")
+			scopeMock.EXPECT().ManagedClusterSpec().Return(azure.ManagedClusterSpec{
+				Name:                  "my-managedcluster",
+				ResourceGroupName:     "my-rg",
+				NodeResourceGroupName: "my-node-rg",
+			}, nil)
+			scopeMock.EXPECT().AllAgentPoolSpecs(gomockinternal.AContext()).Return([]azure.AgentPoolSpec{}, nil)
+			scopeMock.EXPECT().SetManagedClusterProvisioningState(gomock.Any()).AnyTimes()
+			scopeMock.EXPECT().ValidateVersionUpgrade(gomock.Any()).AnyTimes()
+			clientMock.EXPECT().Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found"))
+			tc.expect(clientMock.EXPECT(), nodeResourceGroupMock.EXPECT())
+
+			s := &Service{
+				Scope:                   scopeMock,
+				Client:                  clientMock,
+				nodeResourceGroupClient: nodeResourceGroupMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
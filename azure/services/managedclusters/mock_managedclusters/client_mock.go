@@ -25,6 +25,7 @@ import (
 	reflect "reflect"
 
 	containerservice "github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
+	resources "github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-05-01/resources"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -109,3 +110,71 @@ func (mr *MockClientMockRecorder) GetCredentials(arg0, arg1, arg2 interface{}) *
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCredentials", reflect.TypeOf((*MockClient)(nil).GetCredentials), arg0, arg1, arg2)
 }
+
+// GetUserCredentials mocks base method.
+func (m *MockClient) GetUserCredentials(arg0 context.Context, arg1, arg2 string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserCredentials", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserCredentials indicates an expected call of GetUserCredentials.
+func (mr *MockClientMockRecorder) GetUserCredentials(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserCredentials", reflect.TypeOf((*MockClient)(nil).GetUserCredentials), arg0, arg1, arg2)
+}
+
+// MocknodeResourceGroupClient is a mock of nodeResourceGroupClient interface.
+type MocknodeResourceGroupClient struct {
+	ctrl     *gomock.Controller
+	recorder *MocknodeResourceGroupClientMockRecorder
+}
+
+// MocknodeResourceGroupClientMockRecorder is the mock recorder for MocknodeResourceGroupClient.
+type MocknodeResourceGroupClientMockRecorder struct {
+	mock *MocknodeResourceGroupClient
+}
+
+// NewMocknodeResourceGroupClient creates a new mock instance.
+func NewMocknodeResourceGroupClient(ctrl *gomock.Controller) *MocknodeResourceGroupClient {
+	mock := &MocknodeResourceGroupClient{ctrl: ctrl}
+	mock.recorder = &MocknodeResourceGroupClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocknodeResourceGroupClient) EXPECT() *MocknodeResourceGroupClientMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MocknodeResourceGroupClient) Get(arg0 context.Context, arg1 string) (resources.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].(resources.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MocknodeResourceGroupClientMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MocknodeResourceGroupClient)(nil).Get), arg0, arg1)
+}
+
+// HasResources mocks base method.
+func (m *MocknodeResourceGroupClient) HasResources(arg0 context.Context, arg1 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasResources", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasResources indicates an expected call of HasResources.
+func (mr *MocknodeResourceGroupClientMockRecorder) HasResources(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasResources", reflect.TypeOf((*MocknodeResourceGroupClient)(nil).HasResources), arg0, arg1)
+}
@@ -70,6 +70,21 @@ func (mr *MockManagedClusterScopeMockRecorder) AdditionalTags() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdditionalTags", reflect.TypeOf((*MockManagedClusterScope)(nil).AdditionalTags))
 }
 
+// AllAgentPoolSpecs mocks base method.
+func (m *MockManagedClusterScope) AllAgentPoolSpecs(ctx context.Context) ([]azure.AgentPoolSpec, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AllAgentPoolSpecs", ctx)
+	ret0, _ := ret[0].([]azure.AgentPoolSpec)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AllAgentPoolSpecs indicates an expected call of AllAgentPoolSpecs.
+func (mr *MockManagedClusterScopeMockRecorder) AllAgentPoolSpecs(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllAgentPoolSpecs", reflect.TypeOf((*MockManagedClusterScope)(nil).AllAgentPoolSpecs), ctx)
+}
+
 // Authorizer mocks base method.
 func (m *MockManagedClusterScope) Authorizer() autorest.Authorizer {
 	m.ctrl.T.Helper()
@@ -227,35 +242,6 @@ func (mr *MockManagedClusterScopeMockRecorder) FailureDomains() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailureDomains", reflect.TypeOf((*MockManagedClusterScope)(nil).FailureDomains))
 }
 
-// GetAgentPoolSpecs mocks base method.
-func (m *MockManagedClusterScope) GetAgentPoolSpecs(ctx context.Context) ([]azure.AgentPoolSpec, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAgentPoolSpecs", ctx)
-	ret0, _ := ret[0].([]azure.AgentPoolSpec)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// GetAgentPoolSpecs indicates an expected call of GetAgentPoolSpecs.
-func (mr *MockManagedClusterScopeMockRecorder) GetAgentPoolSpecs(ctx interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAgentPoolSpecs", reflect.TypeOf((*MockManagedClusterScope)(nil).GetAgentPoolSpecs), ctx)
-}
-
-// GetKubeConfigData mocks base method.
-func (m *MockManagedClusterScope) GetKubeConfigData() []byte {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetKubeConfigData")
-	ret0, _ := ret[0].([]byte)
-	return ret0
-}
-
-// GetKubeConfigData indicates an expected call of GetKubeConfigData.
-func (mr *MockManagedClusterScopeMockRecorder) GetKubeConfigData() *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKubeConfigData", reflect.TypeOf((*MockManagedClusterScope)(nil).GetKubeConfigData))
-}
-
 // HashKey mocks base method.
 func (m *MockManagedClusterScope) HashKey() string {
 	m.ctrl.T.Helper()
@@ -301,18 +287,32 @@ func (mr *MockManagedClusterScopeMockRecorder) Location() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockManagedClusterScope)(nil).Location))
 }
 
-// MakeEmptyKubeConfigSecret mocks base method.
-func (m *MockManagedClusterScope) MakeEmptyKubeConfigSecret() v1.Secret {
+// MakeEmptyKubeConfigSecrets mocks base method.
+func (m *MockManagedClusterScope) MakeEmptyKubeConfigSecrets() []v1.Secret {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MakeEmptyKubeConfigSecrets")
+	ret0, _ := ret[0].([]v1.Secret)
+	return ret0
+}
+
+// MakeEmptyKubeConfigSecrets indicates an expected call of MakeEmptyKubeConfigSecrets.
+func (mr *MockManagedClusterScopeMockRecorder) MakeEmptyKubeConfigSecrets() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MakeEmptyKubeConfigSecrets", reflect.TypeOf((*MockManagedClusterScope)(nil).MakeEmptyKubeConfigSecrets))
+}
+
+// ManagedClusterCreateTimedOut mocks base method.
+func (m *MockManagedClusterScope) ManagedClusterCreateTimedOut() bool {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "MakeEmptyKubeConfigSecret")
-	ret0, _ := ret[0].(v1.Secret)
+	ret := m.ctrl.Call(m, "ManagedClusterCreateTimedOut")
+	ret0, _ := ret[0].(bool)
 	return ret0
 }
 
-// MakeEmptyKubeConfigSecret indicates an expected call of MakeEmptyKubeConfigSecret.
-func (mr *MockManagedClusterScopeMockRecorder) MakeEmptyKubeConfigSecret() *gomock.Call {
+// ManagedClusterCreateTimedOut indicates an expected call of ManagedClusterCreateTimedOut.
+func (mr *MockManagedClusterScopeMockRecorder) ManagedClusterCreateTimedOut() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MakeEmptyKubeConfigSecret", reflect.TypeOf((*MockManagedClusterScope)(nil).MakeEmptyKubeConfigSecret))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ManagedClusterCreateTimedOut", reflect.TypeOf((*MockManagedClusterScope)(nil).ManagedClusterCreateTimedOut))
 }
 
 // ManagedClusterSpec mocks base method.
@@ -356,16 +356,28 @@ func (mr *MockManagedClusterScopeMockRecorder) SetControlPlaneEndpoint(arg0 inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetControlPlaneEndpoint", reflect.TypeOf((*MockManagedClusterScope)(nil).SetControlPlaneEndpoint), arg0)
 }
 
-// SetKubeConfigData mocks base method.
-func (m *MockManagedClusterScope) SetKubeConfigData(arg0 []byte) {
+// SetManagedClusterCreateTimedOut mocks base method.
+func (m *MockManagedClusterScope) SetManagedClusterCreateTimedOut(state string) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "SetKubeConfigData", arg0)
+	m.ctrl.Call(m, "SetManagedClusterCreateTimedOut", state)
 }
 
-// SetKubeConfigData indicates an expected call of SetKubeConfigData.
-func (mr *MockManagedClusterScopeMockRecorder) SetKubeConfigData(arg0 interface{}) *gomock.Call {
+// SetManagedClusterCreateTimedOut indicates an expected call of SetManagedClusterCreateTimedOut.
+func (mr *MockManagedClusterScopeMockRecorder) SetManagedClusterCreateTimedOut(state interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetKubeConfigData", reflect.TypeOf((*MockManagedClusterScope)(nil).SetKubeConfigData), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetManagedClusterCreateTimedOut", reflect.TypeOf((*MockManagedClusterScope)(nil).SetManagedClusterCreateTimedOut), state)
+}
+
+// SetManagedClusterProvisioningState mocks base method.
+func (m *MockManagedClusterScope) SetManagedClusterProvisioningState(state string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetManagedClusterProvisioningState", state)
+}
+
+// SetManagedClusterProvisioningState indicates an expected call of SetManagedClusterProvisioningState.
+func (mr *MockManagedClusterScopeMockRecorder) SetManagedClusterProvisioningState(state interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetManagedClusterProvisioningState", reflect.TypeOf((*MockManagedClusterScope)(nil).SetManagedClusterProvisioningState), state)
 }
 
 // SubscriptionID mocks base method.
@@ -410,6 +422,20 @@ func (mr *MockManagedClusterScopeMockRecorder) V(level interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "V", reflect.TypeOf((*MockManagedClusterScope)(nil).V), level)
 }
 
+// ValidateVersionUpgrade mocks base method.
+func (m *MockManagedClusterScope) ValidateVersionUpgrade(currentVersion string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateVersionUpgrade", currentVersion)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateVersionUpgrade indicates an expected call of ValidateVersionUpgrade.
+func (mr *MockManagedClusterScopeMockRecorder) ValidateVersionUpgrade(currentVersion interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateVersionUpgrade", reflect.TypeOf((*MockManagedClusterScope)(nil).ValidateVersionUpgrade), currentVersion)
+}
+
 // WithName mocks base method.
 func (m *MockManagedClusterScope) WithName(name string) logr.Logger {
 	m.ctrl.T.Helper()
@@ -0,0 +1,69 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceproviders
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-05-01/resources"
+	"github.com/Azure/go-autorest/autorest"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// client wraps go-sdk.
+type client interface {
+	Get(context.Context, string) (resources.Provider, error)
+	Register(context.Context, string) (resources.Provider, error)
+}
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	providers resources.ProvidersClient
+}
+
+var _ client = (*azureClient)(nil)
+
+// newClient creates a new resource providers client from subscription ID.
+func newClient(auth azure.Authorizer) *azureClient {
+	c := newProvidersClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
+	return &azureClient{c}
+}
+
+// newProvidersClient creates a new resource providers client from subscription ID.
+func newProvidersClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) resources.ProvidersClient {
+	providersClient := resources.NewProvidersClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&providersClient.Client, authorizer)
+	return providersClient
+}
+
+// Get gets the specified resource provider.
+func (ac *azureClient) Get(ctx context.Context, namespace string) (resources.Provider, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "resourceproviders.AzureClient.Get")
+	defer done()
+
+	return ac.providers.Get(ctx, namespace, "")
+}
+
+// Register registers the subscription with the specified resource provider.
+func (ac *azureClient) Register(ctx context.Context, namespace string) (resources.Provider, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "resourceproviders.AzureClient.Register")
+	defer done()
+
+	return ac.providers.Register(ctx, namespace)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceproviders
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	// registrationStateRegistered is the registration state of a resource provider the subscription is registered with.
+	registrationStateRegistered = "Registered"
+
+	// registrationStateRegistering is the registration state of a resource provider while the subscription is being
+	// registered with it.
+	registrationStateRegistering = "Registering"
+
+	// registrationRequeueAfter is how long to wait before checking again on resource providers being registered.
+	registrationRequeueAfter = 30 * time.Second
+)
+
+// ResourceProviderScope defines the scope interface for a resource providers service.
+type ResourceProviderScope interface {
+	logr.Logger
+	azure.Authorizer
+	RequiredResourceProviders() []string
+	ResourceProviderRegistrationAllowed() bool
+	ResourceProvidersRegistered() bool
+}
+
+// Service provides operations on Azure resource providers.
+type Service struct {
+	Scope ResourceProviderScope
+	client
+}
+
+// New creates a new service.
+func New(scope ResourceProviderScope) *Service {
+	return &Service{
+		Scope:  scope,
+		client: newClient(scope),
+	}
+}
+
+// Reconcile checks that the subscription is registered with the resource providers required by the cluster. If
+// registration is allowed, unregistered resource providers are registered, otherwise an error naming them is returned.
+// The check is skipped once the scope reports the resource providers as registered.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "resourceproviders.Service.Reconcile")
+	defer done()
+
+	if s.Scope.ResourceProvidersRegistered() {
+		return nil
+	}
+
+	var unregistered, registering []string
+	for _, namespace := range s.Scope.RequiredResourceProviders() {
+		provider, err := s.client.Get(ctx, namespace)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get resource provider %s", namespace)
+		}
+
+		state := to.String(provider.RegistrationState)
+		switch {
+		case strings.EqualFold(state, registrationStateRegistered):
+			continue
+		case !s.Scope.ResourceProviderRegistrationAllowed():
+			unregistered = append(unregistered, namespace)
+		case strings.EqualFold(state, registrationStateRegistering):
+			registering = append(registering, namespace)
+		default:
+			s.Scope.V(2).Info("registering resource provider", "namespace", namespace)
+			if _, err := s.client.Register(ctx, namespace); err != nil {
+				return errors.Wrapf(err, "failed to register resource provider %s", namespace)
+			}
+			registering = append(registering, namespace)
+		}
+	}
+
+	if len(unregistered) > 0 {
+		return errors.Errorf("subscription %s is not registered with resource providers %s, register them with \"az provider register --namespace <namespace>\"",
+			s.Scope.SubscriptionID(), strings.Join(unregistered, ", "))
+	}
+
+	if len(registering) > 0 {
+		return azure.WithTransientError(errors.Errorf("waiting for subscription %s to be registered with resource providers %s",
+			s.Scope.SubscriptionID(), strings.Join(registering, ", ")), registrationRequeueAfter)
+	}
+
+	return nil
+}
+
+// Delete is a no-op as resource providers are never unregistered.
+func (s *Service) Delete(ctx context.Context) error {
+	return nil
+}
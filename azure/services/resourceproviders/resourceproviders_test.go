@@ -0,0 +1,140 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceproviders
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-05-01/resources"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/v2/klogr"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceproviders/mock_resourceproviders"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+func TestReconcileResourceProviders(t *testing.T) {
+	testcases := []struct {
+		name              string
+		expect            func(s *mock_resourceproviders.MockResourceProviderScopeMockRecorder, m *mock_resourceproviders.MockclientMockRecorder)
+		expectedError     string
+		expectedTransient bool
+	}{
+		{
+			name:          "all resource providers are registered",
+			expectedError: "",
+			expect: func(s *mock_resourceproviders.MockResourceProviderScopeMockRecorder, m *mock_resourceproviders.MockclientMockRecorder) {
+				s.ResourceProvidersRegistered().Return(false)
+				s.RequiredResourceProviders().Return([]string{"Microsoft.ContainerService", "Microsoft.Network"})
+				s.ResourceProviderRegistrationAllowed().AnyTimes().Return(false)
+				m.Get(gomockinternal.AContext(), "Microsoft.ContainerService").Return(resources.Provider{RegistrationState: to.StringPtr("Registered")}, nil)
+				m.Get(gomockinternal.AContext(), "Microsoft.Network").Return(resources.Provider{RegistrationState: to.StringPtr("Registered")}, nil)
+			},
+		},
+		{
+			name:          "resource providers are not checked once the control plane is initialized",
+			expectedError: "",
+			expect: func(s *mock_resourceproviders.MockResourceProviderScopeMockRecorder, m *mock_resourceproviders.MockclientMockRecorder) {
+				s.ResourceProvidersRegistered().Return(true)
+			},
+		},
+		{
+			name:          "unregistered resource provider is detected",
+			expectedError: "subscription 123 is not registered with resource providers Microsoft.ContainerService, register them with \"az provider register --namespace <namespace>\"",
+			expect: func(s *mock_resourceproviders.MockResourceProviderScopeMockRecorder, m *mock_resourceproviders.MockclientMockRecorder) {
+				s.ResourceProvidersRegistered().Return(false)
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.RequiredResourceProviders().Return([]string{"Microsoft.ContainerService", "Microsoft.Network"})
+				s.ResourceProviderRegistrationAllowed().AnyTimes().Return(false)
+				m.Get(gomockinternal.AContext(), "Microsoft.ContainerService").Return(resources.Provider{RegistrationState: to.StringPtr("NotRegistered")}, nil)
+				m.Get(gomockinternal.AContext(), "Microsoft.Network").Return(resources.Provider{RegistrationState: to.StringPtr("Registered")}, nil)
+			},
+		},
+		{
+			name:              "unregistered resource provider is registered when registration is allowed",
+			expectedError:     "waiting for subscription 123 to be registered with resource providers Microsoft.ContainerService. Object will be requeued after 30s",
+			expectedTransient: true,
+			expect: func(s *mock_resourceproviders.MockResourceProviderScopeMockRecorder, m *mock_resourceproviders.MockclientMockRecorder) {
+				s.ResourceProvidersRegistered().Return(false)
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.RequiredResourceProviders().Return([]string{"Microsoft.ContainerService"})
+				s.ResourceProviderRegistrationAllowed().AnyTimes().Return(true)
+				m.Get(gomockinternal.AContext(), "Microsoft.ContainerService").Return(resources.Provider{RegistrationState: to.StringPtr("NotRegistered")}, nil)
+				m.Register(gomockinternal.AContext(), "Microsoft.ContainerService").Return(resources.Provider{RegistrationState: to.StringPtr("Registering")}, nil)
+			},
+		},
+		{
+			name:              "resource provider being registered is not registered again",
+			expectedError:     "waiting for subscription 123 to be registered with resource providers Microsoft.ContainerService. Object will be requeued after 30s",
+			expectedTransient: true,
+			expect: func(s *mock_resourceproviders.MockResourceProviderScopeMockRecorder, m *mock_resourceproviders.MockclientMockRecorder) {
+				s.ResourceProvidersRegistered().Return(false)
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.RequiredResourceProviders().Return([]string{"Microsoft.ContainerService"})
+				s.ResourceProviderRegistrationAllowed().AnyTimes().Return(true)
+				m.Get(gomockinternal.AContext(), "Microsoft.ContainerService").Return(resources.Provider{RegistrationState: to.StringPtr("Registering")}, nil)
+			},
+		},
+		{
+			name:          "fail to get resource provider",
+			expectedError: "failed to get resource provider Microsoft.ContainerService: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_resourceproviders.MockResourceProviderScopeMockRecorder, m *mock_resourceproviders.MockclientMockRecorder) {
+				s.ResourceProvidersRegistered().Return(false)
+				s.RequiredResourceProviders().Return([]string{"Microsoft.ContainerService"})
+				m.Get(gomockinternal.AContext(), "Microsoft.ContainerService").Return(resources.Provider{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_resourceproviders.NewMockResourceProviderScope(mockCtrl)
+			clientMock := mock_resourceproviders.NewMockclient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+				var reconcileError azure.ReconcileError
+				g.Expect(errors.As(err, &reconcileError) && reconcileError.IsTransient()).To(Equal(tc.expectedTransient))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -18,9 +18,11 @@ package roleassignments
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/Azure/azure-sdk-for-go/profiles/2019-03-01/authorization/mgmt/authorization"
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
 
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
@@ -29,6 +31,7 @@ import (
 // client wraps go-sdk.
 type client interface {
 	Create(context.Context, string, string, authorization.RoleAssignmentCreateParameters) (authorization.RoleAssignment, error)
+	ListForScope(context.Context, string, string) ([]authorization.RoleAssignment, error)
 }
 
 // azureClient contains the Azure go-sdk Client.
@@ -64,5 +67,30 @@ func (ac *azureClient) Create(ctx context.Context, scope string, roleAssignmentN
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "roleassignments.AzureClient.Create")
 	defer done()
 
-	return ac.roleassignments.Create(ctx, scope, roleAssignmentName, parameters)
+	roleAssignment, err := ac.roleassignments.Create(ctx, scope, roleAssignmentName, parameters)
+	return roleAssignment, azure.WithRequestIDs(err)
+}
+
+// ListForScope lists the role assignments for a scope.
+// Parameters:
+// scope - the scope of the role assignments.
+// filter - the filter to apply on the operation. Use $filter=atScope() to return only the role assignments that are
+// assigned at the scope itself, rather than at the scopes above or below it.
+func (ac *azureClient) ListForScope(ctx context.Context, scope string, filter string) ([]authorization.RoleAssignment, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "roleassignments.AzureClient.ListForScope")
+	defer done()
+
+	itr, err := ac.roleassignments.ListForScopeComplete(ctx, scope, filter)
+	if err != nil {
+		return nil, errors.Wrapf(azure.WithRequestIDs(err), "failed to list role assignments for scope %s", scope)
+	}
+
+	var roleAssignments []authorization.RoleAssignment
+	for ; itr.NotDone(); err = itr.NextWithContext(ctx) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate role assignments [%w]", azure.WithRequestIDs(err))
+		}
+		roleAssignments = append(roleAssignments, itr.Value())
+	}
+	return roleAssignments, nil
 }
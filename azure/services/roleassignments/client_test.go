@@ -0,0 +1,58 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package roleassignments
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/profiles/2019-03-01/authorization/mgmt/authorization"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+func TestAzureClientCreateErrorRequestIDs(t *testing.T) {
+	g := NewWithT(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("x-ms-request-id", "my-request-id")
+		w.Header().Set("x-ms-correlation-request-id", "my-correlation-id")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error": {"code": "RoleAssignmentNotFound", "message": "role assignment does not exist"}}`)
+	}))
+	defer server.Close()
+
+	ac := &azureClient{
+		roleassignments: authorization.NewRoleAssignmentsClientWithBaseURI(server.URL, "123"),
+	}
+
+	_, err := ac.Create(context.TODO(), "/subscriptions/123/", "my-role-assignment", authorization.RoleAssignmentCreateParameters{
+		Properties: &authorization.RoleAssignmentProperties{
+			RoleDefinitionID: to.StringPtr("my-role-definition"),
+			PrincipalID:      to.StringPtr("my-principal"),
+		},
+	})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("request ID: my-request-id, correlation ID: my-correlation-id"))
+	g.Expect(azure.ResourceNotFound(err)).To(BeTrue())
+}
@@ -28,6 +28,7 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	v1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
+	v1beta10 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
 // MockRoleAssignmentScope is a mock of RoleAssignmentScope interface.
@@ -255,6 +256,20 @@ func (mr *MockRoleAssignmentScopeMockRecorder) Info(msg interface{}, keysAndValu
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Info", reflect.TypeOf((*MockRoleAssignmentScope)(nil).Info), varargs...)
 }
 
+// IsRoleAssignmentReady mocks base method.
+func (m *MockRoleAssignmentScope) IsRoleAssignmentReady() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsRoleAssignmentReady")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsRoleAssignmentReady indicates an expected call of IsRoleAssignmentReady.
+func (mr *MockRoleAssignmentScopeMockRecorder) IsRoleAssignmentReady() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRoleAssignmentReady", reflect.TypeOf((*MockRoleAssignmentScope)(nil).IsRoleAssignmentReady))
+}
+
 // Location mocks base method.
 func (m *MockRoleAssignmentScope) Location() string {
 	m.ctrl.T.Helper()
@@ -283,6 +298,20 @@ func (mr *MockRoleAssignmentScopeMockRecorder) ResourceGroup() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockRoleAssignmentScope)(nil).ResourceGroup))
 }
 
+// RoleAssignmentCreateAttempts mocks base method.
+func (m *MockRoleAssignmentScope) RoleAssignmentCreateAttempts() int32 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RoleAssignmentCreateAttempts")
+	ret0, _ := ret[0].(int32)
+	return ret0
+}
+
+// RoleAssignmentCreateAttempts indicates an expected call of RoleAssignmentCreateAttempts.
+func (mr *MockRoleAssignmentScopeMockRecorder) RoleAssignmentCreateAttempts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RoleAssignmentCreateAttempts", reflect.TypeOf((*MockRoleAssignmentScope)(nil).RoleAssignmentCreateAttempts))
+}
+
 // RoleAssignmentSpecs mocks base method.
 func (m *MockRoleAssignmentScope) RoleAssignmentSpecs() []azure.RoleAssignmentSpec {
 	m.ctrl.T.Helper()
@@ -297,6 +326,18 @@ func (mr *MockRoleAssignmentScopeMockRecorder) RoleAssignmentSpecs() *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RoleAssignmentSpecs", reflect.TypeOf((*MockRoleAssignmentScope)(nil).RoleAssignmentSpecs))
 }
 
+// SetRoleAssignmentCreateAttempts mocks base method.
+func (m *MockRoleAssignmentScope) SetRoleAssignmentCreateAttempts(arg0 int32) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetRoleAssignmentCreateAttempts", arg0)
+}
+
+// SetRoleAssignmentCreateAttempts indicates an expected call of SetRoleAssignmentCreateAttempts.
+func (mr *MockRoleAssignmentScopeMockRecorder) SetRoleAssignmentCreateAttempts(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRoleAssignmentCreateAttempts", reflect.TypeOf((*MockRoleAssignmentScope)(nil).SetRoleAssignmentCreateAttempts), arg0)
+}
+
 // SubscriptionID mocks base method.
 func (m *MockRoleAssignmentScope) SubscriptionID() string {
 	m.ctrl.T.Helper()
@@ -325,6 +366,18 @@ func (mr *MockRoleAssignmentScopeMockRecorder) TenantID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockRoleAssignmentScope)(nil).TenantID))
 }
 
+// UpdatePutStatus mocks base method.
+func (m *MockRoleAssignmentScope) UpdatePutStatus(arg0 v1beta10.ConditionType, arg1 string, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdatePutStatus", arg0, arg1, arg2)
+}
+
+// UpdatePutStatus indicates an expected call of UpdatePutStatus.
+func (mr *MockRoleAssignmentScopeMockRecorder) UpdatePutStatus(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePutStatus", reflect.TypeOf((*MockRoleAssignmentScope)(nil).UpdatePutStatus), arg0, arg1, arg2)
+}
+
 // V mocks base method.
 func (m *MockRoleAssignmentScope) V(level int) logr.Logger {
 	m.ctrl.T.Helper()
@@ -19,25 +19,83 @@ package roleassignments
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/profiles/2019-03-01/authorization/mgmt/authorization"
+	"github.com/Azure/go-autorest/autorest"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/go-logr/logr"
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesets"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachines"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
-const azureBuiltInContributorID = "b24988ac-6180-42a0-ab88-20f7382dd24c"
+const (
+	serviceName               = "roleassignments"
+	azureBuiltInContributorID = "b24988ac-6180-42a0-ab88-20f7382dd24c"
+
+	// codeRoleAssignmentExists is returned when the role is already assigned to the principal at the scope under
+	// another name.
+	codeRoleAssignmentExists = "RoleAssignmentExists"
+
+	// codeRoleAssignmentUpdateNotPermitted is returned when the name of the role assignment is taken by a role
+	// assignment of another principal, role or scope.
+	codeRoleAssignmentUpdateNotPermitted = "RoleAssignmentUpdateNotPermitted"
+
+	// defaultPropagationPollInterval is the interval at which role assignments are polled while waiting for them to propagate.
+	defaultPropagationPollInterval = 5 * time.Second
+
+	// propagationRequeueAfter is how long to wait before checking again on role assignments that have not propagated.
+	propagationRequeueAfter = 15 * time.Second
+
+	// DefaultMaxCreateAttempts is the default number of consecutive reconciles in which creating a role assignment can
+	// fail with a non-retriable error before giving up.
+	DefaultMaxCreateAttempts = 3
+
+	// createRequeueAfter is how long to wait before trying again to create a role assignment that could not be created.
+	createRequeueAfter = 15 * time.Second
+
+	// scopeRequeueAfter is how long to wait before checking again on the scope of a role assignment that does not exist yet.
+	scopeRequeueAfter = 15 * time.Second
+)
+
+// nonRetriableCreateCodes are the codes of the errors creating a role assignment that retrying is unlikely to fix
+// without a change to the identity or the permissions of the controller.
+var nonRetriableCreateCodes = map[string]bool{
+	"AuthorizationFailed":         true,
+	"LinkedAuthorizationFailed":   true,
+	"InvalidPrincipalId":          true,
+	"InvalidRoleAssignmentId":     true,
+	"PrincipalTypeNotSupported":   true,
+	"RoleAssignmentLimitExceeded": true,
+	"RoleDefinitionDoesNotExist":  true,
+}
 
 // RoleAssignmentScope defines the scope interface for a role assignment service.
 type RoleAssignmentScope interface {
 	logr.Logger
 	azure.ClusterDescriber
 	RoleAssignmentSpecs() []azure.RoleAssignmentSpec
+	IsRoleAssignmentReady() bool
+	RoleAssignmentCreateAttempts() int32
+	SetRoleAssignmentCreateAttempts(int32)
+	UpdatePutStatus(clusterv1.ConditionType, string, error)
+}
+
+// NodeResourceGroupDescriber is implemented by the scopes of managed clusters, whose role assignments can target the
+// node resource group AKS creates the nodes of the cluster in.
+type NodeResourceGroupDescriber interface {
+	NodeResourceGroup() string
 }
 
 // Service provides operations on Azure resources.
@@ -46,80 +104,388 @@ type Service struct {
 	client
 	virtualMachinesClient        virtualmachines.Client
 	virtualMachineScaleSetClient scalesets.Client
+	propagationPollInterval      time.Duration
+	maxCreateAttempts            int
 }
 
-// New creates a new service.
-func New(scope RoleAssignmentScope) *Service {
+// New creates a new service. maxCreateAttempts is the number of consecutive reconciles in which creating a role
+// assignment can fail with a non-retriable error before the failure is surfaced as a terminal error, and defaults to
+// DefaultMaxCreateAttempts when it is not positive.
+func New(scope RoleAssignmentScope, maxCreateAttempts int) *Service {
+	if maxCreateAttempts < 1 {
+		maxCreateAttempts = DefaultMaxCreateAttempts
+	}
 	return &Service{
 		Scope:                        scope,
 		client:                       newClient(scope),
 		virtualMachinesClient:        virtualmachines.NewClient(scope),
 		virtualMachineScaleSetClient: scalesets.NewClient(scope),
+		propagationPollInterval:      defaultPropagationPollInterval,
+		maxCreateAttempts:            maxCreateAttempts,
 	}
 }
 
-// Reconcile creates a role assignment.
+// Reconcile creates the role assignments and sets the RoleAssignmentReady condition once all of them have propagated.
 func (s *Service) Reconcile(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "roleassignments.Service.Reconcile")
 	defer done()
 
+	var scopes []string
+	roleAssignmentNames := make(map[string][]string)
 	for _, roleSpec := range s.Scope.RoleAssignmentSpecs() {
-		switch roleSpec.ResourceType {
-		case azure.VirtualMachine:
-			return s.reconcileVM(ctx, roleSpec)
-		case azure.VirtualMachineScaleSet:
-			return s.reconcileVMSS(ctx, roleSpec)
+		roleSpec, err := s.resolveScope(roleSpec)
+		if err != nil {
+			s.Scope.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, err)
+			return err
+		}
+
+		var names []string
+		err = s.verifyScopeExists(ctx, roleSpec)
+		switch {
+		case err != nil:
+			// The role assignment is not created until its scope exists.
+		case len(roleSpec.PrincipalIDs) > 0:
+			names, err = s.reconcilePrincipals(ctx, roleSpec)
+		case roleSpec.ResourceType == azure.VirtualMachine:
+			names, err = s.reconcileVM(ctx, roleSpec)
+		case roleSpec.ResourceType == azure.VirtualMachineScaleSet:
+			names, err = s.reconcileVMSS(ctx, roleSpec)
 		default:
-			return errors.Errorf("unexpected resource type %q. Expected one of [%s, %s]", roleSpec.ResourceType,
+			err = errors.Errorf("unexpected resource type %q. Expected one of [%s, %s]", roleSpec.ResourceType,
 				azure.VirtualMachine, azure.VirtualMachineScaleSet)
 		}
+		if err != nil {
+			var cerr createError
+			if errors.As(err, &cerr) {
+				err = s.handleCreateError(err)
+			}
+			s.Scope.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, err)
+			return err
+		}
+
+		scope := s.specScope(roleSpec)
+		if _, ok := roleAssignmentNames[scope]; !ok {
+			scopes = append(scopes, scope)
+		}
+		roleAssignmentNames[scope] = append(roleAssignmentNames[scope], names...)
+	}
+
+	if s.Scope.RoleAssignmentCreateAttempts() > 0 {
+		s.Scope.SetRoleAssignmentCreateAttempts(0)
+	}
+
+	// Role assignments don't need to propagate again once they have, so they are no longer listed.
+	if len(scopes) == 0 || s.Scope.IsRoleAssignmentReady() {
+		return nil
+	}
+
+	err := s.verifyPropagation(ctx, scopes, roleAssignmentNames)
+	s.Scope.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, err)
+	return err
+}
+
+// resolveScope returns the role assignment spec with its scope set to the ID of the node resource group of the managed
+// cluster if the spec targets it.
+func (s *Service) resolveScope(roleSpec azure.RoleAssignmentSpec) (azure.RoleAssignmentSpec, error) {
+	if !roleSpec.AtNodeResourceGroup {
+		return roleSpec, nil
+	}
+
+	describer, ok := s.Scope.(NodeResourceGroupDescriber)
+	if !ok || describer.NodeResourceGroup() == "" {
+		return roleSpec, azure.WithTerminalError(errors.Errorf("role assignment %s requires a managed cluster with a node resource group", roleSpec.Name))
+	}
+
+	roleSpec.Scope = azure.ResourceGroupID(s.Scope.SubscriptionID(), describer.NodeResourceGroup())
+	return roleSpec, nil
+}
+
+// handleCreateError returns a transient error to try creating the role assignment again on a later reconcile, unless
+// creating it has failed with a non-retriable error in too many consecutive reconciles, in which case it returns a
+// terminal error.
+func (s *Service) handleCreateError(err error) error {
+	if !nonRetriableCreateCodes[serviceErrorCode(err)] {
+		return azure.WithTransientError(err, createRequeueAfter)
+	}
+
+	attempts := s.Scope.RoleAssignmentCreateAttempts() + 1
+	s.Scope.SetRoleAssignmentCreateAttempts(attempts)
+	if int(attempts) >= s.maxCreateAttempts {
+		return azure.WithTerminalError(errors.Wrapf(err, "giving up after %d attempts", attempts))
+	}
+	s.Scope.V(2).Info("failed to create role assignment with a non-retriable error", "attempts", attempts, "max attempts", s.maxCreateAttempts)
+	return azure.WithTransientError(err, createRequeueAfter)
+}
+
+// verifyScopeExists returns a transient error if the role assignment spec is scoped to a resource that does not exist
+// yet, so that the role assignment is only created once the resource has been created.
+func (s *Service) verifyScopeExists(ctx context.Context, roleSpec azure.RoleAssignmentSpec) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "roleassignments.Service.verifyScopeExists")
+	defer done()
+
+	if roleSpec.Scope == "" {
+		return nil
+	}
+
+	// Listing the role assignments at a scope fails with a not found error if the resource of the scope does not exist.
+	if _, err := s.client.ListForScope(ctx, roleSpec.Scope, "atScope()"); err != nil {
+		if azure.ResourceNotFound(err) {
+			s.Scope.V(2).Info("waiting for the scope of the role assignment to exist", "role assignment", roleSpec.Name, "scope", roleSpec.Scope)
+			future := &infrav1.Future{
+				Type:        infrav1.PutFuture,
+				ServiceName: serviceName,
+				Name:        roleSpec.Name,
+			}
+			err := errors.Wrapf(azure.NewOperationNotDoneError(future), "scope %s of role assignment %s does not exist yet", roleSpec.Scope, roleSpec.Name)
+			return azure.WithTransientError(err, scopeRequeueAfter)
+		}
+		return errors.Wrapf(err, "failed to check the scope of role assignment %s", roleSpec.Name)
 	}
 	return nil
 }
 
-func (s *Service) reconcileVM(ctx context.Context, roleSpec azure.RoleAssignmentSpec) error {
+// verifyPropagation returns a transient error if any of the role assignments with the given names is not yet listable
+// at the scope it was created at.
+func (s *Service) verifyPropagation(ctx context.Context, scopes []string, roleAssignmentNames map[string][]string) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "roleassignments.Service.verifyPropagation")
+	defer done()
+
+	var missing []string
+	for _, scope := range scopes {
+		missingAtScope, err := s.missingRoleAssignments(ctx, scope, roleAssignmentNames[scope])
+		if err != nil {
+			return errors.Wrap(err, "failed to verify role assignments")
+		}
+		missing = append(missing, missingAtScope...)
+	}
+
+	if len(missing) > 0 {
+		s.Scope.V(2).Info("waiting for role assignments to propagate", "role assignments", missing)
+		future := &infrav1.Future{
+			Type:        infrav1.PutFuture,
+			ServiceName: serviceName,
+			Name:        strings.Join(missing, ", "),
+		}
+		return azure.WithTransientError(azure.NewOperationNotDoneError(future), propagationRequeueAfter)
+	}
+
+	return nil
+}
+
+// WaitForPropagation polls until the role assignment with the given name is listable at the given scope, so that
+// callers don't proceed before the role assignment is effective. An error is returned if the role assignment has not
+// propagated once the timeout elapses.
+func (s *Service) WaitForPropagation(ctx context.Context, scope string, roleAssignmentName string, timeout time.Duration) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "roleassignments.Service.WaitForPropagation")
+	defer done()
+
+	interval := s.propagationPollInterval
+	if interval == 0 {
+		interval = defaultPropagationPollInterval
+	}
+
+	err := wait.PollImmediate(interval, timeout, func() (bool, error) {
+		missing, err := s.missingRoleAssignments(ctx, scope, []string{roleAssignmentName})
+		if err != nil {
+			return false, err
+		}
+
+		if len(missing) > 0 {
+			s.Scope.V(2).Info("waiting for role assignment to propagate", "role assignment", roleAssignmentName)
+			return false, nil
+		}
+		return true, nil
+	})
+	if errors.Is(err, wait.ErrWaitTimeout) {
+		return errors.Errorf("timed out waiting for role assignment %s to propagate to scope %s", roleAssignmentName, scope)
+	}
+	return errors.Wrapf(err, "failed to wait for role assignment %s to propagate", roleAssignmentName)
+}
+
+// missingRoleAssignments returns the names of the given role assignments that are not listable at the given scope.
+func (s *Service) missingRoleAssignments(ctx context.Context, scope string, roleAssignmentNames []string) ([]string, error) {
+	roleAssignments, err := s.client.ListForScope(ctx, scope, "atScope()")
+	if err != nil && !azure.ResourceNotFound(err) {
+		return nil, err
+	}
+
+	existing := make(map[string]bool, len(roleAssignments))
+	for _, roleAssignment := range roleAssignments {
+		existing[to.String(roleAssignment.Name)] = true
+	}
+
+	var missing []string
+	for _, name := range roleAssignmentNames {
+		if !existing[name] {
+			missing = append(missing, name)
+		}
+	}
+	return missing, nil
+}
+
+// reconcileVM assigns the role to the system assigned identity of the VM and returns the name of the role assignment.
+func (s *Service) reconcileVM(ctx context.Context, roleSpec azure.RoleAssignmentSpec) ([]string, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "roleassignments.Service.reconcileVM")
 	defer done()
 
 	resultVM, err := s.virtualMachinesClient.Get(ctx, s.Scope.ResourceGroup(), roleSpec.MachineName)
 	if err != nil {
-		return errors.Wrap(err, "cannot get VM to assign role to system assigned identity")
+		return nil, errors.Wrap(err, "cannot get VM to assign role to system assigned identity")
 	}
 
-	err = s.assignRole(ctx, roleSpec.Name, resultVM.Identity.PrincipalID)
+	name, err := s.reconcilePrincipal(ctx, s.specScope(roleSpec), roleSpec.Name, to.String(resultVM.Identity.PrincipalID))
 	if err != nil {
-		return errors.Wrap(err, "cannot assign role to VM system assigned identity")
+		return nil, errors.Wrap(err, "cannot assign role to VM system assigned identity")
 	}
 
-	s.Scope.V(2).Info("successfully created role assignment for generated Identity for VM", "virtual machine", roleSpec.MachineName)
+	s.Scope.V(2).Info("successfully reconciled role assignment for generated Identity for VM", "virtual machine", roleSpec.MachineName)
 
-	return nil
+	return []string{name}, nil
 }
 
-func (s *Service) reconcileVMSS(ctx context.Context, roleSpec azure.RoleAssignmentSpec) error {
+// reconcileVMSS assigns the role to the system assigned identity of the VMSS and returns the name of the role
+// assignment.
+func (s *Service) reconcileVMSS(ctx context.Context, roleSpec azure.RoleAssignmentSpec) ([]string, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "roleassignments.Service.reconcileVMSS")
 	defer done()
 
 	resultVMSS, err := s.virtualMachineScaleSetClient.Get(ctx, s.Scope.ResourceGroup(), roleSpec.MachineName)
 	if err != nil {
-		return errors.Wrap(err, "cannot get VMSS to assign role to system assigned identity")
+		return nil, errors.Wrap(err, "cannot get VMSS to assign role to system assigned identity")
 	}
 
-	err = s.assignRole(ctx, roleSpec.Name, resultVMSS.Identity.PrincipalID)
+	name, err := s.reconcilePrincipal(ctx, s.specScope(roleSpec), roleSpec.Name, to.String(resultVMSS.Identity.PrincipalID))
 	if err != nil {
-		return errors.Wrap(err, "cannot assign role to VMSS system assigned identity")
+		return nil, errors.Wrap(err, "cannot assign role to VMSS system assigned identity")
 	}
 
-	s.Scope.V(2).Info("successfully created role assignment for generated Identity for VMSS", "virtual machine scale set", roleSpec.MachineName)
+	s.Scope.V(2).Info("successfully reconciled role assignment for generated Identity for VMSS", "virtual machine scale set", roleSpec.MachineName)
 
-	return nil
+	return []string{name}, nil
 }
 
-func (s *Service) assignRole(ctx context.Context, roleAssignmentName string, principalID *string) error {
+// principalRoleAssignmentName returns the name of the role assignment for a principal. Role assignment names must be
+// unique GUIDs, so a stable one is derived for each principal from the name of the role assignment spec.
+func principalRoleAssignmentName(namespace uuid.UUID, principalID string) string {
+	return uuid.NewSHA1(namespace, []byte(principalID)).String()
+}
+
+// reconcilePrincipals assigns the role to each of the principals of the role assignment spec and returns the names of
+// the role assignments. A failure to assign the role to a principal does not prevent assigning it to the others, and
+// the returned error names the principals the role could not be assigned to.
+func (s *Service) reconcilePrincipals(ctx context.Context, roleSpec azure.RoleAssignmentSpec) ([]string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "roleassignments.Service.reconcilePrincipals")
+	defer done()
+
+	namespace, err := uuid.Parse(roleSpec.Name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid role assignment name %q", roleSpec.Name)
+	}
+
+	scope := s.specScope(roleSpec)
+	var names []string
+	var failed []string
+	var errs []error
+	createFailed := true
+	for _, principalID := range roleSpec.PrincipalIDs {
+		name, err := s.reconcilePrincipal(ctx, scope, principalRoleAssignmentName(namespace, principalID), principalID)
+		if err != nil {
+			var cerr createError
+			createFailed = createFailed && errors.As(err, &cerr)
+			failed = append(failed, principalID)
+			errs = append(errs, errors.Wrapf(err, "cannot assign role to principal %s", principalID))
+			continue
+		}
+		names = append(names, name)
+		s.Scope.V(2).Info("successfully reconciled role assignment for principal", "principal", principalID)
+	}
+
+	if len(errs) > 0 {
+		err := errors.Wrapf(kerrors.NewAggregate(errs), "failed to assign role to principals %s", strings.Join(failed, ", "))
+		if createFailed {
+			// Failing to create some of the role assignments is retried like failing to create a single one.
+			return nil, createError{err}
+		}
+		return nil, err
+	}
+
+	return names, nil
+}
+
+// reconcilePrincipal assigns the role to the principal at the scope unless the role assignment already exists, and
+// returns the name of the role assignment.
+func (s *Service) reconcilePrincipal(ctx context.Context, scope string, roleAssignmentName string, principalID string) (string, error) {
+	existing, err := s.ListAssignmentsForPrincipal(ctx, principalID, scope)
+	if err != nil {
+		return "", err
+	}
+
+	missing, unexpected := diffRoleAssignments(existing, []string{roleAssignmentName})
+	if len(unexpected) > 0 {
+		s.Scope.V(2).Info("principal has role assignments at the scope that are not desired", "principal", principalID, "scope", scope, "role assignments", unexpected)
+	}
+	if len(missing) == 0 {
+		s.Scope.V(4).Info("role assignment for principal already exists", "principal", principalID, "role assignment", roleAssignmentName)
+		return roleAssignmentName, nil
+	}
+
+	return s.assignRole(ctx, scope, roleAssignmentName, to.StringPtr(principalID))
+}
+
+// ListAssignmentsForPrincipal returns the role assignments of the principal at the given scope. The role assignments
+// of the principal above and below the scope are left out.
+func (s *Service) ListAssignmentsForPrincipal(ctx context.Context, principalID string, scope string) ([]authorization.RoleAssignment, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "roleassignments.Service.ListAssignmentsForPrincipal")
+	defer done()
+
+	roleAssignments, err := s.client.ListForScope(ctx, scope, fmt.Sprintf("principalId eq '%s'", principalID))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list role assignments of principal %s", principalID)
+	}
+
+	var atScope []authorization.RoleAssignment
+	for _, roleAssignment := range roleAssignments {
+		if roleAssignment.Properties != nil && sameScope(to.String(roleAssignment.Properties.Scope), scope) {
+			atScope = append(atScope, roleAssignment)
+		}
+	}
+	return atScope, nil
+}
+
+// diffRoleAssignments returns the names of the desired role assignments that do not exist, and the names of the
+// existing role assignments that are not desired.
+func diffRoleAssignments(existing []authorization.RoleAssignment, desired []string) (missing []string, unexpected []string) {
+	existingNames := make(map[string]bool, len(existing))
+	for _, roleAssignment := range existing {
+		existingNames[to.String(roleAssignment.Name)] = true
+	}
+	desiredNames := make(map[string]bool, len(desired))
+	for _, name := range desired {
+		desiredNames[name] = true
+		if !existingNames[name] {
+			missing = append(missing, name)
+		}
+	}
+	for _, roleAssignment := range existing {
+		if name := to.String(roleAssignment.Name); !desiredNames[name] {
+			unexpected = append(unexpected, name)
+		}
+	}
+	return missing, unexpected
+}
+
+// sameScope returns true if both role assignment scopes are the same, ignoring case and trailing slashes.
+func sameScope(a, b string) bool {
+	return strings.EqualFold(strings.TrimSuffix(a, "/"), strings.TrimSuffix(b, "/"))
+}
+
+// assignRole creates the role assignment and returns its name. If the role is already assigned to the principal at
+// the scope under another name, that role assignment is kept and its name is returned. If the name is taken by a role
+// assignment of another principal, role or scope, the role assignment is created under a new random name.
+func (s *Service) assignRole(ctx context.Context, scope string, roleAssignmentName string, principalID *string) (string, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "roleassignments.Service.assignRole")
 	defer done()
 
-	scope := fmt.Sprintf("/subscriptions/%s/", s.Scope.SubscriptionID())
 	// Azure built-in roles https://docs.microsoft.com/en-us/azure/role-based-access-control/built-in-roles
 	contributorRoleDefinitionID := fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Authorization/roleDefinitions/%s", s.Scope.SubscriptionID(), azureBuiltInContributorID)
 	params := authorization.RoleAssignmentCreateParameters{
@@ -128,8 +494,94 @@ func (s *Service) assignRole(ctx context.Context, roleAssignmentName string, pri
 			PrincipalID:      principalID,
 		},
 	}
+
 	_, err := s.client.Create(ctx, scope, roleAssignmentName, params)
-	return err
+	if err == nil {
+		return roleAssignmentName, nil
+	}
+	if !roleAssignmentConflict(err) {
+		// A new identity can take some time to replicate, so PrincipalNotFound is retried like any other error.
+		return "", createError{errors.Wrapf(err, "failed to create role assignment %s", roleAssignmentName)}
+	}
+
+	existingName, found, err := s.findRoleAssignment(ctx, scope, to.String(principalID), contributorRoleDefinitionID)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to resolve conflict creating role assignment %s", roleAssignmentName)
+	}
+	if found {
+		s.Scope.V(2).Info("role is already assigned to the principal", "role assignment", existingName, "principal", to.String(principalID))
+		return existingName, nil
+	}
+
+	newName := uuid.New().String()
+	s.Scope.V(2).Info("role assignment name is taken by an unrelated role assignment, retrying with a new name", "role assignment", roleAssignmentName, "new role assignment", newName)
+	if _, err := s.client.Create(ctx, scope, newName, params); err != nil {
+		return "", createError{errors.Wrapf(err, "failed to create role assignment %s", newName)}
+	}
+	return newName, nil
+}
+
+// findRoleAssignment returns the name of the role assignment of the role definition to the principal at the scope, and
+// false if there is none.
+func (s *Service) findRoleAssignment(ctx context.Context, scope string, principalID string, roleDefinitionID string) (string, bool, error) {
+	roleAssignments, err := s.ListAssignmentsForPrincipal(ctx, principalID, scope)
+	if err != nil {
+		return "", false, err
+	}
+	for _, roleAssignment := range roleAssignments {
+		if strings.EqualFold(to.String(roleAssignment.Properties.RoleDefinitionID), roleDefinitionID) {
+			return to.String(roleAssignment.Name), true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// roleAssignmentConflict parses the error to check if the role assignment could not be created because of an existing
+// role assignment, either of the same role to the same principal at the same scope, or with the same name.
+func roleAssignmentConflict(err error) bool {
+	code := serviceErrorCode(err)
+	return code == codeRoleAssignmentExists || code == codeRoleAssignmentUpdateNotPermitted
+}
+
+// serviceErrorCode returns the code of the Azure service error the error was caused by, or an empty string if there
+// is none. The service error is wrapped in a RequestError in the responses of the Azure SDK.
+func serviceErrorCode(err error) string {
+	derr := autorest.DetailedError{}
+	if !errors.As(err, &derr) {
+		return ""
+	}
+	rerr := &azureautorest.RequestError{}
+	if errors.As(derr.Original, &rerr) && rerr.ServiceError != nil {
+		return rerr.ServiceError.Code
+	}
+	serr := &azureautorest.ServiceError{}
+	if errors.As(derr.Original, &serr) {
+		return serr.Code
+	}
+	return ""
+}
+
+// createError is returned when the role assignment could not be created.
+type createError struct {
+	error
+}
+
+// Unwrap returns the underlying error.
+func (e createError) Unwrap() error {
+	return e.error
+}
+
+// roleAssignmentScope returns the scope role assignments are created at by default.
+func (s *Service) roleAssignmentScope() string {
+	return fmt.Sprintf("/subscriptions/%s/", s.Scope.SubscriptionID())
+}
+
+// specScope returns the scope the role assignments of the role assignment spec are created at.
+func (s *Service) specScope(roleSpec azure.RoleAssignmentSpec) string {
+	if roleSpec.Scope != "" {
+		return roleSpec.Scope
+	}
+	return s.roleAssignmentScope()
 }
 
 // Delete is a no-op as the role assignments get deleted as part of VM deletion.
@@ -20,16 +20,22 @@ import (
 	"context"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/profiles/2019-03-01/authorization/mgmt/authorization"
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
 	"github.com/Azure/go-autorest/autorest"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 
 	"k8s.io/klog/v2/klogr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments/mock_roleassignments"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesets/mock_scalesets"
@@ -53,6 +59,7 @@ func TestReconcileRoleAssignmentsVM(t *testing.T) {
 				s.RoleAssignmentSpecs().Return([]azure.RoleAssignmentSpec{
 					{
 						MachineName:  "test-vm",
+						Name:         "test-role-assignment",
 						ResourceType: azure.VirtualMachine,
 					},
 				})
@@ -61,12 +68,17 @@ func TestReconcileRoleAssignmentsVM(t *testing.T) {
 						PrincipalID: to.StringPtr("000"),
 					},
 				}, nil)
+				m.ListForScope(gomockinternal.AContext(), "/subscriptions/12345/", "principalId eq '000'").Return(nil, nil)
 				m.Create(gomockinternal.AContext(), "/subscriptions/12345/", gomock.AssignableToTypeOf("uuid"), gomock.AssignableToTypeOf(authorization.RoleAssignmentCreateParameters{
 					Properties: &authorization.RoleAssignmentProperties{
 						RoleDefinitionID: to.StringPtr("/subscriptions/12345/providers/Microsoft.Authorization/roleDefinitions/b24988ac-6180-42a0-ab88-20f7382dd24c"),
 						PrincipalID:      to.StringPtr("000"),
 					},
 				}))
+				m.ListForScope(gomockinternal.AContext(), "/subscriptions/12345/", "atScope()").Return([]authorization.RoleAssignment{
+					{Name: to.StringPtr("test-role-assignment")},
+				}, nil)
+				s.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, nil)
 			},
 		},
 		{
@@ -79,15 +91,17 @@ func TestReconcileRoleAssignmentsVM(t *testing.T) {
 				s.RoleAssignmentSpecs().Return([]azure.RoleAssignmentSpec{
 					{
 						MachineName:  "test-vm",
+						Name:         "test-role-assignment",
 						ResourceType: azure.VirtualMachine,
 					},
 				})
 				v.Get(gomockinternal.AContext(), "my-rg", "test-vm").Return(compute.VirtualMachine{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+				s.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, gomock.Any())
 			},
 		},
 		{
 			name:          "return error when creating a role assignment",
-			expectedError: "cannot assign role to VM system assigned identity: #: Internal Server Error: StatusCode=500",
+			expectedError: "cannot assign role to VM system assigned identity: failed to create role assignment test-role-assignment: #: Internal Server Error: StatusCode=500. Object will be requeued after 15s",
 			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, m *mock_roleassignments.MockclientMockRecorder, v *mock_virtualmachines.MockClientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.SubscriptionID().AnyTimes().Return("12345")
@@ -95,6 +109,7 @@ func TestReconcileRoleAssignmentsVM(t *testing.T) {
 				s.RoleAssignmentSpecs().Return([]azure.RoleAssignmentSpec{
 					{
 						MachineName:  "test-vm",
+						Name:         "test-role-assignment",
 						ResourceType: azure.VirtualMachine,
 					},
 				})
@@ -103,7 +118,9 @@ func TestReconcileRoleAssignmentsVM(t *testing.T) {
 						PrincipalID: to.StringPtr("000"),
 					},
 				}, nil)
+				m.ListForScope(gomockinternal.AContext(), "/subscriptions/12345/", "principalId eq '000'").Return(nil, nil)
 				m.Create(gomockinternal.AContext(), "/subscriptions/12345/", gomock.AssignableToTypeOf("uuid"), gomock.AssignableToTypeOf(authorization.RoleAssignmentCreateParameters{})).Return(authorization.RoleAssignment{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+				s.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, gomock.Any())
 			},
 		},
 	}
@@ -116,6 +133,8 @@ func TestReconcileRoleAssignmentsVM(t *testing.T) {
 			mockCtrl := gomock.NewController(t)
 			defer mockCtrl.Finish()
 			scopeMock := mock_roleassignments.NewMockRoleAssignmentScope(mockCtrl)
+			scopeMock.EXPECT().IsRoleAssignmentReady().AnyTimes().Return(false)
+			scopeMock.EXPECT().RoleAssignmentCreateAttempts().AnyTimes().Return(int32(0))
 			clientMock := mock_roleassignments.NewMockclient(mockCtrl)
 			vmMock := mock_virtualmachines.NewMockClient(mockCtrl)
 
@@ -153,6 +172,7 @@ func TestReconcileRoleAssignmentsVMSS(t *testing.T) {
 				s.RoleAssignmentSpecs().Return([]azure.RoleAssignmentSpec{
 					{
 						MachineName:  "test-vmss",
+						Name:         "test-role-assignment",
 						ResourceType: azure.VirtualMachineScaleSet,
 					},
 				})
@@ -161,12 +181,17 @@ func TestReconcileRoleAssignmentsVMSS(t *testing.T) {
 						PrincipalID: to.StringPtr("000"),
 					},
 				}, nil)
+				m.ListForScope(gomockinternal.AContext(), "/subscriptions/12345/", "principalId eq '000'").Return(nil, nil)
 				m.Create(gomockinternal.AContext(), "/subscriptions/12345/", gomock.AssignableToTypeOf("uuid"), gomock.AssignableToTypeOf(authorization.RoleAssignmentCreateParameters{
 					Properties: &authorization.RoleAssignmentProperties{
 						RoleDefinitionID: to.StringPtr("/subscriptions/12345/providers/Microsoft.Authorization/roleDefinitions/b24988ac-6180-42a0-ab88-20f7382dd24c"),
 						PrincipalID:      to.StringPtr("000"),
 					},
 				}))
+				m.ListForScope(gomockinternal.AContext(), "/subscriptions/12345/", "atScope()").Return([]authorization.RoleAssignment{
+					{Name: to.StringPtr("test-role-assignment")},
+				}, nil)
+				s.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, nil)
 			},
 		},
 		{
@@ -179,15 +204,17 @@ func TestReconcileRoleAssignmentsVMSS(t *testing.T) {
 				s.RoleAssignmentSpecs().Return([]azure.RoleAssignmentSpec{
 					{
 						MachineName:  "test-vmss",
+						Name:         "test-role-assignment",
 						ResourceType: azure.VirtualMachineScaleSet,
 					},
 				})
 				v.Get(gomockinternal.AContext(), "my-rg", "test-vmss").Return(compute.VirtualMachineScaleSet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+				s.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, gomock.Any())
 			},
 		},
 		{
 			name:          "return error when creating a role assignment",
-			expectedError: "cannot assign role to VMSS system assigned identity: #: Internal Server Error: StatusCode=500",
+			expectedError: "cannot assign role to VMSS system assigned identity: failed to create role assignment test-role-assignment: #: Internal Server Error: StatusCode=500. Object will be requeued after 15s",
 			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, m *mock_roleassignments.MockclientMockRecorder, v *mock_scalesets.MockClientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.SubscriptionID().AnyTimes().Return("12345")
@@ -195,6 +222,7 @@ func TestReconcileRoleAssignmentsVMSS(t *testing.T) {
 				s.RoleAssignmentSpecs().Return([]azure.RoleAssignmentSpec{
 					{
 						MachineName:  "test-vmss",
+						Name:         "test-role-assignment",
 						ResourceType: azure.VirtualMachineScaleSet,
 					},
 				})
@@ -203,7 +231,9 @@ func TestReconcileRoleAssignmentsVMSS(t *testing.T) {
 						PrincipalID: to.StringPtr("000"),
 					},
 				}, nil)
+				m.ListForScope(gomockinternal.AContext(), "/subscriptions/12345/", "principalId eq '000'").Return(nil, nil)
 				m.Create(gomockinternal.AContext(), "/subscriptions/12345/", gomock.AssignableToTypeOf("uuid"), gomock.AssignableToTypeOf(authorization.RoleAssignmentCreateParameters{})).Return(authorization.RoleAssignment{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+				s.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, gomock.Any())
 			},
 		},
 	}
@@ -216,6 +246,8 @@ func TestReconcileRoleAssignmentsVMSS(t *testing.T) {
 			mockCtrl := gomock.NewController(t)
 			defer mockCtrl.Finish()
 			scopeMock := mock_roleassignments.NewMockRoleAssignmentScope(mockCtrl)
+			scopeMock.EXPECT().IsRoleAssignmentReady().AnyTimes().Return(false)
+			scopeMock.EXPECT().RoleAssignmentCreateAttempts().AnyTimes().Return(int32(0))
 			clientMock := mock_roleassignments.NewMockclient(mockCtrl)
 			vmssMock := mock_scalesets.NewMockClient(mockCtrl)
 
@@ -237,3 +269,736 @@ func TestReconcileRoleAssignmentsVMSS(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileRoleAssignmentsPrincipals(t *testing.T) {
+	contributorRoleDefinitionID := "/subscriptions/12345/providers/Microsoft.Authorization/roleDefinitions/b24988ac-6180-42a0-ab88-20f7382dd24c"
+	paramsFor := func(principalID string) authorization.RoleAssignmentCreateParameters {
+		return authorization.RoleAssignmentCreateParameters{
+			Properties: &authorization.RoleAssignmentProperties{
+				RoleDefinitionID: to.StringPtr(contributorRoleDefinitionID),
+				PrincipalID:      to.StringPtr(principalID),
+			},
+		}
+	}
+
+	testcases := []struct {
+		name          string
+		expect        func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, m *mock_roleassignments.MockclientMockRecorder)
+		expectedError string
+	}{
+		{
+			name:          "create a role assignment for each principal",
+			expectedError: "",
+			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, m *mock_roleassignments.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.SubscriptionID().AnyTimes().Return("12345")
+				s.RoleAssignmentSpecs().Return([]azure.RoleAssignmentSpec{
+					{
+						Name:         "2d9a7b2a-3f3e-4b5e-9c8e-1f1b7c9f0a11",
+						PrincipalIDs: []string{"kubelet", "control-plane"},
+					},
+				})
+				m.ListForScope(gomockinternal.AContext(), "/subscriptions/12345/", "principalId eq 'kubelet'").Return(nil, nil)
+				m.ListForScope(gomockinternal.AContext(), "/subscriptions/12345/", "principalId eq 'control-plane'").Return(nil, nil)
+				m.Create(gomockinternal.AContext(), "/subscriptions/12345/", "09ca83b4-4138-55ca-8896-86e5aaf1644e", paramsFor("kubelet"))
+				m.Create(gomockinternal.AContext(), "/subscriptions/12345/", "4e86c465-75dc-5960-b8b8-ec64c15488c9", paramsFor("control-plane"))
+				m.ListForScope(gomockinternal.AContext(), "/subscriptions/12345/", "atScope()").Return([]authorization.RoleAssignment{
+					{Name: to.StringPtr("09ca83b4-4138-55ca-8896-86e5aaf1644e")},
+					{Name: to.StringPtr("4e86c465-75dc-5960-b8b8-ec64c15488c9")},
+				}, nil)
+				s.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "create role assignments for the other principals when one fails",
+			expectedError: "failed to assign role to principals bbb: cannot assign role to principal bbb: failed to create role assignment 7e5b0dae-4af5-5534-9752-6d5b46d197d2: #: Internal Server Error: StatusCode=500. Object will be requeued after 15s",
+			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, m *mock_roleassignments.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.SubscriptionID().AnyTimes().Return("12345")
+				s.RoleAssignmentSpecs().Return([]azure.RoleAssignmentSpec{
+					{
+						Name:         "2d9a7b2a-3f3e-4b5e-9c8e-1f1b7c9f0a11",
+						PrincipalIDs: []string{"aaa", "bbb", "ccc"},
+					},
+				})
+				m.ListForScope(gomockinternal.AContext(), "/subscriptions/12345/", "principalId eq 'aaa'").Return(nil, nil)
+				m.ListForScope(gomockinternal.AContext(), "/subscriptions/12345/", "principalId eq 'bbb'").Return(nil, nil)
+				m.ListForScope(gomockinternal.AContext(), "/subscriptions/12345/", "principalId eq 'ccc'").Return(nil, nil)
+				m.Create(gomockinternal.AContext(), "/subscriptions/12345/", gomock.AssignableToTypeOf("uuid"), paramsFor("aaa"))
+				m.Create(gomockinternal.AContext(), "/subscriptions/12345/", gomock.AssignableToTypeOf("uuid"), paramsFor("bbb")).Return(authorization.RoleAssignment{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+				m.Create(gomockinternal.AContext(), "/subscriptions/12345/", gomock.AssignableToTypeOf("uuid"), paramsFor("ccc"))
+				s.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, gomock.Any())
+			},
+		},
+		{
+			name:          "invalid role assignment name",
+			expectedError: "invalid role assignment name \"not-a-uuid\": invalid UUID length: 10",
+			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, m *mock_roleassignments.MockclientMockRecorder) {
+				s.RoleAssignmentSpecs().Return([]azure.RoleAssignmentSpec{
+					{
+						Name:         "not-a-uuid",
+						PrincipalIDs: []string{"aaa"},
+					},
+				})
+				s.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, gomock.Any())
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_roleassignments.NewMockRoleAssignmentScope(mockCtrl)
+			scopeMock.EXPECT().IsRoleAssignmentReady().AnyTimes().Return(false)
+			scopeMock.EXPECT().RoleAssignmentCreateAttempts().AnyTimes().Return(int32(0))
+			clientMock := mock_roleassignments.NewMockclient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestReconcileRoleAssignmentsPrincipalDrift(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	scopeMock := mock_roleassignments.NewMockRoleAssignmentScope(mockCtrl)
+	scopeMock.EXPECT().IsRoleAssignmentReady().AnyTimes().Return(false)
+	scopeMock.EXPECT().RoleAssignmentCreateAttempts().AnyTimes().Return(int32(0))
+	clientMock := mock_roleassignments.NewMockclient(mockCtrl)
+	vmMock := mock_virtualmachines.NewMockClient(mockCtrl)
+
+	s := scopeMock.EXPECT()
+	m := clientMock.EXPECT()
+	s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+	s.SubscriptionID().AnyTimes().Return("12345")
+	s.ResourceGroup().Return("my-rg")
+	s.RoleAssignmentSpecs().Return([]azure.RoleAssignmentSpec{
+		{
+			MachineName:  "test-vm",
+			Name:         "test-role-assignment",
+			ResourceType: azure.VirtualMachine,
+		},
+	})
+	vmMock.EXPECT().Get(gomockinternal.AContext(), "my-rg", "test-vm").Return(compute.VirtualMachine{
+		Identity: &compute.VirtualMachineIdentity{
+			PrincipalID: to.StringPtr("000"),
+		},
+	}, nil)
+	existing := []authorization.RoleAssignment{
+		{
+			Name: to.StringPtr("test-role-assignment"),
+			Properties: &authorization.RoleAssignmentPropertiesWithScope{
+				Scope:       to.StringPtr("/subscriptions/12345"),
+				PrincipalID: to.StringPtr("000"),
+			},
+		},
+		{
+			Name: to.StringPtr("other-role-assignment"),
+			Properties: &authorization.RoleAssignmentPropertiesWithScope{
+				Scope:       to.StringPtr("/subscriptions/12345"),
+				PrincipalID: to.StringPtr("000"),
+			},
+		},
+	}
+	m.ListForScope(gomockinternal.AContext(), "/subscriptions/12345/", "principalId eq '000'").Times(2).Return(existing, nil)
+	m.ListForScope(gomockinternal.AContext(), "/subscriptions/12345/", "atScope()").Return(existing, nil)
+	s.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, nil)
+
+	service := &Service{
+		Scope:                 scopeMock,
+		client:                clientMock,
+		virtualMachinesClient: vmMock,
+	}
+
+	// The role assignment that is not desired is detected.
+	assignments, err := service.ListAssignmentsForPrincipal(context.TODO(), "000", "/subscriptions/12345/")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(assignments).To(HaveLen(2))
+	missing, unexpected := diffRoleAssignments(assignments, []string{"test-role-assignment"})
+	g.Expect(missing).To(BeEmpty())
+	g.Expect(unexpected).To(ConsistOf("other-role-assignment"))
+
+	// The desired role assignment already exists, so it is not created again.
+	g.Expect(service.Reconcile(context.TODO())).To(Succeed())
+}
+
+func TestReconcileRoleAssignmentReadyCondition(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	scopeMock := mock_roleassignments.NewMockRoleAssignmentScope(mockCtrl)
+	scopeMock.EXPECT().IsRoleAssignmentReady().AnyTimes().Return(false)
+	scopeMock.EXPECT().RoleAssignmentCreateAttempts().AnyTimes().Return(int32(0))
+	clientMock := mock_roleassignments.NewMockclient(mockCtrl)
+	vmMock := mock_virtualmachines.NewMockClient(mockCtrl)
+
+	s := scopeMock.EXPECT()
+	m := clientMock.EXPECT()
+	s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+	s.SubscriptionID().AnyTimes().Return("12345")
+	s.ResourceGroup().AnyTimes().Return("my-rg")
+	s.RoleAssignmentSpecs().AnyTimes().Return([]azure.RoleAssignmentSpec{
+		{
+			MachineName:  "test-vm-1",
+			Name:         "test-role-assignment-1",
+			ResourceType: azure.VirtualMachine,
+		},
+		{
+			MachineName:  "test-vm-2",
+			Name:         "test-role-assignment-2",
+			ResourceType: azure.VirtualMachine,
+		},
+	})
+	vmMock.EXPECT().Get(gomockinternal.AContext(), "my-rg", "test-vm-1").Times(2).Return(compute.VirtualMachine{
+		Identity: &compute.VirtualMachineIdentity{PrincipalID: to.StringPtr("111")},
+	}, nil)
+	vmMock.EXPECT().Get(gomockinternal.AContext(), "my-rg", "test-vm-2").Times(2).Return(compute.VirtualMachine{
+		Identity: &compute.VirtualMachineIdentity{PrincipalID: to.StringPtr("222")},
+	}, nil)
+	m.ListForScope(gomockinternal.AContext(), "/subscriptions/12345/", "principalId eq '111'").Times(2).Return(nil, nil)
+	m.ListForScope(gomockinternal.AContext(), "/subscriptions/12345/", "principalId eq '222'").Times(2).Return(nil, nil)
+	m.Create(gomockinternal.AContext(), "/subscriptions/12345/", gomock.AssignableToTypeOf("uuid"), gomock.AssignableToTypeOf(authorization.RoleAssignmentCreateParameters{})).Times(4)
+
+	var notReadyErr error
+	gomock.InOrder(
+		m.ListForScope(gomockinternal.AContext(), "/subscriptions/12345/", "atScope()").Return([]authorization.RoleAssignment{
+			{Name: to.StringPtr("test-role-assignment-1")},
+		}, nil),
+		s.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, gomock.Not(gomock.Nil())).Do(
+			func(_ clusterv1.ConditionType, _ string, err error) {
+				notReadyErr = err
+			},
+		),
+		m.ListForScope(gomockinternal.AContext(), "/subscriptions/12345/", "atScope()").Return([]authorization.RoleAssignment{
+			{Name: to.StringPtr("test-role-assignment-1")},
+			{Name: to.StringPtr("test-role-assignment-2")},
+		}, nil),
+		s.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, nil),
+	)
+
+	service := &Service{
+		Scope:                 scopeMock,
+		client:                clientMock,
+		virtualMachinesClient: vmMock,
+	}
+
+	// The condition is not ready while one of the role assignments has not propagated yet.
+	err := service.Reconcile(context.TODO())
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(azure.IsOperationNotDoneError(notReadyErr)).To(BeTrue())
+	var reconcileError azure.ReconcileError
+	g.Expect(errors.As(err, &reconcileError)).To(BeTrue())
+	g.Expect(reconcileError.IsTransient()).To(BeTrue())
+
+	// The condition becomes ready once all role assignments are confirmed.
+	g.Expect(service.Reconcile(context.TODO())).To(Succeed())
+}
+
+func TestReconcileRoleAssignmentsAlreadyReady(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	scopeMock := mock_roleassignments.NewMockRoleAssignmentScope(mockCtrl)
+	clientMock := mock_roleassignments.NewMockclient(mockCtrl)
+	vmMock := mock_virtualmachines.NewMockClient(mockCtrl)
+
+	s := scopeMock.EXPECT()
+	s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+	s.SubscriptionID().AnyTimes().Return("12345")
+	s.ResourceGroup().Return("my-rg")
+	s.RoleAssignmentSpecs().Return([]azure.RoleAssignmentSpec{
+		{
+			MachineName:  "test-vm",
+			Name:         "test-role-assignment",
+			ResourceType: azure.VirtualMachine,
+		},
+	})
+	s.IsRoleAssignmentReady().Return(true)
+	s.RoleAssignmentCreateAttempts().Return(int32(0))
+	vmMock.EXPECT().Get(gomockinternal.AContext(), "my-rg", "test-vm").Return(compute.VirtualMachine{
+		Identity: &compute.VirtualMachineIdentity{PrincipalID: to.StringPtr("000")},
+	}, nil)
+	// The role assignment is not listed at its scope again once the condition is ready.
+	clientMock.EXPECT().ListForScope(gomockinternal.AContext(), "/subscriptions/12345/", "principalId eq '000'").Return([]authorization.RoleAssignment{
+		{
+			Name: to.StringPtr("test-role-assignment"),
+			Properties: &authorization.RoleAssignmentPropertiesWithScope{
+				Scope:       to.StringPtr("/subscriptions/12345/"),
+				PrincipalID: to.StringPtr("000"),
+			},
+		},
+	}, nil)
+
+	service := &Service{
+		Scope:                 scopeMock,
+		client:                clientMock,
+		virtualMachinesClient: vmMock,
+	}
+	g.Expect(service.Reconcile(context.TODO())).To(Succeed())
+}
+
+func TestReconcileRoleAssignmentsDeferredUntilScopeExists(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	scopeMock := mock_roleassignments.NewMockRoleAssignmentScope(mockCtrl)
+	scopeMock.EXPECT().IsRoleAssignmentReady().AnyTimes().Return(false)
+	scopeMock.EXPECT().RoleAssignmentCreateAttempts().AnyTimes().Return(int32(0))
+	clientMock := mock_roleassignments.NewMockclient(mockCtrl)
+	vmMock := mock_virtualmachines.NewMockClient(mockCtrl)
+
+	subnetID := "/subscriptions/12345/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet"
+	s := scopeMock.EXPECT()
+	m := clientMock.EXPECT()
+	s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+	s.SubscriptionID().AnyTimes().Return("12345")
+	s.ResourceGroup().AnyTimes().Return("my-rg")
+	s.RoleAssignmentSpecs().AnyTimes().Return([]azure.RoleAssignmentSpec{
+		{
+			MachineName:  "test-vm",
+			Name:         "test-role-assignment",
+			ResourceType: azure.VirtualMachine,
+			Scope:        subnetID,
+		},
+	})
+
+	var notReadyErr error
+	gomock.InOrder(
+		m.ListForScope(gomockinternal.AContext(), subnetID, "atScope()").Return(nil, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found")),
+		s.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, gomock.Not(gomock.Nil())).Do(
+			func(_ clusterv1.ConditionType, _ string, err error) {
+				notReadyErr = err
+			},
+		),
+		m.ListForScope(gomockinternal.AContext(), subnetID, "atScope()").Return(nil, nil),
+		vmMock.EXPECT().Get(gomockinternal.AContext(), "my-rg", "test-vm").Return(compute.VirtualMachine{
+			Identity: &compute.VirtualMachineIdentity{PrincipalID: to.StringPtr("000")},
+		}, nil),
+		m.ListForScope(gomockinternal.AContext(), subnetID, "principalId eq '000'").Return(nil, nil),
+		m.Create(gomockinternal.AContext(), subnetID, "test-role-assignment", gomock.AssignableToTypeOf(authorization.RoleAssignmentCreateParameters{})),
+		m.ListForScope(gomockinternal.AContext(), subnetID, "atScope()").Return([]authorization.RoleAssignment{
+			{Name: to.StringPtr("test-role-assignment")},
+		}, nil),
+		s.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, nil),
+	)
+
+	service := &Service{
+		Scope:                 scopeMock,
+		client:                clientMock,
+		virtualMachinesClient: vmMock,
+	}
+
+	// The role assignment is not created while its scope does not exist.
+	err := service.Reconcile(context.TODO())
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(notReadyErr).To(Equal(err))
+	var reconcileError azure.ReconcileError
+	g.Expect(errors.As(err, &reconcileError)).To(BeTrue())
+	g.Expect(reconcileError.IsTransient()).To(BeTrue())
+	// The condition is marked as creating rather than failed while waiting for the scope.
+	g.Expect(azure.IsOperationNotDoneError(notReadyErr)).To(BeTrue())
+
+	// The role assignment is created at the scope once it exists.
+	g.Expect(service.Reconcile(context.TODO())).To(Succeed())
+}
+
+// managedClusterScope is a role assignment scope of a managed cluster with a node resource group.
+type managedClusterScope struct {
+	*mock_roleassignments.MockRoleAssignmentScope
+	nodeResourceGroup string
+}
+
+func (s managedClusterScope) NodeResourceGroup() string {
+	return s.nodeResourceGroup
+}
+
+func TestReconcileRoleAssignmentsAtNodeResourceGroup(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	scopeMock := mock_roleassignments.NewMockRoleAssignmentScope(mockCtrl)
+	scopeMock.EXPECT().IsRoleAssignmentReady().AnyTimes().Return(false)
+	scopeMock.EXPECT().RoleAssignmentCreateAttempts().AnyTimes().Return(int32(0))
+	clientMock := mock_roleassignments.NewMockclient(mockCtrl)
+
+	nodeResourceGroupID := "/subscriptions/12345/resourceGroups/MC_my-rg_my-cluster_westus2"
+	s := scopeMock.EXPECT()
+	m := clientMock.EXPECT()
+	s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+	s.SubscriptionID().AnyTimes().Return("12345")
+	s.RoleAssignmentSpecs().Return([]azure.RoleAssignmentSpec{
+		{
+			Name:                "2d9a7b2a-3f3e-4b5e-9c8e-1f1b7c9f0a11",
+			PrincipalIDs:        []string{"aaa"},
+			AtNodeResourceGroup: true,
+		},
+	})
+	gomock.InOrder(
+		m.ListForScope(gomockinternal.AContext(), nodeResourceGroupID, "atScope()").Return(nil, nil),
+		m.ListForScope(gomockinternal.AContext(), nodeResourceGroupID, "principalId eq 'aaa'").Return(nil, nil),
+		m.Create(gomockinternal.AContext(), nodeResourceGroupID, "8f938d38-ebe2-5dd8-8343-aa2c6e09b00d", gomock.AssignableToTypeOf(authorization.RoleAssignmentCreateParameters{})),
+		m.ListForScope(gomockinternal.AContext(), nodeResourceGroupID, "atScope()").Return([]authorization.RoleAssignment{
+			{Name: to.StringPtr("8f938d38-ebe2-5dd8-8343-aa2c6e09b00d")},
+		}, nil),
+		s.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, nil),
+	)
+
+	service := &Service{
+		Scope:  managedClusterScope{MockRoleAssignmentScope: scopeMock, nodeResourceGroup: "MC_my-rg_my-cluster_westus2"},
+		client: clientMock,
+	}
+	g.Expect(service.Reconcile(context.TODO())).To(Succeed())
+}
+
+func TestReconcileRoleAssignmentsAtNodeResourceGroupWithoutManagedCluster(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	scopeMock := mock_roleassignments.NewMockRoleAssignmentScope(mockCtrl)
+	clientMock := mock_roleassignments.NewMockclient(mockCtrl)
+
+	s := scopeMock.EXPECT()
+	s.RoleAssignmentSpecs().Return([]azure.RoleAssignmentSpec{
+		{
+			Name:                "2d9a7b2a-3f3e-4b5e-9c8e-1f1b7c9f0a11",
+			PrincipalIDs:        []string{"aaa"},
+			AtNodeResourceGroup: true,
+		},
+	})
+	s.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, gomock.Not(gomock.Nil()))
+
+	service := &Service{
+		Scope:  scopeMock,
+		client: clientMock,
+	}
+	err := service.Reconcile(context.TODO())
+	g.Expect(err).To(HaveOccurred())
+	var reconcileError azure.ReconcileError
+	g.Expect(errors.As(err, &reconcileError)).To(BeTrue())
+	g.Expect(reconcileError.IsTerminal()).To(BeTrue())
+}
+
+func TestReconcileRoleAssignmentsCreateAttempts(t *testing.T) {
+	serviceErr := func(statusCode int, code string) error {
+		return autorest.DetailedError{
+			StatusCode: statusCode,
+			Original: &azureautorest.RequestError{
+				ServiceError: &azureautorest.ServiceError{Code: code},
+			},
+		}
+	}
+
+	testcases := []struct {
+		name            string
+		attempts        int32
+		expect          func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, m *mock_roleassignments.MockclientMockRecorder)
+		expectTransient bool
+		expectTerminal  bool
+	}{
+		{
+			name: "throttled creation is retried on a later reconcile without counting an attempt",
+			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, m *mock_roleassignments.MockclientMockRecorder) {
+				m.Create(gomockinternal.AContext(), "/subscriptions/12345/", "test-role-assignment", gomock.AssignableToTypeOf(authorization.RoleAssignmentCreateParameters{})).Return(authorization.RoleAssignment{}, serviceErr(429, "TooManyRequests"))
+				s.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, gomock.Not(gomock.Nil()))
+			},
+			expectTransient: true,
+		},
+		{
+			name: "principal not found is retried on a later reconcile without counting an attempt",
+			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, m *mock_roleassignments.MockclientMockRecorder) {
+				m.Create(gomockinternal.AContext(), "/subscriptions/12345/", "test-role-assignment", gomock.AssignableToTypeOf(authorization.RoleAssignmentCreateParameters{})).Return(authorization.RoleAssignment{}, serviceErr(400, "PrincipalNotFound"))
+				s.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, gomock.Not(gomock.Nil()))
+			},
+			expectTransient: true,
+		},
+		{
+			name:     "non-retriable error is counted and retried on a later reconcile",
+			attempts: 1,
+			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, m *mock_roleassignments.MockclientMockRecorder) {
+				m.Create(gomockinternal.AContext(), "/subscriptions/12345/", "test-role-assignment", gomock.AssignableToTypeOf(authorization.RoleAssignmentCreateParameters{})).Return(authorization.RoleAssignment{}, serviceErr(403, "AuthorizationFailed"))
+				s.SetRoleAssignmentCreateAttempts(int32(2))
+				s.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, gomock.Not(gomock.Nil()))
+			},
+			expectTransient: true,
+		},
+		{
+			name:     "condition is marked as failed once the attempts are exhausted",
+			attempts: 2,
+			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, m *mock_roleassignments.MockclientMockRecorder) {
+				m.Create(gomockinternal.AContext(), "/subscriptions/12345/", "test-role-assignment", gomock.AssignableToTypeOf(authorization.RoleAssignmentCreateParameters{})).Return(authorization.RoleAssignment{}, serviceErr(403, "AuthorizationFailed"))
+				s.SetRoleAssignmentCreateAttempts(int32(3))
+				s.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, gomock.Not(gomock.Nil())).Do(
+					func(_ clusterv1.ConditionType, _ string, err error) {
+						var reconcileError azure.ReconcileError
+						if !errors.As(err, &reconcileError) || !reconcileError.IsTerminal() {
+							t.Errorf("expected the condition to be updated with a terminal error, got %v", err)
+						}
+					},
+				)
+			},
+			expectTerminal: true,
+		},
+		{
+			name:     "attempts are reset once the role assignment is created",
+			attempts: 2,
+			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, m *mock_roleassignments.MockclientMockRecorder) {
+				m.Create(gomockinternal.AContext(), "/subscriptions/12345/", "test-role-assignment", gomock.AssignableToTypeOf(authorization.RoleAssignmentCreateParameters{}))
+				s.SetRoleAssignmentCreateAttempts(int32(0))
+				m.ListForScope(gomockinternal.AContext(), "/subscriptions/12345/", "atScope()").Return([]authorization.RoleAssignment{
+					{Name: to.StringPtr("test-role-assignment")},
+				}, nil)
+				s.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, nil)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_roleassignments.NewMockRoleAssignmentScope(mockCtrl)
+			scopeMock.EXPECT().IsRoleAssignmentReady().AnyTimes().Return(false)
+			clientMock := mock_roleassignments.NewMockclient(mockCtrl)
+			vmMock := mock_virtualmachines.NewMockClient(mockCtrl)
+
+			s := scopeMock.EXPECT()
+			s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+			s.SubscriptionID().AnyTimes().Return("12345")
+			s.ResourceGroup().Return("my-rg")
+			s.RoleAssignmentCreateAttempts().AnyTimes().Return(tc.attempts)
+			s.RoleAssignmentSpecs().Return([]azure.RoleAssignmentSpec{
+				{
+					MachineName:  "test-vm",
+					Name:         "test-role-assignment",
+					ResourceType: azure.VirtualMachine,
+				},
+			})
+			vmMock.EXPECT().Get(gomockinternal.AContext(), "my-rg", "test-vm").Return(compute.VirtualMachine{
+				Identity: &compute.VirtualMachineIdentity{
+					PrincipalID: to.StringPtr("000"),
+				},
+			}, nil)
+			clientMock.EXPECT().ListForScope(gomockinternal.AContext(), "/subscriptions/12345/", "principalId eq '000'").Return(nil, nil)
+			tc.expect(s, clientMock.EXPECT())
+
+			service := &Service{
+				Scope:                 scopeMock,
+				client:                clientMock,
+				virtualMachinesClient: vmMock,
+				maxCreateAttempts:     3,
+			}
+
+			err := service.Reconcile(context.TODO())
+			if !tc.expectTransient && !tc.expectTerminal {
+				g.Expect(err).NotTo(HaveOccurred())
+				return
+			}
+			var reconcileError azure.ReconcileError
+			g.Expect(errors.As(err, &reconcileError)).To(BeTrue())
+			g.Expect(reconcileError.IsTransient()).To(Equal(tc.expectTransient))
+			g.Expect(reconcileError.IsTerminal()).To(Equal(tc.expectTerminal))
+		})
+	}
+}
+
+func TestReconcileRoleAssignmentsNameConflict(t *testing.T) {
+	contributorRoleDefinitionID := "/subscriptions/12345/providers/Microsoft.Authorization/roleDefinitions/" + azureBuiltInContributorID
+	readerRoleDefinitionID := "/subscriptions/12345/providers/Microsoft.Authorization/roleDefinitions/acdd72a7-3385-48ef-bd42-f606fba81ae7"
+	conflictErr := autorest.DetailedError{
+		StatusCode: 409,
+		Original:   &azureautorest.ServiceError{Code: "RoleAssignmentUpdateNotPermitted"},
+	}
+
+	testcases := []struct {
+		name   string
+		expect func(t *testing.T, m *mock_roleassignments.MockclientMockRecorder)
+	}{
+		{
+			name: "existing role assignment matching the desired one is kept",
+			expect: func(t *testing.T, m *mock_roleassignments.MockclientMockRecorder) {
+				existing := []authorization.RoleAssignment{
+					{
+						Name: to.StringPtr("test-role-assignment"),
+						Properties: &authorization.RoleAssignmentPropertiesWithScope{
+							Scope:            to.StringPtr("/subscriptions/12345/"),
+							RoleDefinitionID: to.StringPtr(contributorRoleDefinitionID),
+							PrincipalID:      to.StringPtr("000"),
+						},
+					},
+				}
+				gomock.InOrder(
+					m.ListForScope(gomockinternal.AContext(), "/subscriptions/12345/", "principalId eq '000'").Return(nil, nil),
+					m.Create(gomockinternal.AContext(), "/subscriptions/12345/", "test-role-assignment", gomock.AssignableToTypeOf(authorization.RoleAssignmentCreateParameters{})).Return(authorization.RoleAssignment{}, conflictErr),
+					m.ListForScope(gomockinternal.AContext(), "/subscriptions/12345/", "principalId eq '000'").Return(existing, nil),
+				)
+				m.ListForScope(gomockinternal.AContext(), "/subscriptions/12345/", "atScope()").Return(existing, nil)
+			},
+		},
+		{
+			name: "role assignment is created under a new name when the name is taken by an unrelated role assignment",
+			expect: func(t *testing.T, m *mock_roleassignments.MockclientMockRecorder) {
+				var newName string
+				m.ListForScope(gomockinternal.AContext(), "/subscriptions/12345/", "principalId eq '000'").Times(2).Return([]authorization.RoleAssignment{
+					{
+						Name: to.StringPtr("other-role-assignment"),
+						Properties: &authorization.RoleAssignmentPropertiesWithScope{
+							Scope:            to.StringPtr("/subscriptions/12345/"),
+							RoleDefinitionID: to.StringPtr(readerRoleDefinitionID),
+							PrincipalID:      to.StringPtr("000"),
+						},
+					},
+				}, nil)
+				gomock.InOrder(
+					m.Create(gomockinternal.AContext(), "/subscriptions/12345/", "test-role-assignment", gomock.AssignableToTypeOf(authorization.RoleAssignmentCreateParameters{})).Return(authorization.RoleAssignment{}, conflictErr),
+					m.Create(gomockinternal.AContext(), "/subscriptions/12345/", gomock.Not("test-role-assignment"), gomock.AssignableToTypeOf(authorization.RoleAssignmentCreateParameters{})).DoAndReturn(
+						func(_ context.Context, _ string, name string, _ authorization.RoleAssignmentCreateParameters) (authorization.RoleAssignment, error) {
+							if _, err := uuid.Parse(name); err != nil {
+								t.Errorf("expected the new role assignment name to be a GUID, got %s", name)
+							}
+							newName = name
+							return authorization.RoleAssignment{}, nil
+						},
+					),
+				)
+				m.ListForScope(gomockinternal.AContext(), "/subscriptions/12345/", "atScope()").DoAndReturn(
+					func(_ context.Context, _ string, _ string) ([]authorization.RoleAssignment, error) {
+						return []authorization.RoleAssignment{{Name: to.StringPtr(newName)}}, nil
+					},
+				)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_roleassignments.NewMockRoleAssignmentScope(mockCtrl)
+			scopeMock.EXPECT().IsRoleAssignmentReady().AnyTimes().Return(false)
+			scopeMock.EXPECT().RoleAssignmentCreateAttempts().AnyTimes().Return(int32(0))
+			clientMock := mock_roleassignments.NewMockclient(mockCtrl)
+			vmMock := mock_virtualmachines.NewMockClient(mockCtrl)
+
+			s := scopeMock.EXPECT()
+			s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+			s.SubscriptionID().AnyTimes().Return("12345")
+			s.ResourceGroup().Return("my-rg")
+			s.RoleAssignmentSpecs().Return([]azure.RoleAssignmentSpec{
+				{
+					MachineName:  "test-vm",
+					Name:         "test-role-assignment",
+					ResourceType: azure.VirtualMachine,
+				},
+			})
+			s.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, nil)
+			vmMock.EXPECT().Get(gomockinternal.AContext(), "my-rg", "test-vm").Return(compute.VirtualMachine{
+				Identity: &compute.VirtualMachineIdentity{
+					PrincipalID: to.StringPtr("000"),
+				},
+			}, nil)
+			tc.expect(t, clientMock.EXPECT())
+
+			service := &Service{
+				Scope:                 scopeMock,
+				client:                clientMock,
+				virtualMachinesClient: vmMock,
+			}
+
+			g.Expect(service.Reconcile(context.TODO())).To(Succeed())
+		})
+	}
+}
+
+func TestWaitForPropagation(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expect        func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, m *mock_roleassignments.MockclientMockRecorder)
+		expectedError string
+	}{
+		{
+			name:          "role assignment is found after a couple of polls",
+			expectedError: "",
+			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, m *mock_roleassignments.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				gomock.InOrder(
+					m.ListForScope(gomockinternal.AContext(), "/subscriptions/12345/", "atScope()").Return(nil, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found")),
+					m.ListForScope(gomockinternal.AContext(), "/subscriptions/12345/", "atScope()").Return([]authorization.RoleAssignment{
+						{Name: to.StringPtr("other-role-assignment")},
+					}, nil),
+					m.ListForScope(gomockinternal.AContext(), "/subscriptions/12345/", "atScope()").Return([]authorization.RoleAssignment{
+						{Name: to.StringPtr("other-role-assignment")},
+						{Name: to.StringPtr("my-role-assignment")},
+					}, nil),
+				)
+			},
+		},
+		{
+			name:          "role assignment is never found",
+			expectedError: "timed out waiting for role assignment my-role-assignment to propagate to scope /subscriptions/12345/",
+			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, m *mock_roleassignments.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				m.ListForScope(gomockinternal.AContext(), "/subscriptions/12345/", "atScope()").MinTimes(1).Return(nil, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
+		{
+			name:          "error listing role assignments",
+			expectedError: "failed to wait for role assignment my-role-assignment to propagate: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, m *mock_roleassignments.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				m.ListForScope(gomockinternal.AContext(), "/subscriptions/12345/", "atScope()").Return(nil, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_roleassignments.NewMockRoleAssignmentScope(mockCtrl)
+			clientMock := mock_roleassignments.NewMockclient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:                   scopeMock,
+				client:                  clientMock,
+				propagationPollInterval: 10 * time.Millisecond,
+			}
+
+			err := s.WaitForPropagation(context.TODO(), "/subscriptions/12345/", "my-role-assignment", 100*time.Millisecond)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -26,24 +26,24 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
-// client wraps go-sdk.
-type client interface {
+// Client wraps go-sdk.
+type Client interface {
 	Get(context.Context, string, string) (network.RouteTable, error)
 	CreateOrUpdate(context.Context, string, string, network.RouteTable) error
 	Delete(context.Context, string, string) error
 }
 
-// azureClient contains the Azure go-sdk Client.
-type azureClient struct {
+// AzureClient contains the Azure go-sdk Client.
+type AzureClient struct {
 	routetables network.RouteTablesClient
 }
 
-var _ client = (*azureClient)(nil)
+var _ Client = (*AzureClient)(nil)
 
-// newClient creates a new VM client from subscription ID.
-func newClient(auth azure.Authorizer) *azureClient {
+// NewClient creates a new route tables client from subscription ID.
+func NewClient(auth azure.Authorizer) *AzureClient {
 	c := newRouteTablesClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
-	return &azureClient{c}
+	return &AzureClient{c}
 }
 
 // newRouteTablesClient creates a new route tables client from subscription ID.
@@ -54,7 +54,7 @@ func newRouteTablesClient(subscriptionID string, baseURI string, authorizer auto
 }
 
 // Get gets the specified route table.
-func (ac *azureClient) Get(ctx context.Context, resourceGroupName, rtName string) (network.RouteTable, error) {
+func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, rtName string) (network.RouteTable, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "routetables.AzureClient.Get")
 	defer done()
 
@@ -62,7 +62,7 @@ func (ac *azureClient) Get(ctx context.Context, resourceGroupName, rtName string
 }
 
 // CreateOrUpdate create or updates a route table in a specified resource group.
-func (ac *azureClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, rtName string, rt network.RouteTable) error {
+func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, rtName string, rt network.RouteTable) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "routetables.AzureClient.CreateOrUpdate")
 	defer done()
 
@@ -79,7 +79,7 @@ func (ac *azureClient) CreateOrUpdate(ctx context.Context, resourceGroupName str
 }
 
 // Delete deletes the specified route table.
-func (ac *azureClient) Delete(ctx context.Context, resourceGroupName, rtName string) error {
+func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, rtName string) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "routetables.AzureClient.Delete")
 	defer done()
 
@@ -40,14 +40,14 @@ type RouteTableScope interface {
 // Service provides operations on azure resources.
 type Service struct {
 	Scope RouteTableScope
-	client
+	Client
 }
 
 // New creates a new service.
 func New(scope *scope.ClusterScope) *Service {
 	return &Service{
 		Scope:  scope,
-		client: newClient(scope),
+		Client: NewClient(scope),
 	}
 }
 
@@ -78,7 +78,7 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		}
 
 		s.Scope.V(2).Info("creating Route Table", "route table", routeTableSpec.Name)
-		err = s.client.CreateOrUpdate(
+		err = s.Client.CreateOrUpdate(
 			ctx,
 			s.Scope.ResourceGroup(),
 			routeTableSpec.Name,
@@ -106,7 +106,7 @@ func (s *Service) Delete(ctx context.Context) error {
 	}
 	for _, routeTableSpec := range s.Scope.RouteTableSpecs() {
 		s.Scope.V(2).Info("deleting route table", "route table", routeTableSpec.Name)
-		err := s.client.Delete(ctx, s.Scope.ResourceGroup(), routeTableSpec.Name)
+		err := s.Client.Delete(ctx, s.Scope.ResourceGroup(), routeTableSpec.Name)
 		if err != nil && azure.ResourceNotFound(err) {
 			// already deleted
 			continue
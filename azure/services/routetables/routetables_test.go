@@ -45,7 +45,7 @@ func TestReconcileRouteTables(t *testing.T) {
 		name          string
 		tags          infrav1.Tags
 		expectedError string
-		expect        func(s *mock_routetables.MockRouteTableScopeMockRecorder, m *mock_routetables.MockclientMockRecorder)
+		expect        func(s *mock_routetables.MockRouteTableScopeMockRecorder, m *mock_routetables.MockClientMockRecorder)
 	}{
 		{
 			name: "route tables in custom vnet mode",
@@ -55,7 +55,7 @@ func TestReconcileRouteTables(t *testing.T) {
 				"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
 			},
 			expectedError: "",
-			expect: func(s *mock_routetables.MockRouteTableScopeMockRecorder, m *mock_routetables.MockclientMockRecorder) {
+			expect: func(s *mock_routetables.MockRouteTableScopeMockRecorder, m *mock_routetables.MockClientMockRecorder) {
 				s.Vnet().Return(&infrav1.VnetSpec{
 					ID:   "1234",
 					Name: "my-vnet",
@@ -72,7 +72,7 @@ func TestReconcileRouteTables(t *testing.T) {
 				"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
 			},
 			expectedError: "",
-			expect: func(s *mock_routetables.MockRouteTableScopeMockRecorder, m *mock_routetables.MockclientMockRecorder) {
+			expect: func(s *mock_routetables.MockRouteTableScopeMockRecorder, m *mock_routetables.MockClientMockRecorder) {
 				s.Vnet().Return(&infrav1.VnetSpec{
 					Name: "my-vnet",
 				})
@@ -112,7 +112,7 @@ func TestReconcileRouteTables(t *testing.T) {
 				"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
 			},
 			expectedError: "",
-			expect: func(s *mock_routetables.MockRouteTableScopeMockRecorder, m *mock_routetables.MockclientMockRecorder) {
+			expect: func(s *mock_routetables.MockRouteTableScopeMockRecorder, m *mock_routetables.MockClientMockRecorder) {
 				s.Vnet().Return(&infrav1.VnetSpec{
 					Name: "my-vnet",
 				})
@@ -172,7 +172,7 @@ func TestReconcileRouteTables(t *testing.T) {
 				"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
 			},
 			expectedError: "failed to get route table my-cp-routetable in my-rg: #: Internal Server Error: StatusCode=500",
-			expect: func(s *mock_routetables.MockRouteTableScopeMockRecorder, m *mock_routetables.MockclientMockRecorder) {
+			expect: func(s *mock_routetables.MockRouteTableScopeMockRecorder, m *mock_routetables.MockClientMockRecorder) {
 				s.Vnet().Return(&infrav1.VnetSpec{
 					Name: "my-vnet",
 				})
@@ -200,7 +200,7 @@ func TestReconcileRouteTables(t *testing.T) {
 				"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
 			},
 			expectedError: "failed to create route table my-cp-routetable in resource group my-rg: #: Internal Server Error: StatusCode=500",
-			expect: func(s *mock_routetables.MockRouteTableScopeMockRecorder, m *mock_routetables.MockclientMockRecorder) {
+			expect: func(s *mock_routetables.MockRouteTableScopeMockRecorder, m *mock_routetables.MockClientMockRecorder) {
 				s.Vnet().Return(&infrav1.VnetSpec{
 					Name: "my-vnet",
 				})
@@ -231,13 +231,13 @@ func TestReconcileRouteTables(t *testing.T) {
 			mockCtrl := gomock.NewController(t)
 			defer mockCtrl.Finish()
 			scopeMock := mock_routetables.NewMockRouteTableScope(mockCtrl)
-			clientMock := mock_routetables.NewMockclient(mockCtrl)
+			clientMock := mock_routetables.NewMockClient(mockCtrl)
 
 			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
 
 			s := &Service{
 				Scope:  scopeMock,
-				client: clientMock,
+				Client: clientMock,
 			}
 
 			err := s.Reconcile(context.TODO())
@@ -256,7 +256,7 @@ func TestDeleteRouteTable(t *testing.T) {
 		name          string
 		tags          infrav1.Tags
 		expectedError string
-		expect        func(s *mock_routetables.MockRouteTableScopeMockRecorder, m *mock_routetables.MockclientMockRecorder)
+		expect        func(s *mock_routetables.MockRouteTableScopeMockRecorder, m *mock_routetables.MockClientMockRecorder)
 	}{
 		{
 			name: "route tables in custom vnet mode",
@@ -266,7 +266,7 @@ func TestDeleteRouteTable(t *testing.T) {
 				"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
 			},
 			expectedError: "",
-			expect: func(s *mock_routetables.MockRouteTableScopeMockRecorder, m *mock_routetables.MockclientMockRecorder) {
+			expect: func(s *mock_routetables.MockRouteTableScopeMockRecorder, m *mock_routetables.MockClientMockRecorder) {
 				s.Vnet().Return(&infrav1.VnetSpec{
 					ID:   "1234",
 					Name: "my-vnet",
@@ -283,7 +283,7 @@ func TestDeleteRouteTable(t *testing.T) {
 				"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
 			},
 			expectedError: "",
-			expect: func(s *mock_routetables.MockRouteTableScopeMockRecorder, m *mock_routetables.MockclientMockRecorder) {
+			expect: func(s *mock_routetables.MockRouteTableScopeMockRecorder, m *mock_routetables.MockClientMockRecorder) {
 				s.Vnet().Return(&infrav1.VnetSpec{
 					Name: "my-vnet",
 				})
@@ -320,7 +320,7 @@ func TestDeleteRouteTable(t *testing.T) {
 				"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
 			},
 			expectedError: "",
-			expect: func(s *mock_routetables.MockRouteTableScopeMockRecorder, m *mock_routetables.MockclientMockRecorder) {
+			expect: func(s *mock_routetables.MockRouteTableScopeMockRecorder, m *mock_routetables.MockClientMockRecorder) {
 				s.Vnet().Return(&infrav1.VnetSpec{
 					Name: "my-vnet",
 				})
@@ -357,7 +357,7 @@ func TestDeleteRouteTable(t *testing.T) {
 				"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
 			},
 			expectedError: "failed to delete route table my-cp-routetable in resource group my-rg: #: Internal Server Error: StatusCode=500",
-			expect: func(s *mock_routetables.MockRouteTableScopeMockRecorder, m *mock_routetables.MockclientMockRecorder) {
+			expect: func(s *mock_routetables.MockRouteTableScopeMockRecorder, m *mock_routetables.MockClientMockRecorder) {
 				s.Vnet().Return(&infrav1.VnetSpec{
 					Name: "my-vnet",
 				})
@@ -385,13 +385,13 @@ func TestDeleteRouteTable(t *testing.T) {
 			mockCtrl := gomock.NewController(t)
 			defer mockCtrl.Finish()
 			scopeMock := mock_routetables.NewMockRouteTableScope(mockCtrl)
-			clientMock := mock_routetables.NewMockclient(mockCtrl)
+			clientMock := mock_routetables.NewMockClient(mockCtrl)
 
 			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
 
 			s := &Service{
 				Scope:  scopeMock,
-				client: clientMock,
+				Client: clientMock,
 			}
 
 			err := s.Delete(context.TODO())
@@ -368,7 +368,7 @@ func (s *Service) buildVMSSFromSpec(ctx context.Context, vmssSpec azure.ScaleSet
 		vmssSpec.AcceleratedNetworking = &accelNet
 	}
 
-	extensions := s.generateExtensions()
+	extensions := s.generateExtensions(vmssSpec.OSDisk.OSType)
 
 	storageProfile, err := s.generateStorageProfile(vmssSpec, sku)
 	if err != nil {
@@ -541,19 +541,32 @@ func (s *Service) getVirtualMachineScaleSetIfDone(ctx context.Context, future *i
 	return converters.SDKToVMSS(vmss, vmssInstances), nil
 }
 
-func (s *Service) generateExtensions() []compute.VirtualMachineScaleSetExtension {
-	extensions := make([]compute.VirtualMachineScaleSetExtension, len(s.Scope.VMSSExtensionSpecs()))
-	for i, extensionSpec := range s.Scope.VMSSExtensionSpecs() {
-		extensions[i] = compute.VirtualMachineScaleSetExtension{
-			Name: &extensionSpec.Name,
+// generateExtensions generates the scale set extensions for the extension specs that support the OS type of the scale set.
+func (s *Service) generateExtensions(osType string) []compute.VirtualMachineScaleSetExtension {
+	extensions := make([]compute.VirtualMachineScaleSetExtension, 0, len(s.Scope.VMSSExtensionSpecs()))
+	for _, extensionSpec := range s.Scope.VMSSExtensionSpecs() {
+		if !extensionSpec.SupportsOSType(osType) {
+			s.Scope.V(2).Info("skipping VMSS extension that does not support the OS type of the scale set", "extension", extensionSpec.Name, "extension OS type", extensionSpec.OSType, "scale set", extensionSpec.VMName, "scale set OS type", osType)
+			continue
+		}
+		extension := compute.VirtualMachineScaleSetExtension{
+			Name: to.StringPtr(extensionSpec.Name),
 			VirtualMachineScaleSetExtensionProperties: &compute.VirtualMachineScaleSetExtensionProperties{
-				Publisher:          to.StringPtr(extensionSpec.Publisher),
-				Type:               to.StringPtr(extensionSpec.Name),
-				TypeHandlerVersion: to.StringPtr(extensionSpec.Version),
-				Settings:           nil,
-				ProtectedSettings:  extensionSpec.ProtectedSettings,
+				Publisher:               to.StringPtr(extensionSpec.Publisher),
+				Type:                    to.StringPtr(extensionSpec.Name),
+				TypeHandlerVersion:      extensionSpec.TypeHandlerVersion(),
+				AutoUpgradeMinorVersion: to.BoolPtr(extensionSpec.AutoUpgradeMinorVersion()),
+				Settings:                nil,
+				ProtectedSettings:       extensionSpec.ProtectedSettings,
 			},
 		}
+		if len(extensionSpec.Settings) > 0 {
+			extension.Settings = extensionSpec.Settings
+		}
+		if extensionSpec.ForceUpdateTag != "" {
+			extension.ForceUpdateTag = to.StringPtr(extensionSpec.ForceUpdateTag)
+		}
+		extensions = append(extensions, extension)
 	}
 	return extensions
 }
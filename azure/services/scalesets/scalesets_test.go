@@ -39,6 +39,7 @@ import (
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesets/mock_scalesets"
@@ -601,6 +602,106 @@ func TestReconcileVMSS(t *testing.T) {
 	}
 }
 
+func TestHasModelModifyingDifferencesForceUpdateTag(t *testing.T) {
+	testcases := []struct {
+		name                   string
+		existingForceUpdateTag string
+		desiredForceUpdateTag  string
+		expectModelChanges     bool
+	}{
+		{
+			name:               "no force update tag",
+			expectModelChanges: false,
+		},
+		{
+			name:                   "same force update tag",
+			existingForceUpdateTag: "1",
+			desiredForceUpdateTag:  "1",
+			expectModelChanges:     false,
+		},
+		{
+			name:                   "force update tag bumped",
+			existingForceUpdateTag: "1",
+			desiredForceUpdateTag:  "2",
+			expectModelChanges:     true,
+		},
+		{
+			name:                  "force update tag set for the first time",
+			desiredForceUpdateTag: "1",
+			expectModelChanges:    true,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			extensionSpec := func(forceUpdateTag string) azure.ExtensionSpec {
+				return azure.ExtensionSpec{
+					Name:           "someExtension",
+					VMName:         "my-vmss",
+					Publisher:      "somePublisher",
+					Version:        "someVersion",
+					ForceUpdateTag: forceUpdateTag,
+				}
+			}
+			vmssWithExtensions := func(forceUpdateTag string) compute.VirtualMachineScaleSet {
+				scopeMock := mock_scalesets.NewMockScaleSetScope(mockCtrl)
+				scopeMock.EXPECT().VMSSExtensionSpecs().Return([]azure.ExtensionSpec{extensionSpec(forceUpdateTag)}).AnyTimes()
+				s := &Service{Scope: scopeMock}
+				extensions := s.generateExtensions(azure.LinuxOS)
+				return compute.VirtualMachineScaleSet{
+					VirtualMachineScaleSetProperties: &compute.VirtualMachineScaleSetProperties{
+						VirtualMachineProfile: &compute.VirtualMachineScaleSetVMProfile{
+							ExtensionProfile: &compute.VirtualMachineScaleSetExtensionProfile{
+								Extensions: &extensions,
+							},
+						},
+					},
+				}
+			}
+
+			existing := converters.SDKToVMSS(vmssWithExtensions(tc.existingForceUpdateTag), nil)
+			g.Expect(hasModelModifyingDifferences(existing, vmssWithExtensions(tc.desiredForceUpdateTag))).To(Equal(tc.expectModelChanges))
+		})
+	}
+}
+
+func TestGenerateExtensionsOSType(t *testing.T) {
+	g := NewWithT(t)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	scopeMock := mock_scalesets.NewMockScaleSetScope(mockCtrl)
+	scopeMock.EXPECT().V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+	scopeMock.EXPECT().VMSSExtensionSpecs().Return([]azure.ExtensionSpec{
+		{
+			Name:      "linuxExtension",
+			VMName:    "my-vmss",
+			Publisher: "somePublisher",
+			OSType:    azure.LinuxOS,
+		},
+		{
+			Name:      "anyOSExtension",
+			VMName:    "my-vmss",
+			Publisher: "somePublisher",
+		},
+	}).AnyTimes()
+	s := &Service{Scope: scopeMock}
+
+	extensions := s.generateExtensions(azure.WindowsOS)
+	g.Expect(extensions).To(HaveLen(1))
+	g.Expect(to.String(extensions[0].Name)).To(Equal("anyOSExtension"))
+
+	extensions = s.generateExtensions(azure.LinuxOS)
+	g.Expect(extensions).To(HaveLen(2))
+}
+
 func TestDeleteVMSS(t *testing.T) {
 	const (
 		resourceGroup = "my-rg"
@@ -1071,9 +1172,10 @@ func newDefaultVMSS(vmSize string) compute.VirtualMachineScaleSet {
 						{
 							Name: to.StringPtr("someExtension"),
 							VirtualMachineScaleSetExtensionProperties: &compute.VirtualMachineScaleSetExtensionProperties{
-								Publisher:          to.StringPtr("somePublisher"),
-								Type:               to.StringPtr("someExtension"),
-								TypeHandlerVersion: to.StringPtr("someVersion"),
+								Publisher:               to.StringPtr("somePublisher"),
+								Type:                    to.StringPtr("someExtension"),
+								TypeHandlerVersion:      to.StringPtr("someVersion"),
+								AutoUpgradeMinorVersion: to.BoolPtr(false),
 								ProtectedSettings: map[string]string{
 									"commandToExecute": "echo hello",
 								},
@@ -228,6 +228,62 @@ func TestReconcileTags(t *testing.T) {
 				s.AnnotationJSON("my-annotation").Return(map[string]interface{}{"key": "value"}, nil)
 			},
 		},
+		{
+			name:          "add, update and remove managed cluster tags, keeping tags that are not managed",
+			expectedError: "",
+			expect: func(s *mock_tags.MockTagScopeMockRecorder, m *mock_tags.MockclientMockRecorder) {
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				gomock.InOrder(
+					s.TagsSpecs().Return([]azure.TagsSpec{
+						{
+							Scope: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ContainerService/managedClusters/my-cluster",
+							Tags: map[string]string{
+								"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": "owned",
+								"environment": "prod",
+								"team":        "platform",
+							},
+							Annotation: "my-annotation",
+						},
+					}),
+					m.GetAtScope(gomockinternal.AContext(), "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ContainerService/managedClusters/my-cluster").Return(resources.TagsResource{Properties: &resources.Tags{
+						Tags: map[string]*string{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+							"environment":       to.StringPtr("dev"),
+							"costCenter":        to.StringPtr("1234"),
+							"externalSystemTag": to.StringPtr("randomValue"),
+						},
+					}}, nil),
+					s.AnnotationJSON("my-annotation").Return(map[string]interface{}{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": "owned",
+						"environment": "dev",
+						"costCenter":  "1234",
+					}, nil),
+					m.UpdateAtScope(gomockinternal.AContext(), "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ContainerService/managedClusters/my-cluster", resources.TagsPatchResource{
+						Operation: "Merge",
+						Properties: &resources.Tags{
+							Tags: map[string]*string{
+								"environment": to.StringPtr("prod"),
+								"team":        to.StringPtr("platform"),
+							},
+						},
+					}),
+					m.UpdateAtScope(gomockinternal.AContext(), "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ContainerService/managedClusters/my-cluster", resources.TagsPatchResource{
+						Operation: "Delete",
+						Properties: &resources.Tags{
+							Tags: map[string]*string{
+								"costCenter": to.StringPtr("1234"),
+							},
+						},
+					}),
+					s.UpdateAnnotationJSON("my-annotation", map[string]interface{}{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": "owned",
+						"environment": "prod",
+						"team":        "platform",
+					}),
+				)
+			},
+		},
 	}
 
 	for _, tc := range testcases {
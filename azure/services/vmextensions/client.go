@@ -28,6 +28,7 @@ import (
 // client wraps go-sdk.
 type client interface {
 	Get(ctx context.Context, resourceGroupName, vmName, name string) (compute.VirtualMachineExtension, error)
+	GetInstanceView(ctx context.Context, resourceGroupName, vmName, name string) (compute.VirtualMachineExtensionInstanceView, error)
 	CreateOrUpdateAsync(context.Context, string, string, string, compute.VirtualMachineExtension) error
 	Delete(context.Context, string, string, string) error
 }
@@ -60,6 +61,21 @@ func (ac *azureClient) Get(ctx context.Context, resourceGroupName, vmName, name
 	return ac.vmextensions.Get(ctx, resourceGroupName, vmName, name, "")
 }
 
+// GetInstanceView gets the instance view of the virtual machine extension.
+func (ac *azureClient) GetInstanceView(ctx context.Context, resourceGroupName, vmName, name string) (compute.VirtualMachineExtensionInstanceView, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "vmextensions.AzureClient.GetInstanceView")
+	defer done()
+
+	extension, err := ac.vmextensions.Get(ctx, resourceGroupName, vmName, name, "instanceView")
+	if err != nil {
+		return compute.VirtualMachineExtensionInstanceView{}, err
+	}
+	if extension.VirtualMachineExtensionProperties == nil || extension.InstanceView == nil {
+		return compute.VirtualMachineExtensionInstanceView{}, nil
+	}
+	return *extension.InstanceView, nil
+}
+
 // CreateOrUpdateAsync creates or updates the virtual machine extension.
 func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, resourceGroupName, vmName, name string, parameters compute.VirtualMachineExtension) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "vmextensions.AzureClient.CreateOrUpdate")
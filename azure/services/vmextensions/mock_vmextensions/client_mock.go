@@ -93,3 +93,18 @@ func (mr *MockclientMockRecorder) Get(ctx, resourceGroupName, vmName, name inter
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*Mockclient)(nil).Get), ctx, resourceGroupName, vmName, name)
 }
+
+// GetInstanceView mocks base method.
+func (m *Mockclient) GetInstanceView(ctx context.Context, resourceGroupName, vmName, name string) (compute.VirtualMachineExtensionInstanceView, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInstanceView", ctx, resourceGroupName, vmName, name)
+	ret0, _ := ret[0].(compute.VirtualMachineExtensionInstanceView)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInstanceView indicates an expected call of GetInstanceView.
+func (mr *MockclientMockRecorder) GetInstanceView(ctx, resourceGroupName, vmName, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInstanceView", reflect.TypeOf((*Mockclient)(nil).GetInstanceView), ctx, resourceGroupName, vmName, name)
+}
@@ -284,17 +284,17 @@ func (mr *MockVMExtensionScopeMockRecorder) ResourceGroup() *gomock.Call {
 }
 
 // SetBootstrapConditions mocks base method.
-func (m *MockVMExtensionScope) SetBootstrapConditions(arg0, arg1 string) error {
+func (m *MockVMExtensionScope) SetBootstrapConditions(arg0, arg1, arg2 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetBootstrapConditions", arg0, arg1)
+	ret := m.ctrl.Call(m, "SetBootstrapConditions", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SetBootstrapConditions indicates an expected call of SetBootstrapConditions.
-func (mr *MockVMExtensionScopeMockRecorder) SetBootstrapConditions(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockVMExtensionScopeMockRecorder) SetBootstrapConditions(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBootstrapConditions", reflect.TypeOf((*MockVMExtensionScope)(nil).SetBootstrapConditions), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBootstrapConditions", reflect.TypeOf((*MockVMExtensionScope)(nil).SetBootstrapConditions), arg0, arg1, arg2)
 }
 
 // SubscriptionID mocks base method.
@@ -18,11 +18,13 @@ package vmextensions
 
 import (
 	"context"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
@@ -32,7 +34,7 @@ type VMExtensionScope interface {
 	logr.Logger
 	azure.ClusterDescriber
 	VMExtensionSpecs() []azure.ExtensionSpec
-	SetBootstrapConditions(string, string) error
+	SetBootstrapConditions(string, string, string) error
 }
 
 // Service provides operations on Azure resources.
@@ -56,8 +58,16 @@ func (s *Service) Reconcile(ctx context.Context) error {
 
 	for _, extensionSpec := range s.Scope.VMExtensionSpecs() {
 		if existing, err := s.client.Get(ctx, s.Scope.ResourceGroup(), extensionSpec.VMName, extensionSpec.Name); err == nil {
+			provisioningState := to.String(existing.ProvisioningState)
+			var statusMessage string
+			if infrav1.ProvisioningState(provisioningState) == infrav1.Failed {
+				statusMessage, err = s.GetExtensionStatus(ctx, extensionSpec.VMName, extensionSpec.Name)
+				if err != nil {
+					s.Scope.Error(err, "failed to get the status of the failed VM extension", "vm extension", extensionSpec.Name, "vm", extensionSpec.VMName)
+				}
+			}
 			// check the extension status and set the associated conditions.
-			if retErr := s.Scope.SetBootstrapConditions(to.String(existing.ProvisioningState), extensionSpec.Name); retErr != nil {
+			if retErr := s.Scope.SetBootstrapConditions(provisioningState, extensionSpec.Name, statusMessage); retErr != nil {
 				return retErr
 			}
 			// if the extension already exists, do not update it.
@@ -67,21 +77,29 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		}
 
 		s.Scope.V(2).Info("creating VM extension", "vm extension", extensionSpec.Name)
+		extension := compute.VirtualMachineExtension{
+			VirtualMachineExtensionProperties: &compute.VirtualMachineExtensionProperties{
+				Publisher:               to.StringPtr(extensionSpec.Publisher),
+				Type:                    to.StringPtr(extensionSpec.Name),
+				TypeHandlerVersion:      extensionSpec.TypeHandlerVersion(),
+				AutoUpgradeMinorVersion: to.BoolPtr(extensionSpec.AutoUpgradeMinorVersion()),
+				Settings:                nil,
+				ProtectedSettings:       extensionSpec.ProtectedSettings,
+			},
+			Location: to.StringPtr(s.Scope.Location()),
+		}
+		if len(extensionSpec.Settings) > 0 {
+			extension.Settings = extensionSpec.Settings
+		}
+		if extensionSpec.ForceUpdateTag != "" {
+			extension.ForceUpdateTag = to.StringPtr(extensionSpec.ForceUpdateTag)
+		}
 		err := s.client.CreateOrUpdateAsync(
 			ctx,
 			s.Scope.ResourceGroup(),
 			extensionSpec.VMName,
 			extensionSpec.Name,
-			compute.VirtualMachineExtension{
-				VirtualMachineExtensionProperties: &compute.VirtualMachineExtensionProperties{
-					Publisher:          to.StringPtr(extensionSpec.Publisher),
-					Type:               to.StringPtr(extensionSpec.Name),
-					TypeHandlerVersion: to.StringPtr(extensionSpec.Version),
-					Settings:           nil,
-					ProtectedSettings:  extensionSpec.ProtectedSettings,
-				},
-				Location: to.StringPtr(s.Scope.Location()),
-			},
+			extension,
 		)
 		if err != nil {
 			return errors.Wrapf(err, "failed to create VM extension %s on VM %s in resource group %s", extensionSpec.Name, extensionSpec.VMName, s.Scope.ResourceGroup())
@@ -91,6 +109,29 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	return nil
 }
 
+// GetExtensionStatus returns the substatus messages from the instance view of the VM extension. The substatuses hold
+// the output of the extension, such as the standard error of a failed custom script.
+func (s *Service) GetExtensionStatus(ctx context.Context, vmName, name string) (string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "vmextensions.Service.GetExtensionStatus")
+	defer done()
+
+	instanceView, err := s.client.GetInstanceView(ctx, s.Scope.ResourceGroup(), vmName, name)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get instance view of vm extension %s on vm %s", name, vmName)
+	}
+	if instanceView.Substatuses == nil {
+		return "", nil
+	}
+
+	var messages []string
+	for _, substatus := range *instanceView.Substatuses {
+		if message := strings.TrimSpace(to.String(substatus.Message)); message != "" {
+			messages = append(messages, message)
+		}
+	}
+	return strings.Join(messages, "; "), nil
+}
+
 // Delete is a no-op. Extensions will be deleted as part of VM deletion.
 func (s *Service) Delete(_ context.Context) error {
 	return nil
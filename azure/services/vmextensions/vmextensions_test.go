@@ -64,7 +64,7 @@ func TestReconcileVMExtension(t *testing.T) {
 					ID:   to.StringPtr("fake/id"),
 					Name: to.StringPtr("my-extension-1"),
 				}, nil)
-				s.SetBootstrapConditions(string(compute.ProvisioningStateSucceeded), "my-extension-1")
+				s.SetBootstrapConditions(string(compute.ProvisioningStateSucceeded), "my-extension-1", "")
 			},
 		},
 		{
@@ -91,7 +91,19 @@ func TestReconcileVMExtension(t *testing.T) {
 					ID:   to.StringPtr("fake/id"),
 					Name: to.StringPtr("my-extension-1"),
 				}, nil)
-				s.SetBootstrapConditions(string(compute.ProvisioningStateFailed), "my-extension-1")
+				m.GetInstanceView(gomockinternal.AContext(), "my-rg", "my-vm", "my-extension-1").Return(compute.VirtualMachineExtensionInstanceView{
+					Substatuses: &[]compute.InstanceViewStatus{
+						{
+							Code:    to.StringPtr("ComponentStatus/StdOut/succeeded"),
+							Message: to.StringPtr(""),
+						},
+						{
+							Code:    to.StringPtr("ComponentStatus/StdErr/succeeded"),
+							Message: to.StringPtr("kubeadm join failed: connection refused\n"),
+						},
+					},
+				}, nil)
+				s.SetBootstrapConditions(string(compute.ProvisioningStateFailed), "my-extension-1", "kubeadm join failed: connection refused")
 			},
 		},
 		{
@@ -118,7 +130,7 @@ func TestReconcileVMExtension(t *testing.T) {
 					ID:   to.StringPtr("fake/id"),
 					Name: to.StringPtr("my-extension-1"),
 				}, nil)
-				s.SetBootstrapConditions(string(compute.ProvisioningStateCreating), "my-extension-1")
+				s.SetBootstrapConditions(string(compute.ProvisioningStateCreating), "my-extension-1", "")
 			},
 		},
 		{
@@ -150,6 +162,78 @@ func TestReconcileVMExtension(t *testing.T) {
 				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-vm", "other-extension", gomock.AssignableToTypeOf(compute.VirtualMachineExtension{}))
 			},
 		},
+		{
+			name:          "pinned extension version disables minor version upgrades and settings are passed",
+			expectedError: "",
+			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, m *mock_vmextensions.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.VMExtensionSpecs().Return([]azure.ExtensionSpec{
+					{
+						Name:      "my-extension-1",
+						VMName:    "my-vm",
+						Publisher: "some-publisher",
+						Version:   "1.2",
+						Settings: map[string]interface{}{
+							"port": 10250,
+						},
+						ProtectedSettings: map[string]string{
+							"commandToExecute": "echo hello",
+						},
+					},
+				})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("test-location")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vm", "my-extension-1").
+					Return(compute.VirtualMachineExtension{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-vm", "my-extension-1", compute.VirtualMachineExtension{
+					VirtualMachineExtensionProperties: &compute.VirtualMachineExtensionProperties{
+						Publisher:               to.StringPtr("some-publisher"),
+						Type:                    to.StringPtr("my-extension-1"),
+						TypeHandlerVersion:      to.StringPtr("1.2"),
+						AutoUpgradeMinorVersion: to.BoolPtr(false),
+						Settings: map[string]interface{}{
+							"port": 10250,
+						},
+						ProtectedSettings: map[string]string{
+							"commandToExecute": "echo hello",
+						},
+					},
+					Location: to.StringPtr("test-location"),
+				})
+			},
+		},
+		{
+			name:          "unpinned extension version requests the latest version",
+			expectedError: "",
+			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, m *mock_vmextensions.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.VMExtensionSpecs().Return([]azure.ExtensionSpec{
+					{
+						Name:      "my-extension-1",
+						VMName:    "my-vm",
+						Publisher: "some-publisher",
+						ProtectedSettings: map[string]string{
+							"commandToExecute": "echo hello",
+						},
+					},
+				})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("test-location")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vm", "my-extension-1").
+					Return(compute.VirtualMachineExtension{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-vm", "my-extension-1", compute.VirtualMachineExtension{
+					VirtualMachineExtensionProperties: &compute.VirtualMachineExtensionProperties{
+						Publisher:               to.StringPtr("some-publisher"),
+						Type:                    to.StringPtr("my-extension-1"),
+						AutoUpgradeMinorVersion: to.BoolPtr(true),
+						ProtectedSettings: map[string]string{
+							"commandToExecute": "echo hello",
+						},
+					},
+					Location: to.StringPtr("test-location"),
+				})
+			},
+		},
 		{
 			name:          "error getting the extension",
 			expectedError: "failed to get vm extension my-extension-1 on vm my-vm: #: Internal Server Error: StatusCode=500",
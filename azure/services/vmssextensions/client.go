@@ -18,6 +18,7 @@ package vmssextensions
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
 	"github.com/Azure/go-autorest/autorest"
@@ -28,11 +29,14 @@ import (
 // Client wraps go-sdk.
 type client interface {
 	Get(context.Context, string, string, string) (compute.VirtualMachineScaleSetExtension, error)
+	List(context.Context, string, string) ([]compute.VirtualMachineScaleSetExtension, error)
+	ListInstanceViews(context.Context, string, string) ([]compute.VirtualMachineScaleSetVM, error)
 }
 
 // AzureClient contains the Azure go-sdk Client.
 type azureClient struct {
 	vmssextensions compute.VirtualMachineScaleSetExtensionsClient
+	vmssvms        compute.VirtualMachineScaleSetVMsClient
 }
 
 var _ client = (*azureClient)(nil)
@@ -40,7 +44,8 @@ var _ client = (*azureClient)(nil)
 // newClient creates a new VMSS client from subscription ID.
 func newClient(auth azure.Authorizer) *azureClient {
 	c := newVirtualMachineScaleSetExtensionsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
-	return &azureClient{c}
+	vmsClient := newVirtualMachineScaleSetVMsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
+	return &azureClient{c, vmsClient}
 }
 
 // newVirtualMachineScaleSetExtensionsClient creates a new vmss extension client from subscription ID.
@@ -50,10 +55,59 @@ func newVirtualMachineScaleSetExtensionsClient(subscriptionID string, baseURI st
 	return vmssextensionsClient
 }
 
+// newVirtualMachineScaleSetVMsClient creates a new vmss vm client from subscription ID.
+func newVirtualMachineScaleSetVMsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.VirtualMachineScaleSetVMsClient {
+	vmssvmsClient := compute.NewVirtualMachineScaleSetVMsClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&vmssvmsClient.Client, authorizer)
+	return vmssvmsClient
+}
+
 // Get creates or updates the virtual machine scale set extension.
 func (ac *azureClient) Get(ctx context.Context, resourceGroupName, vmssName, name string) (compute.VirtualMachineScaleSetExtension, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "vmssextensions.AzureClient.Get")
 	defer done()
 
-	return ac.vmssextensions.Get(ctx, resourceGroupName, vmssName, name, "")
+	extension, err := ac.vmssextensions.Get(ctx, resourceGroupName, vmssName, name, "")
+	return extension, azure.WithRequestIDs(err)
+}
+
+// List returns all extensions of the virtual machine scale set, following the result pages.
+func (ac *azureClient) List(ctx context.Context, resourceGroupName, vmssName string) ([]compute.VirtualMachineScaleSetExtension, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "vmssextensions.AzureClient.List")
+	defer done()
+
+	itr, err := ac.vmssextensions.ListComplete(ctx, resourceGroupName, vmssName)
+	if err != nil {
+		return nil, azure.WithRequestIDs(err)
+	}
+
+	var extensions []compute.VirtualMachineScaleSetExtension
+	for ; itr.NotDone(); err = itr.NextWithContext(ctx) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate vmss extensions [%w]", azure.WithRequestIDs(err))
+		}
+		extensions = append(extensions, itr.Value())
+	}
+	return extensions, nil
+}
+
+// ListInstanceViews returns all instances of the virtual machine scale set with their instance views, which hold the
+// statuses of the extensions on each instance, following the result pages.
+func (ac *azureClient) ListInstanceViews(ctx context.Context, resourceGroupName, vmssName string) ([]compute.VirtualMachineScaleSetVM, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "vmssextensions.AzureClient.ListInstanceViews")
+	defer done()
+
+	itr, err := ac.vmssvms.ListComplete(ctx, resourceGroupName, vmssName, "", "", string(compute.InstanceViewTypesInstanceView))
+	if err != nil {
+		return nil, azure.WithRequestIDs(err)
+	}
+
+	var instances []compute.VirtualMachineScaleSetVM
+	for ; itr.NotDone(); err = itr.NextWithContext(ctx) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate vmss instances [%w]", azure.WithRequestIDs(err))
+		}
+		instances = append(instances, itr.Value())
+	}
+	return instances, nil
 }
@@ -0,0 +1,78 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vmssextensions
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/gomega"
+)
+
+func TestAzureClientListPages(t *testing.T) {
+	g := NewWithT(t)
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/page2") {
+			fmt.Fprint(w, `{"value": [{"name": "my-extension-2"}, {"name": "my-extension-3"}]}`)
+			return
+		}
+		fmt.Fprintf(w, `{"value": [{"name": "my-extension-1"}], "nextLink": "%s/page2"}`, server.URL)
+	}))
+	defer server.Close()
+
+	ac := &azureClient{
+		vmssextensions: compute.NewVirtualMachineScaleSetExtensionsClientWithBaseURI(server.URL, "123"),
+	}
+
+	extensions, err := ac.List(context.TODO(), "my-rg", "my-vmss")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(extensions).To(Equal([]compute.VirtualMachineScaleSetExtension{
+		{Name: to.StringPtr("my-extension-1")},
+		{Name: to.StringPtr("my-extension-2")},
+		{Name: to.StringPtr("my-extension-3")},
+	}))
+}
+
+func TestAzureClientGetErrorRequestIDs(t *testing.T) {
+	g := NewWithT(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("x-ms-request-id", "my-request-id")
+		w.Header().Set("x-ms-correlation-request-id", "my-correlation-id")
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"error": {"code": "AuthorizationFailed", "message": "not allowed"}}`)
+	}))
+	defer server.Close()
+
+	ac := &azureClient{
+		vmssextensions: compute.NewVirtualMachineScaleSetExtensionsClientWithBaseURI(server.URL, "123"),
+	}
+
+	_, err := ac.Get(context.TODO(), "my-rg", "my-vmss", "my-extension")
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(HaveSuffix("(request ID: my-request-id, correlation ID: my-correlation-id)"))
+}
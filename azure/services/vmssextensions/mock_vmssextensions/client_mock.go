@@ -65,3 +65,33 @@ func (mr *MockclientMockRecorder) Get(arg0, arg1, arg2, arg3 interface{}) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*Mockclient)(nil).Get), arg0, arg1, arg2, arg3)
 }
+
+// List mocks base method.
+func (m *Mockclient) List(arg0 context.Context, arg1, arg2 string) ([]compute.VirtualMachineScaleSetExtension, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]compute.VirtualMachineScaleSetExtension)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockclientMockRecorder) List(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*Mockclient)(nil).List), arg0, arg1, arg2)
+}
+
+// ListInstanceViews mocks base method.
+func (m *Mockclient) ListInstanceViews(arg0 context.Context, arg1, arg2 string) ([]compute.VirtualMachineScaleSetVM, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInstanceViews", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]compute.VirtualMachineScaleSetVM)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInstanceViews indicates an expected call of ListInstanceViews.
+func (mr *MockclientMockRecorder) ListInstanceViews(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInstanceViews", reflect.TypeOf((*Mockclient)(nil).ListInstanceViews), arg0, arg1, arg2)
+}
@@ -26,8 +26,10 @@ import (
 	autorest "github.com/Azure/go-autorest/autorest"
 	logr "github.com/go-logr/logr"
 	gomock "github.com/golang/mock/gomock"
+	runtime "k8s.io/apimachinery/pkg/runtime"
 	v1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
+	v1beta10 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
 // MockVMSSExtensionScope is a mock of VMSSExtensionScope interface.
@@ -95,6 +97,34 @@ func (mr *MockVMSSExtensionScopeMockRecorder) AvailabilitySetEnabled() *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AvailabilitySetEnabled", reflect.TypeOf((*MockVMSSExtensionScope)(nil).AvailabilitySetEnabled))
 }
 
+// AzureMachinePoolObject mocks base method.
+func (m *MockVMSSExtensionScope) AzureMachinePoolObject() runtime.Object {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AzureMachinePoolObject")
+	ret0, _ := ret[0].(runtime.Object)
+	return ret0
+}
+
+// AzureMachinePoolObject indicates an expected call of AzureMachinePoolObject.
+func (mr *MockVMSSExtensionScopeMockRecorder) AzureMachinePoolObject() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AzureMachinePoolObject", reflect.TypeOf((*MockVMSSExtensionScope)(nil).AzureMachinePoolObject))
+}
+
+// BootstrapSucceededCondition mocks base method.
+func (m *MockVMSSExtensionScope) BootstrapSucceededCondition() *v1beta10.Condition {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapSucceededCondition")
+	ret0, _ := ret[0].(*v1beta10.Condition)
+	return ret0
+}
+
+// BootstrapSucceededCondition indicates an expected call of BootstrapSucceededCondition.
+func (mr *MockVMSSExtensionScopeMockRecorder) BootstrapSucceededCondition() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapSucceededCondition", reflect.TypeOf((*MockVMSSExtensionScope)(nil).BootstrapSucceededCondition))
+}
+
 // BaseURI mocks base method.
 func (m *MockVMSSExtensionScope) BaseURI() string {
 	m.ctrl.T.Helper()
@@ -284,17 +314,17 @@ func (mr *MockVMSSExtensionScopeMockRecorder) ResourceGroup() *gomock.Call {
 }
 
 // SetBootstrapConditions mocks base method.
-func (m *MockVMSSExtensionScope) SetBootstrapConditions(arg0, arg1 string) error {
+func (m *MockVMSSExtensionScope) SetBootstrapConditions(arg0, arg1, arg2 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetBootstrapConditions", arg0, arg1)
+	ret := m.ctrl.Call(m, "SetBootstrapConditions", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SetBootstrapConditions indicates an expected call of SetBootstrapConditions.
-func (mr *MockVMSSExtensionScopeMockRecorder) SetBootstrapConditions(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockVMSSExtensionScopeMockRecorder) SetBootstrapConditions(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBootstrapConditions", reflect.TypeOf((*MockVMSSExtensionScope)(nil).SetBootstrapConditions), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBootstrapConditions", reflect.TypeOf((*MockVMSSExtensionScope)(nil).SetBootstrapConditions), arg0, arg1, arg2)
 }
 
 // SubscriptionID mocks base method.
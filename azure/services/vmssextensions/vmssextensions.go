@@ -18,10 +18,18 @@ package vmssextensions
 
 import (
 	"context"
+	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
@@ -31,20 +39,24 @@ type VMSSExtensionScope interface {
 	logr.Logger
 	azure.ClusterDescriber
 	VMSSExtensionSpecs() []azure.ExtensionSpec
-	SetBootstrapConditions(string, string) error
+	SetBootstrapConditions(string, string, string) error
+	AzureMachinePoolObject() runtime.Object
+	BootstrapSucceededCondition() *clusterv1.Condition
 }
 
 // Service provides operations on Azure resources.
 type Service struct {
 	Scope VMSSExtensionScope
 	client
+	recorder record.EventRecorder
 }
 
 // New creates a new vm extension service.
-func New(scope VMSSExtensionScope) *Service {
+func New(scope VMSSExtensionScope, recorder record.EventRecorder) *Service {
 	return &Service{
-		Scope:  scope,
-		client: newClient(scope),
+		Scope:    scope,
+		client:   newClient(scope),
+		recorder: recorder,
 	}
 }
 
@@ -53,21 +65,101 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	_, _, done := tele.StartSpanWithLogger(ctx, "vmssextensions.Service.Reconcile")
 	defer done()
 
+	// The BootstrapSucceeded condition is read before any extension updates it, so that an event is recorded for each
+	// extension whose provisioning state changes the condition.
+	previous := s.Scope.BootstrapSucceededCondition()
+
+	// existing holds all the extensions of each scale set, so that every page of a scale set's extensions is
+	// considered when comparing them with the desired extensions.
+	existing := make(map[string]map[string]compute.VirtualMachineScaleSetExtension)
 	for _, extensionSpec := range s.Scope.VMSSExtensionSpecs() {
-		if existing, err := s.client.Get(ctx, s.Scope.ResourceGroup(), extensionSpec.VMName, extensionSpec.Name); err == nil {
+		if _, ok := existing[extensionSpec.VMName]; !ok {
+			extensions, err := s.client.List(ctx, s.Scope.ResourceGroup(), extensionSpec.VMName)
+			if err != nil && !azure.ResourceNotFound(err) {
+				return errors.Wrapf(err, "failed to list vm extensions on scale set %s", extensionSpec.VMName)
+			}
+			existing[extensionSpec.VMName] = make(map[string]compute.VirtualMachineScaleSetExtension, len(extensions))
+			for _, extension := range extensions {
+				existing[extensionSpec.VMName][to.String(extension.Name)] = extension
+			}
+		}
+
+		if extension, ok := existing[extensionSpec.VMName][extensionSpec.Name]; ok {
+			var provisioningState string
+			if extension.VirtualMachineScaleSetExtensionProperties != nil {
+				provisioningState = to.String(extension.ProvisioningState)
+			}
+			s.recordProvisioningEvent(extensionSpec, provisioningState, previous)
+			var statusMessage string
+			if infrav1.ProvisioningState(provisioningState) == infrav1.Failed {
+				var err error
+				statusMessage, err = s.GetExtensionStatus(ctx, extensionSpec.VMName, extensionSpec.Name)
+				if err != nil {
+					s.Scope.Error(err, "failed to get the status of the failed VMSS extension", "vmss extension", extensionSpec.Name, "scale set", extensionSpec.VMName)
+				}
+			}
 			// check the extension status and set the associated conditions.
-			if retErr := s.Scope.SetBootstrapConditions(to.String(existing.ProvisioningState), extensionSpec.Name); retErr != nil {
+			if retErr := s.Scope.SetBootstrapConditions(provisioningState, extensionSpec.Name, statusMessage); retErr != nil {
 				return retErr
 			}
-		} else if !azure.ResourceNotFound(err) {
-			return errors.Wrapf(err, "failed to get vm extension %s on scale set %s", extensionSpec.Name, extensionSpec.VMName)
 		}
 		//  Nothing else to do here, the extensions are applied to the model as part of the scale set Reconcile.
-		continue
 	}
 	return nil
 }
 
+// recordProvisioningEvent records an event on the owning machine pool when the extension has finished provisioning,
+// using the extension name as the event reason. No event is recorded if the previous BootstrapSucceeded condition
+// already reflects the provisioning state, so that the event is not recorded again on every reconcile.
+func (s *Service) recordProvisioningEvent(extensionSpec azure.ExtensionSpec, provisioningState string, previous *clusterv1.Condition) {
+	switch infrav1.ProvisioningState(provisioningState) {
+	case infrav1.Succeeded:
+		if previous != nil && previous.Status == corev1.ConditionTrue {
+			return
+		}
+		s.recorder.Eventf(s.Scope.AzureMachinePoolObject(), corev1.EventTypeNormal, extensionSpec.Name, "VMSS extension %s on scale set %s was provisioned successfully", extensionSpec.Name, extensionSpec.VMName)
+	case infrav1.Failed:
+		if previous != nil && previous.Status == corev1.ConditionFalse && previous.Reason == infrav1.BootstrapFailedReason {
+			return
+		}
+		s.recorder.Eventf(s.Scope.AzureMachinePoolObject(), corev1.EventTypeWarning, extensionSpec.Name, "VMSS extension %s on scale set %s failed to provision", extensionSpec.Name, extensionSpec.VMName)
+	}
+}
+
+// GetExtensionStatus returns the distinct substatus messages of the VMSS extension across the instances of the scale
+// set. The substatuses hold the output of the extension, such as the standard error of a failed custom script.
+func (s *Service) GetExtensionStatus(ctx context.Context, vmssName, name string) (string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "vmssextensions.Service.GetExtensionStatus")
+	defer done()
+
+	instances, err := s.client.ListInstanceViews(ctx, s.Scope.ResourceGroup(), vmssName)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to list instance views of scale set %s", vmssName)
+	}
+
+	var messages []string
+	seen := make(map[string]bool)
+	for _, instance := range instances {
+		if instance.VirtualMachineScaleSetVMProperties == nil || instance.InstanceView == nil || instance.InstanceView.Extensions == nil {
+			continue
+		}
+		for _, extension := range *instance.InstanceView.Extensions {
+			if to.String(extension.Name) != name || extension.Substatuses == nil {
+				continue
+			}
+			for _, substatus := range *extension.Substatuses {
+				message := strings.TrimSpace(to.String(substatus.Message))
+				if message == "" || seen[message] {
+					continue
+				}
+				seen[message] = true
+				messages = append(messages, message)
+			}
+		}
+	}
+	return strings.Join(messages, "; "), nil
+}
+
 // Delete is a no-op. Extensions will be deleted as part of VMSS deletion.
 func (s *Service) Delete(_ context.Context) error {
 	return nil
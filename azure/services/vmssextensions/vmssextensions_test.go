@@ -29,20 +29,29 @@ import (
 
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2/klogr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
 )
 
 func TestReconcileVMSSExtension(t *testing.T) {
 	testcases := []struct {
-		name          string
-		expectedError string
-		expect        func(s *mock_vmssextensions.MockVMSSExtensionScopeMockRecorder, m *mock_vmssextensions.MockclientMockRecorder)
+		name              string
+		previousCondition *clusterv1.Condition
+		expectedError     string
+		expectedEvents    []string
+		expect            func(s *mock_vmssextensions.MockVMSSExtensionScopeMockRecorder, m *mock_vmssextensions.MockclientMockRecorder)
 	}{
 		{
-			name:          "extension already exists",
-			expectedError: "",
+			name:           "extension already exists",
+			expectedError:  "",
+			expectedEvents: []string{"Normal my-extension-1 VMSS extension my-extension-1 on scale set my-vmss was provisioned successfully"},
 			expect: func(s *mock_vmssextensions.MockVMSSExtensionScopeMockRecorder, m *mock_vmssextensions.MockclientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.VMSSExtensionSpecs().Return([]azure.ExtensionSpec{
@@ -55,7 +64,7 @@ func TestReconcileVMSSExtension(t *testing.T) {
 				})
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("test-location")
-				m.Get(gomockinternal.AContext(), "my-rg", "my-vmss", "my-extension-1").Return(compute.VirtualMachineScaleSetExtension{
+				m.List(gomockinternal.AContext(), "my-rg", "my-vmss").Return([]compute.VirtualMachineScaleSetExtension{{
 					Name: to.StringPtr("my-extension-1"),
 					VirtualMachineScaleSetExtensionProperties: &compute.VirtualMachineScaleSetExtensionProperties{
 						Publisher:         to.StringPtr("some-publisher"),
@@ -63,8 +72,185 @@ func TestReconcileVMSSExtension(t *testing.T) {
 						ProvisioningState: to.StringPtr(string(compute.ProvisioningStateSucceeded)),
 					},
 					ID: to.StringPtr("some/fake/id"),
+				}}, nil)
+				s.SetBootstrapConditions(string(compute.ProvisioningStateSucceeded), "my-extension-1", "")
+			},
+		},
+		{
+			name:          "all listed extensions are considered",
+			expectedError: "",
+			expectedEvents: []string{
+				"Normal my-extension-1 VMSS extension my-extension-1 on scale set my-vmss was provisioned successfully",
+				"Normal other-extension VMSS extension other-extension on scale set my-vmss was provisioned successfully",
+			},
+			expect: func(s *mock_vmssextensions.MockVMSSExtensionScopeMockRecorder, m *mock_vmssextensions.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.VMSSExtensionSpecs().Return([]azure.ExtensionSpec{
+					{
+						Name:      "my-extension-1",
+						VMName:    "my-vmss",
+						Publisher: "some-publisher",
+						Version:   "1.0",
+					},
+					{
+						Name:      "other-extension",
+						VMName:    "my-vmss",
+						Publisher: "other-publisher",
+						Version:   "2.0",
+					},
+				})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				m.List(gomockinternal.AContext(), "my-rg", "my-vmss").Return([]compute.VirtualMachineScaleSetExtension{
+					{
+						Name: to.StringPtr("my-extension-1"),
+						VirtualMachineScaleSetExtensionProperties: &compute.VirtualMachineScaleSetExtensionProperties{
+							ProvisioningState: to.StringPtr(string(compute.ProvisioningStateSucceeded)),
+						},
+					},
+					{
+						Name: to.StringPtr("unmanaged-extension"),
+						VirtualMachineScaleSetExtensionProperties: &compute.VirtualMachineScaleSetExtensionProperties{
+							ProvisioningState: to.StringPtr(string(compute.ProvisioningStateSucceeded)),
+						},
+					},
+					{
+						Name: to.StringPtr("other-extension"),
+						VirtualMachineScaleSetExtensionProperties: &compute.VirtualMachineScaleSetExtensionProperties{
+							ProvisioningState: to.StringPtr(string(compute.ProvisioningStateSucceeded)),
+						},
+					},
+				}, nil)
+				s.SetBootstrapConditions(string(compute.ProvisioningStateSucceeded), "my-extension-1", "")
+				s.SetBootstrapConditions(string(compute.ProvisioningStateSucceeded), "other-extension", "")
+			},
+		},
+		{
+			name:           "extension failed to provision",
+			expectedError:  "",
+			expectedEvents: []string{"Warning my-extension-1 VMSS extension my-extension-1 on scale set my-vmss failed to provision"},
+			expect: func(s *mock_vmssextensions.MockVMSSExtensionScopeMockRecorder, m *mock_vmssextensions.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.VMSSExtensionSpecs().Return([]azure.ExtensionSpec{
+					{
+						Name:      "my-extension-1",
+						VMName:    "my-vmss",
+						Publisher: "some-publisher",
+						Version:   "1.0",
+					},
+				})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("test-location")
+				m.List(gomockinternal.AContext(), "my-rg", "my-vmss").Return([]compute.VirtualMachineScaleSetExtension{{
+					Name: to.StringPtr("my-extension-1"),
+					VirtualMachineScaleSetExtensionProperties: &compute.VirtualMachineScaleSetExtensionProperties{
+						Publisher:         to.StringPtr("some-publisher"),
+						Type:              to.StringPtr("my-extension-1"),
+						ProvisioningState: to.StringPtr(string(compute.ProvisioningStateFailed)),
+					},
+					ID: to.StringPtr("some/fake/id"),
+				}}, nil)
+				failedInstanceView := func(message string) compute.VirtualMachineScaleSetVM {
+					return compute.VirtualMachineScaleSetVM{
+						VirtualMachineScaleSetVMProperties: &compute.VirtualMachineScaleSetVMProperties{
+							InstanceView: &compute.VirtualMachineScaleSetVMInstanceView{
+								Extensions: &[]compute.VirtualMachineExtensionInstanceView{
+									{
+										Name:        to.StringPtr("other-extension"),
+										Substatuses: &[]compute.InstanceViewStatus{{Message: to.StringPtr("unrelated output")}},
+									},
+									{
+										Name:        to.StringPtr("my-extension-1"),
+										Substatuses: &[]compute.InstanceViewStatus{{Message: to.StringPtr(message)}},
+									},
+								},
+							},
+						},
+					}
+				}
+				m.ListInstanceViews(gomockinternal.AContext(), "my-rg", "my-vmss").Return([]compute.VirtualMachineScaleSetVM{
+					failedInstanceView("kubeadm join failed"),
+					failedInstanceView("kubeadm join failed"),
+					failedInstanceView("  timed out waiting for the kubelet\n"),
+					{},
 				}, nil)
-				s.SetBootstrapConditions(string(compute.ProvisioningStateSucceeded), "my-extension-1")
+				s.SetBootstrapConditions(string(compute.ProvisioningStateFailed), "my-extension-1", "kubeadm join failed; timed out waiting for the kubelet")
+			},
+		},
+		{
+			name: "no event is recorded again for an extension that was already provisioned",
+			previousCondition: &clusterv1.Condition{
+				Type:   infrav1.BootstrapSucceededCondition,
+				Status: corev1.ConditionTrue,
+			},
+			expect: func(s *mock_vmssextensions.MockVMSSExtensionScopeMockRecorder, m *mock_vmssextensions.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.VMSSExtensionSpecs().Return([]azure.ExtensionSpec{
+					{
+						Name:   "my-extension-1",
+						VMName: "my-vmss",
+					},
+				})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				m.List(gomockinternal.AContext(), "my-rg", "my-vmss").Return([]compute.VirtualMachineScaleSetExtension{{
+					Name: to.StringPtr("my-extension-1"),
+					VirtualMachineScaleSetExtensionProperties: &compute.VirtualMachineScaleSetExtensionProperties{
+						ProvisioningState: to.StringPtr(string(compute.ProvisioningStateSucceeded)),
+					},
+				}}, nil)
+				s.SetBootstrapConditions(string(compute.ProvisioningStateSucceeded), "my-extension-1", "")
+			},
+		},
+		{
+			name: "no event is recorded again for an extension that already failed to provision",
+			previousCondition: &clusterv1.Condition{
+				Type:   infrav1.BootstrapSucceededCondition,
+				Status: corev1.ConditionFalse,
+				Reason: infrav1.BootstrapFailedReason,
+			},
+			expect: func(s *mock_vmssextensions.MockVMSSExtensionScopeMockRecorder, m *mock_vmssextensions.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.VMSSExtensionSpecs().Return([]azure.ExtensionSpec{
+					{
+						Name:   "my-extension-1",
+						VMName: "my-vmss",
+					},
+				})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				m.List(gomockinternal.AContext(), "my-rg", "my-vmss").Return([]compute.VirtualMachineScaleSetExtension{{
+					Name: to.StringPtr("my-extension-1"),
+					VirtualMachineScaleSetExtensionProperties: &compute.VirtualMachineScaleSetExtensionProperties{
+						ProvisioningState: to.StringPtr(string(compute.ProvisioningStateFailed)),
+					},
+				}}, nil)
+				m.ListInstanceViews(gomockinternal.AContext(), "my-rg", "my-vmss").Return(nil, autorest.NewError("", "", "internal error"))
+				s.Error(gomock.Any(), "failed to get the status of the failed VMSS extension", "vmss extension", "my-extension-1", "scale set", "my-vmss")
+				s.SetBootstrapConditions(string(compute.ProvisioningStateFailed), "my-extension-1", "")
+			},
+		},
+		{
+			name: "event is recorded when an extension that was provisioning succeeds",
+			previousCondition: &clusterv1.Condition{
+				Type:   infrav1.BootstrapSucceededCondition,
+				Status: corev1.ConditionFalse,
+				Reason: infrav1.BootstrapInProgressReason,
+			},
+			expectedEvents: []string{"Normal my-extension-1 VMSS extension my-extension-1 on scale set my-vmss was provisioned successfully"},
+			expect: func(s *mock_vmssextensions.MockVMSSExtensionScopeMockRecorder, m *mock_vmssextensions.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.VMSSExtensionSpecs().Return([]azure.ExtensionSpec{
+					{
+						Name:   "my-extension-1",
+						VMName: "my-vmss",
+					},
+				})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				m.List(gomockinternal.AContext(), "my-rg", "my-vmss").Return([]compute.VirtualMachineScaleSetExtension{{
+					Name: to.StringPtr("my-extension-1"),
+					VirtualMachineScaleSetExtensionProperties: &compute.VirtualMachineScaleSetExtensionProperties{
+						ProvisioningState: to.StringPtr(string(compute.ProvisioningStateSucceeded)),
+					},
+				}}, nil)
+				s.SetBootstrapConditions(string(compute.ProvisioningStateSucceeded), "my-extension-1", "")
 			},
 		},
 		{
@@ -88,15 +274,12 @@ func TestReconcileVMSSExtension(t *testing.T) {
 				})
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("test-location")
-				m.Get(gomockinternal.AContext(), "my-rg", "my-vmss", "my-extension-1").
-					Return(compute.VirtualMachineScaleSetExtension{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
-				m.Get(gomockinternal.AContext(), "my-rg", "my-vmss", "other-extension").
-					Return(compute.VirtualMachineScaleSetExtension{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.List(gomockinternal.AContext(), "my-rg", "my-vmss").Return([]compute.VirtualMachineScaleSetExtension{}, nil)
 			},
 		},
 		{
-			name:          "error getting the extension",
-			expectedError: "failed to get vm extension my-extension-1 on scale set my-vmss: #: Internal Server Error: StatusCode=500",
+			name:          "error listing the extensions",
+			expectedError: "failed to list vm extensions on scale set my-vmss: #: Internal Server Error: StatusCode=500",
 			expect: func(s *mock_vmssextensions.MockVMSSExtensionScopeMockRecorder, m *mock_vmssextensions.MockclientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.VMSSExtensionSpecs().Return([]azure.ExtensionSpec{
@@ -115,8 +298,8 @@ func TestReconcileVMSSExtension(t *testing.T) {
 				})
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("test-location")
-				m.Get(gomockinternal.AContext(), "my-rg", "my-vmss", "my-extension-1").
-					Return(compute.VirtualMachineScaleSetExtension{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+				m.List(gomockinternal.AContext(), "my-rg", "my-vmss").
+					Return(nil, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
 			},
 		},
 	}
@@ -132,11 +315,16 @@ func TestReconcileVMSSExtension(t *testing.T) {
 			scopeMock := mock_vmssextensions.NewMockVMSSExtensionScope(mockCtrl)
 			clientMock := mock_vmssextensions.NewMockclient(mockCtrl)
 
+			recorder := record.NewFakeRecorder(10)
+
+			scopeMock.EXPECT().AzureMachinePoolObject().AnyTimes().Return(&infrav1exp.AzureMachinePool{})
+			scopeMock.EXPECT().BootstrapSucceededCondition().Return(tc.previousCondition)
 			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
 
 			s := &Service{
-				Scope:  scopeMock,
-				client: clientMock,
+				Scope:    scopeMock,
+				client:   clientMock,
+				recorder: recorder,
 			}
 
 			err := s.Reconcile(context.TODO())
@@ -146,6 +334,12 @@ func TestReconcileVMSSExtension(t *testing.T) {
 			} else {
 				g.Expect(err).NotTo(HaveOccurred())
 			}
+			close(recorder.Events)
+			var events []string
+			for event := range recorder.Events {
+				events = append(events, event)
+			}
+			g.Expect(events).To(Equal(tc.expectedEvents))
 		})
 	}
 }
@@ -18,6 +18,8 @@ package azure
 
 import (
 	"reflect"
+	"strings"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 
@@ -115,6 +117,17 @@ type RoleAssignmentSpec struct {
 	MachineName  string
 	Name         string
 	ResourceType string
+	// PrincipalIDs are the IDs of the principals the role is assigned to, each in a role assignment of its own named
+	// after the principal in the namespace of Name, which must then be a GUID. When they are set, MachineName and
+	// ResourceType are ignored.
+	PrincipalIDs []string
+	// Scope is the ID of the resource the role is assigned at, e.g. a subnet. Defaults to the subscription. When it is
+	// set, the role assignment is not created until the resource exists, so that the scope can be a resource created
+	// by CAPZ in the same reconcile loop.
+	Scope string
+	// AtNodeResourceGroup assigns the role at the node resource group of a managed cluster, whose ID is resolved when
+	// the role is assigned. When it is set, Scope is ignored.
+	AtNodeResourceGroup bool
 }
 
 // ResourceType defines the type azure resource being reconciled.
@@ -202,11 +215,37 @@ type AvailabilitySetSpec struct {
 
 // ExtensionSpec defines the specification for a VM or VMScaleSet extension.
 type ExtensionSpec struct {
-	Name              string
-	VMName            string
-	Publisher         string
+	Name      string
+	VMName    string
+	Publisher string
+	// Version pins the extension handler version. An empty Version requests the latest handler version.
 	Version           string
+	Settings          map[string]interface{}
 	ProtectedSettings map[string]string
+	ForceUpdateTag    string
+	// OSType restricts the extension to VMs running the operating system, either 'Linux' or 'Windows'.
+	// An empty OSType applies the extension to VMs of any operating system.
+	OSType string
+}
+
+// SupportsOSType reports whether the extension can be applied to a VM running the operating system.
+func (e ExtensionSpec) SupportsOSType(osType string) bool {
+	return e.OSType == "" || strings.EqualFold(e.OSType, osType)
+}
+
+// TypeHandlerVersion returns the extension handler version to request, or nil to request the latest version.
+func (e ExtensionSpec) TypeHandlerVersion() *string {
+	if e.Version == "" {
+		return nil
+	}
+	version := e.Version
+	return &version
+}
+
+// AutoUpgradeMinorVersion reports whether the extension handler may be upgraded to newer minor versions.
+// Minor version upgrades are disabled when the handler version is pinned.
+func (e ExtensionSpec) AutoUpgradeMinorVersion() bool {
+	return e.Version == ""
 }
 
 type (
@@ -232,6 +271,8 @@ type (
 		Identity  infrav1.VMIdentity        `json:"identity,omitempty"`
 		Tags      infrav1.Tags              `json:"tags,omitempty"`
 		Instances []VMSSVM                  `json:"instances,omitempty"`
+		// ExtensionForceUpdateTags maps the names of the extensions of the scale set model to their force update tag.
+		ExtensionForceUpdateTags map[string]string `json:"extensionForceUpdateTags,omitempty"`
 	}
 )
 
@@ -241,7 +282,8 @@ func (vmss VMSS) HasModelChanges(other VMSS) bool {
 		cmp.Equal(vmss.Identity, other.Identity) &&
 		cmp.Equal(vmss.Zones, other.Zones) &&
 		cmp.Equal(vmss.Tags, other.Tags) &&
-		cmp.Equal(vmss.Sku, other.Sku)
+		cmp.Equal(vmss.Sku, other.Sku) &&
+		cmp.Equal(vmss.ExtensionForceUpdateTags, other.ExtensionForceUpdateTags)
 	return !equal
 }
 
@@ -293,6 +335,36 @@ func (vmss VMSS) HasLatestModelApplied(vm VMSSVM) bool {
 	return reflect.DeepEqual(vm.Image, vmss.Image)
 }
 
+// MaintenanceConfigurationSpec contains properties to create the planned maintenance configuration of a managed cluster.
+type MaintenanceConfigurationSpec struct {
+	// Name is the name of the maintenance configuration.
+	Name string
+
+	// ResourceGroup is the name of the resource group of the managed cluster.
+	ResourceGroup string
+
+	// Cluster is the name of the managed cluster.
+	Cluster string
+
+	// TimeInWeek are the days of the week and the hours of those days in which maintenance is allowed.
+	TimeInWeek []MaintenanceTimeInWeek
+
+	// NotAllowedTime are the time spans in which maintenance is not allowed.
+	NotAllowedTime []MaintenanceTimeSpan
+}
+
+// MaintenanceTimeInWeek contains the hours of a day of the week in which maintenance is allowed.
+type MaintenanceTimeInWeek struct {
+	Day       string
+	HourSlots []int32
+}
+
+// MaintenanceTimeSpan contains the start and the end of a time span.
+type MaintenanceTimeSpan struct {
+	Start time.Time
+	End   time.Time
+}
+
 // ManagedClusterSpec contains properties to create a managed cluster.
 type ManagedClusterSpec struct {
 	// Name is the name of this AKS Cluster.
@@ -304,6 +376,15 @@ type ManagedClusterSpec struct {
 	// NodeResourceGroupName is the name of the Azure resource group containing IaaS VMs.
 	NodeResourceGroupName string
 
+	// DiskEncryptionSetID is the resource ID of the disk encryption set used to encrypt the OS disks of the nodes.
+	DiskEncryptionSetID *string
+
+	// DNSPrefix is the DNS prefix of the API server of the cluster.
+	DNSPrefix string
+
+	// IdentityType is the type of the identity of the cluster. Possible values include: 'SystemAssigned'.
+	IdentityType string
+
 	// VnetSubnetID is the Azure Resource ID for the subnet which should contain nodes.
 	VnetSubnetID string
 
@@ -328,7 +409,8 @@ type ManagedClusterSpec struct {
 	// SSHPublicKey is a string literal containing an ssh public key. Will autogenerate and discard if not provided.
 	SSHPublicKey string
 
-	// AgentPools is the list of agent pool specifications in this cluster.
+	// AgentPools is the list of agent pool specifications in this cluster. AKS only accepts agent pools in the
+	// managed cluster when the cluster is created, so they are only set then.
 	AgentPools []AgentPoolSpec
 
 	// PodCIDR is the CIDR block for IP addresses distributed to pods
@@ -351,6 +433,46 @@ type ManagedClusterSpec struct {
 
 	// APIServerAccessProfile is the access profile for AKS API server.
 	APIServerAccessProfile *APIServerAccessProfile
+
+	// OutboundType is the outbound (egress) routing method of the cluster. Possible values include: 'loadBalancer', 'userDefinedRouting'. Defaults to loadBalancer.
+	OutboundType string
+
+	// DisableLocalAccounts disables getting static credentials for the cluster.
+	DisableLocalAccounts *bool
+
+	// AutoScalerProfile is the profile of the cluster autoscaler. Nil leaves the AKS defaults.
+	AutoScalerProfile *AutoScalerProfile
+
+	// AddonProfiles are the profiles of the managed cluster add-ons. Add-ons not listed keep their state in AKS.
+	AddonProfiles []AddonProfile
+}
+
+// AddonProfile is the profile of a managed cluster add-on.
+type AddonProfile struct {
+	Name    string
+	Config  map[string]string
+	Enabled bool
+}
+
+// AutoScalerProfile is the profile of the cluster autoscaler. Unset fields keep their AKS defaults.
+type AutoScalerProfile struct {
+	BalanceSimilarNodeGroups      *string
+	Expander                      *string
+	MaxEmptyBulkDelete            *string
+	MaxGracefulTerminationSec     *string
+	MaxNodeProvisionTime          *string
+	MaxTotalUnreadyPercentage     *string
+	NewPodScaleUpDelay            *string
+	OkTotalUnreadyCount           *string
+	ScanInterval                  *string
+	ScaleDownDelayAfterAdd        *string
+	ScaleDownDelayAfterDelete     *string
+	ScaleDownDelayAfterFailure    *string
+	ScaleDownUnneededTime         *string
+	ScaleDownUnreadyTime          *string
+	ScaleDownUtilizationThreshold *string
+	SkipNodesWithLocalStorage     *string
+	SkipNodesWithSystemPods       *string
 }
 
 // AADProfile is Azure Active Directory configuration to integrate with AKS, for aad authentication.
@@ -363,6 +485,9 @@ type AADProfile struct {
 
 	// AdminGroupObjectIDs - AAD group object IDs that will have admin role of the cluster.
 	AdminGroupObjectIDs []string
+
+	// TenantID - The AAD tenant ID to use for authentication. If not specified, the tenant of the subscription is used.
+	TenantID string
 }
 
 // SKU - AKS SKU.
@@ -429,9 +554,96 @@ type AgentPoolSpec struct {
 	// OSDiskSizeGB is the OS disk size in GB for every machine in this agent pool.
 	OSDiskSizeGB int32
 
+	// OSDiskType is the OS disk type of the agent pool nodes. Possible values include: 'Managed', 'Ephemeral'.
+	OSDiskType string
+
+	// EnableEncryptionAtHost enables encryption at host of the agent pool nodes. Nil leaves it to AKS.
+	EnableEncryptionAtHost *bool
+
+	// ProximityPlacementGroupID is the Azure Resource ID of the proximity placement group of the agent pool nodes.
+	ProximityPlacementGroupID *string
+
+	// EnableNodePublicIP assigns a public IP to each node of the agent pool. Nil leaves it to AKS.
+	EnableNodePublicIP *bool
+
+	// NodePublicIPPrefixID is the Azure Resource ID of the public IP prefix the public IPs of the nodes are allocated
+	// from.
+	NodePublicIPPrefixID *string
+
 	// VnetSubnetID is the Azure Resource ID for the subnet which should contain nodes.
 	VnetSubnetID string
 
+	// PodSubnetID is the Azure Resource ID for the subnet the IPs of the pods are allocated from. When empty, pod IPs
+	// are allocated from the node subnet.
+	PodSubnetID string
+
 	// Mode represents mode of an agent pool. Possible values include: 'System', 'User'.
 	Mode string
+
+	// OSType is the operating system type of the agent pool nodes. Possible values include: 'Linux', 'Windows'.
+	OSType string
+
+	// ScaleSetPriority is the Virtual Machine Scale Set priority of the agent pool. Possible values include: 'Regular', 'Spot'.
+	ScaleSetPriority string
+
+	// NodeTaints are the taints applied to new nodes of the agent pool, in the form key=value:effect.
+	NodeTaints []string
+
+	// NodeLabels are the labels applied to the nodes of the agent pool.
+	NodeLabels map[string]string
+
+	// RemovedNodeLabels are the keys of the node labels CAPZ previously applied to the agent pool that are no longer
+	// part of NodeLabels, and must be removed from the agent pool.
+	RemovedNodeLabels []string
+
+	// EnableAutoScaling enables the cluster autoscaler on the agent pool.
+	EnableAutoScaling *bool
+
+	// MinCount is the minimum number of nodes the cluster autoscaler scales the agent pool down to.
+	MinCount *int32
+
+	// MaxCount is the maximum number of nodes the cluster autoscaler scales the agent pool up to.
+	MaxCount *int32
+
+	// CreateOnlyFields are the fields of the agent pool that AKS does not allow to be updated. They are only sent when
+	// the agent pool is created, and omitted from update requests.
+	CreateOnlyFields []AgentPoolField
+}
+
+// AgentPoolField identifies a field of an agent pool.
+type AgentPoolField string
+
+const (
+	// AgentPoolSKU identifies the VM size of an agent pool.
+	AgentPoolSKU AgentPoolField = "SKU"
+	// AgentPoolOSDiskSizeGB identifies the OS disk size of an agent pool.
+	AgentPoolOSDiskSizeGB AgentPoolField = "OSDiskSizeGB"
+	// AgentPoolVnetSubnetID identifies the subnet of an agent pool.
+	AgentPoolVnetSubnetID AgentPoolField = "VnetSubnetID"
+	// AgentPoolPodSubnetID identifies the pod subnet of an agent pool.
+	AgentPoolPodSubnetID AgentPoolField = "PodSubnetID"
+	// AgentPoolOSType identifies the OS type of an agent pool.
+	AgentPoolOSType AgentPoolField = "OSType"
+	// AgentPoolOSDiskType identifies the OS disk type of an agent pool.
+	AgentPoolOSDiskType AgentPoolField = "OSDiskType"
+	// AgentPoolEnableEncryptionAtHost identifies the encryption at host setting of an agent pool.
+	AgentPoolEnableEncryptionAtHost AgentPoolField = "EnableEncryptionAtHost"
+	// AgentPoolProximityPlacementGroupID identifies the proximity placement group of an agent pool.
+	AgentPoolProximityPlacementGroupID AgentPoolField = "ProximityPlacementGroupID"
+	// AgentPoolEnableNodePublicIP identifies the node public IP setting of an agent pool.
+	AgentPoolEnableNodePublicIP AgentPoolField = "EnableNodePublicIP"
+	// AgentPoolNodePublicIPPrefixID identifies the public IP prefix of the nodes of an agent pool.
+	AgentPoolNodePublicIPPrefixID AgentPoolField = "NodePublicIPPrefixID"
+	// AgentPoolScaleSetPriority identifies the scale set priority of an agent pool.
+	AgentPoolScaleSetPriority AgentPoolField = "ScaleSetPriority"
+)
+
+// IsCreateOnly returns true if the given field is only sent when the agent pool is created.
+func (s AgentPoolSpec) IsCreateOnly(field AgentPoolField) bool {
+	for _, f := range s.CreateOnlyFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
 }
@@ -136,6 +136,21 @@ func TestVMSS_HasModelChanges(t *testing.T) {
 			},
 			HasModelChanges: true,
 		},
+		{
+			Name: "with different extension force update tags",
+			Factory: func() (VMSS, VMSS) {
+				l := getDefaultVMSSForModelTesting()
+				l.ExtensionForceUpdateTags = map[string]string{
+					"CAPZ.Linux.Bootstrapping": "2",
+				}
+				r := getDefaultVMSSForModelTesting()
+				r.ExtensionForceUpdateTags = map[string]string{
+					"CAPZ.Linux.Bootstrapping": "1",
+				}
+				return r, l
+			},
+			HasModelChanges: true,
+		},
 	}
 
 	for _, c := range cases {
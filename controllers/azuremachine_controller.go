@@ -50,23 +50,25 @@ import (
 // AzureMachineReconciler reconciles an AzureMachine object.
 type AzureMachineReconciler struct {
 	client.Client
-	Log                       logr.Logger
-	Recorder                  record.EventRecorder
-	ReconcileTimeout          time.Duration
-	WatchFilterValue          string
-	createAzureMachineService azureMachineServiceCreator
+	Log                             logr.Logger
+	Recorder                        record.EventRecorder
+	ReconcileTimeout                time.Duration
+	WatchFilterValue                string
+	RoleAssignmentMaxCreateAttempts int
+	createAzureMachineService       azureMachineServiceCreator
 }
 
-type azureMachineServiceCreator func(machineScope *scope.MachineScope) (*azureMachineService, error)
+type azureMachineServiceCreator func(machineScope *scope.MachineScope, roleAssignmentMaxCreateAttempts int) (*azureMachineService, error)
 
 // NewAzureMachineReconciler returns a new AzureMachineReconciler instance.
-func NewAzureMachineReconciler(client client.Client, log logr.Logger, recorder record.EventRecorder, reconcileTimeout time.Duration, watchFilterValue string) *AzureMachineReconciler {
+func NewAzureMachineReconciler(client client.Client, log logr.Logger, recorder record.EventRecorder, reconcileTimeout time.Duration, watchFilterValue string, roleAssignmentMaxCreateAttempts int) *AzureMachineReconciler {
 	amr := &AzureMachineReconciler{
-		Client:           client,
-		Log:              log,
-		Recorder:         recorder,
-		ReconcileTimeout: reconcileTimeout,
-		WatchFilterValue: watchFilterValue,
+		Client:                          client,
+		Log:                             log,
+		Recorder:                        recorder,
+		ReconcileTimeout:                reconcileTimeout,
+		WatchFilterValue:                watchFilterValue,
+		RoleAssignmentMaxCreateAttempts: roleAssignmentMaxCreateAttempts,
 	}
 
 	amr.createAzureMachineService = newAzureMachineService
@@ -281,7 +283,7 @@ func (amr *AzureMachineReconciler) reconcileNormal(ctx context.Context, machineS
 		return reconcile.Result{}, errors.Wrap(err, "failed to init machine scope cache")
 	}
 
-	ams, err := amr.createAzureMachineService(machineScope)
+	ams, err := amr.createAzureMachineService(machineScope, amr.RoleAssignmentMaxCreateAttempts)
 	if err != nil {
 		return reconcile.Result{}, errors.Wrap(err, "failed to create azure machine service")
 	}
@@ -341,7 +343,7 @@ func (amr *AzureMachineReconciler) reconcileDelete(ctx context.Context, machineS
 
 	if ShouldDeleteIndividualResources(ctx, clusterScope) {
 		machineScope.Info("Deleting AzureMachine")
-		ams, err := amr.createAzureMachineService(machineScope)
+		ams, err := amr.createAzureMachineService(machineScope, amr.RoleAssignmentMaxCreateAttempts)
 		if err != nil {
 			return reconcile.Result{}, errors.Wrap(err, "failed to create azure machine service")
 		}
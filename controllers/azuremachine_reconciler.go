@@ -54,7 +54,7 @@ type azureMachineService struct {
 var _ azure.Reconciler = (*azureMachineService)(nil)
 
 // newAzureMachineService populates all the services based on input scope.
-func newAzureMachineService(machineScope *scope.MachineScope) (*azureMachineService, error) {
+func newAzureMachineService(machineScope *scope.MachineScope, roleAssignmentMaxCreateAttempts int) (*azureMachineService, error) {
 	cache, err := resourceskus.GetCache(machineScope, machineScope.Location())
 	if err != nil {
 		return nil, errors.Wrap(err, "failed creating a NewCache")
@@ -65,7 +65,7 @@ func newAzureMachineService(machineScope *scope.MachineScope) (*azureMachineServ
 		inboundNatRulesSvc:   inboundnatrules.New(machineScope),
 		networkInterfacesSvc: networkinterfaces.New(machineScope, cache),
 		virtualMachinesSvc:   virtualmachines.New(machineScope),
-		roleAssignmentsSvc:   roleassignments.New(machineScope),
+		roleAssignmentsSvc:   roleassignments.New(machineScope, roleAssignmentMaxCreateAttempts),
 		disksSvc:             disks.New(machineScope),
 		publicIPsSvc:         publicips.New(machineScope),
 		tagsSvc:              tags.New(machineScope),
@@ -48,6 +48,8 @@ func (src *AzureMachinePool) ConvertTo(dstRaw conversion.Hub) error { // nolint
 	}
 
 	dst.Spec.Template.SubnetName = restored.Spec.Template.SubnetName
+	dst.Spec.RoleAssignmentPrincipalIDs = restored.Spec.RoleAssignmentPrincipalIDs
+	dst.Spec.RoleAssignmentScope = restored.Spec.RoleAssignmentScope
 
 	dst.Spec.Strategy.Type = restored.Spec.Strategy.Type
 	if restored.Spec.Strategy.RollingUpdate != nil {
@@ -83,6 +85,8 @@ func (src *AzureMachinePool) ConvertTo(dstRaw conversion.Hub) error { // nolint
 		}
 	}
 
+	dst.Status.RoleAssignmentCreateAttempts = restored.Status.RoleAssignmentCreateAttempts
+
 	return nil
 }
 
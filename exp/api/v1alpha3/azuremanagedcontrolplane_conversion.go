@@ -41,8 +41,27 @@ func (src *AzureManagedControlPlane) ConvertTo(dstRaw conversion.Hub) error { //
 	dst.Spec.SKU = restored.Spec.SKU
 	dst.Spec.LoadBalancerProfile = restored.Spec.LoadBalancerProfile
 	dst.Spec.APIServerAccessProfile = restored.Spec.APIServerAccessProfile
+	dst.Spec.OutboundType = restored.Spec.OutboundType
+	dst.Spec.KubeconfigSecret = restored.Spec.KubeconfigSecret
+	dst.Spec.MaintenanceWindow = restored.Spec.MaintenanceWindow
+	dst.Spec.NodeOSUpgradeMaintenanceWindow = restored.Spec.NodeOSUpgradeMaintenanceWindow
+	dst.Spec.DiskEncryptionSetID = restored.Spec.DiskEncryptionSetID
+	dst.Spec.DisableLocalAccounts = restored.Spec.DisableLocalAccounts
+	dst.Spec.CreateTimeout = restored.Spec.CreateTimeout
+	dst.Spec.AutoScalerProfile = restored.Spec.AutoScalerProfile
+	dst.Spec.EgressCheck = restored.Spec.EgressCheck
+	dst.Spec.AddonProfiles = restored.Spec.AddonProfiles
+	dst.Spec.DNSPrefix = restored.Spec.DNSPrefix
+	dst.Spec.NodeResourceGroupRoleAssignmentPrincipalIDs = restored.Spec.NodeResourceGroupRoleAssignmentPrincipalIDs
+	dst.Spec.VirtualNetwork.PodSubnets = restored.Spec.VirtualNetwork.PodSubnets
+	if restored.Spec.AADProfile != nil && dst.Spec.AADProfile != nil {
+		dst.Spec.AADProfile.TenantID = restored.Spec.AADProfile.TenantID
+		dst.Spec.AADProfile.EnableAzureRBAC = restored.Spec.AADProfile.EnableAzureRBAC
+	}
 
 	dst.Status.LongRunningOperationStates = restored.Status.LongRunningOperationStates
+	dst.Status.Conditions = restored.Status.Conditions
+	dst.Status.RoleAssignmentCreateAttempts = restored.Status.RoleAssignmentCreateAttempts
 
 	return nil
 }
@@ -72,3 +91,13 @@ func Convert_v1beta1_AzureManagedControlPlaneSpec_To_v1alpha3_AzureManagedContro
 func Convert_v1beta1_AzureManagedControlPlaneStatus_To_v1alpha3_AzureManagedControlPlaneStatus(in *expv1beta1.AzureManagedControlPlaneStatus, out *AzureManagedControlPlaneStatus, s apiconversion.Scope) error {
 	return autoConvert_v1beta1_AzureManagedControlPlaneStatus_To_v1alpha3_AzureManagedControlPlaneStatus(in, out, s)
 }
+
+// Convert_v1beta1_AADProfile_To_v1alpha3_AADProfile converts from the Hub version (v1beta1) of the AADProfile to this version.
+func Convert_v1beta1_AADProfile_To_v1alpha3_AADProfile(in *expv1beta1.AADProfile, out *AADProfile, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_AADProfile_To_v1alpha3_AADProfile(in, out, s)
+}
+
+// Convert_v1beta1_ManagedControlPlaneVirtualNetwork_To_v1alpha3_ManagedControlPlaneVirtualNetwork converts from the Hub version (v1beta1) of the ManagedControlPlaneVirtualNetwork to this version.
+func Convert_v1beta1_ManagedControlPlaneVirtualNetwork_To_v1alpha3_ManagedControlPlaneVirtualNetwork(in *expv1beta1.ManagedControlPlaneVirtualNetwork, out *ManagedControlPlaneVirtualNetwork, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_ManagedControlPlaneVirtualNetwork_To_v1alpha3_ManagedControlPlaneVirtualNetwork(in, out, s)
+}
@@ -38,6 +38,25 @@ func (src *AzureManagedMachinePool) ConvertTo(dstRaw conversion.Hub) error { //
 	}
 
 	dst.Spec.Name = restored.Spec.Name
+	dst.Spec.OSType = restored.Spec.OSType
+	dst.Spec.ScaleSetPriority = restored.Spec.ScaleSetPriority
+	dst.Spec.NodeDrainTimeout = restored.Spec.NodeDrainTimeout
+	dst.Spec.StartupTaint = restored.Spec.StartupTaint
+	dst.Spec.Taints = restored.Spec.Taints
+	dst.Spec.GPUSharing = restored.Spec.GPUSharing
+	dst.Spec.SKUSelector = restored.Spec.SKUSelector
+	dst.Spec.Scaling = restored.Spec.Scaling
+	dst.Spec.NodeLabels = restored.Spec.NodeLabels
+	dst.Spec.AutoscalerNodeAnnotations = restored.Spec.AutoscalerNodeAnnotations
+	dst.Spec.OSDiskType = restored.Spec.OSDiskType
+	dst.Spec.EnableEncryptionAtHost = restored.Spec.EnableEncryptionAtHost
+	dst.Spec.OrchestratorVersion = restored.Spec.OrchestratorVersion
+	dst.Spec.ProximityPlacementGroupID = restored.Spec.ProximityPlacementGroupID
+	dst.Spec.EnableNodePublicIP = restored.Spec.EnableNodePublicIP
+	dst.Spec.NodePublicIPPrefixID = restored.Spec.NodePublicIPPrefixID
+	dst.Spec.PodSubnetName = restored.Spec.PodSubnetName
+	dst.Status.Conditions = restored.Status.Conditions
+	dst.Status.Version = restored.Status.Version
 
 	return nil
 }
@@ -62,3 +81,8 @@ func (dst *AzureManagedMachinePool) ConvertFrom(srcRaw conversion.Hub) error { /
 func Convert_v1beta1_AzureManagedMachinePoolSpec_To_v1alpha3_AzureManagedMachinePoolSpec(in *expv1beta1.AzureManagedMachinePoolSpec, out *AzureManagedMachinePoolSpec, s apiconversion.Scope) error {
 	return autoConvert_v1beta1_AzureManagedMachinePoolSpec_To_v1alpha3_AzureManagedMachinePoolSpec(in, out, s)
 }
+
+// Convert_v1beta1_AzureManagedMachinePoolStatus_To_v1alpha3_AzureManagedMachinePoolStatus is an autogenerated conversion function.
+func Convert_v1beta1_AzureManagedMachinePoolStatus_To_v1alpha3_AzureManagedMachinePoolStatus(in *expv1beta1.AzureManagedMachinePoolStatus, out *AzureManagedMachinePoolStatus, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_AzureManagedMachinePoolStatus_To_v1alpha3_AzureManagedMachinePoolStatus(in, out, s)
+}
@@ -45,11 +45,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.AADProfile)(nil), (*AADProfile)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_AADProfile_To_v1alpha3_AADProfile(a.(*v1beta1.AADProfile), b.(*AADProfile), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*AzureMachinePool)(nil), (*v1beta1.AzureMachinePool)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_AzureMachinePool_To_v1beta1_AzureMachinePool(a.(*AzureMachinePool), b.(*v1beta1.AzureMachinePool), scope)
 	}); err != nil {
@@ -190,11 +185,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.AzureManagedMachinePoolStatus)(nil), (*AzureManagedMachinePoolStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_AzureManagedMachinePoolStatus_To_v1alpha3_AzureManagedMachinePoolStatus(a.(*v1beta1.AzureManagedMachinePoolStatus), b.(*AzureManagedMachinePoolStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*ManagedControlPlaneSubnet)(nil), (*v1beta1.ManagedControlPlaneSubnet)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_ManagedControlPlaneSubnet_To_v1beta1_ManagedControlPlaneSubnet(a.(*ManagedControlPlaneSubnet), b.(*v1beta1.ManagedControlPlaneSubnet), scope)
 	}); err != nil {
@@ -210,11 +200,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.ManagedControlPlaneVirtualNetwork)(nil), (*ManagedControlPlaneVirtualNetwork)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_ManagedControlPlaneVirtualNetwork_To_v1alpha3_ManagedControlPlaneVirtualNetwork(a.(*v1beta1.ManagedControlPlaneVirtualNetwork), b.(*ManagedControlPlaneVirtualNetwork), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddConversionFunc((*apiv1alpha3.APIEndpoint)(nil), (*apiv1beta1.APIEndpoint)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_APIEndpoint_To_v1beta1_APIEndpoint(a.(*apiv1alpha3.APIEndpoint), b.(*apiv1beta1.APIEndpoint), scope)
 	}); err != nil {
@@ -235,6 +220,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta1.AADProfile)(nil), (*AADProfile)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_AADProfile_To_v1alpha3_AADProfile(a.(*v1beta1.AADProfile), b.(*AADProfile), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*apiv1beta1.APIEndpoint)(nil), (*apiv1alpha3.APIEndpoint)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_APIEndpoint_To_v1alpha3_APIEndpoint(a.(*apiv1beta1.APIEndpoint), b.(*apiv1alpha3.APIEndpoint), scope)
 	}); err != nil {
@@ -270,6 +260,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta1.AzureManagedMachinePoolStatus)(nil), (*AzureManagedMachinePoolStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_AzureManagedMachinePoolStatus_To_v1alpha3_AzureManagedMachinePoolStatus(a.(*v1beta1.AzureManagedMachinePoolStatus), b.(*AzureManagedMachinePoolStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1beta1.ManagedControlPlaneVirtualNetwork)(nil), (*ManagedControlPlaneVirtualNetwork)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ManagedControlPlaneVirtualNetwork_To_v1alpha3_ManagedControlPlaneVirtualNetwork(a.(*v1beta1.ManagedControlPlaneVirtualNetwork), b.(*ManagedControlPlaneVirtualNetwork), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*clusterapiproviderazureapiv1beta1.Image)(nil), (*clusterapiproviderazureapiv1alpha3.Image)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_Image_To_v1alpha3_Image(a.(*clusterapiproviderazureapiv1beta1.Image), b.(*clusterapiproviderazureapiv1alpha3.Image), scope)
 	}); err != nil {
@@ -297,14 +297,11 @@ func Convert_v1alpha3_AADProfile_To_v1beta1_AADProfile(in *AADProfile, out *v1be
 func autoConvert_v1beta1_AADProfile_To_v1alpha3_AADProfile(in *v1beta1.AADProfile, out *AADProfile, s conversion.Scope) error {
 	out.Managed = in.Managed
 	out.AdminGroupObjectIDs = *(*[]string)(unsafe.Pointer(&in.AdminGroupObjectIDs))
+	// WARNING: in.TenantID requires manual conversion: does not exist in peer-type
+	// WARNING: in.EnableAzureRBAC requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta1_AADProfile_To_v1alpha3_AADProfile is an autogenerated conversion function.
-func Convert_v1beta1_AADProfile_To_v1alpha3_AADProfile(in *v1beta1.AADProfile, out *AADProfile, s conversion.Scope) error {
-	return autoConvert_v1beta1_AADProfile_To_v1alpha3_AADProfile(in, out, s)
-}
-
 func autoConvert_v1alpha3_AzureMachinePool_To_v1beta1_AzureMachinePool(in *AzureMachinePool, out *v1beta1.AzureMachinePool, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha3_AzureMachinePoolSpec_To_v1beta1_AzureMachinePoolSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -491,6 +488,8 @@ func autoConvert_v1beta1_AzureMachinePoolSpec_To_v1alpha3_AzureMachinePoolSpec(i
 	out.Identity = clusterapiproviderazureapiv1alpha3.VMIdentity(in.Identity)
 	out.UserAssignedIdentities = *(*[]clusterapiproviderazureapiv1alpha3.UserAssignedIdentity)(unsafe.Pointer(&in.UserAssignedIdentities))
 	out.RoleAssignmentName = in.RoleAssignmentName
+	// WARNING: in.RoleAssignmentPrincipalIDs requires manual conversion: does not exist in peer-type
+	// WARNING: in.RoleAssignmentScope requires manual conversion: does not exist in peer-type
 	// WARNING: in.Strategy requires manual conversion: does not exist in peer-type
 	// WARNING: in.NodeDrainTimeout requires manual conversion: does not exist in peer-type
 	return nil
@@ -520,6 +519,7 @@ func autoConvert_v1beta1_AzureMachinePoolStatus_To_v1alpha3_AzureMachinePoolStat
 	out.FailureMessage = (*string)(unsafe.Pointer(in.FailureMessage))
 	out.Conditions = *(*apiv1alpha3.Conditions)(unsafe.Pointer(&in.Conditions))
 	// WARNING: in.LongRunningOperationStates requires manual conversion: does not exist in peer-type
+	// WARNING: in.RoleAssignmentCreateAttempts requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -733,7 +733,15 @@ func autoConvert_v1alpha3_AzureManagedControlPlaneSpec_To_v1beta1_AzureManagedCo
 	out.SSHPublicKey = in.SSHPublicKey
 	out.DNSServiceIP = (*string)(unsafe.Pointer(in.DNSServiceIP))
 	out.LoadBalancerSKU = (*string)(unsafe.Pointer(in.LoadBalancerSKU))
-	out.AADProfile = (*v1beta1.AADProfile)(unsafe.Pointer(in.AADProfile))
+	if in.AADProfile != nil {
+		in, out := &in.AADProfile, &out.AADProfile
+		*out = new(v1beta1.AADProfile)
+		if err := Convert_v1alpha3_AADProfile_To_v1beta1_AADProfile(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.AADProfile = nil
+	}
 	return nil
 }
 
@@ -746,6 +754,7 @@ func autoConvert_v1beta1_AzureManagedControlPlaneSpec_To_v1alpha3_AzureManagedCo
 	out.Version = in.Version
 	out.ResourceGroupName = in.ResourceGroupName
 	out.NodeResourceGroupName = in.NodeResourceGroupName
+	// WARNING: in.DiskEncryptionSetID requires manual conversion: does not exist in peer-type
 	if err := Convert_v1beta1_ManagedControlPlaneVirtualNetwork_To_v1alpha3_ManagedControlPlaneVirtualNetwork(&in.VirtualNetwork, &out.VirtualNetwork, s); err != nil {
 		return err
 	}
@@ -759,12 +768,31 @@ func autoConvert_v1beta1_AzureManagedControlPlaneSpec_To_v1alpha3_AzureManagedCo
 	out.NetworkPolicy = (*string)(unsafe.Pointer(in.NetworkPolicy))
 	out.SSHPublicKey = in.SSHPublicKey
 	out.DNSServiceIP = (*string)(unsafe.Pointer(in.DNSServiceIP))
+	// WARNING: in.DNSPrefix requires manual conversion: does not exist in peer-type
 	out.LoadBalancerSKU = (*string)(unsafe.Pointer(in.LoadBalancerSKU))
 	// WARNING: in.IdentityRef requires manual conversion: does not exist in peer-type
-	out.AADProfile = (*AADProfile)(unsafe.Pointer(in.AADProfile))
+	if in.AADProfile != nil {
+		in, out := &in.AADProfile, &out.AADProfile
+		*out = new(AADProfile)
+		if err := Convert_v1beta1_AADProfile_To_v1alpha3_AADProfile(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.AADProfile = nil
+	}
 	// WARNING: in.SKU requires manual conversion: does not exist in peer-type
 	// WARNING: in.LoadBalancerProfile requires manual conversion: does not exist in peer-type
+	// WARNING: in.OutboundType requires manual conversion: does not exist in peer-type
 	// WARNING: in.APIServerAccessProfile requires manual conversion: does not exist in peer-type
+	// WARNING: in.KubeconfigSecret requires manual conversion: does not exist in peer-type
+	// WARNING: in.MaintenanceWindow requires manual conversion: does not exist in peer-type
+	// WARNING: in.NodeOSUpgradeMaintenanceWindow requires manual conversion: does not exist in peer-type
+	// WARNING: in.DisableLocalAccounts requires manual conversion: does not exist in peer-type
+	// WARNING: in.CreateTimeout requires manual conversion: does not exist in peer-type
+	// WARNING: in.AutoScalerProfile requires manual conversion: does not exist in peer-type
+	// WARNING: in.EgressCheck requires manual conversion: does not exist in peer-type
+	// WARNING: in.AddonProfiles requires manual conversion: does not exist in peer-type
+	// WARNING: in.NodeResourceGroupRoleAssignmentPrincipalIDs requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -783,6 +811,8 @@ func autoConvert_v1beta1_AzureManagedControlPlaneStatus_To_v1alpha3_AzureManaged
 	out.Ready = in.Ready
 	out.Initialized = in.Initialized
 	// WARNING: in.LongRunningOperationStates requires manual conversion: does not exist in peer-type
+	// WARNING: in.Conditions requires manual conversion: does not exist in peer-type
+	// WARNING: in.RoleAssignmentCreateAttempts requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -876,9 +906,26 @@ func Convert_v1alpha3_AzureManagedMachinePoolSpec_To_v1beta1_AzureManagedMachine
 func autoConvert_v1beta1_AzureManagedMachinePoolSpec_To_v1alpha3_AzureManagedMachinePoolSpec(in *v1beta1.AzureManagedMachinePoolSpec, out *AzureManagedMachinePoolSpec, s conversion.Scope) error {
 	// WARNING: in.Name requires manual conversion: does not exist in peer-type
 	out.Mode = in.Mode
+	// WARNING: in.OrchestratorVersion requires manual conversion: does not exist in peer-type
 	out.SKU = in.SKU
+	// WARNING: in.SKUSelector requires manual conversion: does not exist in peer-type
+	// WARNING: in.OSType requires manual conversion: does not exist in peer-type
+	// WARNING: in.ScaleSetPriority requires manual conversion: does not exist in peer-type
 	out.OSDiskSizeGB = (*int32)(unsafe.Pointer(in.OSDiskSizeGB))
+	// WARNING: in.OSDiskType requires manual conversion: does not exist in peer-type
+	// WARNING: in.EnableEncryptionAtHost requires manual conversion: does not exist in peer-type
+	// WARNING: in.ProximityPlacementGroupID requires manual conversion: does not exist in peer-type
+	// WARNING: in.EnableNodePublicIP requires manual conversion: does not exist in peer-type
+	// WARNING: in.NodePublicIPPrefixID requires manual conversion: does not exist in peer-type
+	// WARNING: in.PodSubnetName requires manual conversion: does not exist in peer-type
 	out.ProviderIDList = *(*[]string)(unsafe.Pointer(&in.ProviderIDList))
+	// WARNING: in.NodeDrainTimeout requires manual conversion: does not exist in peer-type
+	// WARNING: in.StartupTaint requires manual conversion: does not exist in peer-type
+	// WARNING: in.Taints requires manual conversion: does not exist in peer-type
+	// WARNING: in.GPUSharing requires manual conversion: does not exist in peer-type
+	// WARNING: in.Scaling requires manual conversion: does not exist in peer-type
+	// WARNING: in.NodeLabels requires manual conversion: does not exist in peer-type
+	// WARNING: in.AutoscalerNodeAnnotations requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -898,16 +945,13 @@ func Convert_v1alpha3_AzureManagedMachinePoolStatus_To_v1beta1_AzureManagedMachi
 func autoConvert_v1beta1_AzureManagedMachinePoolStatus_To_v1alpha3_AzureManagedMachinePoolStatus(in *v1beta1.AzureManagedMachinePoolStatus, out *AzureManagedMachinePoolStatus, s conversion.Scope) error {
 	out.Ready = in.Ready
 	out.Replicas = in.Replicas
+	// WARNING: in.Version requires manual conversion: does not exist in peer-type
 	out.ErrorReason = (*errors.MachineStatusError)(unsafe.Pointer(in.ErrorReason))
 	out.ErrorMessage = (*string)(unsafe.Pointer(in.ErrorMessage))
+	// WARNING: in.Conditions requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta1_AzureManagedMachinePoolStatus_To_v1alpha3_AzureManagedMachinePoolStatus is an autogenerated conversion function.
-func Convert_v1beta1_AzureManagedMachinePoolStatus_To_v1alpha3_AzureManagedMachinePoolStatus(in *v1beta1.AzureManagedMachinePoolStatus, out *AzureManagedMachinePoolStatus, s conversion.Scope) error {
-	return autoConvert_v1beta1_AzureManagedMachinePoolStatus_To_v1alpha3_AzureManagedMachinePoolStatus(in, out, s)
-}
-
 func autoConvert_v1alpha3_ManagedControlPlaneSubnet_To_v1beta1_ManagedControlPlaneSubnet(in *ManagedControlPlaneSubnet, out *v1beta1.ManagedControlPlaneSubnet, s conversion.Scope) error {
 	out.Name = in.Name
 	out.CIDRBlock = in.CIDRBlock
@@ -950,10 +994,6 @@ func autoConvert_v1beta1_ManagedControlPlaneVirtualNetwork_To_v1alpha3_ManagedCo
 	if err := Convert_v1beta1_ManagedControlPlaneSubnet_To_v1alpha3_ManagedControlPlaneSubnet(&in.Subnet, &out.Subnet, s); err != nil {
 		return err
 	}
+	// WARNING: in.PodSubnets requires manual conversion: does not exist in peer-type
 	return nil
 }
-
-// Convert_v1beta1_ManagedControlPlaneVirtualNetwork_To_v1alpha3_ManagedControlPlaneVirtualNetwork is an autogenerated conversion function.
-func Convert_v1beta1_ManagedControlPlaneVirtualNetwork_To_v1alpha3_ManagedControlPlaneVirtualNetwork(in *v1beta1.ManagedControlPlaneVirtualNetwork, out *ManagedControlPlaneVirtualNetwork, s conversion.Scope) error {
-	return autoConvert_v1beta1_ManagedControlPlaneVirtualNetwork_To_v1alpha3_ManagedControlPlaneVirtualNetwork(in, out, s)
-}
@@ -17,7 +17,9 @@ limitations under the License.
 package v1alpha4
 
 import (
+	apiconversion "k8s.io/apimachinery/pkg/conversion"
 	expv1beta1 "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 )
 
@@ -25,12 +27,72 @@ import (
 func (src *AzureManagedControlPlane) ConvertTo(dstRaw conversion.Hub) error { // nolint
 	dst := dstRaw.(*expv1beta1.AzureManagedControlPlane)
 
-	return Convert_v1alpha4_AzureManagedControlPlane_To_v1beta1_AzureManagedControlPlane(src, dst, nil)
+	if err := Convert_v1alpha4_AzureManagedControlPlane_To_v1beta1_AzureManagedControlPlane(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &expv1beta1.AzureManagedControlPlane{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+
+	dst.Spec.OutboundType = restored.Spec.OutboundType
+	dst.Spec.KubeconfigSecret = restored.Spec.KubeconfigSecret
+	dst.Spec.MaintenanceWindow = restored.Spec.MaintenanceWindow
+	dst.Spec.NodeOSUpgradeMaintenanceWindow = restored.Spec.NodeOSUpgradeMaintenanceWindow
+	dst.Spec.DiskEncryptionSetID = restored.Spec.DiskEncryptionSetID
+	dst.Spec.DisableLocalAccounts = restored.Spec.DisableLocalAccounts
+	dst.Spec.CreateTimeout = restored.Spec.CreateTimeout
+	dst.Spec.AutoScalerProfile = restored.Spec.AutoScalerProfile
+	dst.Spec.EgressCheck = restored.Spec.EgressCheck
+	dst.Spec.AddonProfiles = restored.Spec.AddonProfiles
+	dst.Spec.DNSPrefix = restored.Spec.DNSPrefix
+	dst.Spec.NodeResourceGroupRoleAssignmentPrincipalIDs = restored.Spec.NodeResourceGroupRoleAssignmentPrincipalIDs
+	dst.Spec.VirtualNetwork.PodSubnets = restored.Spec.VirtualNetwork.PodSubnets
+	if restored.Spec.AADProfile != nil && dst.Spec.AADProfile != nil {
+		dst.Spec.AADProfile.TenantID = restored.Spec.AADProfile.TenantID
+		dst.Spec.AADProfile.EnableAzureRBAC = restored.Spec.AADProfile.EnableAzureRBAC
+	}
+
+	dst.Status.Conditions = restored.Status.Conditions
+	dst.Status.RoleAssignmentCreateAttempts = restored.Status.RoleAssignmentCreateAttempts
+
+	return nil
 }
 
 // ConvertFrom converts from the Hub version (v1beta1) to this version.
 func (dst *AzureManagedControlPlane) ConvertFrom(srcRaw conversion.Hub) error { // nolint
 	src := srcRaw.(*expv1beta1.AzureManagedControlPlane)
 
-	return Convert_v1beta1_AzureManagedControlPlane_To_v1alpha4_AzureManagedControlPlane(src, dst, nil)
+	if err := Convert_v1beta1_AzureManagedControlPlane_To_v1alpha4_AzureManagedControlPlane(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion.
+	if err := utilconversion.MarshalData(src, dst); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Convert_v1beta1_AzureManagedControlPlaneSpec_To_v1alpha4_AzureManagedControlPlaneSpec is an autogenerated conversion function.
+func Convert_v1beta1_AzureManagedControlPlaneSpec_To_v1alpha4_AzureManagedControlPlaneSpec(in *expv1beta1.AzureManagedControlPlaneSpec, out *AzureManagedControlPlaneSpec, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_AzureManagedControlPlaneSpec_To_v1alpha4_AzureManagedControlPlaneSpec(in, out, s)
+}
+
+// Convert_v1beta1_AADProfile_To_v1alpha4_AADProfile converts from the Hub version (v1beta1) of the AADProfile to this version.
+func Convert_v1beta1_AADProfile_To_v1alpha4_AADProfile(in *expv1beta1.AADProfile, out *AADProfile, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_AADProfile_To_v1alpha4_AADProfile(in, out, s)
+}
+
+// Convert_v1beta1_AzureManagedControlPlaneStatus_To_v1alpha4_AzureManagedControlPlaneStatus is an autogenerated conversion function.
+func Convert_v1beta1_AzureManagedControlPlaneStatus_To_v1alpha4_AzureManagedControlPlaneStatus(in *expv1beta1.AzureManagedControlPlaneStatus, out *AzureManagedControlPlaneStatus, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_AzureManagedControlPlaneStatus_To_v1alpha4_AzureManagedControlPlaneStatus(in, out, s)
+}
+
+// Convert_v1beta1_ManagedControlPlaneVirtualNetwork_To_v1alpha4_ManagedControlPlaneVirtualNetwork converts from the Hub version (v1beta1) of the ManagedControlPlaneVirtualNetwork to this version.
+func Convert_v1beta1_ManagedControlPlaneVirtualNetwork_To_v1alpha4_ManagedControlPlaneVirtualNetwork(in *expv1beta1.ManagedControlPlaneVirtualNetwork, out *ManagedControlPlaneVirtualNetwork, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_ManagedControlPlaneVirtualNetwork_To_v1alpha4_ManagedControlPlaneVirtualNetwork(in, out, s)
 }
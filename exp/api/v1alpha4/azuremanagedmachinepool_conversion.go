@@ -17,7 +17,9 @@ limitations under the License.
 package v1alpha4
 
 import (
+	apiconversion "k8s.io/apimachinery/pkg/conversion"
 	expv1beta1 "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 )
 
@@ -25,12 +27,61 @@ import (
 func (src *AzureManagedMachinePool) ConvertTo(dstRaw conversion.Hub) error { // nolint
 	dst := dstRaw.(*expv1beta1.AzureManagedMachinePool)
 
-	return Convert_v1alpha4_AzureManagedMachinePool_To_v1beta1_AzureManagedMachinePool(src, dst, nil)
+	if err := Convert_v1alpha4_AzureManagedMachinePool_To_v1beta1_AzureManagedMachinePool(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &expv1beta1.AzureManagedMachinePool{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+
+	dst.Spec.OSType = restored.Spec.OSType
+	dst.Spec.ScaleSetPriority = restored.Spec.ScaleSetPriority
+	dst.Spec.NodeDrainTimeout = restored.Spec.NodeDrainTimeout
+	dst.Spec.StartupTaint = restored.Spec.StartupTaint
+	dst.Spec.Taints = restored.Spec.Taints
+	dst.Spec.GPUSharing = restored.Spec.GPUSharing
+	dst.Spec.SKUSelector = restored.Spec.SKUSelector
+	dst.Spec.Scaling = restored.Spec.Scaling
+	dst.Spec.NodeLabels = restored.Spec.NodeLabels
+	dst.Spec.AutoscalerNodeAnnotations = restored.Spec.AutoscalerNodeAnnotations
+	dst.Spec.OSDiskType = restored.Spec.OSDiskType
+	dst.Spec.EnableEncryptionAtHost = restored.Spec.EnableEncryptionAtHost
+	dst.Spec.OrchestratorVersion = restored.Spec.OrchestratorVersion
+	dst.Spec.ProximityPlacementGroupID = restored.Spec.ProximityPlacementGroupID
+	dst.Spec.EnableNodePublicIP = restored.Spec.EnableNodePublicIP
+	dst.Spec.NodePublicIPPrefixID = restored.Spec.NodePublicIPPrefixID
+	dst.Spec.PodSubnetName = restored.Spec.PodSubnetName
+	dst.Status.Conditions = restored.Status.Conditions
+	dst.Status.Version = restored.Status.Version
+
+	return nil
 }
 
 // ConvertFrom converts from the Hub version (v1beta1) to this version.
 func (dst *AzureManagedMachinePool) ConvertFrom(srcRaw conversion.Hub) error { // nolint
 	src := srcRaw.(*expv1beta1.AzureManagedMachinePool)
 
-	return Convert_v1beta1_AzureManagedMachinePool_To_v1alpha4_AzureManagedMachinePool(src, dst, nil)
+	if err := Convert_v1beta1_AzureManagedMachinePool_To_v1alpha4_AzureManagedMachinePool(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion.
+	if err := utilconversion.MarshalData(src, dst); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Convert_v1beta1_AzureManagedMachinePoolSpec_To_v1alpha4_AzureManagedMachinePoolSpec is an autogenerated conversion function.
+func Convert_v1beta1_AzureManagedMachinePoolSpec_To_v1alpha4_AzureManagedMachinePoolSpec(in *expv1beta1.AzureManagedMachinePoolSpec, out *AzureManagedMachinePoolSpec, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_AzureManagedMachinePoolSpec_To_v1alpha4_AzureManagedMachinePoolSpec(in, out, s)
+}
+
+// Convert_v1beta1_AzureManagedMachinePoolStatus_To_v1alpha4_AzureManagedMachinePoolStatus is an autogenerated conversion function.
+func Convert_v1beta1_AzureManagedMachinePoolStatus_To_v1alpha4_AzureManagedMachinePoolStatus(in *expv1beta1.AzureManagedMachinePoolStatus, out *AzureManagedMachinePoolStatus, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_AzureManagedMachinePoolStatus_To_v1alpha4_AzureManagedMachinePoolStatus(in, out, s)
 }
@@ -134,6 +134,19 @@ type (
 		// +optional
 		RoleAssignmentName string `json:"roleAssignmentName,omitempty"`
 
+		// RoleAssignmentPrincipalIDs are the IDs of additional principals, e.g. of user assigned identities, to assign the
+		// role of the system assigned identity to, each in a role assignment of its own. They can only be set when using a
+		// system assigned identity.
+		// +optional
+		RoleAssignmentPrincipalIDs []string `json:"roleAssignmentPrincipalIDs,omitempty"`
+
+		// RoleAssignmentScope is the ID of the resource, e.g. a subnet, the role of the system assigned identity is
+		// assigned at. If not specified, the role is assigned at the subscription. When the resource does not exist yet,
+		// e.g. because it is still being created by CAPZ, the role assignment is created once it does. It can only be set
+		// when using a system assigned identity.
+		// +optional
+		RoleAssignmentScope string `json:"roleAssignmentScope,omitempty"`
+
 		// The deployment strategy to use to replace existing AzureMachinePoolMachines with new ones.
 		// +optional
 		// +kubebuilder:default={type: "RollingUpdate", rollingUpdate: {maxSurge: 1, maxUnavailable: 0, deletePolicy: Oldest}}
@@ -286,6 +299,11 @@ type (
 		// next reconciliation loop.
 		// +optional
 		LongRunningOperationStates infrav1.Futures `json:"longRunningOperationStates,omitempty"`
+
+		// RoleAssignmentCreateAttempts is the number of consecutive reconciles in which the role assignment of the
+		// AzureMachinePool could not be created because of an error that retrying is unlikely to fix.
+		// +optional
+		RoleAssignmentCreateAttempts int32 `json:"roleAssignmentCreateAttempts,omitempty"`
 	}
 
 	// AzureMachinePoolInstanceStatus provides status information for each instance in the VMSS.
@@ -88,6 +88,8 @@ func (amp *AzureMachinePool) Validate(old runtime.Object) error {
 		amp.ValidateUserAssignedIdentity,
 		amp.ValidateStrategy(),
 		amp.ValidateSystemAssignedIdentity(old),
+		amp.ValidateRoleAssignmentPrincipalIDs,
+		amp.ValidateRoleAssignmentScope(old),
 	}
 
 	var errs []error
@@ -154,6 +156,41 @@ func (amp *AzureMachinePool) ValidateUserAssignedIdentity() error {
 	return nil
 }
 
+// ValidateRoleAssignmentPrincipalIDs validates the IDs of the additional principals to assign the role of the
+// system-assigned identity to.
+func (amp *AzureMachinePool) ValidateRoleAssignmentPrincipalIDs() error {
+	fldPath := field.NewPath("roleAssignmentPrincipalIDs")
+	if errs := infrav1.ValidateRoleAssignmentPrincipalIDs(amp.Spec.Identity, amp.Spec.RoleAssignmentPrincipalIDs, fldPath); len(errs) > 0 {
+		return kerrors.NewAggregate(errs.ToAggregate().Errors())
+	}
+
+	return nil
+}
+
+// ValidateRoleAssignmentScope validates the scope of the role assignment of the system-assigned identity, which cannot
+// be changed once the AzureMachinePool is created.
+func (amp *AzureMachinePool) ValidateRoleAssignmentScope(old runtime.Object) func() error {
+	return func() error {
+		fldPath := field.NewPath("roleAssignmentScope")
+		if old != nil {
+			oldMachinePool, ok := old.(*AzureMachinePool)
+			if !ok {
+				return fmt.Errorf("unexpected type for old azure machine pool object. Expected: %q, Got: %q",
+					"AzureMachinePool", reflect.TypeOf(old))
+			}
+			if amp.Spec.RoleAssignmentScope != oldMachinePool.Spec.RoleAssignmentScope {
+				return field.Invalid(fldPath, amp.Spec.RoleAssignmentScope, "field is immutable")
+			}
+		}
+
+		if errs := infrav1.ValidateRoleAssignmentScope(amp.Spec.Identity, amp.Spec.RoleAssignmentScope, fldPath); len(errs) > 0 {
+			return kerrors.NewAggregate(errs.ToAggregate().Errors())
+		}
+
+		return nil
+	}
+}
+
 // ValidateStrategy validates the strategy.
 func (amp *AzureMachinePool) ValidateStrategy() func() error {
 	return func() error {
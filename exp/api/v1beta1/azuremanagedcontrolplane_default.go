@@ -50,10 +50,15 @@ func (r *AzureManagedControlPlane) setDefaultSSHPublicKey() error {
 // setDefaultNodeResourceGroupName sets the default NodeResourceGroup for an AzureManagedControlPlane.
 func (r *AzureManagedControlPlane) setDefaultNodeResourceGroupName() {
 	if r.Spec.NodeResourceGroupName == "" {
-		r.Spec.NodeResourceGroupName = fmt.Sprintf("MC_%s_%s_%s", r.Spec.ResourceGroupName, r.Name, r.Spec.Location)
+		r.Spec.NodeResourceGroupName = r.defaultNodeResourceGroupName()
 	}
 }
 
+// defaultNodeResourceGroupName returns the name AKS gives the node resource group when none is set.
+func (r *AzureManagedControlPlane) defaultNodeResourceGroupName() string {
+	return fmt.Sprintf("MC_%s_%s_%s", r.Spec.ResourceGroupName, r.Name, r.Spec.Location)
+}
+
 // setDefaultVirtualNetwork sets the default VirtualNetwork for an AzureManagedControlPlane.
 func (r *AzureManagedControlPlane) setDefaultVirtualNetwork() {
 	if r.Spec.VirtualNetwork.Name == "" {
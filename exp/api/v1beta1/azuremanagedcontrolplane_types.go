@@ -30,6 +30,25 @@ const (
 
 	// PrivateDNSZoneModeNone represents mode None for azuremanagedcontrolplane.
 	PrivateDNSZoneModeNone string = "None"
+
+	// OutboundTypeLoadBalancer routes cluster egress through the AKS managed load balancer.
+	OutboundTypeLoadBalancer string = "loadBalancer"
+
+	// OutboundTypeUserDefinedRouting routes cluster egress through the route table associated with the node subnet.
+	OutboundTypeUserDefinedRouting string = "userDefinedRouting"
+
+	// SKUTierFree is the free tier of AKS without a financially backed uptime SLA.
+	SKUTierFree string = "Free"
+
+	// SKUTierPaid is the paid tier of AKS with a financially backed uptime SLA.
+	SKUTierPaid string = "Paid"
+
+	// SKUTierStandard is the current name of the paid tier of AKS with a financially backed uptime SLA.
+	SKUTierStandard string = "Standard"
+
+	// RegisterResourceProvidersAnnotation allows CAPZ to register the subscription with the resource providers an AKS
+	// cluster requires when it is not registered with them yet.
+	RegisterResourceProvidersAnnotation = "azuremanagedcontrolplane.infrastructure.cluster.x-k8s.io/register-resource-providers"
 )
 
 // AzureManagedControlPlaneSpec defines the desired state of AzureManagedControlPlane.
@@ -47,6 +66,11 @@ type AzureManagedControlPlaneSpec struct {
 	// +optional
 	NodeResourceGroupName string `json:"nodeResourceGroupName,omitempty"`
 
+	// DiskEncryptionSetID is the resource ID of the disk encryption set used to encrypt the OS disks of the nodes
+	// with customer-managed keys. It cannot be changed once the cluster is created.
+	// +optional
+	DiskEncryptionSetID *string `json:"diskEncryptionSetID,omitempty"`
+
 	// VirtualNetwork describes the vnet for the AKS cluster. Will be created if it does not exist.
 	// +optional
 	VirtualNetwork ManagedControlPlaneVirtualNetwork `json:"virtualNetwork,omitempty"`
@@ -85,6 +109,15 @@ type AzureManagedControlPlaneSpec struct {
 	// +optional
 	DNSServiceIP *string `json:"dnsServiceIP,omitempty"`
 
+	// DNSPrefix is the DNS prefix of the API server of the managed cluster. It must be between 1 and 54 characters
+	// long, contain only alphanumerics and hyphens, and start and end with an alphanumeric. Defaults to the name of
+	// the AzureManagedControlPlane. Immutable.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=54
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9]$|^[a-zA-Z0-9][-a-zA-Z0-9]{0,52}[a-zA-Z0-9]$`
+	// +optional
+	DNSPrefix *string `json:"dnsPrefix,omitempty"`
+
 	// LoadBalancerSKU is the SKU of the loadBalancer to be provisioned.
 	// +kubebuilder:validation:Enum=Basic;Standard
 	// +optional
@@ -98,7 +131,7 @@ type AzureManagedControlPlaneSpec struct {
 	// +optional
 	AADProfile *AADProfile `json:"aadProfile,omitempty"`
 
-	// SKU is the SKU of the AKS to be provisioned.
+	// SKU is the SKU of the AKS to be provisioned. Defaults to the Free tier.
 	// +optional
 	SKU *SKU `json:"sku,omitempty"`
 
@@ -106,9 +139,211 @@ type AzureManagedControlPlaneSpec struct {
 	// +optional
 	LoadBalancerProfile *LoadBalancerProfile `json:"loadBalancerProfile,omitempty"`
 
+	// OutboundType is the outbound (egress) routing method of the cluster. Defaults to loadBalancer.
+	// When set to userDefinedRouting, the node subnet must be associated with a route table that has a
+	// default route (0.0.0.0/0) before the cluster is created.
+	// +kubebuilder:validation:Enum=loadBalancer;userDefinedRouting
+	// +optional
+	OutboundType *string `json:"outboundType,omitempty"`
+
 	// APIServerAccessProfile is the access profile for AKS API server.
 	// +optional
 	APIServerAccessProfile *APIServerAccessProfile `json:"apiServerAccessProfile,omitempty"`
+
+	// KubeconfigSecret configures the secrets the kubeconfig of the cluster is stored in.
+	// +optional
+	KubeconfigSecret *KubeconfigSecret `json:"kubeconfigSecret,omitempty"`
+
+	// MaintenanceWindow constrains when AKS performs planned maintenance, such as auto-upgrades and node image
+	// updates, on the cluster.
+	// +optional
+	MaintenanceWindow *MaintenanceWindow `json:"maintenanceWindow,omitempty"`
+
+	// NodeOSUpgradeMaintenanceWindow constrains when AKS upgrades the OS of the nodes of the cluster, separately from
+	// the planned maintenance constrained by MaintenanceWindow.
+	// +optional
+	NodeOSUpgradeMaintenanceWindow *MaintenanceWindow `json:"nodeOSUpgradeMaintenanceWindow,omitempty"`
+
+	// DisableLocalAccounts disables getting static credentials for the cluster, so that users can only authenticate
+	// with Azure Active Directory. It requires a managed aadProfile. When set, the kubeconfig of the cluster is
+	// fetched with the user credentials instead of the admin credentials.
+	// +optional
+	DisableLocalAccounts *bool `json:"disableLocalAccounts,omitempty"`
+
+	// CreateTimeout is how long to wait for the cluster to be created. When the cluster is still not provisioned
+	// after the timeout, the CreateTimedOut condition is set with the last known provisioning state and the
+	// control plane is no longer requeued. Defaults to waiting indefinitely.
+	// +optional
+	CreateTimeout *metav1.Duration `json:"createTimeout,omitempty"`
+
+	// AutoScalerProfile tunes the cluster autoscaler of the agent pools with autoscaling enabled. Fields left unset
+	// keep their AKS defaults.
+	// +optional
+	AutoScalerProfile *AutoScalerProfile `json:"autoScalerProfile,omitempty"`
+
+	// EgressCheck runs a Job in the workload cluster that verifies egress through the user-defined routes works
+	// before the control plane is marked ready. The result is reported in the EgressVerified condition. It requires
+	// the userDefinedRouting outbound type.
+	// +optional
+	EgressCheck *EgressCheck `json:"egressCheck,omitempty"`
+
+	// AddonProfiles are the profiles of managed cluster add-ons, such as httpApplicationRouting. Add-ons not listed
+	// keep the state they have in AKS.
+	// +optional
+	AddonProfiles []AddonProfile `json:"addonProfiles,omitempty"`
+
+	// NodeResourceGroupRoleAssignmentPrincipalIDs are the object IDs of the principals, such as the kubelet identity
+	// of the cluster or a service principal of a workload, that are assigned the Contributor role at the node resource
+	// group once the managed cluster is created.
+	// +optional
+	NodeResourceGroupRoleAssignmentPrincipalIDs []string `json:"nodeResourceGroupRoleAssignmentPrincipalIDs,omitempty"`
+}
+
+// AddonProfile represents a managed cluster add-on.
+type AddonProfile struct {
+	// Name - The name of the managed cluster add-on.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Config - Key-value pairs for configuring the add-on.
+	// +optional
+	Config map[string]string `json:"config,omitempty"`
+
+	// Enabled - Whether the add-on is enabled or not.
+	Enabled bool `json:"enabled"`
+}
+
+// EgressCheck defines the Job that verifies the egress of a cluster with user-defined routing.
+type EgressCheck struct {
+	// URL is the http or https URL the Job fetches to verify egress, such as an endpoint allowed by the firewall.
+	// +kubebuilder:validation:MinLength=1
+	URL string `json:"url"`
+
+	// Image is the container image of the Job, which must provide curl. Defaults to mcr.microsoft.com/azure-cli.
+	// +optional
+	Image *string `json:"image,omitempty"`
+}
+
+// AutoScalerProfile - the parameters of the cluster autoscaler. AKS takes all values as strings.
+type AutoScalerProfile struct {
+	// BalanceSimilarNodeGroups - whether to balance the node count between agent pools with the same VM size and labels.
+	// +kubebuilder:validation:Enum="true";"false"
+	// +optional
+	BalanceSimilarNodeGroups *string `json:"balanceSimilarNodeGroups,omitempty"`
+
+	// Expander - the strategy to select the agent pool to scale up. Possible values include: least-waste, most-pods, priority, random.
+	// +kubebuilder:validation:Enum=least-waste;most-pods;priority;random
+	// +optional
+	Expander *string `json:"expander,omitempty"`
+
+	// MaxEmptyBulkDelete - the maximum number of empty nodes deleted at the same time, such as "10".
+	// +optional
+	MaxEmptyBulkDelete *string `json:"maxEmptyBulkDelete,omitempty"`
+
+	// MaxGracefulTerminationSec - the maximum number of seconds to wait for the pods of a node to terminate when scaling it down, such as "600".
+	// +optional
+	MaxGracefulTerminationSec *string `json:"maxGracefulTerminationSec,omitempty"`
+
+	// MaxNodeProvisionTime - the maximum time to wait for a node to be provisioned, such as "15m".
+	// +optional
+	MaxNodeProvisionTime *string `json:"maxNodeProvisionTime,omitempty"`
+
+	// MaxTotalUnreadyPercentage - the maximum percentage of unready nodes, from 0 to 100, above which the autoscaler stops, such as "45".
+	// +optional
+	MaxTotalUnreadyPercentage *string `json:"maxTotalUnreadyPercentage,omitempty"`
+
+	// NewPodScaleUpDelay - how long to ignore unscheduled pods after they are created, such as "0s".
+	// +optional
+	NewPodScaleUpDelay *string `json:"newPodScaleUpDelay,omitempty"`
+
+	// OkTotalUnreadyCount - the number of unready nodes allowed regardless of MaxTotalUnreadyPercentage, such as "3".
+	// +optional
+	OkTotalUnreadyCount *string `json:"okTotalUnreadyCount,omitempty"`
+
+	// ScanInterval - how often the cluster is evaluated for scaling up or down, such as "10s".
+	// +optional
+	ScanInterval *string `json:"scanInterval,omitempty"`
+
+	// ScaleDownDelayAfterAdd - how long after a scale up scale down evaluation resumes, such as "10m".
+	// +optional
+	ScaleDownDelayAfterAdd *string `json:"scaleDownDelayAfterAdd,omitempty"`
+
+	// ScaleDownDelayAfterDelete - how long after a node deletion scale down evaluation resumes, such as "10s". Defaults to ScanInterval.
+	// +optional
+	ScaleDownDelayAfterDelete *string `json:"scaleDownDelayAfterDelete,omitempty"`
+
+	// ScaleDownDelayAfterFailure - how long after a scale down failure scale down evaluation resumes, such as "3m".
+	// +optional
+	ScaleDownDelayAfterFailure *string `json:"scaleDownDelayAfterFailure,omitempty"`
+
+	// ScaleDownUnneededTime - how long a node must be unneeded before it is eligible for scale down, such as "10m".
+	// +optional
+	ScaleDownUnneededTime *string `json:"scaleDownUnneededTime,omitempty"`
+
+	// ScaleDownUnreadyTime - how long an unready node must be unneeded before it is eligible for scale down, such as "20m".
+	// +optional
+	ScaleDownUnreadyTime *string `json:"scaleDownUnreadyTime,omitempty"`
+
+	// ScaleDownUtilizationThreshold - the ratio, from 0 to 1, of requested resources to capacity below which a node is considered for scale down, such as "0.5".
+	// +optional
+	ScaleDownUtilizationThreshold *string `json:"scaleDownUtilizationThreshold,omitempty"`
+
+	// SkipNodesWithLocalStorage - whether to never delete nodes with pods with local storage, such as emptyDir or hostPath volumes.
+	// +kubebuilder:validation:Enum="true";"false"
+	// +optional
+	SkipNodesWithLocalStorage *string `json:"skipNodesWithLocalStorage,omitempty"`
+
+	// SkipNodesWithSystemPods - whether to never delete nodes with pods from kube-system, except for DaemonSet or mirror pods.
+	// +kubebuilder:validation:Enum="true";"false"
+	// +optional
+	SkipNodesWithSystemPods *string `json:"skipNodesWithSystemPods,omitempty"`
+}
+
+// MaintenanceWindow - the time slots in which AKS may perform planned maintenance.
+type MaintenanceWindow struct {
+	// TimeInWeek - the days of the week and the hours of those days in which maintenance is allowed.
+	// +optional
+	TimeInWeek []TimeInWeek `json:"timeInWeek,omitempty"`
+
+	// NotAllowedTime - the time spans in which maintenance is not allowed, such as holidays.
+	// +optional
+	NotAllowedTime []TimeSpan `json:"notAllowedTime,omitempty"`
+}
+
+// TimeInWeek - the hours of a day of the week in which maintenance is allowed.
+type TimeInWeek struct {
+	// Day - the day of the week. Possible values include: Sunday, Monday, Tuesday, Wednesday, Thursday, Friday, Saturday.
+	// +kubebuilder:validation:Enum=Sunday;Monday;Tuesday;Wednesday;Thursday;Friday;Saturday
+	Day string `json:"day"`
+
+	// HourSlots - the hours of the day, from 0 to 23 in UTC, in which maintenance may start.
+	// +kubebuilder:validation:MinItems=1
+	HourSlots []int32 `json:"hourSlots"`
+}
+
+// TimeSpan - a time span with a start and an end.
+type TimeSpan struct {
+	// Start - the start of the time span.
+	Start metav1.Time `json:"start"`
+
+	// End - the end of the time span. It must be after the start.
+	End metav1.Time `json:"end"`
+}
+
+// KubeconfigSecret configures the secrets the kubeconfig of the cluster is stored in.
+type KubeconfigSecret struct {
+	// Name is the name of an additional secret the kubeconfig is stored in, for tools that expect the kubeconfig in a
+	// secret of their choosing. The <cluster name>-kubeconfig secret Cluster API relies on is always created.
+	// +optional
+	Name *string `json:"name,omitempty"`
+
+	// Labels are additional labels set on the kubeconfig secrets.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are additional annotations set on the kubeconfig secrets.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
 // AADProfile - AAD integration managed by AKS.
@@ -118,14 +353,23 @@ type AADProfile struct {
 	Managed bool `json:"managed"`
 
 	// AdminGroupObjectIDs - AAD group object IDs that will have admin role of the cluster.
-	// +kubebuilder:validation:Required
-	AdminGroupObjectIDs []string `json:"adminGroupObjectIDs"`
+	// At least one group is required when Azure RBAC is disabled.
+	// +optional
+	AdminGroupObjectIDs []string `json:"adminGroupObjectIDs,omitempty"`
+
+	// TenantID - the AAD tenant ID to use for authentication. Defaults to the tenant of the subscription.
+	// +optional
+	TenantID string `json:"tenantID,omitempty"`
+
+	// EnableAzureRBAC - Whether to use Azure RBAC for Kubernetes authorization. Defaults to the value of Managed.
+	// +optional
+	EnableAzureRBAC *bool `json:"enableAzureRBAC,omitempty"`
 }
 
 // SKU - AKS SKU.
 type SKU struct {
-	// Tier - Tier of a managed cluster SKU.
-	// +kubebuilder:validation:Enum=Free;Paid
+	// Tier - Tier of a managed cluster SKU. Standard is accepted as the current name of the Paid tier.
+	// +kubebuilder:validation:Enum=Free;Paid;Standard
 	Tier string `json:"tier"`
 }
 
@@ -180,6 +424,10 @@ type ManagedControlPlaneVirtualNetwork struct {
 	CIDRBlock string `json:"cidrBlock"`
 	// +optional
 	Subnet ManagedControlPlaneSubnet `json:"subnet,omitempty"`
+	// PodSubnets are subnets of the virtual network which agent pools allocate the IPs of their pods from, when they
+	// set podSubnetName to the name of one of them. They are created along with the virtual network.
+	// +optional
+	PodSubnets []ManagedControlPlaneSubnet `json:"podSubnets,omitempty"`
 }
 
 // ManagedControlPlaneSubnet describes a subnet for an AKS cluster.
@@ -204,6 +452,15 @@ type AzureManagedControlPlaneStatus struct {
 	// next reconciliation loop.
 	// +optional
 	LongRunningOperationStates infrav1.Futures `json:"longRunningOperationStates,omitempty"`
+
+	// Conditions defines current service state of the AzureManagedControlPlane.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+
+	// RoleAssignmentCreateAttempts is the number of consecutive reconciles in which the role assignments at the node
+	// resource group could not be created because of an error that is not expected to go away on its own.
+	// +optional
+	RoleAssignmentCreateAttempts int32 `json:"roleAssignmentCreateAttempts,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -229,6 +486,16 @@ type AzureManagedControlPlaneList struct {
 	Items           []AzureManagedControlPlane `json:"items"`
 }
 
+// GetConditions returns the list of conditions for an AzureManagedControlPlane API object.
+func (m *AzureManagedControlPlane) GetConditions() clusterv1.Conditions {
+	return m.Status.Conditions
+}
+
+// SetConditions will set the given conditions on an AzureManagedControlPlane object.
+func (m *AzureManagedControlPlane) SetConditions(conditions clusterv1.Conditions) {
+	m.Status.Conditions = conditions
+}
+
 // GetFutures returns the list of long running operation states for an AzureManagedControlPlane API object.
 func (m *AzureManagedControlPlane) GetFutures() infrav1.Futures {
 	return m.Status.LongRunningOperationStates
@@ -18,14 +18,22 @@ package v1beta1
 
 import (
 	"errors"
+	"fmt"
+	"math"
 	"net"
+	"net/url"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
+	autorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/google/uuid"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -39,6 +47,11 @@ var azuremanagedcontrolplanelog = logf.Log.WithName("azuremanagedcontrolplane-re
 
 var kubeSemver = regexp.MustCompile(`^v(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)([-0-9a-zA-Z_\.+]*)?$`)
 
+// nodeResourceGroupName matches the names of resource groups, which cannot end with a period.
+var nodeResourceGroupName = regexp.MustCompile(`^[-\w\.\(\)]*[-\w\(\)]$`)
+
+var dnsPrefix = regexp.MustCompile(`^[a-zA-Z0-9]$|^[a-zA-Z0-9][-a-zA-Z0-9]{0,52}[a-zA-Z0-9]$`)
+
 // SetupWebhookWithManager sets up and registers the webhook with the manager.
 func (r *AzureManagedControlPlane) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
@@ -123,6 +136,14 @@ func (r *AzureManagedControlPlane) ValidateUpdate(oldRaw runtime.Object) error {
 				"field is immutable"))
 	}
 
+	if !reflect.DeepEqual(r.Spec.DiskEncryptionSetID, old.Spec.DiskEncryptionSetID) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "DiskEncryptionSetID"),
+				r.Spec.DiskEncryptionSetID,
+				"field is immutable"))
+	}
+
 	if r.Spec.Location != old.Spec.Location {
 		allErrs = append(allErrs,
 			field.Invalid(
@@ -218,6 +239,25 @@ func (r *AzureManagedControlPlane) ValidateUpdate(oldRaw runtime.Object) error {
 		}
 	}
 
+	if old.Spec.OutboundType != nil {
+		// Prevent OutboundType modification if it was already set to some value
+		if r.Spec.OutboundType == nil {
+			// unsetting the field is not allowed
+			allErrs = append(allErrs,
+				field.Invalid(
+					field.NewPath("Spec", "OutboundType"),
+					r.Spec.OutboundType,
+					"field is immutable, unsetting is not allowed"))
+		} else if *r.Spec.OutboundType != *old.Spec.OutboundType {
+			// changing the field is not allowed
+			allErrs = append(allErrs,
+				field.Invalid(
+					field.NewPath("Spec", "OutboundType"),
+					*r.Spec.OutboundType,
+					"field is immutable"))
+		}
+	}
+
 	if old.Spec.AADProfile != nil {
 		if r.Spec.AADProfile == nil {
 			allErrs = append(allErrs,
@@ -233,16 +273,38 @@ func (r *AzureManagedControlPlane) ValidateUpdate(oldRaw runtime.Object) error {
 						r.Spec.AADProfile.Managed,
 						"cannot set AADProfile.Managed to false"))
 			}
-			if len(r.Spec.AADProfile.AdminGroupObjectIDs) == 0 {
-				allErrs = append(allErrs,
-					field.Invalid(
-						field.NewPath("Spec", "AADProfile.AdminGroupObjectIDs"),
-						r.Spec.AADProfile.AdminGroupObjectIDs,
-						"length of AADProfile.AdminGroupObjectIDs cannot be zero"))
-			}
 		}
 	}
 
+	if old.Spec.KubeconfigSecret != nil && old.Spec.KubeconfigSecret.Name != nil {
+		// Prevent KubeconfigSecret.Name modification if it was already set to some value, as the secret of the old name
+		// would be left behind.
+		if r.Spec.KubeconfigSecret == nil || r.Spec.KubeconfigSecret.Name == nil {
+			// unsetting the field is not allowed
+			allErrs = append(allErrs,
+				field.Invalid(
+					field.NewPath("Spec", "KubeconfigSecret", "Name"),
+					nil,
+					"field is immutable, unsetting is not allowed"))
+		} else if *r.Spec.KubeconfigSecret.Name != *old.Spec.KubeconfigSecret.Name {
+			// changing the field is not allowed
+			allErrs = append(allErrs,
+				field.Invalid(
+					field.NewPath("Spec", "KubeconfigSecret", "Name"),
+					*r.Spec.KubeconfigSecret.Name,
+					"field is immutable"))
+		}
+	}
+
+	// The DNS prefix defaults to the name of the control plane, so only changes of the DNS prefix in effect are rejected.
+	if r.effectiveDNSPrefix() != old.effectiveDNSPrefix() {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "DNSPrefix"),
+				r.Spec.DNSPrefix,
+				"field is immutable"))
+	}
+
 	if errs := r.validateAPIServerAccessProfileUpdate(old); len(errs) > 0 {
 		allErrs = append(allErrs, errs...)
 	}
@@ -266,9 +328,22 @@ func (r *AzureManagedControlPlane) Validate() error {
 	validators := []func() error{
 		r.validateVersion,
 		r.validateDNSServiceIP,
+		r.validateDNSPrefix,
+		r.validateNodeResourceGroupName,
+		r.validateKubeconfigSecret,
+		r.validateDiskEncryptionSetID,
 		r.validateSSHKey,
 		r.validateLoadBalancerProfile,
+		r.validateOutboundType,
 		r.validateAPIServerAccessProfile,
+		r.validateMaintenanceWindow,
+		r.validateAADProfile,
+		r.validateDisableLocalAccounts,
+		r.validateAutoScalerProfile,
+		r.validateEgressCheck,
+		r.validateAddonProfiles,
+		r.validateNodeResourceGroupRoleAssignmentPrincipalIDs,
+		r.validatePodSubnets,
 	}
 
 	var errs []error
@@ -292,6 +367,77 @@ func (r *AzureManagedControlPlane) validateDNSServiceIP() error {
 	return nil
 }
 
+// validateDNSPrefix validates that the DNS prefix is between 1 and 54 characters long, contains only alphanumerics and
+// hyphens, and starts and ends with an alphanumeric.
+func (r *AzureManagedControlPlane) validateDNSPrefix() error {
+	if r.Spec.DNSPrefix != nil && !dnsPrefix.MatchString(*r.Spec.DNSPrefix) {
+		return field.Invalid(field.NewPath("Spec", "DNSPrefix"), *r.Spec.DNSPrefix,
+			"must be between 1 and 54 characters long, contain only alphanumerics and hyphens, and start and end with an alphanumeric")
+	}
+
+	return nil
+}
+
+// validateNodeResourceGroupName validates the name of the node resource group, which AKS limits to 80 characters and
+// requires to differ from the resource group of the cluster. The length and characters of the defaulted name are not
+// validated, as AKS shortens it when it is too long.
+func (r *AzureManagedControlPlane) validateNodeResourceGroupName() error {
+	if r.Spec.NodeResourceGroupName == "" {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("Spec", "NodeResourceGroupName")
+	if r.Spec.NodeResourceGroupName != r.defaultNodeResourceGroupName() &&
+		(len(r.Spec.NodeResourceGroupName) > 80 || !nodeResourceGroupName.MatchString(r.Spec.NodeResourceGroupName)) {
+		allErrs = append(allErrs, field.Invalid(fldPath, r.Spec.NodeResourceGroupName,
+			"must be at most 80 characters long, contain only alphanumerics, underscores, parentheses, hyphens and periods, and not end with a period"))
+	}
+	if strings.EqualFold(r.Spec.NodeResourceGroupName, r.Spec.ResourceGroupName) {
+		allErrs = append(allErrs, field.Invalid(fldPath, r.Spec.NodeResourceGroupName,
+			"must differ from the resource group of the cluster"))
+	}
+
+	return allErrs.ToAggregate()
+}
+
+// validateKubeconfigSecret validates that the name of the additional kubeconfig secret is a valid secret name.
+func (r *AzureManagedControlPlane) validateKubeconfigSecret() error {
+	if r.Spec.KubeconfigSecret == nil || r.Spec.KubeconfigSecret.Name == nil {
+		return nil
+	}
+
+	if errs := validation.IsDNS1123Subdomain(*r.Spec.KubeconfigSecret.Name); len(errs) > 0 {
+		return field.Invalid(field.NewPath("Spec", "KubeconfigSecret", "Name"), *r.Spec.KubeconfigSecret.Name, strings.Join(errs, ", "))
+	}
+
+	return nil
+}
+
+// validateDiskEncryptionSetID validates that the DiskEncryptionSetID is the resource ID of a disk encryption set.
+func (r *AzureManagedControlPlane) validateDiskEncryptionSetID() error {
+	if r.Spec.DiskEncryptionSetID == nil {
+		return nil
+	}
+
+	resource, err := autorest.ParseResourceID(*r.Spec.DiskEncryptionSetID)
+	if err != nil || !strings.EqualFold(resource.Provider, "Microsoft.Compute") || !strings.EqualFold(resource.ResourceType, "diskEncryptionSets") {
+		return field.Invalid(
+			field.NewPath("Spec", "DiskEncryptionSetID"),
+			*r.Spec.DiskEncryptionSetID,
+			"must be the resource ID of a disk encryption set, e.g. /subscriptions/<subscription>/resourceGroups/<resource group>/providers/Microsoft.Compute/diskEncryptionSets/<name>")
+	}
+	return nil
+}
+
+// effectiveDNSPrefix returns the DNS prefix of the managed cluster, which defaults to the name of the control plane.
+func (r *AzureManagedControlPlane) effectiveDNSPrefix() string {
+	if r.Spec.DNSPrefix != nil {
+		return *r.Spec.DNSPrefix
+	}
+	return r.Name
+}
+
 func (r *AzureManagedControlPlane) validateVersion() error {
 	if !kubeSemver.MatchString(r.Spec.Version) {
 		return errors.New("must be a valid semantic version")
@@ -373,6 +519,7 @@ func (r *AzureManagedControlPlane) validateAPIServerAccessProfile() error {
 				allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "APIServerAccessProfile", "AuthorizedIPRanges"), ipRange, "invalid CIDR format"))
 			}
 		}
+		allErrs = append(allErrs, r.validatePrivateClusterAccess()...)
 		if len(allErrs) > 0 {
 			agg := kerrors.NewAggregate(allErrs.ToAggregate().Errors())
 			azuremanagedcontrolplanelog.Info("Invalid apiServerAccessProfile: %s", agg.Error())
@@ -382,6 +529,152 @@ func (r *AzureManagedControlPlane) validateAPIServerAccessProfile() error {
 	return nil
 }
 
+// validatePrivateClusterAccess rejects APIServerAccessProfile settings that conflict with whether the cluster is private.
+// Authorized IP ranges only apply to a public API server, while the private DNS zone and public FQDN only apply to a private one.
+func (r *AzureManagedControlPlane) validatePrivateClusterAccess() field.ErrorList {
+	var allErrs field.ErrorList
+	profile := r.Spec.APIServerAccessProfile
+	fldPath := field.NewPath("Spec", "APIServerAccessProfile")
+
+	if profile.EnablePrivateCluster != nil && *profile.EnablePrivateCluster {
+		if len(profile.AuthorizedIPRanges) > 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("AuthorizedIPRanges"), "authorized IP ranges cannot be set when EnablePrivateCluster is true"))
+		}
+		return allErrs
+	}
+
+	if profile.PrivateDNSZone != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("PrivateDNSZone"), "PrivateDNSZone can only be set when EnablePrivateCluster is true"))
+	}
+	if profile.EnablePrivateClusterPublicFQDN != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("EnablePrivateClusterPublicFQDN"), "EnablePrivateClusterPublicFQDN can only be set when EnablePrivateCluster is true"))
+	}
+	return allErrs
+}
+
+// validateMaintenanceWindow validates the days, hour slots and time spans of the MaintenanceWindow and the
+// NodeOSUpgradeMaintenanceWindow.
+func (r *AzureManagedControlPlane) validateMaintenanceWindow() error {
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, validateMaintenanceWindowFields(r.Spec.MaintenanceWindow, field.NewPath("Spec", "MaintenanceWindow"))...)
+	allErrs = append(allErrs, validateMaintenanceWindowFields(r.Spec.NodeOSUpgradeMaintenanceWindow, field.NewPath("Spec", "NodeOSUpgradeMaintenanceWindow"))...)
+
+	if len(allErrs) > 0 {
+		agg := kerrors.NewAggregate(allErrs.ToAggregate().Errors())
+		azuremanagedcontrolplanelog.Info("Invalid maintenanceWindow: %s", agg.Error())
+		return agg
+	}
+	return nil
+}
+
+// validateMaintenanceWindowFields validates the days, hour slots and time spans of a MaintenanceWindow.
+func validateMaintenanceWindowFields(window *MaintenanceWindow, fldPath *field.Path) field.ErrorList {
+	if window == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	if len(window.TimeInWeek) == 0 && len(window.NotAllowedTime) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath, "either TimeInWeek or NotAllowedTime must be set"))
+	}
+
+	days := make(map[string]bool)
+	for i, timeInWeek := range window.TimeInWeek {
+		timeInWeekPath := fldPath.Child("TimeInWeek").Index(i)
+		if days[timeInWeek.Day] {
+			allErrs = append(allErrs, field.Duplicate(timeInWeekPath.Child("Day"), timeInWeek.Day))
+		}
+		days[timeInWeek.Day] = true
+
+		if len(timeInWeek.HourSlots) == 0 {
+			allErrs = append(allErrs, field.Required(timeInWeekPath.Child("HourSlots"), "at least one hour slot must be set"))
+		}
+		hourSlots := make(map[int32]bool)
+		for j, hourSlot := range timeInWeek.HourSlots {
+			if hourSlot < 0 || hourSlot > 23 {
+				allErrs = append(allErrs, field.Invalid(timeInWeekPath.Child("HourSlots").Index(j), hourSlot, "hour slot must be between 0 and 23"))
+			} else if hourSlots[hourSlot] {
+				allErrs = append(allErrs, field.Duplicate(timeInWeekPath.Child("HourSlots").Index(j), hourSlot))
+			}
+			hourSlots[hourSlot] = true
+		}
+	}
+
+	for i, timeSpan := range window.NotAllowedTime {
+		if !timeSpan.End.After(timeSpan.Start.Time) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("NotAllowedTime").Index(i).Child("End"), timeSpan.End.String(), "end of the time span must be after its start"))
+		}
+	}
+	return allErrs
+}
+
+// validateAADProfile validates that managed AAD without Azure RBAC has an admin group, as there would be no other way
+// to grant cluster admin permissions, and that Azure RBAC and the tenant are only set for managed AAD.
+func (r *AzureManagedControlPlane) validateAADProfile() error {
+	aadProfile := r.Spec.AADProfile
+	if aadProfile == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("Spec", "AADProfile")
+	enableAzureRBAC := aadProfile.Managed
+	if aadProfile.EnableAzureRBAC != nil {
+		enableAzureRBAC = *aadProfile.EnableAzureRBAC
+	}
+	if aadProfile.Managed && !enableAzureRBAC && len(aadProfile.AdminGroupObjectIDs) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("AdminGroupObjectIDs"), "at least one admin group is required when Azure RBAC is disabled"))
+	}
+	if !aadProfile.Managed && enableAzureRBAC {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("EnableAzureRBAC"), "EnableAzureRBAC can only be set when Managed is true"))
+	}
+	if aadProfile.TenantID != "" {
+		if !aadProfile.Managed {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("TenantID"), "TenantID can only be set when Managed is true"))
+		} else if _, err := uuid.Parse(aadProfile.TenantID); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("TenantID"), aadProfile.TenantID, "TenantID must be a valid UUID"))
+		}
+	}
+
+	if len(allErrs) > 0 {
+		return allErrs.ToAggregate()
+	}
+	return nil
+}
+
+// validateDisableLocalAccounts validates that local accounts are only disabled for clusters with managed AAD, as there
+// would be no other way to authenticate to the cluster.
+func (r *AzureManagedControlPlane) validateDisableLocalAccounts() error {
+	if r.Spec.DisableLocalAccounts == nil || !*r.Spec.DisableLocalAccounts {
+		return nil
+	}
+
+	if r.Spec.AADProfile == nil || !r.Spec.AADProfile.Managed {
+		return field.Forbidden(field.NewPath("Spec", "DisableLocalAccounts"), "DisableLocalAccounts can only be set when AADProfile.Managed is true")
+	}
+	return nil
+}
+
+// validateOutboundType validates that a cluster routing egress through a user-defined route table uses a Standard
+// load balancer and does not configure the outbound IPs of the load balancer, which AKS then does not manage.
+func (r *AzureManagedControlPlane) validateOutboundType() error {
+	if r.Spec.OutboundType == nil || *r.Spec.OutboundType != OutboundTypeUserDefinedRouting {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	if r.Spec.LoadBalancerSKU != nil && *r.Spec.LoadBalancerSKU != "Standard" {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "LoadBalancerSKU"), *r.Spec.LoadBalancerSKU,
+			fmt.Sprintf("OutboundType %s requires the Standard load balancer SKU", OutboundTypeUserDefinedRouting)))
+	}
+	if r.Spec.LoadBalancerProfile != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("Spec", "LoadBalancerProfile"),
+			fmt.Sprintf("LoadBalancerProfile cannot be set when OutboundType is %s", OutboundTypeUserDefinedRouting)))
+	}
+
+	return allErrs.ToAggregate()
+}
+
 // validateAPIServerAccessProfileUpdate validates update to APIServerAccessProfile.
 func (r *AzureManagedControlPlane) validateAPIServerAccessProfileUpdate(old *AzureManagedControlPlane) field.ErrorList {
 	var allErrs field.ErrorList
@@ -412,3 +705,138 @@ func (r *AzureManagedControlPlane) validateAPIServerAccessProfileUpdate(old *Azu
 
 	return allErrs
 }
+
+// validateAutoScalerProfile validates that the numeric and duration fields of the AutoScalerProfile are in the form
+// AKS accepts.
+func (r *AzureManagedControlPlane) validateAutoScalerProfile() error {
+	profile := r.Spec.AutoScalerProfile
+	if profile == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("Spec", "AutoScalerProfile")
+	allErrs = append(allErrs, validateAutoScalerProfileInt(fldPath.Child("MaxEmptyBulkDelete"), profile.MaxEmptyBulkDelete, 1, math.MaxInt32)...)
+	allErrs = append(allErrs, validateAutoScalerProfileInt(fldPath.Child("MaxGracefulTerminationSec"), profile.MaxGracefulTerminationSec, 0, math.MaxInt32)...)
+	allErrs = append(allErrs, validateAutoScalerProfileDuration(fldPath.Child("MaxNodeProvisionTime"), profile.MaxNodeProvisionTime)...)
+	allErrs = append(allErrs, validateAutoScalerProfileInt(fldPath.Child("MaxTotalUnreadyPercentage"), profile.MaxTotalUnreadyPercentage, 0, 100)...)
+	allErrs = append(allErrs, validateAutoScalerProfileDuration(fldPath.Child("NewPodScaleUpDelay"), profile.NewPodScaleUpDelay)...)
+	allErrs = append(allErrs, validateAutoScalerProfileInt(fldPath.Child("OkTotalUnreadyCount"), profile.OkTotalUnreadyCount, 0, math.MaxInt32)...)
+	allErrs = append(allErrs, validateAutoScalerProfileDuration(fldPath.Child("ScanInterval"), profile.ScanInterval)...)
+	allErrs = append(allErrs, validateAutoScalerProfileDuration(fldPath.Child("ScaleDownDelayAfterAdd"), profile.ScaleDownDelayAfterAdd)...)
+	allErrs = append(allErrs, validateAutoScalerProfileDuration(fldPath.Child("ScaleDownDelayAfterDelete"), profile.ScaleDownDelayAfterDelete)...)
+	allErrs = append(allErrs, validateAutoScalerProfileDuration(fldPath.Child("ScaleDownDelayAfterFailure"), profile.ScaleDownDelayAfterFailure)...)
+	allErrs = append(allErrs, validateAutoScalerProfileDuration(fldPath.Child("ScaleDownUnneededTime"), profile.ScaleDownUnneededTime)...)
+	allErrs = append(allErrs, validateAutoScalerProfileDuration(fldPath.Child("ScaleDownUnreadyTime"), profile.ScaleDownUnreadyTime)...)
+	allErrs = append(allErrs, validateAutoScalerProfileRatio(fldPath.Child("ScaleDownUtilizationThreshold"), profile.ScaleDownUtilizationThreshold)...)
+
+	if len(allErrs) > 0 {
+		agg := kerrors.NewAggregate(allErrs.ToAggregate().Errors())
+		azuremanagedcontrolplanelog.Info("Invalid autoScalerProfile: %s", agg.Error())
+		return agg
+	}
+
+	return nil
+}
+
+// validateAutoScalerProfileInt validates that the value is an integer between min and max.
+func validateAutoScalerProfileInt(fldPath *field.Path, value *string, min, max int64) field.ErrorList {
+	if value == nil {
+		return nil
+	}
+	if n, err := strconv.ParseInt(*value, 10, 32); err != nil || n < min || n > max {
+		return field.ErrorList{field.Invalid(fldPath, *value, fmt.Sprintf("value must be an integer between %d and %d", min, max))}
+	}
+	return nil
+}
+
+// validateAutoScalerProfileRatio validates that the value is a number between 0 and 1.
+func validateAutoScalerProfileRatio(fldPath *field.Path, value *string) field.ErrorList {
+	if value == nil {
+		return nil
+	}
+	if f, err := strconv.ParseFloat(*value, 64); err != nil || f < 0 || f > 1 {
+		return field.ErrorList{field.Invalid(fldPath, *value, "value must be a number between 0 and 1")}
+	}
+	return nil
+}
+
+// validateAutoScalerProfileDuration validates that the value is a non-negative duration with a unit, such as 10m.
+func validateAutoScalerProfileDuration(fldPath *field.Path, value *string) field.ErrorList {
+	if value == nil {
+		return nil
+	}
+	if d, err := time.ParseDuration(*value); err != nil || d < 0 {
+		return field.ErrorList{field.Invalid(fldPath, *value, "value must be a non-negative duration, such as 10m")}
+	}
+	return nil
+}
+
+// validateEgressCheck validates that the egress check is only set for clusters with user-defined routing, and that
+// it fetches an http or https URL.
+func (r *AzureManagedControlPlane) validateEgressCheck() error {
+	if r.Spec.EgressCheck == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("Spec", "EgressCheck")
+	if r.Spec.OutboundType == nil || *r.Spec.OutboundType != OutboundTypeUserDefinedRouting {
+		allErrs = append(allErrs, field.Forbidden(fldPath,
+			fmt.Sprintf("EgressCheck can only be set when OutboundType is %s", OutboundTypeUserDefinedRouting)))
+	}
+	if u, err := url.Parse(r.Spec.EgressCheck.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("URL"), r.Spec.EgressCheck.URL, "URL must be an http or https URL"))
+	}
+
+	return allErrs.ToAggregate()
+}
+
+// validateAddonProfiles validates that every add-on is only listed once.
+func (r *AzureManagedControlPlane) validateAddonProfiles() error {
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("Spec", "AddonProfiles")
+	names := map[string]bool{}
+	for i, addon := range r.Spec.AddonProfiles {
+		if names[addon.Name] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i).Child("Name"), addon.Name))
+		}
+		names[addon.Name] = true
+	}
+
+	return allErrs.ToAggregate()
+}
+
+// validateNodeResourceGroupRoleAssignmentPrincipalIDs validates that the principals assigned a role at the node
+// resource group are GUIDs and are only listed once.
+func (r *AzureManagedControlPlane) validateNodeResourceGroupRoleAssignmentPrincipalIDs() error {
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("Spec", "NodeResourceGroupRoleAssignmentPrincipalIDs")
+	seen := map[string]bool{}
+	for i, principalID := range r.Spec.NodeResourceGroupRoleAssignmentPrincipalIDs {
+		if _, err := uuid.Parse(principalID); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), principalID, "principal ID must be a valid GUID"))
+		} else if seen[principalID] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i), principalID))
+		}
+		seen[principalID] = true
+	}
+
+	return allErrs.ToAggregate()
+}
+
+// validatePodSubnets validates that the pod subnets of the virtual network have distinct names, which are not the name
+// of the node subnet.
+func (r *AzureManagedControlPlane) validatePodSubnets() error {
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("Spec", "VirtualNetwork", "PodSubnets")
+	names := map[string]bool{r.Spec.VirtualNetwork.Subnet.Name: true}
+	for i, subnet := range r.Spec.VirtualNetwork.PodSubnets {
+		if names[subnet.Name] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i).Child("Name"), subnet.Name))
+		}
+		names[subnet.Name] = true
+	}
+
+	return allErrs.ToAggregate()
+}
@@ -17,7 +17,9 @@ limitations under the License.
 package v1beta1
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Azure/go-autorest/autorest/to"
 	. "github.com/onsi/gomega"
@@ -73,6 +75,27 @@ func TestDefaultingWebhook(t *testing.T) {
 	g.Expect(amcp.Spec.VirtualNetwork.Subnet.Name).To(Equal("fooSubnetName"))
 }
 
+func TestDefaultedNodeResourceGroupNameIsValid(t *testing.T) {
+	g := NewWithT(t)
+
+	amcp := &AzureManagedControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: strings.Repeat("a", 63),
+		},
+		Spec: AzureManagedControlPlaneSpec{
+			ResourceGroupName: "my-resource-group",
+			Location:          "westus2",
+			Version:           "v1.21.2",
+		},
+	}
+	amcp.Default()
+	g.Expect(len(amcp.Spec.NodeResourceGroupName)).To(BeNumerically(">", 80))
+	g.Expect(amcp.ValidateCreate()).To(Succeed())
+
+	old := amcp.DeepCopy()
+	g.Expect(amcp.ValidateUpdate(old)).To(Succeed())
+}
+
 func TestValidatingWebhook(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -80,139 +103,740 @@ func TestValidatingWebhook(t *testing.T) {
 		expectErr bool
 	}{
 		{
-			name: "Testing valid DNSServiceIP",
+			name: "Testing valid DNSServiceIP",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: pointer.StringPtr("192.168.0.0"),
+					Version:      "v1.17.8",
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "Testing invalid DNSServiceIP",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: pointer.StringPtr("192.168.0.0.3"),
+					Version:      "v1.17.8",
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Invalid Version",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: pointer.StringPtr("192.168.0.0"),
+					Version:      "honk",
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "not following the kuberntes Version pattern",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: pointer.StringPtr("192.168.0.0"),
+					Version:      "1.19.0",
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Version not set",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: pointer.StringPtr("192.168.0.0"),
+					Version:      "",
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Valid Version",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: pointer.StringPtr("192.168.0.0"),
+					Version:      "v1.17.8",
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "Valid Managed AADProfile",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					AADProfile: &AADProfile{
+						Managed: true,
+						AdminGroupObjectIDs: []string{
+							"616077a8-5db7-4c98-b856-b34619afg75h",
+						},
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "Valid LoadBalancerProfile",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					LoadBalancerProfile: &LoadBalancerProfile{
+						ManagedOutboundIPs:     to.Int32Ptr(10),
+						AllocatedOutboundPorts: to.Int32Ptr(1000),
+						IdleTimeoutInMinutes:   to.Int32Ptr(60),
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "Invalid LoadBalancerProfile.ManagedOutboundIPs",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					LoadBalancerProfile: &LoadBalancerProfile{
+						ManagedOutboundIPs: to.Int32Ptr(200),
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Invalid LoadBalancerProfile.AllocatedOutboundPorts",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					LoadBalancerProfile: &LoadBalancerProfile{
+						AllocatedOutboundPorts: to.Int32Ptr(80000),
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Invalid LoadBalancerProfile.IdleTimeoutInMinutes",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					LoadBalancerProfile: &LoadBalancerProfile{
+						IdleTimeoutInMinutes: to.Int32Ptr(600),
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "LoadBalancerProfile must specify at most one of ManagedOutboundIPs, OutboundIPPrefixes and OutboundIPs",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					LoadBalancerProfile: &LoadBalancerProfile{
+						ManagedOutboundIPs: to.Int32Ptr(1),
+						OutboundIPs: []string{
+							"/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/foo-bar/providers/Microsoft.Network/publicIPAddresses/my-public-ip",
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Valid OutboundType userDefinedRouting",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:         "v1.21.2",
+					LoadBalancerSKU: to.StringPtr("Standard"),
+					OutboundType:    to.StringPtr(OutboundTypeUserDefinedRouting),
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "OutboundType userDefinedRouting cannot be set with a LoadBalancerProfile",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:      "v1.21.2",
+					OutboundType: to.StringPtr(OutboundTypeUserDefinedRouting),
+					LoadBalancerProfile: &LoadBalancerProfile{
+						ManagedOutboundIPs: to.Int32Ptr(2),
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "OutboundType userDefinedRouting requires the Standard LoadBalancerSKU",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:         "v1.21.2",
+					LoadBalancerSKU: to.StringPtr("Basic"),
+					OutboundType:    to.StringPtr(OutboundTypeUserDefinedRouting),
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Invalid CIDR for AuthorizedIPRanges",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					APIServerAccessProfile: &APIServerAccessProfile{
+						AuthorizedIPRanges: []string{"1.2.3.400/32"},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "AuthorizedIPRanges with a private cluster",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					APIServerAccessProfile: &APIServerAccessProfile{
+						AuthorizedIPRanges:   []string{"1.2.3.4/32"},
+						EnablePrivateCluster: to.BoolPtr(true),
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "PrivateDNSZone with a public cluster",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					APIServerAccessProfile: &APIServerAccessProfile{
+						PrivateDNSZone: to.StringPtr(PrivateDNSZoneModeSystem),
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "AuthorizedIPRanges with a public cluster",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					APIServerAccessProfile: &APIServerAccessProfile{
+						AuthorizedIPRanges:   []string{"1.2.3.4/32", "10.0.0.0/16"},
+						EnablePrivateCluster: to.BoolPtr(false),
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "Private cluster with PrivateDNSZone and public FQDN",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					APIServerAccessProfile: &APIServerAccessProfile{
+						EnablePrivateCluster:           to.BoolPtr(true),
+						PrivateDNSZone:                 to.StringPtr(PrivateDNSZoneModeNone),
+						EnablePrivateClusterPublicFQDN: to.BoolPtr(true),
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "Valid MaintenanceWindow",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					MaintenanceWindow: &MaintenanceWindow{
+						TimeInWeek: []TimeInWeek{
+							{Day: "Saturday", HourSlots: []int32{0, 1, 2}},
+							{Day: "Sunday", HourSlots: []int32{23}},
+						},
+						NotAllowedTime: []TimeSpan{
+							{
+								Start: metav1.NewTime(time.Date(2021, time.December, 24, 0, 0, 0, 0, time.UTC)),
+								End:   metav1.NewTime(time.Date(2021, time.December, 27, 0, 0, 0, 0, time.UTC)),
+							},
+						},
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "MaintenanceWindow hour slot out of range",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					MaintenanceWindow: &MaintenanceWindow{
+						TimeInWeek: []TimeInWeek{
+							{Day: "Saturday", HourSlots: []int32{24}},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "MaintenanceWindow with a duplicate day",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					MaintenanceWindow: &MaintenanceWindow{
+						TimeInWeek: []TimeInWeek{
+							{Day: "Saturday", HourSlots: []int32{1}},
+							{Day: "Saturday", HourSlots: []int32{2}},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "MaintenanceWindow not allowed time ending before its start",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					MaintenanceWindow: &MaintenanceWindow{
+						NotAllowedTime: []TimeSpan{
+							{
+								Start: metav1.NewTime(time.Date(2021, time.December, 27, 0, 0, 0, 0, time.UTC)),
+								End:   metav1.NewTime(time.Date(2021, time.December, 24, 0, 0, 0, 0, time.UTC)),
+							},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Empty MaintenanceWindow",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:           "v1.21.2",
+					MaintenanceWindow: &MaintenanceWindow{},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Valid NodeOSUpgradeMaintenanceWindow",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					NodeOSUpgradeMaintenanceWindow: &MaintenanceWindow{
+						TimeInWeek: []TimeInWeek{
+							{Day: "Sunday", HourSlots: []int32{0, 1}},
+						},
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "NodeOSUpgradeMaintenanceWindow hour slot out of range",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					NodeOSUpgradeMaintenanceWindow: &MaintenanceWindow{
+						TimeInWeek: []TimeInWeek{
+							{Day: "Sunday", HourSlots: []int32{24}},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "managed AAD with Azure RBAC and a tenant",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					AADProfile: &AADProfile{
+						Managed:         true,
+						EnableAzureRBAC: to.BoolPtr(true),
+						TenantID:        "72f988bf-86f1-41af-91ab-2d7cd011db47",
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "managed AAD without Azure RBAC or admin groups",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					AADProfile: &AADProfile{
+						Managed:         true,
+						EnableAzureRBAC: to.BoolPtr(false),
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Azure RBAC without managed AAD",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					AADProfile: &AADProfile{
+						Managed:         false,
+						EnableAzureRBAC: to.BoolPtr(true),
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "managed AAD with an invalid tenant",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					AADProfile: &AADProfile{
+						Managed:  true,
+						TenantID: "not-a-uuid",
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "DisableLocalAccounts with managed AAD",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:              "v1.21.2",
+					DisableLocalAccounts: to.BoolPtr(true),
+					AADProfile: &AADProfile{
+						Managed:             true,
+						AdminGroupObjectIDs: []string{"616077a8-5db7-4c98-b856-b34619afg75h"},
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "DisableLocalAccounts without AAD",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:              "v1.21.2",
+					DisableLocalAccounts: to.BoolPtr(true),
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "EgressCheck with user-defined routing",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:      "v1.21.2",
+					OutboundType: to.StringPtr(OutboundTypeUserDefinedRouting),
+					EgressCheck: &EgressCheck{
+						URL: "https://mcr.microsoft.com",
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "EgressCheck without user-defined routing",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					EgressCheck: &EgressCheck{
+						URL: "https://mcr.microsoft.com",
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "EgressCheck with an invalid URL",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:      "v1.21.2",
+					OutboundType: to.StringPtr(OutboundTypeUserDefinedRouting),
+					EgressCheck: &EgressCheck{
+						URL: "mcr.microsoft.com",
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "valid DNSPrefix",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:   "v1.21.2",
+					DNSPrefix: to.StringPtr("my-cluster-1"),
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "DNSPrefix starting with a hyphen",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:   "v1.21.2",
+					DNSPrefix: to.StringPtr("-my-cluster"),
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "DNSPrefix with an invalid character",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:   "v1.21.2",
+					DNSPrefix: to.StringPtr("my_cluster"),
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "DNSPrefix longer than 54 characters",
 			amcp: AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP: pointer.StringPtr("192.168.0.0"),
-					Version:      "v1.17.8",
+					Version:   "v1.21.2",
+					DNSPrefix: to.StringPtr(strings.Repeat("a", 55)),
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "valid AutoScalerProfile",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					AutoScalerProfile: &AutoScalerProfile{
+						MaxEmptyBulkDelete:            to.StringPtr("10"),
+						MaxTotalUnreadyPercentage:     to.StringPtr("45"),
+						ScanInterval:                  to.StringPtr("10s"),
+						ScaleDownDelayAfterAdd:        to.StringPtr("10m"),
+						ScaleDownUtilizationThreshold: to.StringPtr("0.5"),
+					},
 				},
 			},
 			expectErr: false,
 		},
 		{
-			name: "Testing invalid DNSServiceIP",
+			name: "AutoScalerProfile with an invalid duration",
 			amcp: AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP: pointer.StringPtr("192.168.0.0.3"),
-					Version:      "v1.17.8",
+					Version: "v1.21.2",
+					AutoScalerProfile: &AutoScalerProfile{
+						ScaleDownUnneededTime: to.StringPtr("10"),
+					},
 				},
 			},
 			expectErr: true,
 		},
 		{
-			name: "Invalid Version",
+			name: "AutoScalerProfile with an out of range percentage",
 			amcp: AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP: pointer.StringPtr("192.168.0.0"),
-					Version:      "honk",
+					Version: "v1.21.2",
+					AutoScalerProfile: &AutoScalerProfile{
+						MaxTotalUnreadyPercentage: to.StringPtr("101"),
+					},
 				},
 			},
 			expectErr: true,
 		},
 		{
-			name: "not following the kuberntes Version pattern",
+			name: "AutoScalerProfile with an out of range utilization threshold",
 			amcp: AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP: pointer.StringPtr("192.168.0.0"),
-					Version:      "1.19.0",
+					Version: "v1.21.2",
+					AutoScalerProfile: &AutoScalerProfile{
+						ScaleDownUtilizationThreshold: to.StringPtr("1.5"),
+					},
 				},
 			},
 			expectErr: true,
 		},
 		{
-			name: "Version not set",
+			name: "custom node resource group name",
 			amcp: AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP: pointer.StringPtr("192.168.0.0"),
-					Version:      "",
+					Version:               "v1.21.2",
+					ResourceGroupName:     "my-rg",
+					NodeResourceGroupName: "my-rg-nodes_(westus2)",
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "node resource group name ending with a period",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:               "v1.21.2",
+					ResourceGroupName:     "my-rg",
+					NodeResourceGroupName: "my-rg-nodes.",
 				},
 			},
 			expectErr: true,
 		},
 		{
-			name: "Valid Version",
+			name: "node resource group name with invalid characters",
 			amcp: AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP: pointer.StringPtr("192.168.0.0"),
-					Version:      "v1.17.8",
+					Version:               "v1.21.2",
+					ResourceGroupName:     "my-rg",
+					NodeResourceGroupName: "my rg/nodes",
 				},
 			},
-			expectErr: false,
+			expectErr: true,
 		},
 		{
-			name: "Valid Managed AADProfile",
+			name: "node resource group name longer than 80 characters",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:               "v1.21.2",
+					ResourceGroupName:     "my-rg",
+					NodeResourceGroupName: strings.Repeat("a", 81),
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "node resource group name same as the resource group",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:               "v1.21.2",
+					ResourceGroupName:     "my-rg",
+					NodeResourceGroupName: "MY-RG",
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "KubeconfigSecret name that is not a valid secret name",
 			amcp: AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
 					Version: "v1.21.2",
-					AADProfile: &AADProfile{
-						Managed: true,
-						AdminGroupObjectIDs: []string{
-							"616077a8-5db7-4c98-b856-b34619afg75h",
-						},
+					KubeconfigSecret: &KubeconfigSecret{
+						Name: to.StringPtr("My_Kubeconfig"),
 					},
 				},
 			},
+			expectErr: true,
+		},
+		{
+			name: "valid DiskEncryptionSetID",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:             "v1.21.2",
+					DiskEncryptionSetID: to.StringPtr("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/my-rg/providers/Microsoft.Compute/diskEncryptionSets/my-des"),
+				},
+			},
 			expectErr: false,
 		},
 		{
-			name: "Valid LoadBalancerProfile",
+			name: "DiskEncryptionSetID of another resource type",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:             "v1.21.2",
+					DiskEncryptionSetID: to.StringPtr("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/my-rg/providers/Microsoft.KeyVault/vaults/my-vault"),
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "DiskEncryptionSetID that is not a resource ID",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:             "v1.21.2",
+					DiskEncryptionSetID: to.StringPtr("my-des"),
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "AddonProfiles with distinct names",
 			amcp: AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
 					Version: "v1.21.2",
-					LoadBalancerProfile: &LoadBalancerProfile{
-						ManagedOutboundIPs:     to.Int32Ptr(10),
-						AllocatedOutboundPorts: to.Int32Ptr(1000),
-						IdleTimeoutInMinutes:   to.Int32Ptr(60),
+					AddonProfiles: []AddonProfile{
+						{Name: "httpApplicationRouting", Enabled: true},
+						{Name: "azurepolicy", Enabled: false},
 					},
 				},
 			},
 			expectErr: false,
 		},
 		{
-			name: "Invalid LoadBalancerProfile.ManagedOutboundIPs",
+			name: "AddonProfiles with a duplicate name",
 			amcp: AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
 					Version: "v1.21.2",
-					LoadBalancerProfile: &LoadBalancerProfile{
-						ManagedOutboundIPs: to.Int32Ptr(200),
+					AddonProfiles: []AddonProfile{
+						{Name: "httpApplicationRouting", Enabled: true},
+						{Name: "httpApplicationRouting", Enabled: false},
 					},
 				},
 			},
 			expectErr: true,
 		},
 		{
-			name: "Invalid LoadBalancerProfile.AllocatedOutboundPorts",
+			name: "NodeResourceGroupRoleAssignmentPrincipalIDs with valid GUIDs",
 			amcp: AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
 					Version: "v1.21.2",
-					LoadBalancerProfile: &LoadBalancerProfile{
-						AllocatedOutboundPorts: to.Int32Ptr(80000),
+					NodeResourceGroupRoleAssignmentPrincipalIDs: []string{
+						"00000000-0000-0000-0000-000000000001",
+						"00000000-0000-0000-0000-000000000002",
 					},
 				},
 			},
+			expectErr: false,
+		},
+		{
+			name: "NodeResourceGroupRoleAssignmentPrincipalIDs with an invalid GUID",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					NodeResourceGroupRoleAssignmentPrincipalIDs: []string{"not-a-guid"},
+				},
+			},
 			expectErr: true,
 		},
 		{
-			name: "Invalid LoadBalancerProfile.IdleTimeoutInMinutes",
+			name: "NodeResourceGroupRoleAssignmentPrincipalIDs with a duplicate",
 			amcp: AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
 					Version: "v1.21.2",
-					LoadBalancerProfile: &LoadBalancerProfile{
-						IdleTimeoutInMinutes: to.Int32Ptr(600),
+					NodeResourceGroupRoleAssignmentPrincipalIDs: []string{
+						"00000000-0000-0000-0000-000000000001",
+						"00000000-0000-0000-0000-000000000001",
 					},
 				},
 			},
 			expectErr: true,
 		},
 		{
-			name: "LoadBalancerProfile must specify at most one of ManagedOutboundIPs, OutboundIPPrefixes and OutboundIPs",
+			name: "PodSubnets with distinct names",
 			amcp: AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
 					Version: "v1.21.2",
-					LoadBalancerProfile: &LoadBalancerProfile{
-						ManagedOutboundIPs: to.Int32Ptr(1),
-						OutboundIPs: []string{
-							"/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/foo-bar/providers/Microsoft.Network/publicIPAddresses/my-public-ip",
+					VirtualNetwork: ManagedControlPlaneVirtualNetwork{
+						Subnet: ManagedControlPlaneSubnet{Name: "my-subnet", CIDRBlock: "10.240.0.0/16"},
+						PodSubnets: []ManagedControlPlaneSubnet{
+							{Name: "my-pod-subnet", CIDRBlock: "10.241.0.0/16"},
+							{Name: "my-other-pod-subnet", CIDRBlock: "10.242.0.0/16"},
+						},
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "PodSubnets with the name of the node subnet",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					VirtualNetwork: ManagedControlPlaneVirtualNetwork{
+						Subnet: ManagedControlPlaneSubnet{Name: "my-subnet", CIDRBlock: "10.240.0.0/16"},
+						PodSubnets: []ManagedControlPlaneSubnet{
+							{Name: "my-subnet", CIDRBlock: "10.241.0.0/16"},
 						},
 					},
 				},
@@ -220,12 +844,16 @@ func TestValidatingWebhook(t *testing.T) {
 			expectErr: true,
 		},
 		{
-			name: "Invalid CIDR for AuthorizedIPRanges",
+			name: "PodSubnets with a duplicate name",
 			amcp: AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
 					Version: "v1.21.2",
-					APIServerAccessProfile: &APIServerAccessProfile{
-						AuthorizedIPRanges: []string{"1.2.3.400/32"},
+					VirtualNetwork: ManagedControlPlaneVirtualNetwork{
+						Subnet: ManagedControlPlaneSubnet{Name: "my-subnet", CIDRBlock: "10.240.0.0/16"},
+						PodSubnets: []ManagedControlPlaneSubnet{
+							{Name: "my-pod-subnet", CIDRBlock: "10.241.0.0/16"},
+							{Name: "my-pod-subnet", CIDRBlock: "10.242.0.0/16"},
+						},
 					},
 				},
 			},
@@ -345,6 +973,102 @@ func TestAzureManagedControlPlane_ValidateUpdate(t *testing.T) {
 			amcp:    createAzureManagedControlPlane(t, "192.168.0.0", "1.999.9", generateSSHPublicKey(true)),
 			wantErr: true,
 		},
+		{
+			name: "AzureManagedControlPlane KubeconfigSecret name is immutable",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.18.0",
+					KubeconfigSecret: &KubeconfigSecret{
+						Name: to.StringPtr("my-kubeconfig"),
+					},
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.18.0",
+					KubeconfigSecret: &KubeconfigSecret{
+						Name: to.StringPtr("my-other-kubeconfig"),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "AzureManagedControlPlane KubeconfigSecret name cannot be unset",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.18.0",
+					KubeconfigSecret: &KubeconfigSecret{
+						Name: to.StringPtr("my-kubeconfig"),
+					},
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.18.0",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "AzureManagedControlPlane KubeconfigSecret name can be set",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.18.0",
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.18.0",
+					KubeconfigSecret: &KubeconfigSecret{
+						Name: to.StringPtr("my-kubeconfig"),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "AzureManagedControlPlane DNSPrefix is immutable",
+			oldAMCP: &AzureManagedControlPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-cluster-control-plane",
+				},
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.18.0",
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-cluster-control-plane",
+				},
+				Spec: AzureManagedControlPlaneSpec{
+					Version:   "v1.18.0",
+					DNSPrefix: to.StringPtr("my-cluster"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "AzureManagedControlPlane DNSPrefix can be set to its default",
+			oldAMCP: &AzureManagedControlPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-cluster-control-plane",
+				},
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.18.0",
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-cluster-control-plane",
+				},
+				Spec: AzureManagedControlPlaneSpec{
+					Version:   "v1.18.0",
+					DNSPrefix: to.StringPtr("my-cluster-control-plane"),
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "AzureManagedControlPlane SubscriptionID is immutable",
 			oldAMCP: &AzureManagedControlPlane{
@@ -399,6 +1123,41 @@ func TestAzureManagedControlPlane_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "AzureManagedControlPlane DiskEncryptionSetID is immutable",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP:        to.StringPtr("192.168.0.0"),
+					DiskEncryptionSetID: to.StringPtr("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/my-rg/providers/Microsoft.Compute/diskEncryptionSets/my-des-1"),
+					Version:             "v1.18.0",
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP:        to.StringPtr("192.168.0.0"),
+					DiskEncryptionSetID: to.StringPtr("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/my-rg/providers/Microsoft.Compute/diskEncryptionSets/my-des-2"),
+					Version:             "v1.18.0",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "AzureManagedControlPlane DiskEncryptionSetID cannot be set after creation",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.StringPtr("192.168.0.0"),
+					Version:      "v1.18.0",
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP:        to.StringPtr("192.168.0.0"),
+					DiskEncryptionSetID: to.StringPtr("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/my-rg/providers/Microsoft.Compute/diskEncryptionSets/my-des"),
+					Version:             "v1.18.0",
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "AzureManagedControlPlane Location is immutable",
 			oldAMCP: &AzureManagedControlPlane{
@@ -571,6 +1330,22 @@ func TestAzureManagedControlPlane_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "AzureManagedControlPlane OutboundType is immutable",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:      "v1.18.0",
+					OutboundType: to.StringPtr(OutboundTypeLoadBalancer),
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:      "v1.18.0",
+					OutboundType: to.StringPtr(OutboundTypeUserDefinedRouting),
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "AzureManagedControlPlane ManagedAad can be set after cluster creation",
 			oldAMCP: &AzureManagedControlPlane{
@@ -640,12 +1415,13 @@ func TestAzureManagedControlPlane_ValidateUpdate(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name: "AzureManagedControlPlane adminGroupObjectIDs cannot set to empty",
+			name: "AzureManagedControlPlane adminGroupObjectIDs cannot set to empty when Azure RBAC is disabled",
 			oldAMCP: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
 					Version: "v1.18.0",
 					AADProfile: &AADProfile{
-						Managed: true,
+						Managed:         true,
+						EnableAzureRBAC: to.BoolPtr(false),
 						AdminGroupObjectIDs: []string{
 							"616077a8-5db7-4c98-b856-b34619afg75h",
 						},
@@ -657,12 +1433,38 @@ func TestAzureManagedControlPlane_ValidateUpdate(t *testing.T) {
 					Version: "v1.18.0",
 					AADProfile: &AADProfile{
 						Managed:             true,
+						EnableAzureRBAC:     to.BoolPtr(false),
 						AdminGroupObjectIDs: []string{},
 					},
 				},
 			},
 			wantErr: true,
 		},
+		{
+			name: "AzureManagedControlPlane adminGroupObjectIDs can be set to empty when Azure RBAC is enabled",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.18.0",
+					AADProfile: &AADProfile{
+						Managed: true,
+						AdminGroupObjectIDs: []string{
+							"616077a8-5db7-4c98-b856-b34619afg75h",
+						},
+					},
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.18.0",
+					AADProfile: &AADProfile{
+						Managed:             true,
+						EnableAzureRBAC:     to.BoolPtr(true),
+						AdminGroupObjectIDs: []string{},
+					},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "AzureManagedControlPlane ManagedAad cannot be disabled",
 			oldAMCP: &AzureManagedControlPlane{
@@ -18,6 +18,7 @@ package v1beta1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	capierrors "sigs.k8s.io/cluster-api/errors"
 )
 
@@ -25,16 +26,69 @@ const (
 	// LabelAgentPoolMode represents mode of an agent pool. Possible values include: System, User.
 	LabelAgentPoolMode = "azuremanagedmachinepool.infrastructure.cluster.x-k8s.io/agentpoolmode"
 
+	// AgentPoolRecreateAnnotation allows the agent pool to be deleted and recreated to apply changes to fields that
+	// AKS does not allow to be updated, such as the scale set priority.
+	AgentPoolRecreateAnnotation = "azuremanagedmachinepool.infrastructure.cluster.x-k8s.io/allow-recreate"
+
 	// NodePoolModeSystem represents mode system for azuremachinepool.
 	NodePoolModeSystem NodePoolMode = "System"
 
 	// NodePoolModeUser represents mode user for azuremachinepool.
 	NodePoolModeUser NodePoolMode = "User"
+
+	// TaintEffectNoSchedule prevents pods that do not tolerate the taint from being scheduled onto the node.
+	TaintEffectNoSchedule TaintEffect = "NoSchedule"
+
+	// TaintEffectPreferNoSchedule avoids scheduling pods that do not tolerate the taint onto the node.
+	TaintEffectPreferNoSchedule TaintEffect = "PreferNoSchedule"
+
+	// TaintEffectNoExecute evicts pods that do not tolerate the taint from the node.
+	TaintEffectNoExecute TaintEffect = "NoExecute"
+
+	// WindowsAgentPoolNameMaxLength is the maximum length of the name of an agent pool running Windows nodes.
+	WindowsAgentPoolNameMaxLength = 6
+
+	// AgentPoolNodeLabelsAnnotation records the keys of the node labels CAPZ applied to the agent pool, so that the
+	// labels removed from the spec can be removed from the agent pool and its nodes.
+	AgentPoolNodeLabelsAnnotation = "azuremanagedmachinepool.infrastructure.cluster.x-k8s.io/node-labels"
+
+	// AgentPoolUpgradeGroupLabel orders the Kubernetes version upgrades of the agent pools of a cluster. Its value is a
+	// non-negative integer. An agent pool with the label is only upgraded once all agent pools of the cluster with a
+	// lower upgrade group run their desired Kubernetes version. Agent pools without the label are upgraded right away.
+	AgentPoolUpgradeGroupLabel = "azuremanagedmachinepool.infrastructure.cluster.x-k8s.io/upgrade-group"
+
+	// GPUDevicePluginConfigLabel is the node label the NVIDIA GPU operator reads to select the device plugin
+	// configuration of a node, such as its GPU time-slicing configuration.
+	GPUDevicePluginConfigLabel = "nvidia.com/device-plugin.config"
+
+	// AutoscalerAnnotationPrefix is the prefix of the cluster autoscaler node annotations.
+	AutoscalerAnnotationPrefix = "cluster-autoscaler.kubernetes.io/"
 )
 
 // NodePoolMode enumerates the values for agent pool mode.
 type NodePoolMode string
 
+// TaintEffect enumerates the values for the effect of a node taint.
+type TaintEffect string
+
+// SKUSelector describes the VM size an agent pool should use. The smallest available VM size that satisfies all
+// the constraints is selected.
+type SKUSelector struct {
+	// Family is the VM size family, such as standardDSv3Family.
+	// +optional
+	Family *string `json:"family,omitempty"`
+
+	// MinVCPUs is the minimum number of vCPUs of the VM size.
+	// +kubebuilder:validation:Minimum=2
+	// +optional
+	MinVCPUs *int32 `json:"minVCPUs,omitempty"`
+
+	// MinMemoryGB is the minimum amount of memory of the VM size, in GB.
+	// +kubebuilder:validation:Minimum=2
+	// +optional
+	MinMemoryGB *int32 `json:"minMemoryGB,omitempty"`
+}
+
 // AzureManagedMachinePoolSpec defines the desired state of AzureManagedMachinePool.
 type AzureManagedMachinePoolSpec struct {
 
@@ -46,17 +100,168 @@ type AzureManagedMachinePoolSpec struct {
 	// +kubebuilder:validation:Enum=System;User
 	Mode string `json:"mode"`
 
-	// SKU is the size of the VMs in the node pool.
-	SKU string `json:"sku"`
+	// OrchestratorVersion is the Kubernetes version of the nodes in the agent pool, which may lag behind the version of
+	// the control plane during upgrades but cannot be newer than it. Defaults to the version of the MachinePool, or to
+	// the version of the control plane when the MachinePool has none.
+	// +optional
+	OrchestratorVersion *string `json:"orchestratorVersion,omitempty"`
+
+	// SKU is the size of the VMs in the node pool. Either SKU or SKUSelector must be set.
+	// When SKU is empty, CAPZ sets it to the VM size resolved from SKUSelector.
+	// +optional
+	SKU string `json:"sku,omitempty"`
+
+	// SKUSelector selects the size of the VMs in the node pool from the VM sizes available in the location of the
+	// cluster. It is only used when SKU is not set.
+	// +optional
+	SKUSelector *SKUSelector `json:"skuSelector,omitempty"`
+
+	// OSType - The operating system type of the nodes in the agent pool. Possible values include: Linux, Windows.
+	// Defaults to Linux. Windows agent pools must be in User mode and their name must be at most 6 characters long.
+	// +kubebuilder:validation:Enum=Linux;Windows
+	// +optional
+	OSType *string `json:"osType,omitempty"`
+
+	// ScaleSetPriority - The Virtual Machine Scale Set priority of the agent pool. Possible values include: Regular, Spot.
+	// Defaults to Regular. The scale set priority can only be changed when the agent pool has the
+	// azuremanagedmachinepool.infrastructure.cluster.x-k8s.io/allow-recreate annotation, in which case the agent pool
+	// is deleted and recreated with its node labels and taints preserved.
+	// +kubebuilder:validation:Enum=Regular;Spot
+	// +optional
+	ScaleSetPriority *string `json:"scaleSetPriority,omitempty"`
 
 	// OSDiskSizeGB is the disk size for every machine in this agent pool.
 	// If you specify 0, it will apply the default osDisk size according to the vmSize specified.
 	// +optional
 	OSDiskSizeGB *int32 `json:"osDiskSizeGB,omitempty"`
 
+	// OSDiskType is the type of the OS disk of the nodes in the agent pool. Possible values include: Managed, Ephemeral.
+	// Ephemeral OS disks are placed on the cache disk of the VM size, which must be larger than OSDiskSizeGB.
+	// Defaults to Ephemeral when the VM size supports it, and to Managed otherwise.
+	// +kubebuilder:validation:Enum=Managed;Ephemeral
+	// +optional
+	OSDiskType *string `json:"osDiskType,omitempty"`
+
+	// EnableEncryptionAtHost enables encryption at host of the nodes in the agent pool, which encrypts their OS and
+	// temporary disks and the caches of their data disks. The VM size must support it. Immutable.
+	// +optional
+	EnableEncryptionAtHost *bool `json:"enableEncryptionAtHost,omitempty"`
+
+	// ProximityPlacementGroupID is the resource ID of the proximity placement group the nodes of the agent pool are
+	// placed in, to reduce the network latency between them. Immutable.
+	// +optional
+	ProximityPlacementGroupID *string `json:"proximityPlacementGroupID,omitempty"`
+
+	// EnableNodePublicIP assigns a public IP to each node of the agent pool. Immutable.
+	// +optional
+	EnableNodePublicIP *bool `json:"enableNodePublicIP,omitempty"`
+
+	// NodePublicIPPrefixID is the resource ID of the public IP prefix the public IPs of the nodes are allocated from.
+	// It requires EnableNodePublicIP. Immutable.
+	// +optional
+	NodePublicIPPrefixID *string `json:"nodePublicIPPrefixID,omitempty"`
+
+	// PodSubnetName is the name of a subnet of the virtual network of the cluster that the IPs of the pods of the
+	// agent pool are allocated from. When unset, pod IPs are allocated from the node subnet. Pod subnets require the
+	// azure network plugin. Immutable.
+	// +optional
+	PodSubnetName *string `json:"podSubnetName,omitempty"`
+
 	// ProviderIDList is the unique identifier as specified by the cloud provider.
 	// +optional
 	ProviderIDList []string `json:"providerIDList,omitempty"`
+
+	// NodeDrainTimeout is the total amount of time to wait for the nodes of the agent pool to be drained before the
+	// agent pool is deleted. When set, all nodes of the agent pool are cordoned and drained first so that their
+	// workloads are rescheduled onto other agent pools. The agent pool is deleted once its nodes are drained or
+	// the timeout has elapsed. When unset, the agent pool is deleted without cordoning its nodes.
+	// +optional
+	NodeDrainTimeout *metav1.Duration `json:"nodeDrainTimeout,omitempty"`
+
+	// StartupTaint is a NoSchedule taint applied to the nodes of the agent pool when they are created, so that no
+	// workloads are scheduled onto them before their post-boot setup is complete. CAPZ removes the taint from each node
+	// once the node passes its readiness check.
+	// +optional
+	StartupTaint *StartupTaint `json:"startupTaint,omitempty"`
+
+	// Taints are the taints applied to new nodes of the agent pool.
+	// +optional
+	Taints []Taint `json:"taints,omitempty"`
+
+	// GPUSharing configures the GPUs of the nodes of the agent pool to be shared between workloads. The nodes are
+	// labeled so that the NVIDIA GPU operator applies the matching device plugin configuration.
+	// +optional
+	GPUSharing *GPUSharing `json:"gpuSharing,omitempty"`
+
+	// NodeLabels are the labels applied to the nodes of the agent pool. Labels removed from NodeLabels are removed
+	// from the agent pool and from its existing nodes.
+	// +optional
+	NodeLabels map[string]string `json:"nodeLabels,omitempty"`
+
+	// AutoscalerNodeAnnotations are cluster autoscaler annotations, such as
+	// cluster-autoscaler.kubernetes.io/scale-down-disabled, that CAPZ applies to the nodes of the agent pool. Their
+	// keys must have the cluster-autoscaler.kubernetes.io/ prefix.
+	// +optional
+	AutoscalerNodeAnnotations map[string]string `json:"autoscalerNodeAnnotations,omitempty"`
+
+	// Scaling enables the cluster autoscaler on the agent pool and sets the bounds it scales the agent pool within.
+	// The replicas of the owner MachinePool are only used as the initial node count of the agent pool; once the
+	// agent pool exists its node count is governed by the cluster autoscaler.
+	// +optional
+	Scaling *ManagedMachinePoolScaling `json:"scaling,omitempty"`
+}
+
+// ManagedMachinePoolScaling defines the bounds the cluster autoscaler scales an agent pool within.
+type ManagedMachinePoolScaling struct {
+	// MinSize is the minimum number of nodes of the agent pool. System agent pools must have at least one node.
+	// +kubebuilder:validation:Minimum=0
+	MinSize int32 `json:"minSize"`
+
+	// MaxSize is the maximum number of nodes of the agent pool.
+	// +kubebuilder:validation:Minimum=1
+	MaxSize int32 `json:"maxSize"`
+}
+
+// GPUSharing defines how the GPUs of the nodes of an agent pool are shared between workloads.
+type GPUSharing struct {
+	// TimeSlicingReplicas is the number of replicas each GPU is advertised as, so that up to this many workloads
+	// share each GPU through time-slicing. The nodes are labeled with nvidia.com/device-plugin.config set to
+	// time-slicing-<TimeSlicingReplicas>, which must name a time-slicing configuration of the GPU operator device plugin.
+	// +kubebuilder:validation:Minimum=2
+	TimeSlicingReplicas int32 `json:"timeSlicingReplicas"`
+}
+
+// Taint defines a taint applied to the nodes of an agent pool.
+type Taint struct {
+	// Key is the key of the taint.
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+
+	// Value is the value of the taint.
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// Effect is the effect of the taint on pods that do not tolerate it. Possible values include: NoSchedule,
+	// PreferNoSchedule, NoExecute.
+	// +kubebuilder:validation:Enum=NoSchedule;PreferNoSchedule;NoExecute
+	Effect TaintEffect `json:"effect"`
+}
+
+// StartupTaint defines a taint applied to the nodes of an agent pool until they pass a readiness check.
+type StartupTaint struct {
+	// Key is the key of the taint.
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+
+	// Value is the value of the taint.
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// ReadinessConditionType is the type of the node condition that must be True for the taint to be removed from the
+	// node. Defaults to Ready. Set it to a custom node condition reported by the post-boot setup to wait for the setup
+	// to complete.
+	// +optional
+	ReadinessConditionType *string `json:"readinessConditionType,omitempty"`
 }
 
 // AzureManagedMachinePoolStatus defines the observed state of AzureManagedMachinePool.
@@ -69,6 +274,10 @@ type AzureManagedMachinePoolStatus struct {
 	// +optional
 	Replicas int32 `json:"replicas"`
 
+	// Version is the most recently observed Kubernetes version of the agent pool.
+	// +optional
+	Version string `json:"version,omitempty"`
+
 	// Any transient errors that occur during the reconciliation of Machines
 	// can be added as events to the Machine object and/or logged in the
 	// controller's output.
@@ -80,6 +289,10 @@ type AzureManagedMachinePoolStatus struct {
 	// controller's output.
 	// +optional
 	ErrorMessage *string `json:"errorMessage,omitempty"`
+
+	// Conditions defines current service state of the AzureManagedMachinePool.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -96,6 +309,16 @@ type AzureManagedMachinePool struct {
 	Status AzureManagedMachinePoolStatus `json:"status,omitempty"`
 }
 
+// GetConditions returns the list of conditions for an AzureManagedMachinePool API object.
+func (m *AzureManagedMachinePool) GetConditions() clusterv1.Conditions {
+	return m.Status.Conditions
+}
+
+// SetConditions will set the given conditions on an AzureManagedMachinePool object.
+func (m *AzureManagedMachinePool) SetConditions(conditions clusterv1.Conditions) {
+	m.Status.Conditions = conditions
+}
+
 // +kubebuilder:object:root=true
 
 // AzureManagedMachinePoolList contains a list of AzureManagedMachinePools.
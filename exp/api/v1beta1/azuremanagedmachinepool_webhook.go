@@ -18,7 +18,14 @@ package v1beta1
 
 import (
 	"context"
-
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	autorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/blang/semver"
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -50,11 +57,28 @@ func (r *AzureManagedMachinePool) Default(client client.Client) {
 	}
 }
 
-//+kubebuilder:webhook:verbs=update;delete,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-azuremanagedmachinepool,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azuremanagedmachinepools,versions=v1beta1,name=validation.azuremanagedmachinepools.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+//+kubebuilder:webhook:verbs=create;update;delete,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-azuremanagedmachinepool,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azuremanagedmachinepools,versions=v1beta1,name=validation.azuremanagedmachinepools.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
 func (r *AzureManagedMachinePool) ValidateCreate(client client.Client) error {
 	azuremanagedmachinepoollog.Info("validate create", "name", r.Name)
+
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, r.validateSKU()...)
+	allErrs = append(allErrs, r.validateWindowsAgentPool()...)
+	allErrs = append(allErrs, r.validateScaling()...)
+	allErrs = append(allErrs, r.validateTaints()...)
+	allErrs = append(allErrs, r.validateUpgradeGroup()...)
+	allErrs = append(allErrs, r.validateAutoscalerNodeAnnotations()...)
+	allErrs = append(allErrs, r.validatePodSubnet(client)...)
+	allErrs = append(allErrs, r.validateProximityPlacementGroupID()...)
+	allErrs = append(allErrs, r.validateNodePublicIP()...)
+	allErrs = append(allErrs, r.validateOrchestratorVersion(client)...)
+
+	if len(allErrs) != 0 {
+		return apierrors.NewInvalid(GroupVersion.WithKind("AzureManagedMachinePool").GroupKind(), r.Name, allErrs)
+	}
+
 	return nil
 }
 
@@ -63,7 +87,8 @@ func (r *AzureManagedMachinePool) ValidateUpdate(oldRaw runtime.Object, client c
 	old := oldRaw.(*AzureManagedMachinePool)
 	var allErrs field.ErrorList
 
-	if r.Spec.SKU != old.Spec.SKU {
+	// The SKU may be set once from the SKU selector, after which it cannot change.
+	if r.Spec.SKU != old.Spec.SKU && (old.Spec.SKU != "" || old.Spec.SKUSelector == nil) {
 		allErrs = append(allErrs,
 			field.Invalid(
 				field.NewPath("Spec", "SKU"),
@@ -71,6 +96,78 @@ func (r *AzureManagedMachinePool) ValidateUpdate(oldRaw runtime.Object, client c
 				"field is immutable"))
 	}
 
+	if !reflect.DeepEqual(r.Spec.SKUSelector, old.Spec.SKUSelector) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "SKUSelector"),
+				r.Spec.SKUSelector,
+				"field is immutable"))
+	}
+
+	if !reflect.DeepEqual(r.Spec.OSType, old.Spec.OSType) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "OSType"),
+				r.Spec.OSType,
+				"field is immutable"))
+	}
+
+	if !reflect.DeepEqual(r.Spec.OSDiskType, old.Spec.OSDiskType) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "OSDiskType"),
+				r.Spec.OSDiskType,
+				"field is immutable"))
+	}
+
+	if !reflect.DeepEqual(r.Spec.EnableEncryptionAtHost, old.Spec.EnableEncryptionAtHost) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "EnableEncryptionAtHost"),
+				r.Spec.EnableEncryptionAtHost,
+				"field is immutable"))
+	}
+
+	if !reflect.DeepEqual(r.Spec.ProximityPlacementGroupID, old.Spec.ProximityPlacementGroupID) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "ProximityPlacementGroupID"),
+				r.Spec.ProximityPlacementGroupID,
+				"field is immutable"))
+	}
+
+	if !reflect.DeepEqual(r.Spec.EnableNodePublicIP, old.Spec.EnableNodePublicIP) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "EnableNodePublicIP"),
+				r.Spec.EnableNodePublicIP,
+				"field is immutable"))
+	}
+
+	if !reflect.DeepEqual(r.Spec.NodePublicIPPrefixID, old.Spec.NodePublicIPPrefixID) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "NodePublicIPPrefixID"),
+				r.Spec.NodePublicIPPrefixID,
+				"field is immutable"))
+	}
+
+	if !reflect.DeepEqual(r.Spec.PodSubnetName, old.Spec.PodSubnetName) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "PodSubnetName"),
+				r.Spec.PodSubnetName,
+				"field is immutable"))
+	}
+
+	if _, allowRecreate := r.Annotations[AgentPoolRecreateAnnotation]; !allowRecreate && !reflect.DeepEqual(r.Spec.ScaleSetPriority, old.Spec.ScaleSetPriority) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "ScaleSetPriority"),
+				r.Spec.ScaleSetPriority,
+				fmt.Sprintf("field is immutable unless the %s annotation is set", AgentPoolRecreateAnnotation)))
+	}
+
 	if old.Spec.OSDiskSizeGB != nil {
 		// Prevent OSDiskSizeGB modification if it was already set to some value
 		if r.Spec.OSDiskSizeGB == nil {
@@ -96,10 +193,20 @@ func (r *AzureManagedMachinePool) ValidateUpdate(oldRaw runtime.Object, client c
 			allErrs = append(allErrs, field.Invalid(
 				field.NewPath("Spec", "Mode"),
 				r.Spec.Mode,
-				"Last system node pool cannot be mutated to user node pool"))
+				fmt.Sprintf("Last system node pool cannot be mutated to user node pool: %v", err)))
 		}
 	}
 
+	allErrs = append(allErrs, r.validateSKU()...)
+	allErrs = append(allErrs, r.validateWindowsAgentPool()...)
+	allErrs = append(allErrs, r.validateScaling()...)
+	allErrs = append(allErrs, r.validateTaints()...)
+	allErrs = append(allErrs, r.validateUpgradeGroup()...)
+	allErrs = append(allErrs, r.validateAutoscalerNodeAnnotations()...)
+	allErrs = append(allErrs, r.validateProximityPlacementGroupID()...)
+	allErrs = append(allErrs, r.validateNodePublicIP()...)
+	allErrs = append(allErrs, r.validateOrchestratorVersion(client)...)
+
 	if len(allErrs) != 0 {
 		return apierrors.NewInvalid(GroupVersion.WithKind("AzureManagedMachinePool").GroupKind(), r.Name, allErrs)
 	}
@@ -118,6 +225,319 @@ func (r *AzureManagedMachinePool) ValidateDelete(client client.Client) error {
 	return errors.Wrapf(r.validateLastSystemNodePool(client), "if the delete is triggered via owner MachinePool please refer to trouble shooting section in https://capz.sigs.k8s.io/topics/managedcluster.html")
 }
 
+// validateSKU validates that the VM size of the agent pool is either set or can be selected.
+func (r *AzureManagedMachinePool) validateSKU() field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.SKU == "" && r.Spec.SKUSelector == nil {
+		allErrs = append(allErrs,
+			field.Required(
+				field.NewPath("Spec", "SKU"),
+				"either SKU or SKUSelector must be set"))
+	}
+
+	return allErrs
+}
+
+// validateWindowsAgentPool validates the constraints AKS places on agent pools running Windows nodes:
+// their name must be at most 6 characters long and they cannot be system node pools.
+func (r *AzureManagedMachinePool) validateWindowsAgentPool() field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.OSType == nil || *r.Spec.OSType != azure.WindowsOS {
+		return allErrs
+	}
+
+	name := r.Name
+	if r.Spec.Name != nil && *r.Spec.Name != "" {
+		name = *r.Spec.Name
+	}
+	if len(name) > WindowsAgentPoolNameMaxLength {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "Name"),
+				name,
+				fmt.Sprintf("Windows agent pool name must be at most %d characters long", WindowsAgentPoolNameMaxLength)))
+	}
+
+	if r.Spec.Mode != string(NodePoolModeUser) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "Mode"),
+				r.Spec.Mode,
+				"Windows agent pools must be user node pools"))
+	}
+
+	return allErrs
+}
+
+// validateScaling validates that the autoscaling bounds of the agent pool are consistent and that system agent pools
+// cannot be scaled down to zero nodes.
+func (r *AzureManagedMachinePool) validateScaling() field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.Scaling == nil {
+		return allErrs
+	}
+
+	if r.Spec.Scaling.MinSize > r.Spec.Scaling.MaxSize {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "Scaling", "MinSize"),
+				r.Spec.Scaling.MinSize,
+				"MinSize must be less than or equal to MaxSize"))
+	}
+
+	if r.Spec.Mode == string(NodePoolModeSystem) && r.Spec.Scaling.MinSize < 1 {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "Scaling", "MinSize"),
+				r.Spec.Scaling.MinSize,
+				"system node pools must have at least one node"))
+	}
+
+	return allErrs
+}
+
+// validateTaints validates that the effects of the taints of the agent pool are ones Kubernetes supports.
+func (r *AzureManagedMachinePool) validateTaints() field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, taint := range r.Spec.Taints {
+		switch taint.Effect {
+		case TaintEffectNoSchedule, TaintEffectPreferNoSchedule, TaintEffectNoExecute:
+		default:
+			allErrs = append(allErrs,
+				field.NotSupported(
+					field.NewPath("Spec", "Taints").Index(i).Child("Effect"),
+					taint.Effect,
+					[]string{string(TaintEffectNoSchedule), string(TaintEffectPreferNoSchedule), string(TaintEffectNoExecute)}))
+		}
+	}
+
+	return allErrs
+}
+
+// validateUpgradeGroup validates that the upgrade group of the agent pool is a non-negative integer.
+func (r *AzureManagedMachinePool) validateUpgradeGroup() field.ErrorList {
+	var allErrs field.ErrorList
+
+	group, ok := r.Labels[AgentPoolUpgradeGroupLabel]
+	if !ok {
+		return allErrs
+	}
+
+	if n, err := strconv.Atoi(group); err != nil || n < 0 {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Metadata", "Labels", AgentPoolUpgradeGroupLabel),
+				group,
+				"upgrade group must be a non-negative integer"))
+	}
+
+	return allErrs
+}
+
+// validateAutoscalerNodeAnnotations validates that the autoscaler node annotations of the agent pool are cluster
+// autoscaler annotations.
+func (r *AzureManagedMachinePool) validateAutoscalerNodeAnnotations() field.ErrorList {
+	var allErrs field.ErrorList
+
+	keys := make([]string, 0, len(r.Spec.AutoscalerNodeAnnotations))
+	for key := range r.Spec.AutoscalerNodeAnnotations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if !strings.HasPrefix(key, AutoscalerAnnotationPrefix) || key == AutoscalerAnnotationPrefix {
+			allErrs = append(allErrs,
+				field.Invalid(
+					field.NewPath("Spec", "AutoscalerNodeAnnotations").Key(key),
+					key,
+					fmt.Sprintf("annotation key must have the %s prefix", AutoscalerAnnotationPrefix)))
+		}
+	}
+
+	return allErrs
+}
+
+// validatePodSubnet validates that the network plugin of the control plane of the cluster supports pod subnets when
+// the agent pool sets one, and that it is one of the pod subnets of the virtual network of the control plane. The pod
+// subnet is not validated while the cluster or its control plane does not exist.
+func (r *AzureManagedMachinePool) validatePodSubnet(cli client.Client) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.PodSubnetName == nil {
+		return allErrs
+	}
+
+	controlPlane, err := r.ownerControlPlane(cli)
+	if err != nil {
+		allErrs = append(allErrs,
+			field.InternalError(
+				field.NewPath("Spec", "PodSubnetName"),
+				errors.Wrap(err, "failed to get the control plane of the cluster")))
+		return allErrs
+	}
+	if controlPlane == nil {
+		return allErrs
+	}
+
+	if controlPlane.Spec.NetworkPlugin == nil || *controlPlane.Spec.NetworkPlugin != "azure" {
+		allErrs = append(allErrs,
+			field.Forbidden(
+				field.NewPath("Spec", "PodSubnetName"),
+				"pod subnets require the azure network plugin"))
+	}
+
+	for _, subnet := range controlPlane.Spec.VirtualNetwork.PodSubnets {
+		if subnet.Name == *r.Spec.PodSubnetName {
+			return allErrs
+		}
+	}
+	allErrs = append(allErrs,
+		field.NotFound(
+			field.NewPath("Spec", "PodSubnetName"),
+			*r.Spec.PodSubnetName))
+
+	return allErrs
+}
+
+// validateProximityPlacementGroupID validates that the proximity placement group of the agent pool is the resource ID
+// of a proximity placement group.
+func (r *AzureManagedMachinePool) validateProximityPlacementGroupID() field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.ProximityPlacementGroupID == nil {
+		return allErrs
+	}
+
+	resource, err := autorest.ParseResourceID(*r.Spec.ProximityPlacementGroupID)
+	if err != nil || !strings.EqualFold(resource.Provider, "Microsoft.Compute") || !strings.EqualFold(resource.ResourceType, "proximityPlacementGroups") {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "ProximityPlacementGroupID"),
+				*r.Spec.ProximityPlacementGroupID,
+				"must be the resource ID of a proximity placement group, e.g. /subscriptions/<subscription>/resourceGroups/<resource group>/providers/Microsoft.Compute/proximityPlacementGroups/<name>"))
+	}
+
+	return allErrs
+}
+
+// validateNodePublicIP validates that the public IP prefix of the nodes is only set when node public IPs are enabled,
+// and that it is the resource ID of a public IP prefix.
+func (r *AzureManagedMachinePool) validateNodePublicIP() field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.NodePublicIPPrefixID == nil {
+		return allErrs
+	}
+
+	path := field.NewPath("Spec", "NodePublicIPPrefixID")
+	if r.Spec.EnableNodePublicIP == nil || !*r.Spec.EnableNodePublicIP {
+		allErrs = append(allErrs,
+			field.Forbidden(path, "NodePublicIPPrefixID can only be set when EnableNodePublicIP is true"))
+		return allErrs
+	}
+
+	resource, err := autorest.ParseResourceID(*r.Spec.NodePublicIPPrefixID)
+	if err != nil || !strings.EqualFold(resource.Provider, "Microsoft.Network") || !strings.EqualFold(resource.ResourceType, "publicIPPrefixes") {
+		allErrs = append(allErrs,
+			field.Invalid(
+				path,
+				*r.Spec.NodePublicIPPrefixID,
+				"must be the resource ID of a public IP prefix, e.g. /subscriptions/<subscription>/resourceGroups/<resource group>/providers/Microsoft.Network/publicIPPrefixes/<name>"))
+	}
+
+	return allErrs
+}
+
+// validateOrchestratorVersion validates that the Kubernetes version of the agent pool is a valid version that is not
+// newer than the version of the control plane of the cluster. The control plane version is not compared while the
+// cluster or its control plane does not exist.
+func (r *AzureManagedMachinePool) validateOrchestratorVersion(cli client.Client) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.OrchestratorVersion == nil {
+		return allErrs
+	}
+
+	path := field.NewPath("Spec", "OrchestratorVersion")
+	version, err := semver.ParseTolerant(*r.Spec.OrchestratorVersion)
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(path, *r.Spec.OrchestratorVersion, "must be a valid semantic version"))
+		return allErrs
+	}
+
+	controlPlane, err := r.ownerControlPlane(cli)
+	if err != nil {
+		allErrs = append(allErrs, field.InternalError(path, errors.Wrap(err, "failed to get the control plane of the cluster")))
+		return allErrs
+	}
+	if controlPlane == nil {
+		return allErrs
+	}
+
+	controlPlaneVersion, err := semver.ParseTolerant(controlPlane.Spec.Version)
+	if err != nil {
+		return allErrs
+	}
+	if version.GT(controlPlaneVersion) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				path,
+				*r.Spec.OrchestratorVersion,
+				fmt.Sprintf("must not be newer than the version %s of the control plane", controlPlane.Spec.Version)))
+	}
+
+	return allErrs
+}
+
+// ownerControlPlane returns the AzureManagedControlPlane of the cluster the agent pool belongs to, or nil if the
+// cluster or its control plane does not exist.
+func (r *AzureManagedMachinePool) ownerControlPlane(cli client.Client) (*AzureManagedControlPlane, error) {
+	ctx := context.Background()
+
+	clusterName, ok := r.Labels[clusterv1.ClusterLabelName]
+	if !ok {
+		return nil, nil
+	}
+
+	ownerCluster := &clusterv1.Cluster{}
+	key := client.ObjectKey{
+		Namespace: r.Namespace,
+		Name:      clusterName,
+	}
+	if err := cli.Get(ctx, key, ownerCluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if ownerCluster.Spec.ControlPlaneRef == nil {
+		return nil, nil
+	}
+
+	controlPlane := &AzureManagedControlPlane{}
+	key = client.ObjectKey{
+		Namespace: ownerCluster.Spec.ControlPlaneRef.Namespace,
+		Name:      ownerCluster.Spec.ControlPlaneRef.Name,
+	}
+	if key.Namespace == "" {
+		key.Namespace = r.Namespace
+	}
+	if err := cli.Get(ctx, key, controlPlane); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return controlPlane, nil
+}
+
 // validateLastSystemNodePool is used to check if the existing system node pool is the last system node pool.
 // If it is a last system node pool it cannot be deleted or mutated to user node pool as AKS expects min 1 system node pool.
 func (r *AzureManagedMachinePool) validateLastSystemNodePool(cli client.Client) error {
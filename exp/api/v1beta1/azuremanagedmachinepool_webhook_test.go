@@ -21,8 +21,12 @@ import (
 
 	"github.com/Azure/go-autorest/autorest/to"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestAzureManagedMachinePoolDefaultingWebhook(t *testing.T) {
@@ -89,6 +93,41 @@ func TestAzureManagedMachinePoolUpdatingWebhook(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Can set SKU resolved from the SKU selector",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:        "System",
+					SKU:         "Standard_D2s_v3",
+					SKUSelector: &SKUSelector{MinVCPUs: to.Int32Ptr(2)},
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:        "System",
+					SKUSelector: &SKUSelector{MinVCPUs: to.Int32Ptr(2)},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Cannot change SKU resolved from the SKU selector",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:        "System",
+					SKU:         "Standard_D4s_v3",
+					SKUSelector: &SKUSelector{MinVCPUs: to.Int32Ptr(2)},
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:        "System",
+					SKU:         "Standard_D2s_v3",
+					SKUSelector: &SKUSelector{MinVCPUs: to.Int32Ptr(2)},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "Cannot change OSDiskSizeGB of the agentpool",
 			new: &AzureManagedMachinePool{
@@ -107,6 +146,173 @@ func TestAzureManagedMachinePoolUpdatingWebhook(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Cannot change OSType of the agentpool",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:   "User",
+					SKU:    "StandardD2S_V3",
+					OSType: to.StringPtr("Windows"),
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:   "User",
+					SKU:    "StandardD2S_V3",
+					OSType: to.StringPtr("Linux"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Cannot change OSDiskType of the agentpool",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:       "System",
+					SKU:        "StandardD2S_V3",
+					OSDiskType: to.StringPtr("Ephemeral"),
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:       "System",
+					SKU:        "StandardD2S_V3",
+					OSDiskType: to.StringPtr("Managed"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Cannot enable EnableEncryptionAtHost on an existing agentpool",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:                   "System",
+					SKU:                    "StandardD2S_V3",
+					EnableEncryptionAtHost: to.BoolPtr(true),
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode: "System",
+					SKU:  "StandardD2S_V3",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Cannot change ProximityPlacementGroupID of an existing agentpool",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:                      "System",
+					SKU:                       "StandardD2S_V3",
+					ProximityPlacementGroupID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/proximityPlacementGroups/other-ppg"),
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:                      "System",
+					SKU:                       "StandardD2S_V3",
+					ProximityPlacementGroupID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/proximityPlacementGroups/my-ppg"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Cannot enable node public IPs of an existing agentpool",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:               "System",
+					SKU:                "StandardD2S_V3",
+					EnableNodePublicIP: to.BoolPtr(true),
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode: "System",
+					SKU:  "StandardD2S_V3",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Cannot change NodePublicIPPrefixID of an existing agentpool",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:                 "System",
+					SKU:                  "StandardD2S_V3",
+					EnableNodePublicIP:   to.BoolPtr(true),
+					NodePublicIPPrefixID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPPrefixes/other-prefix"),
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:                 "System",
+					SKU:                  "StandardD2S_V3",
+					EnableNodePublicIP:   to.BoolPtr(true),
+					NodePublicIPPrefixID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPPrefixes/my-prefix"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Cannot change ScaleSetPriority of the agentpool without the recreate annotation",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:             "User",
+					SKU:              "StandardD2S_V3",
+					ScaleSetPriority: to.StringPtr("Spot"),
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:             "User",
+					SKU:              "StandardD2S_V3",
+					ScaleSetPriority: to.StringPtr("Regular"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Cannot change PodSubnetName of the agentpool",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:          "User",
+					SKU:           "StandardD2S_V3",
+					PodSubnetName: to.StringPtr("pod-subnet-2"),
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:          "User",
+					SKU:           "StandardD2S_V3",
+					PodSubnetName: to.StringPtr("pod-subnet-1"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Can change ScaleSetPriority of the agentpool with the recreate annotation",
+			new: &AzureManagedMachinePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						AgentPoolRecreateAnnotation: "",
+					},
+				},
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:             "User",
+					SKU:              "StandardD2S_V3",
+					ScaleSetPriority: to.StringPtr("Spot"),
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:             "User",
+					SKU:              "StandardD2S_V3",
+					ScaleSetPriority: to.StringPtr("Regular"),
+				},
+			},
+			wantErr: false,
+		},
 	}
 	var client client.Client
 	for _, tc := range tests {
@@ -120,3 +326,542 @@ func TestAzureManagedMachinePoolUpdatingWebhook(t *testing.T) {
 		})
 	}
 }
+
+func TestAzureManagedMachinePoolUpdatingWebhookMode(t *testing.T) {
+	systemPool := func(name string) *AzureManagedMachinePool {
+		return &AzureManagedMachinePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					clusterv1.ClusterLabelName: "my-cluster",
+					LabelAgentPoolMode:         string(NodePoolModeSystem),
+				},
+			},
+			Spec: AzureManagedMachinePoolSpec{
+				Mode: string(NodePoolModeSystem),
+				SKU:  "StandardD2S_V3",
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		pools   []*AzureManagedMachinePool
+		wantErr bool
+	}{
+		{
+			name:    "Cannot demote the only system agentpool to a user agentpool",
+			pools:   []*AzureManagedMachinePool{systemPool("pool0")},
+			wantErr: true,
+		},
+		{
+			name:    "Can demote a system agentpool to a user agentpool when another system agentpool remains",
+			pools:   []*AzureManagedMachinePool{systemPool("pool0"), systemPool("pool1")},
+			wantErr: false,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+			g.Expect(AddToScheme(scheme)).To(Succeed())
+			initObjects := []client.Object{
+				&clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "my-cluster",
+						Namespace: "default",
+					},
+				},
+			}
+			for _, pool := range tc.pools {
+				initObjects = append(initObjects, pool)
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjects...).Build()
+
+			old := tc.pools[0]
+			ammp := old.DeepCopy()
+			ammp.Spec.Mode = string(NodePoolModeUser)
+			ammp.Labels[LabelAgentPoolMode] = string(NodePoolModeUser)
+
+			err := ammp.ValidateUpdate(old, c)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring("Last system node pool cannot be mutated to user node pool"))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAzureManagedMachinePoolCreatingWebhook(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name    string
+		ammp    *AzureManagedMachinePool
+		wantErr bool
+	}{
+		{
+			name: "Linux agentpool with a long name",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Name: to.StringPtr("linuxpool"),
+					Mode: "System",
+					SKU:  "StandardD2S_V3",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "agentpool with a SKU selector",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Name: to.StringPtr("pool0"),
+					Mode: "System",
+					SKUSelector: &SKUSelector{
+						Family:   to.StringPtr("standardDSv3Family"),
+						MinVCPUs: to.Int32Ptr(4),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "agentpool without a SKU or SKU selector",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Name: to.StringPtr("pool0"),
+					Mode: "System",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Windows user agentpool",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Name:   to.StringPtr("win1"),
+					Mode:   "User",
+					SKU:    "StandardD2S_V3",
+					OSType: to.StringPtr("Windows"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Windows agentpool name cannot be longer than 6 characters",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Name:   to.StringPtr("windows"),
+					Mode:   "User",
+					SKU:    "StandardD2S_V3",
+					OSType: to.StringPtr("Windows"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Windows agentpool cannot be a system node pool",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Name:   to.StringPtr("win1"),
+					Mode:   "System",
+					SKU:    "StandardD2S_V3",
+					OSType: to.StringPtr("Windows"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "agentpool with autoscaling bounds",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Name: to.StringPtr("pool0"),
+					Mode: "System",
+					SKU:  "StandardD2S_V3",
+					Scaling: &ManagedMachinePoolScaling{
+						MinSize: 1,
+						MaxSize: 5,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "agentpool autoscaling MinSize cannot be greater than MaxSize",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Name: to.StringPtr("pool0"),
+					Mode: "User",
+					SKU:  "StandardD2S_V3",
+					Scaling: &ManagedMachinePoolScaling{
+						MinSize: 6,
+						MaxSize: 5,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "system agentpool cannot be scaled down to zero nodes",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Name: to.StringPtr("pool0"),
+					Mode: "System",
+					SKU:  "StandardD2S_V3",
+					Scaling: &ManagedMachinePoolScaling{
+						MinSize: 0,
+						MaxSize: 5,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "agentpool with valid taint effects",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Name: to.StringPtr("pool0"),
+					Mode: "User",
+					SKU:  "StandardD2S_V3",
+					Taints: []Taint{
+						{Key: "a", Value: "b", Effect: TaintEffectNoSchedule},
+						{Key: "c", Effect: TaintEffectPreferNoSchedule},
+						{Key: "d", Value: "e", Effect: TaintEffectNoExecute},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "agentpool with an invalid taint effect",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Name: to.StringPtr("pool0"),
+					Mode: "User",
+					SKU:  "StandardD2S_V3",
+					Taints: []Taint{
+						{Key: "a", Value: "b", Effect: "NoScheduleX"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "agentpool with autoscaler node annotations",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Name: to.StringPtr("pool0"),
+					Mode: "User",
+					SKU:  "StandardD2S_V3",
+					AutoscalerNodeAnnotations: map[string]string{
+						"cluster-autoscaler.kubernetes.io/scale-down-disabled": "true",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "agentpool with a node annotation that is not an autoscaler annotation",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Name: to.StringPtr("pool0"),
+					Mode: "User",
+					SKU:  "StandardD2S_V3",
+					AutoscalerNodeAnnotations: map[string]string{
+						"example.com/scale-down-disabled": "true",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "agentpool with a valid upgrade group",
+			ammp: &AzureManagedMachinePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{AgentPoolUpgradeGroupLabel: "1"},
+				},
+				Spec: AzureManagedMachinePoolSpec{
+					Name: to.StringPtr("pool0"),
+					Mode: "User",
+					SKU:  "StandardD2S_V3",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "agentpool with an invalid upgrade group",
+			ammp: &AzureManagedMachinePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{AgentPoolUpgradeGroupLabel: "-1"},
+				},
+				Spec: AzureManagedMachinePoolSpec{
+					Name: to.StringPtr("pool0"),
+					Mode: "User",
+					SKU:  "StandardD2S_V3",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "agentpool with a proximity placement group",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Name:                      to.StringPtr("pool0"),
+					Mode:                      "User",
+					SKU:                       "StandardD2S_V3",
+					ProximityPlacementGroupID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/proximityPlacementGroups/my-ppg"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "agentpool with a malformed proximity placement group ID",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Name:                      to.StringPtr("pool0"),
+					Mode:                      "User",
+					SKU:                       "StandardD2S_V3",
+					ProximityPlacementGroupID: to.StringPtr("my-ppg"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "agentpool with the resource ID of another resource type as proximity placement group",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Name:                      to.StringPtr("pool0"),
+					Mode:                      "User",
+					SKU:                       "StandardD2S_V3",
+					ProximityPlacementGroupID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/availabilitySets/my-as"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "agentpool with node public IPs from a public IP prefix",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Name:                 to.StringPtr("pool0"),
+					Mode:                 "User",
+					SKU:                  "StandardD2S_V3",
+					EnableNodePublicIP:   to.BoolPtr(true),
+					NodePublicIPPrefixID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPPrefixes/my-prefix"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "agentpool with a public IP prefix but without node public IPs",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Name:                 to.StringPtr("pool0"),
+					Mode:                 "User",
+					SKU:                  "StandardD2S_V3",
+					NodePublicIPPrefixID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPPrefixes/my-prefix"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "agentpool with the resource ID of another resource type as public IP prefix",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Name:                 to.StringPtr("pool0"),
+					Mode:                 "User",
+					SKU:                  "StandardD2S_V3",
+					EnableNodePublicIP:   to.BoolPtr(true),
+					NodePublicIPPrefixID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/my-ip"),
+				},
+			},
+			wantErr: true,
+		},
+	}
+	var client client.Client
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.ammp.ValidateCreate(client)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAzureManagedMachinePoolCreatingWebhookPodSubnet(t *testing.T) {
+	tests := []struct {
+		name          string
+		networkPlugin *string
+		podSubnets    []ManagedControlPlaneSubnet
+		wantErr       bool
+	}{
+		{
+			name:          "pod subnet with the azure network plugin",
+			networkPlugin: to.StringPtr("azure"),
+			podSubnets:    []ManagedControlPlaneSubnet{{Name: "my-pod-subnet", CIDRBlock: "10.241.0.0/16"}},
+			wantErr:       false,
+		},
+		{
+			name:          "pod subnet with the kubenet network plugin",
+			networkPlugin: to.StringPtr("kubenet"),
+			podSubnets:    []ManagedControlPlaneSubnet{{Name: "my-pod-subnet", CIDRBlock: "10.241.0.0/16"}},
+			wantErr:       true,
+		},
+		{
+			name:          "pod subnet which is not a pod subnet of the virtual network",
+			networkPlugin: to.StringPtr("azure"),
+			podSubnets:    []ManagedControlPlaneSubnet{{Name: "my-other-pod-subnet", CIDRBlock: "10.241.0.0/16"}},
+			wantErr:       true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+			g.Expect(AddToScheme(scheme)).To(Succeed())
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				&clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "my-cluster",
+						Namespace: "default",
+					},
+					Spec: clusterv1.ClusterSpec{
+						ControlPlaneRef: &corev1.ObjectReference{
+							Name: "my-cluster-control-plane",
+						},
+					},
+				},
+				&AzureManagedControlPlane{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "my-cluster-control-plane",
+						Namespace: "default",
+					},
+					Spec: AzureManagedControlPlaneSpec{
+						NetworkPlugin: tc.networkPlugin,
+						VirtualNetwork: ManagedControlPlaneVirtualNetwork{
+							PodSubnets: tc.podSubnets,
+						},
+					},
+				},
+			).Build()
+
+			ammp := &AzureManagedMachinePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pool1",
+					Namespace: "default",
+					Labels: map[string]string{
+						clusterv1.ClusterLabelName: "my-cluster",
+					},
+				},
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:          "User",
+					SKU:           "StandardD2S_V3",
+					PodSubnetName: to.StringPtr("my-pod-subnet"),
+				},
+			}
+			err := ammp.ValidateCreate(c)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAzureManagedMachinePoolWebhookOrchestratorVersion(t *testing.T) {
+	tests := []struct {
+		name                string
+		orchestratorVersion *string
+		wantErr             bool
+	}{
+		{
+			name:                "agent pool version matching the control plane",
+			orchestratorVersion: to.StringPtr("v1.22.4"),
+			wantErr:             false,
+		},
+		{
+			name:                "agent pool version older than the control plane",
+			orchestratorVersion: to.StringPtr("v1.21.7"),
+			wantErr:             false,
+		},
+		{
+			name:                "agent pool version newer than the control plane",
+			orchestratorVersion: to.StringPtr("v1.23.3"),
+			wantErr:             true,
+		},
+		{
+			name:                "invalid agent pool version",
+			orchestratorVersion: to.StringPtr("latest"),
+			wantErr:             true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+			g.Expect(AddToScheme(scheme)).To(Succeed())
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				&clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "my-cluster",
+						Namespace: "default",
+					},
+					Spec: clusterv1.ClusterSpec{
+						ControlPlaneRef: &corev1.ObjectReference{
+							Name: "my-cluster-control-plane",
+						},
+					},
+				},
+				&AzureManagedControlPlane{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "my-cluster-control-plane",
+						Namespace: "default",
+					},
+					Spec: AzureManagedControlPlaneSpec{
+						Version: "v1.22.4",
+					},
+				},
+			).Build()
+
+			ammp := &AzureManagedMachinePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pool1",
+					Namespace: "default",
+					Labels: map[string]string{
+						clusterv1.ClusterLabelName: "my-cluster",
+					},
+				},
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:                "User",
+					SKU:                 "StandardD2S_V3",
+					OrchestratorVersion: tc.orchestratorVersion,
+				},
+			}
+			err := ammp.ValidateCreate(c)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+
+			err = ammp.ValidateUpdate(ammp.DeepCopy(), c)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
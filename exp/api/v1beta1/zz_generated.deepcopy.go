@@ -1,3 +1,4 @@
+//go:build !ignore_autogenerated
 // +build !ignore_autogenerated
 
 /*
@@ -38,6 +39,11 @@ func (in *AADProfile) DeepCopyInto(out *AADProfile) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.EnableAzureRBAC != nil {
+		in, out := &in.EnableAzureRBAC, &out.EnableAzureRBAC
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AADProfile.
@@ -85,6 +91,128 @@ func (in *APIServerAccessProfile) DeepCopy() *APIServerAccessProfile {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddonProfile) DeepCopyInto(out *AddonProfile) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonProfile.
+func (in *AddonProfile) DeepCopy() *AddonProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(AddonProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoScalerProfile) DeepCopyInto(out *AutoScalerProfile) {
+	*out = *in
+	if in.BalanceSimilarNodeGroups != nil {
+		in, out := &in.BalanceSimilarNodeGroups, &out.BalanceSimilarNodeGroups
+		*out = new(string)
+		**out = **in
+	}
+	if in.Expander != nil {
+		in, out := &in.Expander, &out.Expander
+		*out = new(string)
+		**out = **in
+	}
+	if in.MaxEmptyBulkDelete != nil {
+		in, out := &in.MaxEmptyBulkDelete, &out.MaxEmptyBulkDelete
+		*out = new(string)
+		**out = **in
+	}
+	if in.MaxGracefulTerminationSec != nil {
+		in, out := &in.MaxGracefulTerminationSec, &out.MaxGracefulTerminationSec
+		*out = new(string)
+		**out = **in
+	}
+	if in.MaxNodeProvisionTime != nil {
+		in, out := &in.MaxNodeProvisionTime, &out.MaxNodeProvisionTime
+		*out = new(string)
+		**out = **in
+	}
+	if in.MaxTotalUnreadyPercentage != nil {
+		in, out := &in.MaxTotalUnreadyPercentage, &out.MaxTotalUnreadyPercentage
+		*out = new(string)
+		**out = **in
+	}
+	if in.NewPodScaleUpDelay != nil {
+		in, out := &in.NewPodScaleUpDelay, &out.NewPodScaleUpDelay
+		*out = new(string)
+		**out = **in
+	}
+	if in.OkTotalUnreadyCount != nil {
+		in, out := &in.OkTotalUnreadyCount, &out.OkTotalUnreadyCount
+		*out = new(string)
+		**out = **in
+	}
+	if in.ScanInterval != nil {
+		in, out := &in.ScanInterval, &out.ScanInterval
+		*out = new(string)
+		**out = **in
+	}
+	if in.ScaleDownDelayAfterAdd != nil {
+		in, out := &in.ScaleDownDelayAfterAdd, &out.ScaleDownDelayAfterAdd
+		*out = new(string)
+		**out = **in
+	}
+	if in.ScaleDownDelayAfterDelete != nil {
+		in, out := &in.ScaleDownDelayAfterDelete, &out.ScaleDownDelayAfterDelete
+		*out = new(string)
+		**out = **in
+	}
+	if in.ScaleDownDelayAfterFailure != nil {
+		in, out := &in.ScaleDownDelayAfterFailure, &out.ScaleDownDelayAfterFailure
+		*out = new(string)
+		**out = **in
+	}
+	if in.ScaleDownUnneededTime != nil {
+		in, out := &in.ScaleDownUnneededTime, &out.ScaleDownUnneededTime
+		*out = new(string)
+		**out = **in
+	}
+	if in.ScaleDownUnreadyTime != nil {
+		in, out := &in.ScaleDownUnreadyTime, &out.ScaleDownUnreadyTime
+		*out = new(string)
+		**out = **in
+	}
+	if in.ScaleDownUtilizationThreshold != nil {
+		in, out := &in.ScaleDownUtilizationThreshold, &out.ScaleDownUtilizationThreshold
+		*out = new(string)
+		**out = **in
+	}
+	if in.SkipNodesWithLocalStorage != nil {
+		in, out := &in.SkipNodesWithLocalStorage, &out.SkipNodesWithLocalStorage
+		*out = new(string)
+		**out = **in
+	}
+	if in.SkipNodesWithSystemPods != nil {
+		in, out := &in.SkipNodesWithSystemPods, &out.SkipNodesWithSystemPods
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoScalerProfile.
+func (in *AutoScalerProfile) DeepCopy() *AutoScalerProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoScalerProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureMachinePool) DeepCopyInto(out *AzureMachinePool) {
 	*out = *in
@@ -374,6 +502,11 @@ func (in *AzureMachinePoolSpec) DeepCopyInto(out *AzureMachinePoolSpec) {
 		*out = make([]apiv1beta1.UserAssignedIdentity, len(*in))
 		copy(*out, *in)
 	}
+	if in.RoleAssignmentPrincipalIDs != nil {
+		in, out := &in.RoleAssignmentPrincipalIDs, &out.RoleAssignmentPrincipalIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	in.Strategy.DeepCopyInto(&out.Strategy)
 	if in.NodeDrainTimeout != nil {
 		in, out := &in.NodeDrainTimeout, &out.NodeDrainTimeout
@@ -602,7 +735,12 @@ func (in *AzureManagedControlPlaneList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureManagedControlPlaneSpec) DeepCopyInto(out *AzureManagedControlPlaneSpec) {
 	*out = *in
-	out.VirtualNetwork = in.VirtualNetwork
+	if in.DiskEncryptionSetID != nil {
+		in, out := &in.DiskEncryptionSetID, &out.DiskEncryptionSetID
+		*out = new(string)
+		**out = **in
+	}
+	in.VirtualNetwork.DeepCopyInto(&out.VirtualNetwork)
 	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
 	if in.AdditionalTags != nil {
 		in, out := &in.AdditionalTags, &out.AdditionalTags
@@ -626,6 +764,11 @@ func (in *AzureManagedControlPlaneSpec) DeepCopyInto(out *AzureManagedControlPla
 		*out = new(string)
 		**out = **in
 	}
+	if in.DNSPrefix != nil {
+		in, out := &in.DNSPrefix, &out.DNSPrefix
+		*out = new(string)
+		**out = **in
+	}
 	if in.LoadBalancerSKU != nil {
 		in, out := &in.LoadBalancerSKU, &out.LoadBalancerSKU
 		*out = new(string)
@@ -651,11 +794,63 @@ func (in *AzureManagedControlPlaneSpec) DeepCopyInto(out *AzureManagedControlPla
 		*out = new(LoadBalancerProfile)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.OutboundType != nil {
+		in, out := &in.OutboundType, &out.OutboundType
+		*out = new(string)
+		**out = **in
+	}
 	if in.APIServerAccessProfile != nil {
 		in, out := &in.APIServerAccessProfile, &out.APIServerAccessProfile
 		*out = new(APIServerAccessProfile)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.KubeconfigSecret != nil {
+		in, out := &in.KubeconfigSecret, &out.KubeconfigSecret
+		*out = new(KubeconfigSecret)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindow)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeOSUpgradeMaintenanceWindow != nil {
+		in, out := &in.NodeOSUpgradeMaintenanceWindow, &out.NodeOSUpgradeMaintenanceWindow
+		*out = new(MaintenanceWindow)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DisableLocalAccounts != nil {
+		in, out := &in.DisableLocalAccounts, &out.DisableLocalAccounts
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CreateTimeout != nil {
+		in, out := &in.CreateTimeout, &out.CreateTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.AutoScalerProfile != nil {
+		in, out := &in.AutoScalerProfile, &out.AutoScalerProfile
+		*out = new(AutoScalerProfile)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EgressCheck != nil {
+		in, out := &in.EgressCheck, &out.EgressCheck
+		*out = new(EgressCheck)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AddonProfiles != nil {
+		in, out := &in.AddonProfiles, &out.AddonProfiles
+		*out = make([]AddonProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeResourceGroupRoleAssignmentPrincipalIDs != nil {
+		in, out := &in.NodeResourceGroupRoleAssignmentPrincipalIDs, &out.NodeResourceGroupRoleAssignmentPrincipalIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureManagedControlPlaneSpec.
@@ -676,6 +871,13 @@ func (in *AzureManagedControlPlaneStatus) DeepCopyInto(out *AzureManagedControlP
 		*out = make(apiv1beta1.Futures, len(*in))
 		copy(*out, *in)
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(cluster_apiapiv1beta1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureManagedControlPlaneStatus.
@@ -755,16 +957,105 @@ func (in *AzureManagedMachinePoolSpec) DeepCopyInto(out *AzureManagedMachinePool
 		*out = new(string)
 		**out = **in
 	}
+	if in.OrchestratorVersion != nil {
+		in, out := &in.OrchestratorVersion, &out.OrchestratorVersion
+		*out = new(string)
+		**out = **in
+	}
+	if in.SKUSelector != nil {
+		in, out := &in.SKUSelector, &out.SKUSelector
+		*out = new(SKUSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OSType != nil {
+		in, out := &in.OSType, &out.OSType
+		*out = new(string)
+		**out = **in
+	}
+	if in.ScaleSetPriority != nil {
+		in, out := &in.ScaleSetPriority, &out.ScaleSetPriority
+		*out = new(string)
+		**out = **in
+	}
 	if in.OSDiskSizeGB != nil {
 		in, out := &in.OSDiskSizeGB, &out.OSDiskSizeGB
 		*out = new(int32)
 		**out = **in
 	}
+	if in.OSDiskType != nil {
+		in, out := &in.OSDiskType, &out.OSDiskType
+		*out = new(string)
+		**out = **in
+	}
+	if in.EnableEncryptionAtHost != nil {
+		in, out := &in.EnableEncryptionAtHost, &out.EnableEncryptionAtHost
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ProximityPlacementGroupID != nil {
+		in, out := &in.ProximityPlacementGroupID, &out.ProximityPlacementGroupID
+		*out = new(string)
+		**out = **in
+	}
+	if in.EnableNodePublicIP != nil {
+		in, out := &in.EnableNodePublicIP, &out.EnableNodePublicIP
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NodePublicIPPrefixID != nil {
+		in, out := &in.NodePublicIPPrefixID, &out.NodePublicIPPrefixID
+		*out = new(string)
+		**out = **in
+	}
+	if in.PodSubnetName != nil {
+		in, out := &in.PodSubnetName, &out.PodSubnetName
+		*out = new(string)
+		**out = **in
+	}
 	if in.ProviderIDList != nil {
 		in, out := &in.ProviderIDList, &out.ProviderIDList
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.NodeDrainTimeout != nil {
+		in, out := &in.NodeDrainTimeout, &out.NodeDrainTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.StartupTaint != nil {
+		in, out := &in.StartupTaint, &out.StartupTaint
+		*out = new(StartupTaint)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Taints != nil {
+		in, out := &in.Taints, &out.Taints
+		*out = make([]Taint, len(*in))
+		copy(*out, *in)
+	}
+	if in.GPUSharing != nil {
+		in, out := &in.GPUSharing, &out.GPUSharing
+		*out = new(GPUSharing)
+		**out = **in
+	}
+	if in.Scaling != nil {
+		in, out := &in.Scaling, &out.Scaling
+		*out = new(ManagedMachinePoolScaling)
+		**out = **in
+	}
+	if in.NodeLabels != nil {
+		in, out := &in.NodeLabels, &out.NodeLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AutoscalerNodeAnnotations != nil {
+		in, out := &in.AutoscalerNodeAnnotations, &out.AutoscalerNodeAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureManagedMachinePoolSpec.
@@ -790,6 +1081,13 @@ func (in *AzureManagedMachinePoolStatus) DeepCopyInto(out *AzureManagedMachinePo
 		*out = new(string)
 		**out = **in
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(cluster_apiapiv1beta1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureManagedMachinePoolStatus.
@@ -802,6 +1100,75 @@ func (in *AzureManagedMachinePoolStatus) DeepCopy() *AzureManagedMachinePoolStat
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EgressCheck) DeepCopyInto(out *EgressCheck) {
+	*out = *in
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EgressCheck.
+func (in *EgressCheck) DeepCopy() *EgressCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(EgressCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUSharing) DeepCopyInto(out *GPUSharing) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUSharing.
+func (in *GPUSharing) DeepCopy() *GPUSharing {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUSharing)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeconfigSecret) DeepCopyInto(out *KubeconfigSecret) {
+	*out = *in
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeconfigSecret.
+func (in *KubeconfigSecret) DeepCopy() *KubeconfigSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeconfigSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoadBalancerProfile) DeepCopyInto(out *LoadBalancerProfile) {
 	*out = *in
@@ -867,6 +1234,35 @@ func (in *MachineRollingUpdateDeployment) DeepCopy() *MachineRollingUpdateDeploy
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	if in.TimeInWeek != nil {
+		in, out := &in.TimeInWeek, &out.TimeInWeek
+		*out = make([]TimeInWeek, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NotAllowedTime != nil {
+		in, out := &in.NotAllowedTime, &out.NotAllowedTime
+		*out = make([]TimeSpan, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagedControlPlaneSubnet) DeepCopyInto(out *ManagedControlPlaneSubnet) {
 	*out = *in
@@ -886,6 +1282,11 @@ func (in *ManagedControlPlaneSubnet) DeepCopy() *ManagedControlPlaneSubnet {
 func (in *ManagedControlPlaneVirtualNetwork) DeepCopyInto(out *ManagedControlPlaneVirtualNetwork) {
 	*out = *in
 	out.Subnet = in.Subnet
+	if in.PodSubnets != nil {
+		in, out := &in.PodSubnets, &out.PodSubnets
+		*out = make([]ManagedControlPlaneSubnet, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedControlPlaneVirtualNetwork.
@@ -898,6 +1299,21 @@ func (in *ManagedControlPlaneVirtualNetwork) DeepCopy() *ManagedControlPlaneVirt
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedMachinePoolScaling) DeepCopyInto(out *ManagedMachinePoolScaling) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedMachinePoolScaling.
+func (in *ManagedMachinePoolScaling) DeepCopy() *ManagedMachinePoolScaling {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedMachinePoolScaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SKU) DeepCopyInto(out *SKU) {
 	*out = *in
@@ -912,3 +1328,105 @@ func (in *SKU) DeepCopy() *SKU {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SKUSelector) DeepCopyInto(out *SKUSelector) {
+	*out = *in
+	if in.Family != nil {
+		in, out := &in.Family, &out.Family
+		*out = new(string)
+		**out = **in
+	}
+	if in.MinVCPUs != nil {
+		in, out := &in.MinVCPUs, &out.MinVCPUs
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MinMemoryGB != nil {
+		in, out := &in.MinMemoryGB, &out.MinMemoryGB
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SKUSelector.
+func (in *SKUSelector) DeepCopy() *SKUSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(SKUSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StartupTaint) DeepCopyInto(out *StartupTaint) {
+	*out = *in
+	if in.ReadinessConditionType != nil {
+		in, out := &in.ReadinessConditionType, &out.ReadinessConditionType
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StartupTaint.
+func (in *StartupTaint) DeepCopy() *StartupTaint {
+	if in == nil {
+		return nil
+	}
+	out := new(StartupTaint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Taint) DeepCopyInto(out *Taint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Taint.
+func (in *Taint) DeepCopy() *Taint {
+	if in == nil {
+		return nil
+	}
+	out := new(Taint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeInWeek) DeepCopyInto(out *TimeInWeek) {
+	*out = *in
+	if in.HourSlots != nil {
+		in, out := &in.HourSlots, &out.HourSlots
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeInWeek.
+func (in *TimeInWeek) DeepCopy() *TimeInWeek {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeInWeek)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeSpan) DeepCopyInto(out *TimeSpan) {
+	*out = *in
+	in.Start.DeepCopyInto(&out.Start)
+	in.End.DeepCopyInto(&out.End)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeSpan.
+func (in *TimeSpan) DeepCopy() *TimeSpan {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeSpan)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -52,12 +52,13 @@ type (
 	// AzureMachinePoolReconciler reconciles an AzureMachinePool object.
 	AzureMachinePoolReconciler struct {
 		client.Client
-		Log                           logr.Logger
-		Scheme                        *runtime.Scheme
-		Recorder                      record.EventRecorder
-		ReconcileTimeout              time.Duration
-		WatchFilterValue              string
-		createAzureMachinePoolService azureMachinePoolServiceCreator
+		Log                             logr.Logger
+		Scheme                          *runtime.Scheme
+		Recorder                        record.EventRecorder
+		ReconcileTimeout                time.Duration
+		WatchFilterValue                string
+		RoleAssignmentMaxCreateAttempts int
+		createAzureMachinePoolService   azureMachinePoolServiceCreator
 	}
 
 	// annotationReaderWriter provides an interface to read and write annotations.
@@ -67,16 +68,17 @@ type (
 	}
 )
 
-type azureMachinePoolServiceCreator func(machinePoolScope *scope.MachinePoolScope) (*azureMachinePoolService, error)
+type azureMachinePoolServiceCreator func(machinePoolScope *scope.MachinePoolScope, recorder record.EventRecorder, roleAssignmentMaxCreateAttempts int) (*azureMachinePoolService, error)
 
 // NewAzureMachinePoolReconciler returns a new AzureMachinePoolReconciler instance.
-func NewAzureMachinePoolReconciler(client client.Client, log logr.Logger, recorder record.EventRecorder, reconcileTimeout time.Duration, watchFilterValue string) *AzureMachinePoolReconciler {
+func NewAzureMachinePoolReconciler(client client.Client, log logr.Logger, recorder record.EventRecorder, reconcileTimeout time.Duration, watchFilterValue string, roleAssignmentMaxCreateAttempts int) *AzureMachinePoolReconciler {
 	ampr := &AzureMachinePoolReconciler{
-		Client:           client,
-		Log:              log,
-		Recorder:         recorder,
-		ReconcileTimeout: reconcileTimeout,
-		WatchFilterValue: watchFilterValue,
+		Client:                          client,
+		Log:                             log,
+		Recorder:                        recorder,
+		ReconcileTimeout:                reconcileTimeout,
+		WatchFilterValue:                watchFilterValue,
+		RoleAssignmentMaxCreateAttempts: roleAssignmentMaxCreateAttempts,
 	}
 
 	ampr.createAzureMachinePoolService = newAzureMachinePoolService
@@ -287,7 +289,7 @@ func (ampr *AzureMachinePoolReconciler) reconcileNormal(ctx context.Context, mac
 		return reconcile.Result{}, nil
 	}
 
-	ams, err := ampr.createAzureMachinePoolService(machinePoolScope)
+	ams, err := ampr.createAzureMachinePoolService(machinePoolScope, ampr.Recorder, ampr.RoleAssignmentMaxCreateAttempts)
 	if err != nil {
 		return reconcile.Result{}, errors.Wrap(err, "failed creating a newAzureMachinePoolService")
 	}
@@ -343,7 +345,7 @@ func (ampr *AzureMachinePoolReconciler) reconcileDelete(ctx context.Context, mac
 	machinePoolScope.V(2).Info("handling deleted AzureMachinePool")
 
 	if infracontroller.ShouldDeleteIndividualResources(ctx, clusterScope) {
-		amps, err := ampr.createAzureMachinePoolService(machinePoolScope)
+		amps, err := ampr.createAzureMachinePoolService(machinePoolScope, ampr.Recorder, ampr.RoleAssignmentMaxCreateAttempts)
 		if err != nil {
 			return reconcile.Result{}, errors.Wrap(err, "failed creating a new AzureMachinePoolService")
 		}
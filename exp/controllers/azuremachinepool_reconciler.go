@@ -20,6 +20,7 @@ import (
 	"context"
 
 	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/record"
 
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
@@ -42,7 +43,7 @@ type azureMachinePoolService struct {
 var _ azure.Reconciler = (*azureMachinePoolService)(nil)
 
 // newAzureMachinePoolService populates all the services based on input scope.
-func newAzureMachinePoolService(machinePoolScope *scope.MachinePoolScope) (*azureMachinePoolService, error) {
+func newAzureMachinePoolService(machinePoolScope *scope.MachinePoolScope, recorder record.EventRecorder, roleAssignmentMaxCreateAttempts int) (*azureMachinePoolService, error) {
 	cache, err := resourceskus.GetCache(machinePoolScope, machinePoolScope.Location())
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create a NewCache")
@@ -52,8 +53,8 @@ func newAzureMachinePoolService(machinePoolScope *scope.MachinePoolScope) (*azur
 		scope:                      machinePoolScope,
 		virtualMachinesScaleSetSvc: scalesets.NewService(machinePoolScope, cache),
 		skuCache:                   cache,
-		roleAssignmentsSvc:         roleassignments.New(machinePoolScope),
-		vmssExtensionSvc:           vmssextensions.New(machinePoolScope),
+		roleAssignmentsSvc:         roleassignments.New(machinePoolScope, roleAssignmentMaxCreateAttempts),
+		vmssExtensionSvc:           vmssextensions.New(machinePoolScope, recorder),
 	}, nil
 }
 
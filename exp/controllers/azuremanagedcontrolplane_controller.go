@@ -39,6 +39,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	infracontroller "sigs.k8s.io/cluster-api-provider-azure/controllers"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
@@ -50,10 +51,11 @@ import (
 // AzureManagedControlPlaneReconciler reconciles an AzureManagedControlPlane object.
 type AzureManagedControlPlaneReconciler struct {
 	client.Client
-	Log              logr.Logger
-	Recorder         record.EventRecorder
-	ReconcileTimeout time.Duration
-	WatchFilterValue string
+	Log                             logr.Logger
+	Recorder                        record.EventRecorder
+	ReconcileTimeout                time.Duration
+	WatchFilterValue                string
+	RoleAssignmentMaxCreateAttempts int
 }
 
 // SetupWithManager initializes this controller with a manager.
@@ -212,7 +214,26 @@ func (amcpr *AzureManagedControlPlaneReconciler) reconcileNormal(ctx context.Con
 		return reconcile.Result{}, err
 	}
 
-	if err := newAzureManagedControlPlaneReconciler(scope).Reconcile(ctx); err != nil {
+	// Roll up the conditions of the agent pools first, so that the summary of the control plane reflects them even
+	// when reconciling the managed cluster fails.
+	if err := scope.SetAgentPoolsReadyCondition(ctx); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to aggregate the conditions of the agent pools")
+	}
+
+	if err := newAzureManagedControlPlaneReconciler(scope, amcpr.RoleAssignmentMaxCreateAttempts).Reconcile(ctx); err != nil {
+		var reconcileError azure.ReconcileError
+		if errors.As(err, &reconcileError) {
+			if reconcileError.IsTerminal() {
+				amcpr.Recorder.Eventf(scope.ControlPlane, corev1.EventTypeWarning, "ReconcileError", errors.Wrap(err, "failed to reconcile AzureManagedControlPlane").Error())
+				scope.Error(err, "failed to reconcile AzureManagedControlPlane", "name", scope.ControlPlane.Name)
+				return reconcile.Result{}, nil
+			}
+
+			if reconcileError.IsTransient() {
+				scope.V(4).Info("failed to reconcile AzureManagedControlPlane", "transient_error", err)
+				return reconcile.Result{RequeueAfter: reconcileError.RequeueAfter()}, nil
+			}
+		}
 		return reconcile.Result{}, errors.Wrapf(err, "error creating AzureManagedControlPlane %s/%s", scope.ControlPlane.Namespace, scope.ControlPlane.Name)
 	}
 
@@ -229,7 +250,7 @@ func (amcpr *AzureManagedControlPlaneReconciler) reconcileDelete(ctx context.Con
 
 	scope.Logger.Info("Reconciling AzureManagedControlPlane delete")
 
-	if err := newAzureManagedControlPlaneReconciler(scope).Delete(ctx); err != nil {
+	if err := newAzureManagedControlPlaneReconciler(scope, amcpr.RoleAssignmentMaxCreateAttempts).Delete(ctx); err != nil {
 		return reconcile.Result{}, errors.Wrapf(err, "error deleting AzureManagedControlPlane %s/%s", scope.ControlPlane.Namespace, scope.ControlPlane.Name)
 	}
 
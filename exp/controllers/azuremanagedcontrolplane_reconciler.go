@@ -20,6 +20,7 @@ import (
 	"context"
 
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/cluster-api/util/secret"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -27,7 +28,10 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/groups"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/maintenanceconfigurations"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/managedclusters"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceproviders"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/tags"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualnetworks"
@@ -36,25 +40,52 @@ import (
 
 // azureManagedControlPlaneService contains the services required by the cluster controller.
 type azureManagedControlPlaneService struct {
-	kubeclient         client.Client
-	scope              managedclusters.ManagedClusterScope
-	managedClustersSvc azure.Reconciler
-	groupsSvc          azure.Reconciler
-	vnetSvc            azure.Reconciler
-	subnetsSvc         azure.Reconciler
-	tagsSvc            azure.Reconciler
+	kubeclient                   client.Client
+	scope                        managedclusters.ManagedClusterScope
+	resourceProvidersSvc         azure.Reconciler
+	managedClustersSvc           azure.Reconciler
+	maintenanceConfigurationsSvc azure.Reconciler
+	groupsSvc                    azure.Reconciler
+	vnetSvc                      azure.Reconciler
+	subnetsSvc                   azure.Reconciler
+	roleAssignmentsSvc           azure.Reconciler
+	tagsSvc                      azure.Reconciler
+	egressVerifier               EgressVerifier
+	kubeconfigGetter             KubeconfigGetter
+}
+
+// EgressVerifier is a service interface for verifying the egress of a cluster with user-defined routing before its
+// control plane is marked ready.
+type EgressVerifier interface {
+	VerifyEgress(context.Context) error
+}
+
+// KubeconfigGetter is a service interface for fetching the kubeconfig of a managed cluster to store in its kubeconfig
+// secrets.
+type KubeconfigGetter interface {
+	AreLocalAccountsDisabled() bool
+	GetAdminKubeconfig(context.Context) ([]byte, error)
+	GetUserKubeconfig(context.Context) ([]byte, error)
 }
 
 // newAzureManagedControlPlaneReconciler populates all the services based on input scope.
-func newAzureManagedControlPlaneReconciler(scope *scope.ManagedControlPlaneScope) *azureManagedControlPlaneService {
+func newAzureManagedControlPlaneReconciler(scope *scope.ManagedControlPlaneScope, roleAssignmentMaxCreateAttempts int) *azureManagedControlPlaneService {
+	managedClustersSvc := managedclusters.New(scope)
+	scope.ManagedClustersClient = managedClustersSvc.Client
+
 	return &azureManagedControlPlaneService{
-		kubeclient:         scope.Client,
-		scope:              scope,
-		managedClustersSvc: managedclusters.New(scope),
-		groupsSvc:          groups.New(scope),
-		vnetSvc:            virtualnetworks.New(scope),
-		subnetsSvc:         subnets.New(scope),
-		tagsSvc:            tags.New(scope),
+		kubeclient:                   scope.Client,
+		scope:                        scope,
+		resourceProvidersSvc:         resourceproviders.New(scope),
+		managedClustersSvc:           managedClustersSvc,
+		maintenanceConfigurationsSvc: maintenanceconfigurations.New(scope),
+		groupsSvc:                    groups.New(scope),
+		vnetSvc:                      virtualnetworks.New(scope),
+		subnetsSvc:                   subnets.New(scope),
+		roleAssignmentsSvc:           roleassignments.New(scope, roleAssignmentMaxCreateAttempts),
+		tagsSvc:                      tags.New(scope),
+		egressVerifier:               scope,
+		kubeconfigGetter:             scope,
 	}
 }
 
@@ -63,6 +94,10 @@ func (r *azureManagedControlPlaneService) Reconcile(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureManagedControlPlaneService.Reconcile")
 	defer done()
 
+	if err := r.resourceProvidersSvc.Reconcile(ctx); err != nil {
+		return errors.Wrap(err, "failed to check resource provider registration")
+	}
+
 	if err := r.groupsSvc.Reconcile(ctx); err != nil {
 		return errors.Wrap(err, "failed to reconcile managed cluster resource group")
 	}
@@ -80,6 +115,14 @@ func (r *azureManagedControlPlaneService) Reconcile(ctx context.Context) error {
 		return errors.Wrapf(err, "failed to reconcile managed cluster")
 	}
 
+	if err := r.maintenanceConfigurationsSvc.Reconcile(ctx); err != nil {
+		return errors.Wrap(err, "failed to reconcile maintenance configurations")
+	}
+
+	if err := r.roleAssignmentsSvc.Reconcile(ctx); err != nil {
+		return errors.Wrap(err, "failed to reconcile role assignments")
+	}
+
 	if err := r.reconcileKubeconfig(ctx); err != nil {
 		return errors.Wrap(err, "failed to reconcile kubeconfig secret")
 	}
@@ -88,6 +131,10 @@ func (r *azureManagedControlPlaneService) Reconcile(ctx context.Context) error {
 		return errors.Wrap(err, "unable to update tags")
 	}
 
+	if err := r.egressVerifier.VerifyEgress(ctx); err != nil {
+		return errors.Wrap(err, "failed to verify egress")
+	}
+
 	return nil
 }
 
@@ -115,21 +162,54 @@ func (r *azureManagedControlPlaneService) reconcileKubeconfig(ctx context.Contex
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureManagedControlPlaneService.reconcileKubeconfig")
 	defer done()
 
-	kubeConfigData := r.scope.GetKubeConfigData()
-	if kubeConfigData == nil {
-		return nil
+	// Always fetch the kubeconfig in case of rotation.
+	getKubeconfig := r.kubeconfigGetter.GetAdminKubeconfig
+	if r.kubeconfigGetter.AreLocalAccountsDisabled() {
+		// The admin kubeconfig holds static credentials, which can't be fetched when local accounts are disabled.
+		getKubeconfig = r.kubeconfigGetter.GetUserKubeconfig
+	}
+	kubeConfigData, err := getKubeconfig(ctx)
+	if err != nil {
+		return err
 	}
-	kubeConfigSecret := r.scope.MakeEmptyKubeConfigSecret()
 
-	// Always update credentials in case of rotation
-	if _, err := controllerutil.CreateOrUpdate(ctx, r.kubeclient, &kubeConfigSecret, func() error {
-		kubeConfigSecret.Data = map[string][]byte{
-			secret.KubeconfigDataName: kubeConfigData,
+	for _, desired := range r.scope.MakeEmptyKubeConfigSecrets() {
+		desired := desired
+		kubeConfigSecret := desired.DeepCopy()
+
+		if _, err := controllerutil.CreateOrUpdate(ctx, r.kubeclient, kubeConfigSecret, func() error {
+			// A secret that already exists is only updated if it was created for this control plane, so that a
+			// kubeconfigSecret name cannot be used to overwrite an unrelated secret.
+			if kubeConfigSecret.ResourceVersion != "" && !sameController(kubeConfigSecret, &desired) {
+				return errors.Errorf("secret %s/%s is not controlled by the control plane", kubeConfigSecret.Namespace, kubeConfigSecret.Name)
+			}
+			for k, v := range desired.Labels {
+				if kubeConfigSecret.Labels == nil {
+					kubeConfigSecret.Labels = map[string]string{}
+				}
+				kubeConfigSecret.Labels[k] = v
+			}
+			for k, v := range desired.Annotations {
+				if kubeConfigSecret.Annotations == nil {
+					kubeConfigSecret.Annotations = map[string]string{}
+				}
+				kubeConfigSecret.Annotations[k] = v
+			}
+			kubeConfigSecret.Data = map[string][]byte{
+				secret.KubeconfigDataName: kubeConfigData,
+			}
+			return nil
+		}); err != nil {
+			return errors.Wrapf(err, "failed to reconcile kubeconfig secret %s for cluster", desired.Name)
 		}
-		return nil
-	}); err != nil {
-		return errors.Wrap(err, "failed to kubeconfig secret for cluster")
 	}
 
 	return nil
 }
+
+// sameController returns true if both objects are controlled by the same object.
+func sameController(obj, other metav1.Object) bool {
+	controller, otherController := metav1.GetControllerOf(obj), metav1.GetControllerOf(other)
+	return controller != nil && otherController != nil && controller.UID == otherController.UID &&
+		controller.Kind == otherController.Kind && controller.Name == otherController.Name
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/secret"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+)
+
+// fakeCredentialsClient returns the kubeconfigs of a managed cluster.
+type fakeCredentialsClient struct{}
+
+func (fakeCredentialsClient) GetCredentials(context.Context, string, string) ([]byte, error) {
+	return []byte("admin-kubeconfig"), nil
+}
+
+func (fakeCredentialsClient) GetUserCredentials(context.Context, string, string) ([]byte, error) {
+	return []byte("user-kubeconfig"), nil
+}
+
+func TestReconcileKubeconfigSecrets(t *testing.T) {
+	g := NewWithT(t)
+	scheme := newScheme(g)
+	g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	managedControlPlaneScope := &scope.ManagedControlPlaneScope{
+		Client: kubeClient,
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      clusterName,
+				Namespace: "default",
+			},
+		},
+		ControlPlane: &infrav1exp.AzureManagedControlPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cpName,
+				Namespace: "default",
+			},
+			Spec: infrav1exp.AzureManagedControlPlaneSpec{
+				KubeconfigSecret: &infrav1exp.KubeconfigSecret{
+					Name: pointer.String("my-gitops-kubeconfig"),
+					Labels: map[string]string{
+						"gitops.example.com/cluster": clusterName,
+					},
+					Annotations: map[string]string{
+						"gitops.example.com/sync": "true",
+					},
+				},
+			},
+		},
+	}
+	managedControlPlaneScope.ManagedClustersClient = fakeCredentialsClient{}
+
+	r := &azureManagedControlPlaneService{
+		kubeclient:       kubeClient,
+		scope:            managedControlPlaneScope,
+		kubeconfigGetter: managedControlPlaneScope,
+	}
+	g.Expect(r.reconcileKubeconfig(context.TODO())).To(Succeed())
+	// The secrets controlled by the control plane are updated on later reconciles.
+	g.Expect(r.reconcileKubeconfig(context.TODO())).To(Succeed())
+
+	for _, name := range []string{secret.Name(clusterName, secret.Kubeconfig), "my-gitops-kubeconfig"} {
+		kubeConfigSecret := &corev1.Secret{}
+		g.Expect(kubeClient.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: name}, kubeConfigSecret)).To(Succeed())
+		g.Expect(kubeConfigSecret.Labels).To(HaveKeyWithValue("gitops.example.com/cluster", clusterName))
+		g.Expect(kubeConfigSecret.Annotations).To(HaveKeyWithValue("gitops.example.com/sync", "true"))
+		g.Expect(kubeConfigSecret.Data).To(HaveKeyWithValue(secret.KubeconfigDataName, []byte("admin-kubeconfig")))
+		g.Expect(kubeConfigSecret.OwnerReferences).To(HaveLen(1))
+		g.Expect(kubeConfigSecret.OwnerReferences[0].Name).To(Equal(cpName))
+	}
+}
+
+func TestReconcileKubeconfigSecretsNotControlled(t *testing.T) {
+	g := NewWithT(t)
+	scheme := newScheme(g)
+	g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	unrelated := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "unrelated",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			"password": []byte("hunter2"),
+		},
+	}
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(unrelated).Build()
+
+	managedControlPlaneScope := &scope.ManagedControlPlaneScope{
+		Client: kubeClient,
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      clusterName,
+				Namespace: "default",
+			},
+		},
+		ControlPlane: &infrav1exp.AzureManagedControlPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cpName,
+				Namespace: "default",
+				UID:       "cp-uid",
+			},
+			Spec: infrav1exp.AzureManagedControlPlaneSpec{
+				KubeconfigSecret: &infrav1exp.KubeconfigSecret{
+					Name: pointer.String("unrelated"),
+				},
+			},
+		},
+	}
+	managedControlPlaneScope.ManagedClustersClient = fakeCredentialsClient{}
+
+	r := &azureManagedControlPlaneService{
+		kubeclient:       kubeClient,
+		scope:            managedControlPlaneScope,
+		kubeconfigGetter: managedControlPlaneScope,
+	}
+	g.Expect(r.reconcileKubeconfig(context.TODO())).NotTo(Succeed())
+
+	kubeConfigSecret := &corev1.Secret{}
+	g.Expect(kubeClient.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: "unrelated"}, kubeConfigSecret)).To(Succeed())
+	g.Expect(kubeConfigSecret.Data).To(Equal(unrelated.Data))
+	g.Expect(kubeConfigSecret.OwnerReferences).To(BeEmpty())
+}
+
+func TestReconcileKubeconfigSecretsWithoutLocalAccounts(t *testing.T) {
+	g := NewWithT(t)
+	scheme := newScheme(g)
+	g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	managedControlPlaneScope := &scope.ManagedControlPlaneScope{
+		Client: kubeClient,
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      clusterName,
+				Namespace: "default",
+			},
+		},
+		ControlPlane: &infrav1exp.AzureManagedControlPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cpName,
+				Namespace: "default",
+			},
+			Spec: infrav1exp.AzureManagedControlPlaneSpec{
+				DisableLocalAccounts: pointer.Bool(true),
+			},
+		},
+		ManagedClustersClient: fakeCredentialsClient{},
+	}
+
+	r := &azureManagedControlPlaneService{
+		kubeclient:       kubeClient,
+		scope:            managedControlPlaneScope,
+		kubeconfigGetter: managedControlPlaneScope,
+	}
+	g.Expect(r.reconcileKubeconfig(context.TODO())).To(Succeed())
+
+	// The user kubeconfig is stored as the admin kubeconfig is not available without local accounts.
+	kubeConfigSecret := &corev1.Secret{}
+	g.Expect(kubeClient.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: secret.Name(clusterName, secret.Kubeconfig)}, kubeConfigSecret)).To(Succeed())
+	g.Expect(kubeConfigSecret.Data).To(HaveKeyWithValue(secret.KubeconfigDataName, []byte("user-kubeconfig")))
+}
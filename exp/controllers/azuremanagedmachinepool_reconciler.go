@@ -34,9 +34,15 @@ import (
 type (
 	// azureManagedMachinePoolService contains the services required by the cluster controller.
 	azureManagedMachinePoolService struct {
-		scope         agentpools.ManagedMachinePoolScope
-		agentPoolsSvc azure.Reconciler
-		scaleSetsSvc  NodeLister
+		scope               agentpools.ManagedMachinePoolScope
+		agentPoolsSvc       azure.Reconciler
+		scaleSetsSvc        NodeLister
+		nodeDrainer         AgentPoolNodeDrainer
+		startupTaintRemover AgentPoolStartupTaintRemover
+		nodeLabelRemover    AgentPoolNodeLabelRemover
+		nodeAnnotator       AgentPoolNodeAnnotator
+		skuResolver         AgentPoolSKUResolver
+		upgradeGroupWaiter  AgentPoolUpgradeGroupWaiter
 	}
 
 	// AgentPoolVMSSNotFoundError represents a reconcile error when the VMSS for an agent pool can't be found.
@@ -50,6 +56,40 @@ type (
 		ListInstances(context.Context, string, string) ([]compute.VirtualMachineScaleSetVM, error)
 		List(context.Context, string) ([]compute.VirtualMachineScaleSet, error)
 	}
+
+	// AgentPoolNodeDrainer is a service interface for draining the nodes of an agent pool before it is deleted.
+	AgentPoolNodeDrainer interface {
+		DrainAgentPoolNodes(context.Context) error
+	}
+
+	// AgentPoolStartupTaintRemover is a service interface for removing the startup taint from the nodes of an agent
+	// pool once they are ready.
+	AgentPoolStartupTaintRemover interface {
+		RemoveAgentPoolStartupTaints(context.Context) error
+	}
+
+	// AgentPoolNodeLabelRemover is a service interface for removing the node labels removed from the spec of an agent
+	// pool from its nodes.
+	AgentPoolNodeLabelRemover interface {
+		RemoveAgentPoolNodeLabels(context.Context) error
+	}
+
+	// AgentPoolNodeAnnotator is a service interface for applying the autoscaler node annotations of an agent pool to
+	// its nodes.
+	AgentPoolNodeAnnotator interface {
+		AnnotateAgentPoolNodes(context.Context) error
+	}
+
+	// AgentPoolSKUResolver is a service interface for setting the SKU of an agent pool from its SKU selector.
+	AgentPoolSKUResolver interface {
+		SetSKUFromSelector(context.Context) error
+	}
+
+	// AgentPoolUpgradeGroupWaiter is a service interface for waiting for the agent pools of earlier upgrade groups to
+	// be upgraded before an agent pool is upgraded.
+	AgentPoolUpgradeGroupWaiter interface {
+		WaitForAgentPoolUpgradeGroup(context.Context) error
+	}
 )
 
 var (
@@ -79,9 +119,15 @@ func (a *AgentPoolVMSSNotFoundError) Is(target error) bool {
 // newAzureManagedMachinePoolService populates all the services based on input scope.
 func newAzureManagedMachinePoolService(scope *scope.ManagedControlPlaneScope) *azureManagedMachinePoolService {
 	return &azureManagedMachinePoolService{
-		scope:         scope,
-		agentPoolsSvc: agentpools.New(scope),
-		scaleSetsSvc:  scalesets.NewClient(scope),
+		scope:               scope,
+		agentPoolsSvc:       agentpools.New(scope),
+		scaleSetsSvc:        scalesets.NewClient(scope),
+		nodeDrainer:         scope,
+		startupTaintRemover: scope,
+		nodeLabelRemover:    scope,
+		nodeAnnotator:       scope,
+		skuResolver:         scope,
+		upgradeGroupWaiter:  scope,
 	}
 }
 
@@ -91,7 +137,15 @@ func (s *azureManagedMachinePoolService) Reconcile(ctx context.Context) error {
 	defer done()
 
 	s.scope.Info("reconciling machine pool")
-	agentPoolName := s.scope.AgentPoolSpec().Name
+	if err := s.skuResolver.SetSKUFromSelector(ctx); err != nil {
+		return errors.Wrap(err, "failed to set agent pool SKU")
+	}
+
+	agentPoolSpec, err := s.scope.AgentPoolSpec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get agent pool spec")
+	}
+	agentPoolName := agentPoolSpec.Name
 
 	if err := s.agentPoolsSvc.Reconcile(ctx); err != nil {
 		return errors.Wrapf(err, "failed to reconcile machine pool %s", agentPoolName)
@@ -128,8 +182,24 @@ func (s *azureManagedMachinePoolService) Reconcile(ctx context.Context) error {
 
 	s.scope.SetAgentPoolProviderIDList(providerIDs)
 	s.scope.SetAgentPoolReplicas(int32(len(providerIDs)))
+	if err := s.startupTaintRemover.RemoveAgentPoolStartupTaints(ctx); err != nil {
+		return errors.Wrapf(err, "failed to remove the startup taint from the nodes of machine pool %s", agentPoolName)
+	}
+
+	if err := s.nodeLabelRemover.RemoveAgentPoolNodeLabels(ctx); err != nil {
+		return errors.Wrapf(err, "failed to remove labels from the nodes of machine pool %s", agentPoolName)
+	}
+
+	if err := s.nodeAnnotator.AnnotateAgentPoolNodes(ctx); err != nil {
+		return errors.Wrapf(err, "failed to annotate the nodes of machine pool %s", agentPoolName)
+	}
+
 	s.scope.SetAgentPoolReady(true)
 
+	if err := s.upgradeGroupWaiter.WaitForAgentPoolUpgradeGroup(ctx); err != nil {
+		return errors.Wrapf(err, "failed to upgrade machine pool %s", agentPoolName)
+	}
+
 	s.scope.Info("reconciled machine pool successfully")
 	return nil
 }
@@ -139,8 +209,17 @@ func (s *azureManagedMachinePoolService) Delete(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureManagedMachinePoolService.Delete")
 	defer done()
 
+	agentPoolSpec, err := s.scope.AgentPoolSpec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get agent pool spec")
+	}
+
+	if err := s.nodeDrainer.DrainAgentPoolNodes(ctx); err != nil {
+		return errors.Wrapf(err, "failed to drain the nodes of machine pool %s", agentPoolSpec.Name)
+	}
+
 	if err := s.agentPoolsSvc.Delete(ctx); err != nil {
-		return errors.Wrapf(err, "failed to delete machine pool %s", s.scope.AgentPoolSpec().Name)
+		return errors.Wrapf(err, "failed to delete machine pool %s", agentPoolSpec.Name)
 	}
 
 	return nil
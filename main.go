@@ -52,6 +52,7 @@ import (
 	infrav1alpha3 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
 	infrav1alpha4 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
 	infrav1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
 	"sigs.k8s.io/cluster-api-provider-azure/controllers"
 	infrav1alpha3exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1alpha3"
 	infrav1alpha4exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1alpha4"
@@ -59,6 +60,7 @@ import (
 	infrav1controllersexp "sigs.k8s.io/cluster-api-provider-azure/exp/controllers"
 	"sigs.k8s.io/cluster-api-provider-azure/feature"
 	"sigs.k8s.io/cluster-api-provider-azure/pkg/ot"
+	"sigs.k8s.io/cluster-api-provider-azure/util/ratelimit"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/webhook"
 	"sigs.k8s.io/cluster-api-provider-azure/version"
@@ -118,6 +120,9 @@ var (
 	webhookPort                        int
 	reconcileTimeout                   time.Duration
 	enableTracing                      bool
+	armWriteQPS                        float32
+	armWriteBurst                      int
+	roleAssignmentMaxCreateAttempts    int
 )
 
 // InitFlags initializes all command-line flags.
@@ -244,6 +249,24 @@ func InitFlags(fs *pflag.FlagSet) {
 		"Enable tracing to the opentelemetry-collector service in the same namespace.",
 	)
 
+	fs.Float32Var(&armWriteQPS,
+		"arm-write-qps",
+		ratelimit.DefaultWriteQPS,
+		"The maximum number of Azure Resource Manager writes per second in a subscription, shared by all reconcilers. Zero disables the limit.",
+	)
+
+	fs.IntVar(&armWriteBurst,
+		"arm-write-burst",
+		ratelimit.DefaultWriteBurst,
+		"The maximum number of Azure Resource Manager writes in a subscription in a single burst, shared by all reconcilers.",
+	)
+
+	fs.IntVar(&roleAssignmentMaxCreateAttempts,
+		"role-assignment-max-create-attempts",
+		roleassignments.DefaultMaxCreateAttempts,
+		"The number of consecutive reconciles in which creating a role assignment can fail with a non-retriable error before reconciling the owner of the role assignment fails. Must be at least 1.",
+	)
+
 	feature.MutableGates.AddFlag(fs)
 }
 
@@ -265,6 +288,13 @@ func main() {
 
 	ctrl.SetLogger(klogr.New())
 
+	ratelimit.SetWrites(ratelimit.New(armWriteQPS, armWriteBurst))
+
+	if roleAssignmentMaxCreateAttempts < 1 {
+		setupLog.Error(fmt.Errorf("--role-assignment-max-create-attempts must be at least 1, got %d", roleAssignmentMaxCreateAttempts), "invalid flag")
+		os.Exit(1)
+	}
+
 	// Machine and cluster operations can create enough events to trigger the event recorder spam filter
 	// Setting the burst size higher ensures all events will be recorded and submitted to the API
 	broadcaster := cgrecord.NewBroadcasterWithCorrelatorOptions(cgrecord.CorrelatorOptions{
@@ -333,6 +363,7 @@ func registerControllers(ctx context.Context, mgr manager.Manager) {
 		mgr.GetEventRecorderFor("azuremachine-reconciler"),
 		reconcileTimeout,
 		watchFilterValue,
+		roleAssignmentMaxCreateAttempts,
 	).SetupWithManager(ctx, mgr, controllers.Options{Options: controller.Options{MaxConcurrentReconciles: azureMachineConcurrency}, Cache: machineCache}); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AzureMachine")
 		os.Exit(1)
@@ -400,6 +431,7 @@ func registerControllers(ctx context.Context, mgr manager.Manager) {
 			mgr.GetEventRecorderFor("azuremachinepool-reconciler"),
 			reconcileTimeout,
 			watchFilterValue,
+			roleAssignmentMaxCreateAttempts,
 		).SetupWithManager(ctx, mgr, controllers.Options{Options: controller.Options{MaxConcurrentReconciles: azureMachinePoolConcurrency}, Cache: mpCache}); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "AzureMachinePool")
 			os.Exit(1)
@@ -471,11 +503,12 @@ func registerControllers(ctx context.Context, mgr manager.Manager) {
 			}
 
 			if err := (&infrav1controllersexp.AzureManagedControlPlaneReconciler{
-				Client:           mgr.GetClient(),
-				Log:              ctrl.Log.WithName("controllers").WithName("AzureManagedControlPlane"),
-				Recorder:         mgr.GetEventRecorderFor("azuremanagedcontrolplane-reconciler"),
-				ReconcileTimeout: reconcileTimeout,
-				WatchFilterValue: watchFilterValue,
+				Client:                          mgr.GetClient(),
+				Log:                             ctrl.Log.WithName("controllers").WithName("AzureManagedControlPlane"),
+				Recorder:                        mgr.GetEventRecorderFor("azuremanagedcontrolplane-reconciler"),
+				ReconcileTimeout:                reconcileTimeout,
+				WatchFilterValue:                watchFilterValue,
+				RoleAssignmentMaxCreateAttempts: roleAssignmentMaxCreateAttempts,
 			}).SetupWithManager(ctx, mgr, controllers.Options{Options: controller.Options{MaxConcurrentReconciles: azureClusterConcurrency}, Cache: mcpCache}); err != nil {
 				setupLog.Error(err, "unable to create controller", "controller", "AzureManagedControlPlane")
 				os.Exit(1)
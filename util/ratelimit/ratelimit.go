@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"sync"
+
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+const (
+	// DefaultWriteQPS is the default number of ARM writes per second allowed in a subscription.
+	DefaultWriteQPS = 10
+	// DefaultWriteBurst is the default number of ARM writes allowed in a subscription in a single burst.
+	DefaultWriteBurst = 100
+)
+
+// Limiters hands out a single rate limiter per subscription ID, so that all the services issuing ARM requests in
+// the same subscription share it, whichever cluster they reconcile.
+type Limiters struct {
+	qps   float32
+	burst int
+
+	mu       sync.Mutex
+	limiters map[string]flowcontrol.RateLimiter
+}
+
+var (
+	writeLimitersMu sync.RWMutex
+	writeLimiters   = New(DefaultWriteQPS, DefaultWriteBurst)
+)
+
+// New returns limiters allowing qps requests per second with bursts of burst requests in each subscription.
+// A qps of zero or less disables rate limiting.
+func New(qps float32, burst int) *Limiters {
+	return &Limiters{
+		qps:      qps,
+		burst:    burst,
+		limiters: map[string]flowcontrol.RateLimiter{},
+	}
+}
+
+// NewPermissive returns limiters that never delay a request. Used for testing.
+func NewPermissive() *Limiters {
+	return New(0, 0)
+}
+
+// ForSubscription returns the rate limiter of the subscription, creating it on first use.
+func (l *Limiters) ForSubscription(subscriptionID string) flowcontrol.RateLimiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limiter, ok := l.limiters[subscriptionID]
+	if !ok {
+		if l.qps <= 0 {
+			limiter = flowcontrol.NewFakeAlwaysRateLimiter()
+		} else {
+			limiter = flowcontrol.NewTokenBucketRateLimiter(l.qps, l.burst)
+		}
+		l.limiters[subscriptionID] = limiter
+	}
+	return limiter
+}
+
+// Writes returns the limiters consulted before issuing ARM writes.
+func Writes() *Limiters {
+	writeLimitersMu.RLock()
+	defer writeLimitersMu.RUnlock()
+	return writeLimiters
+}
+
+// SetWrites replaces the limiters consulted before issuing ARM writes, e.g. to configure their QPS and burst at
+// startup or to use permissive limiters in tests.
+func SetWrites(limiters *Limiters) {
+	writeLimitersMu.Lock()
+	defer writeLimitersMu.Unlock()
+	writeLimiters = limiters
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestLimitersForSubscription(t *testing.T) {
+	g := NewWithT(t)
+
+	limiters := New(1, 1)
+	g.Expect(limiters.ForSubscription("123")).To(BeIdenticalTo(limiters.ForSubscription("123")))
+	g.Expect(limiters.ForSubscription("123")).NotTo(BeIdenticalTo(limiters.ForSubscription("456")))
+
+	g.Expect(limiters.ForSubscription("123").TryAccept()).To(BeTrue())
+	g.Expect(limiters.ForSubscription("123").TryAccept()).To(BeFalse())
+	g.Expect(limiters.ForSubscription("456").TryAccept()).To(BeTrue())
+}
+
+func TestNewPermissive(t *testing.T) {
+	g := NewWithT(t)
+
+	limiter := NewPermissive().ForSubscription("123")
+	for i := 0; i < 100; i++ {
+		g.Expect(limiter.TryAccept()).To(BeTrue())
+	}
+}